@@ -18,6 +18,8 @@ type App struct {
 	LatitudeRef  string `json:"latitude_ref"`
 	LongitudeRef string `json:"longitude_ref"`
 	MaxDistance  int    `json:"max_distance"`
+
+	InternalApiKey string `json:"internal_api_key"`
 }
 
 type Database struct {
@@ -91,6 +93,8 @@ func NewConfig() *Config {
 			LatitudeRef:  viper.GetString("LATITUDE_REF"),
 			LongitudeRef: viper.GetString("LONGITUDE_REF"),
 			MaxDistance:  viper.GetInt("MAX_DISTANCE"),
+
+			InternalApiKey: viper.GetString("INTERNAL_API_KEY"),
 		},
 		Database: Database{
 			Host:               viper.GetString("DATABASE_HOST"),
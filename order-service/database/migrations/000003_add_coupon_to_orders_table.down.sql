@@ -0,0 +1,3 @@
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS discount_amount,
+    DROP COLUMN IF EXISTS coupon_code;
@@ -0,0 +1,9 @@
+ALTER TABLE order_items
+    DROP COLUMN IF EXISTS line_total,
+    DROP COLUMN IF EXISTS actual_weight,
+    DROP COLUMN IF EXISTS estimated_weight,
+    DROP COLUMN IF EXISTS weight_unit,
+    DROP COLUMN IF EXISTS quantity_unit,
+    DROP COLUMN IF EXISTS price_unit,
+    DROP COLUMN IF EXISTS sell_by,
+    DROP COLUMN IF EXISTS price;
@@ -0,0 +1,9 @@
+ALTER TABLE order_items
+    ADD COLUMN IF NOT EXISTS price DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS sell_by VARCHAR(20) NOT NULL DEFAULT 'item',
+    ADD COLUMN IF NOT EXISTS price_unit VARCHAR(20) NULL,
+    ADD COLUMN IF NOT EXISTS quantity_unit VARCHAR(20) NULL,
+    ADD COLUMN IF NOT EXISTS weight_unit VARCHAR(20) NULL,
+    ADD COLUMN IF NOT EXISTS estimated_weight INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS actual_weight INT NULL,
+    ADD COLUMN IF NOT EXISTS line_total DECIMAL(12, 2) NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS promotion_discount DECIMAL(10, 2) NOT NULL DEFAULT 0;
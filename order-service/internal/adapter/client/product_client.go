@@ -2,8 +2,10 @@ package client
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"order-service/config"
 	httpclient "order-service/internal/adapter/http_client"
 	"order-service/internal/core/domain/entity"
@@ -16,6 +18,11 @@ import (
 type IProductClient interface {
 	GetProduct(productID int64, accessToken string, isCustomer bool) (*entity.ProductResponseEntity, error)
 	GetProductsBulk(productIDs []int64, accessToken string, isCustomer bool) (map[int64]entity.ProductResponseEntity, error)
+	RedeemCoupon(code, orderCode string, buyerID, subtotal int64) (*entity.CouponRedemptionResponseEntity, error)
+	ReleaseCoupon(orderCode string, buyerID int64) error
+	ReservePurchaseLimits(orderCode string, buyerID int64, items []entity.OrderItemEntity) ([]entity.PurchaseLimitViolationEntity, error)
+	ReleasePurchaseLimits(orderCode string) error
+	PriceCart(items []entity.OrderItemEntity, shippingFee int64, accessToken string) (*entity.CartPriceResponseEntity, error)
 }
 
 type productClient struct {
@@ -113,3 +120,218 @@ func (c *productClient) GetProductsBulk(productIDs []int64, accessToken string,
 
 	return productMap, nil
 }
+
+// RedeemCoupon records the coupon against the order in product-service, which
+// checks the limits and returns the discount to apply on subtotal.
+func (c *productClient) RedeemCoupon(code, orderCode string, buyerID, subtotal int64) (*entity.CouponRedemptionResponseEntity, error) {
+	baseUrlProduct := fmt.Sprintf("%s/internal/coupons/redeem", c.cfg.App.ProductServiceUrl)
+
+	rawData, err := json.Marshal(map[string]interface{}{
+		"code":       code,
+		"order_code": orderCode,
+		"buyer_id":   buyerID,
+		"subtotal":   subtotal,
+	})
+	if err != nil {
+		log.Errorf("[ProductClient-1] RedeemCoupon: %v", err)
+		return nil, err
+	}
+
+	header := map[string]string{
+		"X-Service-Key": c.cfg.App.InternalApiKey,
+		"Accept":        "application/json",
+		"Content-Type":  "application/json",
+	}
+
+	resp, err := c.httpClient.CallURL("POST", baseUrlProduct, header, rawData)
+	if err != nil {
+		log.Errorf("[ProductClient-2] RedeemCoupon: %v", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Errorf("[ProductClient-3] RedeemCoupon: %v", err)
+		return nil, err
+	}
+
+	var redemptionResponse entity.CouponRedemptionHttpClientResponse
+	if err := json.Unmarshal(body, &redemptionResponse); err != nil {
+		log.Errorf("[ProductClient-4] RedeemCoupon: %v. Body: %s", err, string(body))
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		err = errors.New(redemptionResponse.Message)
+		log.Errorf("[ProductClient-5] RedeemCoupon: %v", err)
+		return nil, err
+	}
+
+	return &redemptionResponse.Data, nil
+}
+
+func (c *productClient) ReleaseCoupon(orderCode string, buyerID int64) error {
+	baseUrlProduct := fmt.Sprintf("%s/internal/coupons/redeem/%s?buyer_id=%d", c.cfg.App.ProductServiceUrl, orderCode, buyerID)
+
+	header := map[string]string{
+		"X-Service-Key": c.cfg.App.InternalApiKey,
+		"Accept":        "application/json",
+	}
+
+	resp, err := c.httpClient.CallURL("DELETE", baseUrlProduct, header, nil)
+	if err != nil {
+		log.Errorf("[ProductClient-1] ReleaseCoupon: %v", err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("release coupon failed with status %d", resp.StatusCode)
+		log.Errorf("[ProductClient-2] ReleaseCoupon: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// ReservePurchaseLimits counts the items of the order against the purchase
+// limits of the buyer in product-service. It returns the limits the items go
+// over, none when they were reserved.
+func (c *productClient) ReservePurchaseLimits(orderCode string, buyerID int64, items []entity.OrderItemEntity) ([]entity.PurchaseLimitViolationEntity, error) {
+	baseUrlProduct := fmt.Sprintf("%s/internal/purchase-limits/reservations", c.cfg.App.ProductServiceUrl)
+
+	reqItems := []map[string]int64{}
+	for _, item := range items {
+		reqItems = append(reqItems, map[string]int64{
+			"product_id": item.ProductID,
+			"quantity":   item.Quantity,
+		})
+	}
+
+	rawData, err := json.Marshal(map[string]interface{}{
+		"order_code": orderCode,
+		"buyer_id":   buyerID,
+		"items":      reqItems,
+	})
+	if err != nil {
+		log.Errorf("[ProductClient-1] ReservePurchaseLimits: %v", err)
+		return nil, err
+	}
+
+	header := map[string]string{
+		"X-Service-Key": c.cfg.App.InternalApiKey,
+		"Accept":        "application/json",
+		"Content-Type":  "application/json",
+	}
+
+	resp, err := c.httpClient.CallURL("POST", baseUrlProduct, header, rawData)
+	if err != nil {
+		log.Errorf("[ProductClient-2] ReservePurchaseLimits: %v", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Errorf("[ProductClient-3] ReservePurchaseLimits: %v", err)
+		return nil, err
+	}
+
+	var limitResponse entity.PurchaseLimitHttpClientResponse
+	if err := json.Unmarshal(body, &limitResponse); err != nil {
+		log.Errorf("[ProductClient-4] ReservePurchaseLimits: %v. Body: %s", err, string(body))
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil, nil
+	case http.StatusUnprocessableEntity:
+		return limitResponse.Data, nil
+	default:
+		err = errors.New(limitResponse.Message)
+		log.Errorf("[ProductClient-5] ReservePurchaseLimits: %v", err)
+		return nil, err
+	}
+}
+
+func (c *productClient) ReleasePurchaseLimits(orderCode string) error {
+	baseUrlProduct := fmt.Sprintf("%s/internal/purchase-limits/reservations/%s", c.cfg.App.ProductServiceUrl, orderCode)
+
+	header := map[string]string{
+		"X-Service-Key": c.cfg.App.InternalApiKey,
+		"Accept":        "application/json",
+	}
+
+	resp, err := c.httpClient.CallURL("DELETE", baseUrlProduct, header, nil)
+	if err != nil {
+		log.Errorf("[ProductClient-1] ReleasePurchaseLimits: %v", err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("release purchase limits failed with status %d", resp.StatusCode)
+		log.Errorf("[ProductClient-2] ReleasePurchaseLimits: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// PriceCart prices the items with the promotions of the customer the token
+// belongs to, as product-service does for the cart.
+func (c *productClient) PriceCart(items []entity.OrderItemEntity, shippingFee int64, accessToken string) (*entity.CartPriceResponseEntity, error) {
+	baseUrlProduct := fmt.Sprintf("%s/cart/price", c.cfg.App.ProductServiceUrl)
+
+	reqItems := []map[string]int64{}
+	for _, item := range items {
+		reqItems = append(reqItems, map[string]int64{
+			"product_id": item.ProductID,
+			"quantity":   item.Quantity,
+		})
+	}
+
+	rawData, err := json.Marshal(map[string]interface{}{
+		"shipping_fee": shippingFee,
+		"items":        reqItems,
+	})
+	if err != nil {
+		log.Errorf("[ProductClient-1] PriceCart: %v", err)
+		return nil, err
+	}
+
+	header := map[string]string{
+		"Authorization": "Bearer " + accessToken,
+		"Accept":        "application/json",
+		"Content-Type":  "application/json",
+	}
+
+	resp, err := c.httpClient.CallURL("POST", baseUrlProduct, header, rawData)
+	if err != nil {
+		log.Errorf("[ProductClient-2] PriceCart: %v", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Errorf("[ProductClient-3] PriceCart: %v", err)
+		return nil, err
+	}
+
+	var priceResponse entity.CartPriceHttpClientResponse
+	if err := json.Unmarshal(body, &priceResponse); err != nil {
+		log.Errorf("[ProductClient-4] PriceCart: %v. Body: %s", err, string(body))
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		err = errors.New(priceResponse.Message)
+		log.Errorf("[ProductClient-5] PriceCart: %v", err)
+		return nil, err
+	}
+
+	return &priceResponse.Data, nil
+}
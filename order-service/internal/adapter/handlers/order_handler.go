@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"order-service/config"
 	"order-service/internal/adapter"
@@ -25,6 +26,7 @@ type IOrderHandler interface {
 	GetAllCustomer(c echo.Context) error
 	GetOrderByOrderCode(c echo.Context) error
 	UpdateStatus(c echo.Context) error
+	UpdateItemWeight(c echo.Context) error
 	DeleteByID(c echo.Context) error
 
 	GetPublicOrderByOrderCode(c echo.Context) error
@@ -155,6 +157,65 @@ func (o *orderHandler) UpdateStatus(c echo.Context) error {
 
 }
 
+// UpdateItemWeight implements [IOrderHandler].
+func (o *orderHandler) UpdateItemWeight(c echo.Context) error {
+	var (
+		ctx = c.Request().Context()
+		req = request.OrderItemWeightRequest{}
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		log.Errorf("[OrderHandler-1] UpdateItemWeight: %s", "data token not found")
+		return c.JSON(http.StatusUnauthorized, response.ResponseError("data token not found"))
+	}
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[OrderHandler-2] UpdateItemWeight: %v", err)
+		return c.JSON(http.StatusBadRequest, response.ResponseError(err.Error()))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Errorf("[OrderHandler-3] UpdateItemWeight: %v", err)
+		return c.JSON(http.StatusUnprocessableEntity, response.ResponseError(err.Error()))
+	}
+
+	orderID, err := conv.StringToInt64(c.Param("orderID"))
+	if err != nil {
+		log.Errorf("[OrderHandler-4] UpdateItemWeight: %v", err)
+		return c.JSON(http.StatusBadRequest, response.ResponseError(err.Error()))
+	}
+
+	itemID, err := conv.StringToInt64(c.Param("itemID"))
+	if err != nil {
+		log.Errorf("[OrderHandler-5] UpdateItemWeight: %v", err)
+		return c.JSON(http.StatusBadRequest, response.ResponseError(err.Error()))
+	}
+
+	order, err := o.orderService.UpdateItemWeight(ctx, orderID, itemID, req.ActualWeight, user)
+	if err != nil {
+		log.Errorf("[OrderHandler-6] UpdateItemWeight: %v", err)
+		if err.Error() == "404" {
+			return c.JSON(http.StatusNotFound, response.ResponseError("data not found"))
+		}
+		if errors.Is(err, service.ErrNotEstimated) || errors.Is(err, service.ErrOrderPaid) || errors.Is(err, service.ErrAlreadyWeighed) {
+			return c.JSON(http.StatusUnprocessableEntity, response.ResponseError(err.Error()))
+		}
+		return c.JSON(http.StatusInternalServerError, response.ResponseError(err.Error()))
+	}
+
+	orderDetail := []response.OrderDetail{}
+	for _, item := range order.OrderItems {
+		orderDetail = append(orderDetail, response.NewOrderDetail(item))
+	}
+
+	return c.JSON(http.StatusOK, response.ResponseSuccess("success", map[string]interface{}{
+		"order_id":     order.ID,
+		"total_amount": order.TotalAmount,
+		"order_detail": orderDetail,
+	}))
+}
+
 // GetOrderByOrderCode implements [IOrderHandler].
 func (o *orderHandler) GetOrderByOrderCode(c echo.Context) error {
 	var (
@@ -189,6 +250,9 @@ func (o *orderHandler) GetOrderByOrderCode(c echo.Context) error {
 	respOrder.TotalAmount = order.TotalAmount
 	respOrder.OrderDatetime = order.OrderDate
 	respOrder.ShippingFee = order.ShippingFee
+	respOrder.CouponCode = order.CouponCode
+	respOrder.Discount = order.Discount
+	respOrder.PromotionDiscount = order.PromotionDiscount
 	respOrder.Remarks = order.Remarks
 	respOrder.PaymentMethod = order.PaymentMethod
 	respOrder.Customer = response.CustomerOrder{
@@ -200,12 +264,7 @@ func (o *orderHandler) GetOrderByOrderCode(c echo.Context) error {
 	}
 
 	for _, item := range order.OrderItems {
-		respOrder.OrderDetail = append(respOrder.OrderDetail, response.OrderDetail{
-			ProductName:  item.ProductName,
-			ProductImage: item.ProductImage,
-			ProductPrice: item.Price,
-			Quantity:     item.Quantity,
-		})
+		respOrder.OrderDetail = append(respOrder.OrderDetail, response.NewOrderDetail(item))
 	}
 
 	return c.JSON(http.StatusOK, response.ResponseSuccess("success", respOrder))
@@ -251,6 +310,9 @@ func (o *orderHandler) GetByIDAdmin(c echo.Context) error {
 	respOrder.TotalAmount = order.TotalAmount
 	respOrder.OrderDatetime = order.OrderDate
 	respOrder.ShippingFee = order.ShippingFee
+	respOrder.CouponCode = order.CouponCode
+	respOrder.Discount = order.Discount
+	respOrder.PromotionDiscount = order.PromotionDiscount
 	respOrder.Remarks = order.Remarks
 	respOrder.Customer = response.CustomerOrder{
 		CustomerName:    order.BuyerName,
@@ -261,12 +323,7 @@ func (o *orderHandler) GetByIDAdmin(c echo.Context) error {
 	}
 
 	for _, item := range order.OrderItems {
-		respOrder.OrderDetail = append(respOrder.OrderDetail, response.OrderDetail{
-			ProductName:  item.ProductName,
-			ProductImage: item.ProductImage,
-			ProductPrice: item.Price,
-			Quantity:     item.Quantity,
-		})
+		respOrder.OrderDetail = append(respOrder.OrderDetail, response.NewOrderDetail(item))
 	}
 
 	return c.JSON(http.StatusOK, response.ResponseSuccess("success", respOrder))
@@ -387,6 +444,9 @@ func (o *orderHandler) GetDetailCustomer(c echo.Context) error {
 	respOrder.TotalAmount = order.TotalAmount
 	respOrder.OrderDatetime = order.OrderDate
 	respOrder.ShippingFee = order.ShippingFee
+	respOrder.CouponCode = order.CouponCode
+	respOrder.Discount = order.Discount
+	respOrder.PromotionDiscount = order.PromotionDiscount
 	respOrder.ShippingType = order.ShippingType
 	respOrder.Remarks = order.Remarks
 	respOrder.Customer = response.CustomerOrder{
@@ -398,12 +458,7 @@ func (o *orderHandler) GetDetailCustomer(c echo.Context) error {
 	}
 
 	for _, item := range order.OrderItems {
-		respOrder.OrderDetail = append(respOrder.OrderDetail, response.OrderDetail{
-			ProductName:  item.ProductName,
-			ProductImage: item.ProductImage,
-			ProductPrice: item.Price,
-			Quantity:     item.Quantity,
-		})
+		respOrder.OrderDetail = append(respOrder.OrderDetail, response.NewOrderDetail(item))
 	}
 
 	return c.JSON(http.StatusOK, response.ResponseSuccess("success", respOrder))
@@ -439,6 +494,7 @@ func (o *orderHandler) CreateOrder(c echo.Context) error {
 		ShippingType: req.ShippingType,
 		Remarks:      req.Remarks,
 		OrderTime:    req.OrderTime,
+		CouponCode:   req.CouponCode,
 	}
 
 	orderDetails := []entity.OrderItemEntity{}
@@ -453,6 +509,13 @@ func (o *orderHandler) CreateOrder(c echo.Context) error {
 	orderID, err := o.orderService.CreateOrder(ctx, reqEntity, user)
 	if err != nil {
 		log.Errorf("[OrderHandler-4] CreateOrder: %v", err)
+		if errors.Is(err, service.ErrInvalidOrderQuantity) {
+			return c.JSON(http.StatusUnprocessableEntity, response.ResponseError(err.Error()))
+		}
+		var limitErr *service.PurchaseLimitError
+		if errors.As(err, &limitErr) {
+			return c.JSON(http.StatusUnprocessableEntity, response.DefaultResponse{Message: err.Error(), Data: limitErr.Violations})
+		}
 		return c.JSON(http.StatusInternalServerError, response.ResponseError(err.Error()))
 	}
 
@@ -539,6 +602,7 @@ func NewOrderHandler(orderService service.IOrderService, e *echo.Echo, cfg *conf
 	adminGroup.GET("/orders", ordHandler.GetAllAdmin)
 	adminGroup.GET("/orders/:orderID", ordHandler.GetByIDAdmin)
 	adminGroup.PUT("/orders/:orderID/status", ordHandler.UpdateStatus)
+	adminGroup.PUT("/orders/:orderID/items/:itemID/weight", ordHandler.UpdateItemWeight)
 	adminGroup.DELETE("/orders/:orderID", ordHandler.DeleteByID)
 
 	return ordHandler
@@ -7,6 +7,7 @@ type CreateOrderRequest struct {
 	ShippingType string               `json:"shipping_type" validate:"required"`
 	PaymentType  string               `json:"payment_type" validate:"required"`
 	Remarks      string               `json:"remarks"`
+	CouponCode   string               `json:"coupon_code"`
 	OrderTime    string               `json:"order_time" validate:"required"`
 	OrderDetails []OrderDetailRequest `json:"order_details" validate:"required"`
 }
@@ -20,4 +21,8 @@ type OrderDetailRequest struct {
 type OrderUpdateStatusRequest struct {
 	Status  string `json:"status" validate:"required"`
 	Remarks string `json:"remarks"`
-}
\ No newline at end of file
+}
+
+type OrderItemWeightRequest struct {
+	ActualWeight int64 `json:"actual_weight" validate:"required,gt=0"`
+}
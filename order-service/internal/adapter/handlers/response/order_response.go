@@ -30,18 +30,21 @@ func NewOrderAdminList(e entity.OrderEntity) OrderAdminList {
 }
 
 type OrderAdminDetail struct {
-	ID            int64         `json:"id"`
-	OrderCode     string        `json:"order_code"`
-	ProductImage  string        `json:"product_image"`
-	OrderDatetime string        `json:"order_datetime"`
-	Status        string        `json:"order_status"`
-	PaymentMethod string        `json:"payment_method"`
-	ShippingFee   int64         `json:"shipping_fee"`
-	ShippingType  string        `json:"shipping_type"`
-	Remarks       string        `json:"remarks"`
-	TotalAmount   int64         `json:"total_amount"`
-	Customer      CustomerOrder `json:"customer"`
-	OrderDetail   []OrderDetail `json:"order_detail"`
+	ID                int64         `json:"id"`
+	OrderCode         string        `json:"order_code"`
+	ProductImage      string        `json:"product_image"`
+	OrderDatetime     string        `json:"order_datetime"`
+	Status            string        `json:"order_status"`
+	PaymentMethod     string        `json:"payment_method"`
+	ShippingFee       int64         `json:"shipping_fee"`
+	ShippingType      string        `json:"shipping_type"`
+	CouponCode        string        `json:"coupon_code"`
+	Discount          int64         `json:"discount_amount"`
+	PromotionDiscount int64         `json:"promotion_discount"`
+	Remarks           string        `json:"remarks"`
+	TotalAmount       int64         `json:"total_amount"`
+	Customer          CustomerOrder `json:"customer"`
+	OrderDetail       []OrderDetail `json:"order_detail"`
 }
 
 type CustomerOrder struct {
@@ -53,10 +56,35 @@ type CustomerOrder struct {
 }
 
 type OrderDetail struct {
-	ProductName  string `json:"product_name"`
-	ProductImage string `json:"product_image"`
-	ProductPrice int64  `json:"product_price"`
-	Quantity     int64  `json:"quantity"`
+	ID              int64  `json:"id"`
+	ProductName     string `json:"product_name"`
+	ProductImage    string `json:"product_image"`
+	ProductPrice    int64  `json:"product_price"`
+	Quantity        int64  `json:"quantity"`
+	SellBy          string `json:"sell_by"`
+	PriceUnit       string `json:"price_unit"`
+	QuantityUnit    string `json:"quantity_unit"`
+	WeightUnit      string `json:"weight_unit"`
+	EstimatedWeight int64  `json:"estimated_weight"`
+	ActualWeight    *int64 `json:"actual_weight"`
+	LineTotal       int64  `json:"line_total"`
+}
+
+func NewOrderDetail(e entity.OrderItemEntity) OrderDetail {
+	return OrderDetail{
+		ID:              e.ID,
+		ProductName:     e.ProductName,
+		ProductImage:    e.ProductImage,
+		ProductPrice:    e.Price,
+		Quantity:        e.Quantity,
+		SellBy:          e.SellBy,
+		PriceUnit:       e.PriceUnit,
+		QuantityUnit:    e.QuantityUnit,
+		WeightUnit:      e.WeightUnit,
+		EstimatedWeight: e.EstimatedWeight,
+		ActualWeight:    e.ActualWeight,
+		LineTotal:       e.LineTotal,
+	}
 }
 
 type OrderCustomerList struct {
@@ -71,4 +99,4 @@ type OrderCustomerList struct {
 	Unit          string `json:"unit"`
 	Quantity      int64  `json:"quantity"`
 	OrderDateTime string `json:"order_datetime"`
-}
\ No newline at end of file
+}
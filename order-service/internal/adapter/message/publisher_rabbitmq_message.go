@@ -347,10 +347,33 @@ func (p *PublisherRabbitMQ) PublishOrderToQueue(order entity.OrderEntity) error
 		return err
 	}
 
+	// orders go out on a fanout exchange of the same name, so other services
+	// such as the product recommendations get their own copy through their
+	// own queue
+	err = ch.ExchangeDeclare(
+		p.cfg.PublisherName.OrderPublish,
+		"fanout",
+		true,
+		false,
+		false,
+		false,
+		nil,
+	)
+	if err != nil {
+		log.Errorf("[PublishOrderToQueue-5] Failed to declare exchange: %v", err)
+		return err
+	}
+
+	err = ch.QueueBind(q.Name, "", p.cfg.PublisherName.OrderPublish, false, nil)
+	if err != nil {
+		log.Errorf("[PublishOrderToQueue-6] Failed to bind queue: %v", err)
+		return err
+	}
+
 	data, _ := json.Marshal(order)
 	err = ch.Publish(
+		p.cfg.PublisherName.OrderPublish,
 		"",
-		q.Name,
 		false,
 		false,
 		amqp.Publishing{
@@ -4,11 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
+	"io"
 	"log"
 	"math"
 	"order-service/internal/core/domain/entity"
-	"strings"
+	"order-service/utils/esquery"
 
 	"github.com/elastic/go-elasticsearch/v7"
 )
@@ -24,46 +24,17 @@ type elasticRepository struct {
 
 // SearchOrderElasticByBuyerId implements [IElasticRepository].
 func (e *elasticRepository) SearchOrderElasticByBuyerId(ctx context.Context, query entity.QueryStringEntity, buyerId int64) ([]entity.OrderEntity, int64, int64, error) {
-	from := (query.Page - 1) * query.Limit
-
-	statusFilter := ""
-	if query.Status != "" {
-		statusFilter = fmt.Sprintf(`{ "match": { "status": "%s" } },`, query.Status)
-	}
-
-	searchFilter := `{"match_all": {}}`
-	if query.Search != "" {
-		searchFilter = fmt.Sprintf(`{ "multi_match": { "query": "%s", "fields": ["order_code", "status", "buyer_name"] } }`, query.Search)
+	body, err := orderSearchBody(query, buyerId, true)
+	if err != nil {
+		log.Printf("Error building Elasticsearch query: %s", err)
+		return nil, 0, 0, err
 	}
 
-	idFilter := ""
-	if buyerId != 0 {
-		idFilter = fmt.Sprintf(`{ "term": { "buyer_id": %d } },`, buyerId)
-	}
-
-	// Query Elasticsearch dengan filtering dan pagination
-	mainQuery := fmt.Sprintf(`{
-		"from": %d,
-		"size": %d,
-		"query": {
-			"bool": {
-				"must": [
-					%s
-					%s
-					%s
-				]
-			}
-		},
-		"sort": [
-			{ "id": "desc" }
-		]
-	}`, from, query.Limit, idFilter, statusFilter, searchFilter)
-
 	// Kirim query ke Elasticsearch
 	res, err := e.esClient.Search(
 		e.esClient.Search.WithContext(ctx),
 		e.esClient.Search.WithIndex("orders"),
-		e.esClient.Search.WithBody(strings.NewReader(mainQuery)),
+		e.esClient.Search.WithBody(body),
 		e.esClient.Search.WithPretty(),
 	)
 
@@ -127,39 +98,17 @@ func (e *elasticRepository) SearchOrderElasticByBuyerId(ctx context.Context, que
 
 // SearchOrderElastic implements [IElasticRepository].
 func (e *elasticRepository) SearchOrderElastic(ctx context.Context, query entity.QueryStringEntity) ([]entity.OrderEntity, int64, int64, error) {
-	from := (query.Page - 1) * query.Limit
-
-	statusFilter := ""
-	if query.Status != "" {
-		statusFilter = fmt.Sprintf(`{ "match": { "status": "%s" } },`, query.Status)
-	}
-
-	searchFilter := `{"match_all": {}}`
-	if query.Search != "" {
-		searchFilter = fmt.Sprintf(`{ "multi_match": { "query": "%s", "fields": ["order_code", "status", "buyer_name"] } }`, query.Search)
-	}
-
-	mainQuery := fmt.Sprintf(`{
-		"from": %d,
-		"size": %d,
-		"query": {
-			"bool": {
-				"must": [
-					%s
-					%s
-				]
-			}
-		},
-		"sort": [
-			{ "id": "asc" }
-		]
-	}`, from, query.Limit, statusFilter, searchFilter)
+	body, err := orderSearchBody(query, 0, false)
+	if err != nil {
+		log.Printf("Error building Elasticsearch query: %s", err)
+		return nil, 0, 0, err
+	}
 
 	// Kirim query ke Elasticsearch
 	res, err := e.esClient.Search(
 		e.esClient.Search.WithContext(ctx),
 		e.esClient.Search.WithIndex("orders"),
-		e.esClient.Search.WithBody(strings.NewReader(mainQuery)),
+		e.esClient.Search.WithBody(body),
 		e.esClient.Search.WithPretty(),
 	)
 
@@ -221,6 +170,30 @@ func (e *elasticRepository) SearchOrderElastic(ctx context.Context, query entity
 	return orders, int64(totalData), int64(totalPage), nil
 }
 
+// orderSearchBody filters orders by buyer and status and searches the order
+// code, status and buyer name; newest first when desc is set.
+func orderSearchBody(query entity.QueryStringEntity, buyerId int64, desc bool) (io.Reader, error) {
+	boolQuery := esquery.Bool{}
+	if buyerId != 0 {
+		boolQuery.Filter = append(boolQuery.Filter, esquery.Term("buyer_id", buyerId))
+	}
+
+	if query.Status != "" {
+		boolQuery.Must = append(boolQuery.Must, esquery.Match("status", query.Status))
+	}
+
+	if search := esquery.CleanText(query.Search); search != "" {
+		boolQuery.Must = append(boolQuery.Must, esquery.MultiMatch(search, "order_code", "status", "buyer_name"))
+	}
+
+	return esquery.Search{
+		From:  int((query.Page - 1) * query.Limit),
+		Size:  int(query.Limit),
+		Query: boolQuery.Query(),
+		Sort:  []esquery.Sort{{Field: "id", Desc: desc}},
+	}.Reader()
+}
+
 func NewElasticRepository(esClient *elasticsearch.Client) IElasticRepository {
 	return &elasticRepository{
 		esClient: esClient,
@@ -17,6 +17,7 @@ type IOrderRepository interface {
 	GetByID(ctx context.Context, orderID int64) (*entity.OrderEntity, error)
 	CreateOrder(ctx context.Context, req entity.OrderEntity) (int64, error)
 	UpdateStatus(ctx context.Context, req entity.OrderEntity) (int64, string, string, error)
+	UpdateItemWeight(ctx context.Context, item entity.OrderItemEntity, totalAmount int64) error
 	DeleteOrder(ctx context.Context, orderID int64) error
 
 	GetOrderByOrderCode(ctx context.Context, orderCode string) (*entity.OrderEntity, error)
@@ -94,6 +95,35 @@ func (o *OrderRepository) UpdateStatus(ctx context.Context, req entity.OrderEnti
 
 }
 
+// UpdateItemWeight implements [IOrderRepository]. The weighed line and the
+// new order total are saved together.
+func (o *OrderRepository) UpdateItemWeight(ctx context.Context, item entity.OrderItemEntity, totalAmount int64) error {
+	return o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.OrderItem{}).
+			Where("id = ? AND order_id = ?", item.ID, item.OrderID).
+			Updates(map[string]interface{}{
+				"actual_weight": item.ActualWeight,
+				"line_total":    item.LineTotal,
+				"updated_at":    time.Now(),
+			})
+		if result.Error != nil {
+			log.Errorf("[OrderRepository-1] UpdateItemWeight: %v", result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			log.Infof("[OrderRepository-2] UpdateItemWeight: Order item not found")
+			return errors.New("404")
+		}
+
+		if err := tx.Model(&model.Order{}).Where("id = ?", item.OrderID).Update("total_amount", totalAmount).Error; err != nil {
+			log.Errorf("[OrderRepository-3] UpdateItemWeight: %v", err)
+			return err
+		}
+
+		return nil
+	})
+}
+
 // GetOrderByOrderCode implements [IOrderRepository].
 func (o *OrderRepository) GetOrderByOrderCode(ctx context.Context, orderCode string) (*entity.OrderEntity, error) {
 	var modelOrder model.Order
@@ -107,26 +137,22 @@ func (o *OrderRepository) GetOrderByOrderCode(ctx context.Context, orderCode str
 		return nil, err
 	}
 
-	orderItemEntities := []entity.OrderItemEntity{}
-	for _, item := range modelOrder.OrderItems {
-		orderItemEntities = append(orderItemEntities, entity.OrderItemEntity{
-			ID:        item.ID,
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-		})
-	}
+	orderItemEntities := o.mapOrderItemModelsToEntities(modelOrder.OrderItems)
 
 	return &entity.OrderEntity{
-		ID:           modelOrder.ID,
-		OrderCode:    modelOrder.OrderCode,
-		Status:       modelOrder.Status,
-		BuyerId:      modelOrder.BuyerId,
-		OrderDate:    modelOrder.OrderDate.Format("2006-01-02 15:04:05"),
-		TotalAmount:  int64(modelOrder.TotalAmount),
-		OrderItems:   orderItemEntities,
-		Remarks:      modelOrder.Remarks,
-		ShippingType: modelOrder.ShippingType,
-		ShippingFee:  int64(modelOrder.ShippingFee),
+		ID:                modelOrder.ID,
+		OrderCode:         modelOrder.OrderCode,
+		Status:            modelOrder.Status,
+		BuyerId:           modelOrder.BuyerId,
+		OrderDate:         modelOrder.OrderDate.Format("2006-01-02 15:04:05"),
+		TotalAmount:       int64(modelOrder.TotalAmount),
+		OrderItems:        orderItemEntities,
+		Remarks:           modelOrder.Remarks,
+		ShippingType:      modelOrder.ShippingType,
+		ShippingFee:       int64(modelOrder.ShippingFee),
+		CouponCode:        modelOrder.CouponCode,
+		Discount:          int64(modelOrder.Discount),
+		PromotionDiscount: int64(modelOrder.PromotionDiscount),
 	}, nil
 }
 
@@ -145,16 +171,19 @@ func (o *OrderRepository) GetByID(ctx context.Context, orderID int64) (*entity.O
 	orderItemEntities := o.mapOrderItemModelsToEntities(modelOrder.OrderItems)
 
 	return &entity.OrderEntity{
-		ID:           modelOrder.ID,
-		OrderCode:    modelOrder.OrderCode,
-		Status:       modelOrder.Status,
-		BuyerId:      modelOrder.BuyerId,
-		OrderDate:    modelOrder.OrderDate.Format("2006-01-02 15:04:05"),
-		TotalAmount:  int64(modelOrder.TotalAmount),
-		OrderItems:   orderItemEntities,
-		Remarks:      modelOrder.Remarks,
-		ShippingType: modelOrder.ShippingType,
-		ShippingFee:  int64(modelOrder.ShippingFee),
+		ID:                modelOrder.ID,
+		OrderCode:         modelOrder.OrderCode,
+		Status:            modelOrder.Status,
+		BuyerId:           modelOrder.BuyerId,
+		OrderDate:         modelOrder.OrderDate.Format("2006-01-02 15:04:05"),
+		TotalAmount:       int64(modelOrder.TotalAmount),
+		OrderItems:        orderItemEntities,
+		Remarks:           modelOrder.Remarks,
+		ShippingType:      modelOrder.ShippingType,
+		ShippingFee:       int64(modelOrder.ShippingFee),
+		CouponCode:        modelOrder.CouponCode,
+		Discount:          int64(modelOrder.Discount),
+		PromotionDiscount: int64(modelOrder.PromotionDiscount),
 	}, nil
 }
 
@@ -169,16 +198,19 @@ func (o *OrderRepository) CreateOrder(ctx context.Context, req entity.OrderEntit
 	orderItems = o.mapOrderItemEntitiesToModels(req.OrderItems)
 
 	modelOrder := model.Order{
-		OrderCode:    req.OrderCode,
-		BuyerId:      req.BuyerId,
-		OrderDate:    orderDate,
-		OrderTime:    req.OrderTime,
-		Status:       req.Status,
-		TotalAmount:  float64(req.TotalAmount),
-		ShippingType: req.ShippingType,
-		ShippingFee:  float64(req.ShippingFee),
-		Remarks:      req.Remarks,
-		OrderItems:   orderItems,
+		OrderCode:         req.OrderCode,
+		BuyerId:           req.BuyerId,
+		OrderDate:         orderDate,
+		OrderTime:         req.OrderTime,
+		Status:            req.Status,
+		TotalAmount:       float64(req.TotalAmount),
+		ShippingType:      req.ShippingType,
+		ShippingFee:       float64(req.ShippingFee),
+		CouponCode:        req.CouponCode,
+		Discount:          float64(req.Discount),
+		PromotionDiscount: float64(req.PromotionDiscount),
+		Remarks:           req.Remarks,
+		OrderItems:        orderItems,
 	}
 
 	if err := o.db.Create(&modelOrder).Error; err != nil {
@@ -248,8 +280,15 @@ func (o *OrderRepository) mapOrderItemEntitiesToModels(itemEntities []entity.Ord
 	orderItems := make([]model.OrderItem, 0, len(itemEntities))
 	for _, item := range itemEntities {
 		orderItems = append(orderItems, model.OrderItem{
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
+			ProductID:       item.ProductID,
+			Quantity:        item.Quantity,
+			Price:           float64(item.Price),
+			SellBy:          item.SellBy,
+			PriceUnit:       item.PriceUnit,
+			QuantityUnit:    item.QuantityUnit,
+			WeightUnit:      item.WeightUnit,
+			EstimatedWeight: item.EstimatedWeight,
+			LineTotal:       float64(item.LineTotal),
 		})
 	}
 	return orderItems
@@ -259,9 +298,18 @@ func (o *OrderRepository) mapOrderItemModelsToEntities(itemModels []model.OrderI
 	orderItemEntities := make([]entity.OrderItemEntity, 0, len(itemModels))
 	for _, item := range itemModels {
 		orderItemEntities = append(orderItemEntities, entity.OrderItemEntity{
-			ID:        item.ID,
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
+			ID:              item.ID,
+			OrderID:         item.OrderID,
+			ProductID:       item.ProductID,
+			Quantity:        item.Quantity,
+			Price:           int64(item.Price),
+			SellBy:          item.SellBy,
+			PriceUnit:       item.PriceUnit,
+			QuantityUnit:    item.QuantityUnit,
+			WeightUnit:      item.WeightUnit,
+			EstimatedWeight: item.EstimatedWeight,
+			ActualWeight:    item.ActualWeight,
+			LineTotal:       int64(item.LineTotal),
 		})
 	}
 	return orderItemEntities
@@ -0,0 +1,39 @@
+package entity
+
+// CartPriceHttpClientResponse matches the JSON response of the product service cart pricing endpoint.
+type CartPriceHttpClientResponse struct {
+	Message string                  `json:"message"`
+	Data    CartPriceResponseEntity `json:"data"`
+}
+
+// CartPriceResponseEntity is the cart priced with the promotions of the
+// customer. Total is what is left to pay before coupons.
+type CartPriceResponseEntity struct {
+	Items            []CartPriceLineResponseEntity `json:"items"`
+	Subtotal         int64                         `json:"subtotal"`
+	LineDiscount     int64                         `json:"line_discount"`
+	OrderDiscount    int64                         `json:"order_discount"`
+	ShippingFee      int64                         `json:"shipping_fee"`
+	ShippingDiscount int64                         `json:"shipping_discount"`
+	Total            int64                         `json:"total"`
+}
+
+type CartPriceLineResponseEntity struct {
+	ProductID int64 `json:"product_id"`
+	Quantity  int64 `json:"quantity"`
+	UnitPrice int64 `json:"unit_price"`
+	Subtotal  int64 `json:"subtotal"`
+	Discount  int64 `json:"discount"`
+	Total     int64 `json:"total"`
+}
+
+// PromotionDiscount is everything the promotions take off the order.
+func (c CartPriceResponseEntity) PromotionDiscount() int64 {
+	return c.LineDiscount + c.OrderDiscount + c.ShippingDiscount
+}
+
+// CouponSubtotal is the goods after promotions, before shipping; coupons are
+// checked and computed on it.
+func (c CartPriceResponseEntity) CouponSubtotal() int64 {
+	return c.Subtotal - c.LineDiscount - c.OrderDiscount
+}
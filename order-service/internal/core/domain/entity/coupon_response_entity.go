@@ -0,0 +1,14 @@
+package entity
+
+// CouponRedemptionHttpClientResponse matches the JSON response of the product service coupon redemption endpoint.
+type CouponRedemptionHttpClientResponse struct {
+	Message string                         `json:"message"`
+	Data    CouponRedemptionResponseEntity `json:"data"`
+}
+
+type CouponRedemptionResponseEntity struct {
+	ID        int64  `json:"id"`
+	Code      string `json:"code"`
+	OrderCode string `json:"order_code"`
+	Discount  int64  `json:"discount"`
+}
@@ -2,26 +2,32 @@ package entity
 
 import "time"
 
+// OrderEntity is an order. PromotionDiscount is what the promotions took off
+// the order, Discount what the coupon took off; both are fixed at checkout
+// and are not priced again when estimated items are weighed.
 type OrderEntity struct {
-	ID            int64             `json:"id"`
-	OrderCode     string            `json:"order_code"`
-	BuyerId       int64             `json:"buyer_id"`
-	OrderDate     string            `json:"order_date"`
-	Status        string            `json:"status"`
-	TotalAmount   int64             `json:"total_amount"`
-	PaymentMethod string            `json:"payment_method"`
-	ShippingType  string            `json:"shipping_type"`
-	ShippingFee   int64             `json:"shipping_fee"`
-	OrderTime     string            `json:"order_time"`
-	Remarks       string            `json:"remarks"`
-	CreatedAt     time.Time         `json:"created_at"`
-	OrderItems    []OrderItemEntity `json:"order_items"`
-	BuyerName     string            `json:"buyer_name"`
-	BuyerEmail    string            `json:"buyer_email"`
-	BuyerPhone    string            `json:"buyer_phone"`
-	BuyerAddress  string            `json:"buyer_address"`
-	BuyerLat      string            `json:"buyer_lat"`
-	BuyerLng      string            `json:"buyer_lng"`
+	ID                int64             `json:"id"`
+	OrderCode         string            `json:"order_code"`
+	BuyerId           int64             `json:"buyer_id"`
+	OrderDate         string            `json:"order_date"`
+	Status            string            `json:"status"`
+	TotalAmount       int64             `json:"total_amount"`
+	PaymentMethod     string            `json:"payment_method"`
+	ShippingType      string            `json:"shipping_type"`
+	ShippingFee       int64             `json:"shipping_fee"`
+	CouponCode        string            `json:"coupon_code"`
+	Discount          int64             `json:"discount_amount"`
+	PromotionDiscount int64             `json:"promotion_discount"`
+	OrderTime         string            `json:"order_time"`
+	Remarks           string            `json:"remarks"`
+	CreatedAt         time.Time         `json:"created_at"`
+	OrderItems        []OrderItemEntity `json:"order_items"`
+	BuyerName         string            `json:"buyer_name"`
+	BuyerEmail        string            `json:"buyer_email"`
+	BuyerPhone        string            `json:"buyer_phone"`
+	BuyerAddress      string            `json:"buyer_address"`
+	BuyerLat          string            `json:"buyer_lat"`
+	BuyerLng          string            `json:"buyer_lng"`
 }
 
 type QueryStringEntity struct {
@@ -30,4 +36,4 @@ type QueryStringEntity struct {
 	Limit   int64
 	Status  string
 	BuyerID int64
-}
\ No newline at end of file
+}
@@ -11,9 +11,19 @@ type OrderItemEntity struct {
 	Price         int64  `json:"price"`
 	ProductUnit   string `json:"product_unit"`
 	ProductWeight int64  `json:"product_weight"`
+	// Price is the unit price at ordering, per PriceUnit for products sold by
+	// weight. Estimated items are billed by ActualWeight once packed and by
+	// EstimatedWeight, in WeightUnit, until then.
+	SellBy          string `json:"sell_by"`
+	PriceUnit       string `json:"price_unit"`
+	QuantityUnit    string `json:"quantity_unit"`
+	WeightUnit      string `json:"weight_unit"`
+	EstimatedWeight int64  `json:"estimated_weight"`
+	ActualWeight    *int64 `json:"actual_weight"`
+	LineTotal       int64  `json:"line_total"`
 }
 
 type PublishOrderItemEntity struct {
 	ProductID int64 `json:"product_id"`
 	Quantity  int64 `json:"quantity"`
-}
\ No newline at end of file
+}
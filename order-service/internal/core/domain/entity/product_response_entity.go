@@ -22,6 +22,18 @@ type ProductResponseEntity struct {
 	Stock         int                          `json:"stock"`
 	Child         []ChildProductResponseEntity `json:"child"`
 	ProductID     int64                        `json:"product_id"`
+	Selling       ProductSellingEntity         `json:"selling"`
+}
+
+// ProductSellingEntity are the selling rules of a product; quantities and
+// limits are in QuantityUnit for products sold by measure.
+type ProductSellingEntity struct {
+	SellBy       string `json:"sell_by"`
+	PriceUnit    string `json:"price_unit"`
+	QuantityUnit string `json:"quantity_unit"`
+	QuantityStep int64  `json:"quantity_step"`
+	MinQuantity  int64  `json:"min_quantity"`
+	MaxQuantity  int64  `json:"max_quantity"`
 }
 
 type ChildProductResponseEntity struct {
@@ -32,4 +44,4 @@ type ChildProductResponseEntity struct {
 	SalePrice    float64 `json:"sale_price"`
 	Unit         string  `json:"unit"`
 	Image        string  `json:"image"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,22 @@
+package entity
+
+// PurchaseLimitHttpClientResponse matches the JSON response of the product service purchase limit check endpoint.
+type PurchaseLimitHttpClientResponse struct {
+	Message string                         `json:"message"`
+	Data    []PurchaseLimitViolationEntity `json:"data"`
+}
+
+// PurchaseLimitViolationEntity is a limit the order goes over; Remaining is
+// what the customer can still buy within the window.
+type PurchaseLimitViolationEntity struct {
+	LimitID            int64   `json:"limit_id"`
+	Name               string  `json:"name"`
+	ProductID          *int64  `json:"product_id"`
+	CategorySlug       string  `json:"category_slug"`
+	MaxQuantity        int64   `json:"max_quantity"`
+	WindowHours        int     `json:"window_hours"`
+	Purchased          int64   `json:"purchased"`
+	Requested          int64   `json:"requested"`
+	Remaining          int64   `json:"remaining"`
+	AffectedProductIDs []int64 `json:"affected_product_ids"`
+}
@@ -7,12 +7,20 @@ import (
 )
 
 type OrderItem struct {
-	ID        int64          `gorm:"primaryKey"`
-	OrderID   int64          `gorm:"column:order_id;not null;references:orders.id;onDelete:CASCADE"`
-	ProductID int64          `gorm:"column:product_id;not null"` 
-	Quantity  int64          `gorm:"column:quantity;not null;default:1"`
-	CreatedAt time.Time      `gorm:"column:created_at;default:CURRENT_TIMESTAMP"`
-	UpdatedAt *time.Time     `gorm:"column:updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index"`
-	Order     Order          `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
-}
\ No newline at end of file
+	ID              int64          `gorm:"primaryKey"`
+	OrderID         int64          `gorm:"column:order_id;not null;references:orders.id;onDelete:CASCADE"`
+	ProductID       int64          `gorm:"column:product_id;not null"`
+	Quantity        int64          `gorm:"column:quantity;not null;default:1"`
+	Price           float64        `gorm:"column:price;not null;default:0"`
+	SellBy          string         `gorm:"column:sell_by;not null;default:'item';size:20"`
+	PriceUnit       string         `gorm:"column:price_unit;size:20"`
+	QuantityUnit    string         `gorm:"column:quantity_unit;size:20"`
+	WeightUnit      string         `gorm:"column:weight_unit;size:20"`
+	EstimatedWeight int64          `gorm:"column:estimated_weight;not null;default:0"`
+	ActualWeight    *int64         `gorm:"column:actual_weight"`
+	LineTotal       float64        `gorm:"column:line_total;not null;default:0"`
+	CreatedAt       time.Time      `gorm:"column:created_at;default:CURRENT_TIMESTAMP"`
+	UpdatedAt       *time.Time     `gorm:"column:updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"column:deleted_at;index"`
+	Order           Order          `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+}
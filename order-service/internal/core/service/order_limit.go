@@ -0,0 +1,29 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"order-service/internal/core/domain/entity"
+	"strings"
+)
+
+var ErrPurchaseLimitExceeded = errors.New("purchase limit exceeded")
+
+// PurchaseLimitError lists the purchase limits an order goes over and what
+// is left of each. It unwraps to ErrPurchaseLimitExceeded.
+type PurchaseLimitError struct {
+	Violations []entity.PurchaseLimitViolationEntity
+}
+
+func (e *PurchaseLimitError) Error() string {
+	messages := []string{}
+	for _, val := range e.Violations {
+		messages = append(messages, fmt.Sprintf("%s allows %d per %d hours, %d left", val.Name, val.MaxQuantity, val.WindowHours, val.Remaining))
+	}
+
+	return fmt.Sprintf("%v: %s", ErrPurchaseLimitExceeded, strings.Join(messages, "; "))
+}
+
+func (e *PurchaseLimitError) Unwrap() error {
+	return ErrPurchaseLimitExceeded
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"order-service/internal/core/domain/entity"
+)
+
+// Ways of selling, as product-service defines them.
+const (
+	sellByItem      = "item"
+	sellByEstimated = "estimated"
+)
+
+var (
+	ErrInvalidOrderQuantity = errors.New("invalid quantity")
+	ErrNotEstimated         = errors.New("only items priced by weight at packing can be weighed")
+	ErrOrderPaid            = errors.New("items can only be weighed before the order is paid")
+	ErrAlreadyWeighed       = errors.New("the item is already weighed")
+)
+
+// priceOrderItems fills the price and selling rules of every item from the
+// current product data and checks the quantities, so the order total never
+// depends on what the client sent. The line totals come from product-service
+// with applyLineTotals.
+func priceOrderItems(items []entity.OrderItemEntity, products map[int64]entity.ProductResponseEntity) error {
+	for i := range items {
+		item := &items[i]
+		product, ok := products[item.ProductID]
+		if !ok {
+			return fmt.Errorf("%w: product %d not found", ErrInvalidOrderQuantity, item.ProductID)
+		}
+
+		selling := product.Selling
+		if selling.SellBy == "" {
+			selling.SellBy = sellByItem
+		}
+		step := max(selling.QuantityStep, 1)
+
+		if item.Quantity <= 0 || item.Quantity%step != 0 {
+			return fmt.Errorf("%w: %s is sold in steps of %d", ErrInvalidOrderQuantity, product.ProductName, step)
+		}
+		if item.Quantity < selling.MinQuantity {
+			return fmt.Errorf("%w: order at least %d of %s", ErrInvalidOrderQuantity, selling.MinQuantity, product.ProductName)
+		}
+		if selling.MaxQuantity > 0 && item.Quantity > selling.MaxQuantity {
+			return fmt.Errorf("%w: order at most %d of %s", ErrInvalidOrderQuantity, selling.MaxQuantity, product.ProductName)
+		}
+
+		price := product.SalePrice
+		if price <= 0 {
+			price = product.RegulerPrice
+		}
+
+		item.Price = int64(price)
+		item.SellBy = selling.SellBy
+		item.PriceUnit = selling.PriceUnit
+		item.QuantityUnit = selling.QuantityUnit
+		item.WeightUnit = product.Unit
+		if selling.SellBy == sellByEstimated {
+			item.EstimatedWeight = int64(product.Weight) * item.Quantity
+		}
+	}
+
+	return nil
+}
+
+// applyLineTotals takes the price of every line as product-service computed
+// it, by quantity, by the measured amount or by the estimated weight, so the
+// units are converted in one place only. The lines come back in the order
+// they were sent.
+func applyLineTotals(items []entity.OrderItemEntity, lines []entity.CartPriceLineResponseEntity) error {
+	if len(lines) != len(items) {
+		return fmt.Errorf("priced %d of %d items", len(lines), len(items))
+	}
+
+	for i := range items {
+		if lines[i].ProductID != items[i].ProductID {
+			return fmt.Errorf("priced product %d instead of %d", lines[i].ProductID, items[i].ProductID)
+		}
+		items[i].LineTotal = lines[i].Subtotal
+	}
+
+	return nil
+}
+
+// weighedLineTotal is the price of an estimated item once weighed: the
+// estimated price scaled to the actual weight, both in the unit of the
+// product.
+func weighedLineTotal(item entity.OrderItemEntity, actualWeight int64) int64 {
+	if item.EstimatedWeight <= 0 {
+		return item.LineTotal
+	}
+
+	return int64(math.Round(float64(item.LineTotal) * float64(actualWeight) / float64(item.EstimatedWeight)))
+}
+
+// orderTotal adds up the lines and the shipping fee, less the promotions and
+// the coupon. The discounts are the amounts fixed at checkout, also after
+// weighing, and never make the total negative.
+func orderTotal(order entity.OrderEntity) int64 {
+	total := order.ShippingFee - order.PromotionDiscount - order.Discount
+	for _, item := range order.OrderItems {
+		total += item.LineTotal
+	}
+
+	return max(total, 0)
+}
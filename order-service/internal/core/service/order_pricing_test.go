@@ -0,0 +1,162 @@
+package service
+
+import (
+	"errors"
+	"order-service/internal/core/domain/entity"
+	"testing"
+)
+
+func TestPriceOrderItems(t *testing.T) {
+	products := map[int64]entity.ProductResponseEntity{
+		1: {ProductName: "Apel", SalePrice: 12000, RegulerPrice: 15000, Unit: "gram", Weight: 200},
+		2: {
+			ProductName:  "Beras",
+			RegulerPrice: 14000,
+			Selling:      entity.ProductSellingEntity{SellBy: "measure", PriceUnit: "kg", QuantityUnit: "gram", QuantityStep: 250, MinQuantity: 500, MaxQuantity: 5000},
+		},
+		3: {
+			ProductName: "Ikan",
+			SalePrice:   60000,
+			Unit:        "gram",
+			Weight:      750,
+			Selling:     entity.ProductSellingEntity{SellBy: sellByEstimated, PriceUnit: "kg"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		item    entity.OrderItemEntity
+		want    entity.OrderItemEntity
+		wantErr error
+	}{
+		{
+			name: "by item at the sale price",
+			item: entity.OrderItemEntity{ProductID: 1, Quantity: 3, Price: 1},
+			want: entity.OrderItemEntity{ProductID: 1, Quantity: 3, Price: 12000, SellBy: sellByItem, WeightUnit: "gram"},
+		},
+		{
+			name: "by measure at the regular price",
+			item: entity.OrderItemEntity{ProductID: 2, Quantity: 750},
+			want: entity.OrderItemEntity{ProductID: 2, Quantity: 750, Price: 14000, SellBy: "measure", PriceUnit: "kg", QuantityUnit: "gram"},
+		},
+		{
+			name: "estimated weight",
+			item: entity.OrderItemEntity{ProductID: 3, Quantity: 2},
+			want: entity.OrderItemEntity{ProductID: 3, Quantity: 2, Price: 60000, SellBy: sellByEstimated, PriceUnit: "kg", WeightUnit: "gram", EstimatedWeight: 1500},
+		},
+		{name: "unknown product", item: entity.OrderItemEntity{ProductID: 9, Quantity: 1}, wantErr: ErrInvalidOrderQuantity},
+		{name: "no quantity", item: entity.OrderItemEntity{ProductID: 1}, wantErr: ErrInvalidOrderQuantity},
+		{name: "off step", item: entity.OrderItemEntity{ProductID: 2, Quantity: 600}, wantErr: ErrInvalidOrderQuantity},
+		{name: "below the minimum", item: entity.OrderItemEntity{ProductID: 2, Quantity: 250}, wantErr: ErrInvalidOrderQuantity},
+		{name: "above the maximum", item: entity.OrderItemEntity{ProductID: 2, Quantity: 5250}, wantErr: ErrInvalidOrderQuantity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := []entity.OrderItemEntity{tt.item}
+			err := priceOrderItems(items, products)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("priceOrderItems() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && items[0] != tt.want {
+				t.Errorf("priceOrderItems() = %+v, want %+v", items[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyLineTotals(t *testing.T) {
+	items := func() []entity.OrderItemEntity {
+		return []entity.OrderItemEntity{{ProductID: 1}, {ProductID: 2}}
+	}
+
+	tests := []struct {
+		name    string
+		lines   []entity.CartPriceLineResponseEntity
+		want    []int64
+		wantErr bool
+	}{
+		{
+			name:  "in order",
+			lines: []entity.CartPriceLineResponseEntity{{ProductID: 1, Subtotal: 36000}, {ProductID: 2, Subtotal: 10500}},
+			want:  []int64{36000, 10500},
+		},
+		{
+			name:    "a line missing",
+			lines:   []entity.CartPriceLineResponseEntity{{ProductID: 1, Subtotal: 36000}},
+			wantErr: true,
+		},
+		{
+			name:    "out of order",
+			lines:   []entity.CartPriceLineResponseEntity{{ProductID: 2, Subtotal: 10500}, {ProductID: 1, Subtotal: 36000}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := items()
+			err := applyLineTotals(items, tt.lines)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyLineTotals() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for i, want := range tt.want {
+				if items[i].LineTotal != want {
+					t.Errorf("items[%d].LineTotal = %d, want %d", i, items[i].LineTotal, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWeighedLineTotal(t *testing.T) {
+	tests := []struct {
+		name   string
+		item   entity.OrderItemEntity
+		actual int64
+		want   int64
+	}{
+		{name: "as estimated", item: entity.OrderItemEntity{EstimatedWeight: 1500, LineTotal: 90000}, actual: 1500, want: 90000},
+		{name: "heavier", item: entity.OrderItemEntity{EstimatedWeight: 1500, LineTotal: 90000}, actual: 1620, want: 97200},
+		{name: "lighter, rounded", item: entity.OrderItemEntity{EstimatedWeight: 3, LineTotal: 1000}, actual: 2, want: 667},
+		{name: "no estimate keeps the total", item: entity.OrderItemEntity{LineTotal: 5000}, actual: 800, want: 5000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := weighedLineTotal(tt.item, tt.actual); got != tt.want {
+				t.Errorf("weighedLineTotal() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderTotal(t *testing.T) {
+	lines := []entity.OrderItemEntity{{LineTotal: 36000}, {LineTotal: 10500}}
+
+	tests := []struct {
+		name  string
+		order entity.OrderEntity
+		want  int64
+	}{
+		{name: "lines and shipping", order: entity.OrderEntity{OrderItems: lines, ShippingFee: 10000}, want: 56500},
+		{
+			name:  "less promotions and coupon",
+			order: entity.OrderEntity{OrderItems: lines, ShippingFee: 10000, PromotionDiscount: 6500, Discount: 5000},
+			want:  45000,
+		},
+		{
+			name:  "never negative",
+			order: entity.OrderEntity{OrderItems: []entity.OrderItemEntity{{LineTotal: 1000}}, PromotionDiscount: 800, Discount: 500},
+			want:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := orderTotal(tt.order); got != tt.want {
+				t.Errorf("orderTotal() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
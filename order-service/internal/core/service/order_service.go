@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"order-service/config"
 	"order-service/internal/adapter/client"
@@ -25,6 +26,7 @@ type IOrderService interface {
 
 	GetOrderByOrderCode(ctx context.Context, orderCode, accessToken string) (*entity.OrderEntity, error)
 	UpdateStatus(ctx context.Context, req entity.OrderEntity, accessToken string) error
+	UpdateItemWeight(ctx context.Context, orderID, itemID, actualWeight int64, accessToken string) (*entity.OrderEntity, error)
 	DeleteByID(ctx context.Context, orderID int64) error
 	GetPublicOrderIDByOrderCode(ctx context.Context, orderCode string) (int64, error)
 }
@@ -49,20 +51,29 @@ func (o *orderService) GetPublicOrderIDByOrderCode(ctx context.Context, orderCod
 	return result.ID, nil
 }
 
-// DeleteByID implements [IOrderService].
+// DeleteByID implements [IOrderService]. The coupon and the purchase limits
+// the order held are given back.
 func (o *orderService) DeleteByID(ctx context.Context, orderID int64) error {
-	err := o.repo.DeleteOrder(ctx, orderID)
+	order, err := o.repo.GetByID(ctx, orderID)
 	if err != nil {
 		log.Errorf("[OrderService-1] DeleteByID: %v", err)
 		return err
 	}
 
-	err = o.publisherRabbitMQ.PublishDeleteOrderFromQueue(orderID)
+	err = o.repo.DeleteOrder(ctx, orderID)
 	if err != nil {
 		log.Errorf("[OrderService-2] DeleteByID: %v", err)
 		return err
 	}
 
+	o.releaseOrder(*order)
+
+	err = o.publisherRabbitMQ.PublishDeleteOrderFromQueue(orderID)
+	if err != nil {
+		log.Errorf("[OrderService-3] DeleteByID: %v", err)
+		return err
+	}
+
 	return nil
 }
 
@@ -74,16 +85,27 @@ func (o *orderService) UpdateStatus(ctx context.Context, req entity.OrderEntity,
 		return err
 	}
 
+	// a cancelled order no longer holds its coupon nor counts against the
+	// purchase limits
+	if statusOrder == "Cancelled" {
+		order, err := o.repo.GetByID(ctx, req.ID)
+		if err != nil {
+			log.Errorf("[OrderService-2] UpdateStatus: %v", err)
+		} else {
+			o.releaseOrder(*order)
+		}
+	}
+
 	var token map[string]interface{}
 	err = json.Unmarshal([]byte(accessToken), &token)
 	if err != nil {
-		log.Errorf("[OrderService-2] UpdateStatus: %v", err)
+		log.Errorf("[OrderService-3] UpdateStatus: %v", err)
 		return err
 	}
 
 	userResponse, err := o.userClient.GetUser(buyerID, token["token"].(string), false)
 	if err != nil {
-		log.Errorf("[OrderService-3] UpdateStatus: %v", err)
+		log.Errorf("[OrderService-4] UpdateStatus: %v", err)
 		return err
 	}
 	message := fmt.Sprintf("Hello,\n\nYour order with ID %s has been updated to status: %s.\n\nThank you for shopping with us!", orderCode, statusOrder)
@@ -94,6 +116,82 @@ func (o *orderService) UpdateStatus(ctx context.Context, req entity.OrderEntity,
 	return nil
 }
 
+// releaseOrder gives back what the order held in product-service: its
+// coupon and its purchase limit reservation. The order is already cancelled
+// or deleted, so failures are only logged.
+func (o *orderService) releaseOrder(order entity.OrderEntity) {
+	if order.CouponCode != "" {
+		if err := o.productClient.ReleaseCoupon(order.OrderCode, order.BuyerId); err != nil {
+			log.Errorf("[OrderService-1] releaseOrder: %v", err)
+		}
+	}
+
+	if err := o.productClient.ReleasePurchaseLimits(order.OrderCode); err != nil {
+		log.Errorf("[OrderService-2] releaseOrder: %v", err)
+	}
+}
+
+// UpdateItemWeight implements [IOrderService]. It records the weight of an
+// estimated item measured at packing, in the unit of the product, and prices
+// the line and the order again. Every item is weighed once, while the order
+// is pending; payment-service only takes the payment once all are weighed,
+// so the amount paid is the final one.
+//
+// The promotion and coupon discounts stay the amounts granted at checkout on
+// purpose: the coupon redemption in product-service records that amount, and
+// the promotions may have ended by the time the order is packed, so pricing
+// them again could take back a discount the customer was shown. Only the
+// weighed lines, and so the total, change.
+func (o *orderService) UpdateItemWeight(ctx context.Context, orderID, itemID, actualWeight int64, accessToken string) (*entity.OrderEntity, error) {
+	order, err := o.repo.GetByID(ctx, orderID)
+	if err != nil {
+		log.Errorf("[OrderService-1] UpdateItemWeight: %v", err)
+		return nil, err
+	}
+
+	if order.Status != "Pending" {
+		return nil, ErrOrderPaid
+	}
+
+	var item *entity.OrderItemEntity
+	for i := range order.OrderItems {
+		if order.OrderItems[i].ID == itemID {
+			item = &order.OrderItems[i]
+			break
+		}
+	}
+	if item == nil {
+		return nil, errors.New("404")
+	}
+	if item.SellBy != sellByEstimated {
+		return nil, ErrNotEstimated
+	}
+	if item.ActualWeight != nil {
+		return nil, ErrAlreadyWeighed
+	}
+
+	item.LineTotal = weighedLineTotal(*item, actualWeight)
+	item.ActualWeight = &actualWeight
+	order.TotalAmount = orderTotal(*order)
+
+	if err := o.repo.UpdateItemWeight(ctx, *item, order.TotalAmount); err != nil {
+		log.Errorf("[OrderService-2] UpdateItemWeight: %v", err)
+		return nil, err
+	}
+
+	result, err := o.GetByID(ctx, orderID, accessToken)
+	if err != nil {
+		log.Errorf("[OrderService-3] UpdateItemWeight: %v", err)
+		return nil, err
+	}
+
+	if err := o.publisherRabbitMQ.PublishOrderToQueue(*result); err != nil {
+		log.Errorf("[OrderService-4] UpdateItemWeight: %v", err)
+	}
+
+	return result, nil
+}
+
 // GetOrderByOrderCode implements [IOrderService].
 func (o *orderService) GetOrderByOrderCode(ctx context.Context, orderCode string, accessToken string) (*entity.OrderEntity, error) {
 	result, err := o.repo.GetOrderByOrderCode(ctx, orderCode)
@@ -144,7 +242,10 @@ func (o *orderService) GetOrderByOrderCode(ctx context.Context, orderCode string
 		if product, ok := productsMap[result.OrderItems[i].ProductID]; ok {
 			result.OrderItems[i].ProductImage = product.ProductImage
 			result.OrderItems[i].ProductName = product.ProductName
-			result.OrderItems[i].Price = int64(product.SalePrice)
+			// orders placed before prices were stored show today's price
+			if result.OrderItems[i].Price == 0 {
+				result.OrderItems[i].Price = int64(product.SalePrice)
+			}
 		}
 	}
 
@@ -237,7 +338,9 @@ func (o *orderService) GetAllCustomer(ctx context.Context, queryString entity.Qu
 			if product, ok := productsMap[results[i].OrderItems[j].ProductID]; ok {
 				results[i].OrderItems[j].ProductImage = product.ProductImage
 				results[i].OrderItems[j].ProductName = product.ProductName
-				results[i].OrderItems[j].Price = int64(product.SalePrice)
+				if results[i].OrderItems[j].Price == 0 {
+					results[i].OrderItems[j].Price = int64(product.SalePrice)
+				}
 				results[i].OrderItems[j].ProductUnit = product.Unit
 				results[i].OrderItems[j].ProductWeight = int64(product.Weight)
 			}
@@ -295,7 +398,9 @@ func (o *orderService) GetDetailCustomer(ctx context.Context, orderID int64, acc
 				result.OrderItems[i].ProductImage = product.Child[0].Image
 			}
 			result.OrderItems[i].ProductName = product.ProductName
-			result.OrderItems[i].Price = int64(product.SalePrice)
+			if result.OrderItems[i].Price == 0 {
+				result.OrderItems[i].Price = int64(product.SalePrice)
+			}
 			result.OrderItems[i].ProductWeight = int64(product.Weight)
 			result.OrderItems[i].ProductUnit = product.Unit
 		}
@@ -314,20 +419,101 @@ func (o *orderService) CreateOrder(ctx context.Context, req entity.OrderEntity,
 	req.ShippingFee = int64(shippingFee)
 	req.Status = "Pending"
 
+	var token map[string]interface{}
+	if err := json.Unmarshal([]byte(accessToken), &token); err != nil {
+		log.Errorf("[OrderService-1] CreateOrder: %v", err)
+		return 0, err
+	}
+
+	productIDList := make([]int64, 0, len(req.OrderItems))
+	for _, item := range req.OrderItems {
+		productIDList = append(productIDList, item.ProductID)
+	}
+
+	productsMap, err := o.productClient.GetProductsBulk(productIDList, token["token"].(string), true)
+	if err != nil {
+		log.Errorf("[OrderService-7] CreateOrder: %v", err)
+		return 0, err
+	}
+
+	// estimated items are corrected once weighed at packing
+	if err := priceOrderItems(req.OrderItems, productsMap); err != nil {
+		log.Errorf("[OrderService-8] CreateOrder: %v", err)
+		return 0, err
+	}
+
+	// promotions are evaluated by product-service for the customer of the
+	// token, the same way the cart shows them
+	priced, err := o.productClient.PriceCart(req.OrderItems, req.ShippingFee, token["token"].(string))
+	if err != nil {
+		log.Errorf("[OrderService-11] CreateOrder: %v", err)
+		return 0, err
+	}
+	if err := applyLineTotals(req.OrderItems, priced.Items); err != nil {
+		log.Errorf("[OrderService-12] CreateOrder: %v", err)
+		return 0, err
+	}
+	req.PromotionDiscount = priced.PromotionDiscount()
+	req.TotalAmount = orderTotal(req)
+
+	// the purchase limits and the coupon belong to the customer signed in,
+	// whoever the order is for
+	buyerID := int64(token["user_id"].(float64))
+
+	// the items are counted against the purchase limits before the order
+	// exists, so orders placed at the same time cannot both take what is
+	// left of a limit; the cart only checks them when items are added
+	violations, err := o.productClient.ReservePurchaseLimits(req.OrderCode, buyerID, req.OrderItems)
+	if err != nil {
+		log.Errorf("[OrderService-9] CreateOrder: %v", err)
+		return 0, err
+	}
+	if len(violations) > 0 {
+		err = &PurchaseLimitError{Violations: violations}
+		log.Errorf("[OrderService-10] CreateOrder: %v", err)
+		return 0, err
+	}
+
+	if req.CouponCode != "" {
+		// the coupon is redeemed against the order code before the order
+		// exists, so product-service can refuse it once the limit is reached;
+		// it works on the subtotal the promotions were priced with above
+		redemption, err := o.productClient.RedeemCoupon(req.CouponCode, req.OrderCode, buyerID, priced.CouponSubtotal())
+		if err != nil {
+			log.Errorf("[OrderService-2] CreateOrder: %v", err)
+			if errRelease := o.productClient.ReleasePurchaseLimits(req.OrderCode); errRelease != nil {
+				log.Errorf("[OrderService-13] CreateOrder: %v", errRelease)
+			}
+			return 0, err
+		}
+
+		req.CouponCode = redemption.Code
+		req.Discount = redemption.Discount
+		req.TotalAmount = orderTotal(req)
+	}
+
 	orderID, err := o.repo.CreateOrder(ctx, req)
 	if err != nil {
-		log.Errorf("[OrderService-1] CreateOrder: %v", err)
+		log.Errorf("[OrderService-3] CreateOrder: %v", err)
+		if req.CouponCode != "" {
+			if errRelease := o.productClient.ReleaseCoupon(req.OrderCode, buyerID); errRelease != nil {
+				log.Errorf("[OrderService-4] CreateOrder: %v", errRelease)
+			}
+		}
+		if errRelease := o.productClient.ReleasePurchaseLimits(req.OrderCode); errRelease != nil {
+			log.Errorf("[OrderService-14] CreateOrder: %v", errRelease)
+		}
 		return 0, err
 	}
 
 	resultData, err := o.GetByID(ctx, orderID, accessToken)
 	if err != nil {
-		log.Errorf("[OrderService-2] CreateOrder: %v", err)
+		log.Errorf("[OrderService-5] CreateOrder: %v", err)
 		return 0, err
 	}
 
 	if err := o.publisherRabbitMQ.PublishOrderToQueue(*resultData); err != nil {
-		log.Errorf("[OrderService-3] CreateOrder: %v", err)
+		log.Errorf("[OrderService-6] CreateOrder: %v", err)
 	}
 
 	for _, orderItem := range req.OrderItems {
@@ -471,7 +657,9 @@ func (o *orderService) GetByID(ctx context.Context, orderID int64, accessToken s
 		if product, ok := productsMap[result.OrderItems[i].ProductID]; ok {
 			result.OrderItems[i].ProductImage = product.ProductImage
 			result.OrderItems[i].ProductName = product.ProductName
-			result.OrderItems[i].Price = int64(product.SalePrice)
+			if result.OrderItems[i].Price == 0 {
+				result.OrderItems[i].Price = int64(product.SalePrice)
+			}
 		}
 	}
 
@@ -0,0 +1,124 @@
+// Package esquery builds Elasticsearch search bodies from typed values instead
+// of formatted strings. Bodies are encoded with encoding/json, so user input
+// always ends up as a properly escaped JSON string and cannot change the shape
+// of the query. It holds only what the order search needs.
+package esquery
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// maxTextLength caps free text sent to Elasticsearch; longer input is cut.
+const maxTextLength = 200
+
+// Query is a single query clause such as {"term": {...}}.
+type Query map[string]interface{}
+
+func MatchAll() Query {
+	return Query{"match_all": map[string]interface{}{}}
+}
+
+func Match(field, text string) Query {
+	return Query{"match": map[string]interface{}{field: text}}
+}
+
+func MultiMatch(text string, fields ...string) Query {
+	return Query{"multi_match": map[string]interface{}{
+		"query":  text,
+		"fields": fields,
+	}}
+}
+
+func Term(field string, value interface{}) Query {
+	return Query{"term": map[string]interface{}{field: value}}
+}
+
+// Bool combines clauses; Must is scored, Filter is not.
+type Bool struct {
+	Must   []Query
+	Filter []Query
+}
+
+// Query returns the bool clause, or match_all when no clause was added.
+func (b Bool) Query() Query {
+	clauses := map[string]interface{}{}
+	if len(b.Must) > 0 {
+		clauses["must"] = b.Must
+	}
+	if len(b.Filter) > 0 {
+		clauses["filter"] = b.Filter
+	}
+
+	if len(clauses) == 0 {
+		return MatchAll()
+	}
+	return Query{"bool": clauses}
+}
+
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Search is a complete search request body.
+type Search struct {
+	From  int
+	Size  int
+	Query Query
+	Sort  []Sort
+}
+
+// Reader encodes the search as a JSON body for the Search API.
+func (s Search) Reader() (io.Reader, error) {
+	body := map[string]interface{}{
+		"from": max(s.From, 0),
+		"size": max(s.Size, 0),
+	}
+
+	if s.Query != nil {
+		body["query"] = s.Query
+	} else {
+		body["query"] = MatchAll()
+	}
+
+	if len(s.Sort) > 0 {
+		sorts := []map[string]interface{}{}
+		for _, sort := range s.Sort {
+			order := "asc"
+			if sort.Desc {
+				order = "desc"
+			}
+			sorts = append(sorts, map[string]interface{}{
+				sort.Field: map[string]string{"order": order},
+			})
+		}
+		body["sort"] = sorts
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+// CleanText drops control characters, collapses whitespace and caps the
+// length of free text typed by users.
+func CleanText(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, text)
+	text = strings.Join(strings.Fields(text), " ")
+
+	if runes := []rune(text); len(runes) > maxTextLength {
+		text = string(runes[:maxTextLength])
+	}
+	return text
+}
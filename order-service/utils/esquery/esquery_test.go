@@ -0,0 +1,133 @@
+package esquery
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestQueries(t *testing.T) {
+	tests := []struct {
+		name  string
+		query Query
+		want  string
+	}{
+		{
+			name:  "match all",
+			query: MatchAll(),
+			want:  `{"match_all":{}}`,
+		},
+		{
+			name:  "match",
+			query: Match("order_code", "ORD-1"),
+			want:  `{"match":{"order_code":"ORD-1"}}`,
+		},
+		{
+			name:  "multi match",
+			query: MultiMatch("budi", "buyer_name", "order_code"),
+			want:  `{"multi_match":{"fields":["buyer_name","order_code"],"query":"budi"}}`,
+		},
+		{
+			name:  "term",
+			query: Term("buyer_id", 7),
+			want:  `{"term":{"buyer_id":7}}`,
+		},
+		{
+			name:  "empty bool",
+			query: Bool{}.Query(),
+			want:  `{"match_all":{}}`,
+		},
+		{
+			name: "bool",
+			query: Bool{
+				Must:   []Query{Match("order_code", "ORD-1")},
+				Filter: []Query{Term("buyer_id", 7)},
+			}.Query(),
+			want: `{"bool":{"filter":[{"term":{"buyer_id":7}}],"must":[{"match":{"order_code":"ORD-1"}}]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.query)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("query = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchReader(t *testing.T) {
+	tests := []struct {
+		name   string
+		search Search
+		want   string
+	}{
+		{
+			name:   "defaults to match all",
+			search: Search{Size: 10},
+			want:   `{"from":0,"query":{"match_all":{}},"size":10}`,
+		},
+		{
+			name:   "negative paging is clamped",
+			search: Search{From: -5, Size: -1},
+			want:   `{"from":0,"query":{"match_all":{}},"size":0}`,
+		},
+		{
+			name: "sorted",
+			search: Search{
+				From:  10,
+				Size:  10,
+				Query: Term("buyer_id", 7),
+				Sort:  []Sort{{Field: "created_at", Desc: true}, {Field: "id"}},
+			},
+			want: `{"from":10,"query":{"term":{"buyer_id":7}},"size":10,"sort":[{"created_at":{"order":"desc"}},{"id":{"order":"asc"}}]}`,
+		},
+		{
+			name:   "user input stays a string",
+			search: Search{Query: Match("order_code", `"}},"size":10000,"x":{"`)},
+			want:   `{"from":0,"query":{"match":{"order_code":"\"}},\"size\":10000,\"x\":{\""}},"size":0}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := tt.search.Reader()
+			if err != nil {
+				t.Fatalf("Reader() error = %v", err)
+			}
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("body = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestCleanText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "untouched", text: "ORD-1", want: "ORD-1"},
+		{name: "whitespace collapsed", text: "  budi \t\n santoso  ", want: "budi santoso"},
+		{name: "control characters dropped", text: "budi\x00santoso\x1b", want: "budi santoso"},
+		{name: "capped in runes", text: strings.Repeat("é", maxTextLength+10), want: strings.Repeat("é", maxTextLength)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CleanText(tt.text); got != tt.want {
+				t.Errorf("CleanText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"payment-service/config"
 	"payment-service/internal/adapter"
@@ -304,6 +305,9 @@ func (p *paymentHandler) Create(c echo.Context) error {
 	result, err := p.PaymentService.ProcessPayment(ctx, paymentEntity, user)
 	if err != nil {
 		log.Errorf("[PaymentHandler-4] Create: %v", err)
+		if errors.Is(err, service.ErrAwaitingWeighing) {
+			return c.JSON(http.StatusUnprocessableEntity, response.ResponseDefault(err.Error(), nil))
+		}
 		return c.JSON(http.StatusInternalServerError, response.ResponseDefault(err.Error(), nil))
 	}
 
@@ -13,6 +13,8 @@ type OrderDetail struct {
 	ProductImage string `json:"product_image"`
 	ProductPrice int64  `json:"product_price"`
 	Quantity     int64  `json:"quantity"`
+	SellBy       string `json:"sell_by"`
+	ActualWeight *int64 `json:"actual_weight"`
 }
 
 type OrderDetailHttpResponse struct {
@@ -30,6 +32,17 @@ type OrderDetailHttpResponse struct {
 	OrderDetail   []OrderDetail `json:"order_detail"`
 }
 
+// AwaitingWeighing tells whether an item priced by weight at packing is not
+// weighed yet, so the total of the order is still an estimate.
+func (o OrderDetailHttpResponse) AwaitingWeighing() bool {
+	for _, item := range o.OrderDetail {
+		if item.SellBy == "estimated" && item.ActualWeight == nil {
+			return true
+		}
+	}
+	return false
+}
+
 type OrderHttpClientResponse struct {
 	Message string                  `json:"message"`
 	Data    OrderDetailHttpResponse `json:"data"`
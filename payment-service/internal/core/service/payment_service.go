@@ -13,6 +13,10 @@ import (
 	"github.com/labstack/gommon/log"
 )
 
+// ErrAwaitingWeighing is returned for orders whose total changes once their
+// items priced by weight are weighed at packing.
+var ErrAwaitingWeighing = errors.New("the order is waiting to be weighed")
+
 type IPaymentService interface {
 	ProcessPayment(ctx context.Context, payment entity.PaymentEntity, accessToken string) (*entity.PaymentEntity, error)
 	UpdateStatusByOrderCode(ctx context.Context, orderCode, status string) error
@@ -131,40 +135,46 @@ func (p *paymentService) ProcessPayment(ctx context.Context, payment entity.Paym
 		return nil, errors.New("Payment already exists")
 	}
 
+	var token map[string]interface{}
+	if err := json.Unmarshal([]byte(accessToken), &token); err != nil {
+		log.Errorf("[PaymentService] ProcessPayment-2: %v", err)
+		return nil, err
+	}
+
+	orderDetail, err := p.serviceCall.OrderService(int64(payment.OrderID), token["token"].(string))
+	if err != nil {
+		log.Errorf("[PaymentService] ProcessPayment-3: %v", err)
+		return nil, err
+	}
+
+	// items sold by weight are paid for once weighed, and the order is paid
+	// in full, whatever amount the client sent
+	if orderDetail.AwaitingWeighing() {
+		return nil, ErrAwaitingWeighing
+	}
+	payment.GrossAmount = float64(orderDetail.TotalAmount)
+
 	if payment.PaymentMethod == "cod" {
 		payment.PaymentStatus = "Success"
 		if err := p.paymentRepo.CreatePayment(ctx, payment); err != nil {
-			log.Errorf("[PaymentService] ProcessPayment-2: %v", err)
+			log.Errorf("[PaymentService] ProcessPayment-4: %v", err)
 			return nil, err
 		}
 
 		if err := p.publisherRabbitMQ.PublishPaymentSuccess(payment); err != nil {
-			log.Errorf("[PaymentService] ProcessPayment-3: %v", err)
+			log.Errorf("[PaymentService] ProcessPayment-5: %v", err)
 		}
 
 		return &payment, nil
 	}
 
 	if payment.PaymentMethod == "midtrans" {
-		var token map[string]interface{}
-		err := json.Unmarshal([]byte(accessToken), &token)
-		if err != nil {
-			log.Errorf("[PaymentService] ProcessPayment-4: %v", err)
-			return nil, err
-		}
-
 		isAdmin := false
 		if token["role_name"].(string) == "Super Admin" {
 			isAdmin = true
 		}
 
 		userResponse, err := p.serviceCall.UserService(token["token"].(string), int64(payment.UserID), isAdmin)
-		if err != nil {
-			log.Errorf("[PaymentService] ProcessPayment-5: %v", err)
-			return nil, err
-		}
-
-		orderDetail, err := p.serviceCall.OrderService(int64(payment.OrderID), token["token"].(string))
 		if err != nil {
 			log.Errorf("[PaymentService] ProcessPayment-6: %v", err)
 			return nil, err
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+	"product-service/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+var reindexVerify bool
+
+var reindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Membangun ulang index produk di Elasticsearch dari database tanpa downtime",
+	Long: "Membuat index produk versi baru dengan mapping eksplisit, mengisi semua produk dari Postgres " +
+		"dengan bulk API, lalu memindahkan alias products secara atomik dan menghapus versi lama. " +
+		"Dengan --verify hanya membandingkan jumlah dokumen dan checksum index dengan database.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if reindexVerify {
+			fmt.Println("Memverifikasi index produk...")
+		} else {
+			fmt.Println("Reindex produk sedang berjalan...")
+		}
+		app.RunReindex(reindexVerify)
+	},
+}
+
+func init() {
+	reindexCmd.Flags().BoolVar(&reindexVerify, "verify", false, "hanya bandingkan index dengan database")
+	rootCmd.AddCommand(reindexCmd)
+}
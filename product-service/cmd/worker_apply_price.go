@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"fmt"
+	"product-service/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+var workerApplyPriceCmd = &cobra.Command{
+	Use:   "worker:apply-price",
+	Short: "Menjalankan worker untuk menerapkan jadwal harga dan publish ulang produk ke Elasticsearch",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Worker untuk jadwal harga sedang berjalan...")
+		app.RunPriceScheduler()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(workerApplyPriceCmd)
+}
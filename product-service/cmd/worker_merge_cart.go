@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"fmt"
+	"product-service/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+var workerMergeCartCmd = &cobra.Command{
+	Use:   "worker:merge-cart",
+	Short: "Menjalankan worker untuk menggabungkan keranjang tamu ke keranjang customer setelah login",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Worker untuk merge cart sedang berjalan...")
+		app.RunCartMergeConsumer()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(workerMergeCartCmd)
+}
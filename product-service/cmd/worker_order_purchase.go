@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"fmt"
+	"product-service/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+var workerOrderPurchaseCmd = &cobra.Command{
+	Use:   "worker:order-purchase",
+	Short: "Menjalankan worker untuk menyimpan item pesanan dari order-service sebagai data rekomendasi produk",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Worker untuk order purchase sedang berjalan...")
+		app.RunOrderPurchaseConsumer()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(workerOrderPurchaseCmd)
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"fmt"
+	"product-service/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+var workerProductScheduleCmd = &cobra.Command{
+	Use:   "worker:product-schedule",
+	Short: "Menjalankan worker untuk publish produk terjadwal dan mengarsipkan produk yang sudah berakhir",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Worker untuk jadwal produk sedang berjalan...")
+		app.RunProductScheduler()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(workerProductScheduleCmd)
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"fmt"
+	"product-service/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+var workerPurgeTrashCmd = &cobra.Command{
+	Use:   "worker:purge-trash",
+	Short: "Menjalankan worker untuk menghapus permanen produk dan kategori yang sudah lewat masa simpan di trash",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Worker untuk purge trash sedang berjalan...")
+		app.RunTrashPurge()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(workerPurgeTrashCmd)
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"fmt"
+	"product-service/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+var workerRecommendationCmd = &cobra.Command{
+	Use:   "worker:recommendation",
+	Short: "Menjalankan worker untuk menghitung produk yang sering dibeli bersamaan secara berkala",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Worker untuk rekomendasi produk sedang berjalan...")
+		app.RunRecommendationScheduler()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(workerRecommendationCmd)
+}
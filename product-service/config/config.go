@@ -10,6 +10,27 @@ type App struct {
 	JwtExpire    int    `json:"jwt_expire"`
 	JwtIssuer    string `json:"jwt_issuer"`
 
+	PriceSchedulerInterval   int `json:"price_scheduler_interval"`
+	ProductSchedulerInterval int `json:"product_scheduler_interval"`
+
+	CartIdleTTL int    `json:"cart_idle_ttl"`
+	CartSecret  string `json:"cart_secret"`
+
+	TrashRetentionDays int `json:"trash_retention_days"`
+	TrashPurgeInterval int `json:"trash_purge_interval"`
+
+	RecommendationInterval   int `json:"recommendation_interval"`
+	RecommendationWindowDays int `json:"recommendation_window_days"`
+	RecommendationTopN       int `json:"recommendation_top_n"`
+
+	PurchaseLimitMaxWindowHours int `json:"purchase_limit_max_window_hours"`
+
+	CatalogCacheTTL int `json:"catalog_cache_ttl"`
+
+	GraphQLMaxDepth      int `json:"graphql_max_depth"`
+	GraphQLMaxComplexity int `json:"graphql_max_complexity"`
+
+	InternalApiKey string `json:"internal_api_key"`
 }
 
 type Database struct {
@@ -49,6 +70,8 @@ type PublisherName struct {
 	ProductPublish     string `json:"product_publish"`
 	ProductDelete      string `json:"product_delete"`
 	ProductToOrder     string `json:"product_to_order"`
+	CartMerge          string `json:"cart_merge"`
+	OrderPublish       string `json:"order_publish"`
 }
 
 type Config struct {
@@ -72,7 +95,27 @@ func NewConfig() *Config {
 			JwtExpire:    viper.GetInt("JWT_EXPIRATION"),
 			JwtIssuer:    viper.GetString("JWT_ISSUER"),
 
-			
+			PriceSchedulerInterval:   viper.GetInt("PRICE_SCHEDULER_INTERVAL"),
+			ProductSchedulerInterval: viper.GetInt("PRODUCT_SCHEDULER_INTERVAL"),
+
+			CartIdleTTL: viper.GetInt("CART_IDLE_TTL"),
+			CartSecret:  viper.GetString("CART_SECRET"),
+
+			TrashRetentionDays: viper.GetInt("TRASH_RETENTION_DAYS"),
+			TrashPurgeInterval: viper.GetInt("TRASH_PURGE_INTERVAL"),
+
+			RecommendationInterval:   viper.GetInt("RECOMMENDATION_INTERVAL"),
+			RecommendationWindowDays: viper.GetInt("RECOMMENDATION_WINDOW_DAYS"),
+			RecommendationTopN:       viper.GetInt("RECOMMENDATION_TOP_N"),
+
+			PurchaseLimitMaxWindowHours: viper.GetInt("PURCHASE_LIMIT_MAX_WINDOW_HOURS"),
+
+			CatalogCacheTTL: viper.GetInt("CATALOG_CACHE_TTL"),
+
+			GraphQLMaxDepth:      viper.GetInt("GRAPHQL_MAX_DEPTH"),
+			GraphQLMaxComplexity: viper.GetInt("GRAPHQL_MAX_COMPLEXITY"),
+
+			InternalApiKey: viper.GetString("INTERNAL_API_KEY"),
 		},
 		Database: Database{
 			Host:               viper.GetString("DATABASE_HOST"),
@@ -106,6 +149,8 @@ func NewConfig() *Config {
 			ProductPublish:     viper.GetString("PRODUCT_PUBLISH_NAME"),
 			ProductDelete:      viper.GetString("PRODUCT_DELETE"),
 			ProductToOrder:     viper.GetString("PRODUCT_TO_ORDER"),
+			CartMerge:          viper.GetString("CART_MERGE_NAME"),
+			OrderPublish:       viper.GetString("ORDER_PUBLISH_NAME"),
 		},
 	}
 }
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS product_images (
+    id SERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    image_url VARCHAR(255) NOT NULL,
+    alt_text VARCHAR(255) NULL,
+    position INT DEFAULT 0,
+    is_primary BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL
+);
+
+CREATE INDEX idx_product_images_product_id ON product_images(product_id);
+CREATE UNIQUE INDEX idx_product_images_primary ON product_images(product_id) WHERE is_primary;
+
+INSERT INTO product_images (product_id, image_url, position, is_primary)
+SELECT id, image, 0, TRUE FROM products WHERE image <> '' AND deleted_at IS NULL;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS price_history;
+DROP TABLE IF EXISTS price_lists;
+ALTER TABLE products DROP COLUMN IF EXISTS price_list_id;
+ALTER TABLE products DROP COLUMN IF EXISTS base_sale_price;
@@ -0,0 +1,30 @@
+ALTER TABLE products ADD COLUMN base_sale_price BIGINT DEFAULT 0;
+ALTER TABLE products ADD COLUMN price_list_id BIGINT NULL;
+UPDATE products SET base_sale_price = sale_price;
+
+CREATE TABLE IF NOT EXISTS price_lists (
+    id SERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    sale_price BIGINT NOT NULL,
+    start_at TIMESTAMP NOT NULL,
+    end_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL
+);
+
+CREATE INDEX idx_price_lists_product_id ON price_lists(product_id);
+CREATE INDEX idx_price_lists_window ON price_lists(start_at, end_at);
+
+CREATE TABLE IF NOT EXISTS price_history (
+    id SERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    price_list_id BIGINT NULL,
+    reguler_price BIGINT DEFAULT 0,
+    sale_price BIGINT DEFAULT 0,
+    source VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_price_history_product_id ON price_history(product_id, created_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS promotion_tiers;
+DROP TABLE IF EXISTS promotions;
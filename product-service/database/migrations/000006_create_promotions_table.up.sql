@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS promotions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NULL,
+    type VARCHAR(30) NOT NULL,
+    value BIGINT DEFAULT 0,
+    buy_quantity INT DEFAULT 0,
+    get_quantity INT DEFAULT 0,
+    category_slug VARCHAR(120) NULL,
+    product_id BIGINT NULL,
+    min_quantity INT DEFAULT 0,
+    min_subtotal BIGINT DEFAULT 0,
+    customer_segment VARCHAR(50) NULL,
+    priority INT DEFAULT 0,
+    stackable BOOLEAN DEFAULT FALSE,
+    status BOOLEAN DEFAULT TRUE,
+    start_at TIMESTAMP NULL,
+    end_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL
+);
+
+CREATE INDEX idx_promotions_status ON promotions(status);
+CREATE INDEX idx_promotions_window ON promotions(start_at, end_at);
+
+CREATE TABLE IF NOT EXISTS promotion_tiers (
+    id SERIAL PRIMARY KEY,
+    promotion_id BIGINT NOT NULL REFERENCES promotions(id) ON DELETE CASCADE,
+    min_quantity INT NOT NULL,
+    value BIGINT NOT NULL
+);
+
+CREATE INDEX idx_promotion_tiers_promotion_id ON promotion_tiers(promotion_id);
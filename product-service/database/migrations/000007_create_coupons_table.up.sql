@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS coupons (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(40) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    discount_type VARCHAR(20) NOT NULL,
+    value BIGINT NOT NULL DEFAULT 0,
+    max_discount BIGINT DEFAULT 0,
+    min_spend BIGINT DEFAULT 0,
+    usage_limit INT DEFAULT 0,
+    per_customer_limit INT DEFAULT 0,
+    used_count INT NOT NULL DEFAULT 0,
+    status BOOLEAN DEFAULT TRUE,
+    start_at TIMESTAMP NULL,
+    end_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL
+);
+
+CREATE UNIQUE INDEX idx_coupons_code ON coupons(code) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS coupon_redemptions (
+    id SERIAL PRIMARY KEY,
+    coupon_id BIGINT NOT NULL REFERENCES coupons(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL,
+    order_code VARCHAR(64) NOT NULL,
+    discount BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_coupon_redemptions_order ON coupon_redemptions(coupon_id, order_code);
+CREATE INDEX idx_coupon_redemptions_user ON coupon_redemptions(coupon_id, user_id);
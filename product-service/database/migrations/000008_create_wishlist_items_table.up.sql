@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS wishlist_items (
+    id SERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    product_id BIGINT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    list VARCHAR(20) NOT NULL DEFAULT 'wishlist',
+    quantity INT NOT NULL DEFAULT 1,
+    last_price BIGINT DEFAULT 0,
+    last_in_stock BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL
+);
+
+CREATE UNIQUE INDEX idx_wishlist_items_user_product ON wishlist_items(user_id, product_id, list);
+CREATE INDEX idx_wishlist_items_product ON wishlist_items(product_id);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS search_synonyms (
+    id SERIAL PRIMARY KEY,
+    terms TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL
+);
+
+INSERT INTO search_synonyms (terms) VALUES
+    ('cabai,cabe'),
+    ('tomat,tomato'),
+    ('bawang bombay,bawang bombai,onion'),
+    ('kentang,potato');
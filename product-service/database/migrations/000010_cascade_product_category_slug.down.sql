@@ -0,0 +1,4 @@
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_category_slug_fkey;
+ALTER TABLE products
+    ADD CONSTRAINT products_category_slug_fkey FOREIGN KEY (category_slug)
+    REFERENCES categories(slug) ON DELETE CASCADE;
@@ -0,0 +1,6 @@
+-- Renaming a category slug moves its products along, and a category that
+-- still has products can no longer be hard deleted together with them.
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_category_slug_fkey;
+ALTER TABLE products
+    ADD CONSTRAINT products_category_slug_fkey FOREIGN KEY (category_slug)
+    REFERENCES categories(slug) ON UPDATE CASCADE ON DELETE RESTRICT;
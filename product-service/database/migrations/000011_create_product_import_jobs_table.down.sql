@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS product_import_jobs;
+
+DROP INDEX IF EXISTS idx_products_sku;
+
+ALTER TABLE products DROP COLUMN IF EXISTS sku;
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_products_unpublish_at;
+DROP INDEX IF EXISTS idx_products_publish_at;
+
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_status_check;
+ALTER TABLE products ALTER COLUMN status SET DEFAULT 'DRAFT';
+UPDATE products SET status = 'Unpublished' WHERE status <> 'Published';
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS review_note,
+    DROP COLUMN IF EXISTS approved_at,
+    DROP COLUMN IF EXISTS approved_by,
+    DROP COLUMN IF EXISTS unpublish_at,
+    DROP COLUMN IF EXISTS publish_at;
@@ -0,0 +1,21 @@
+-- Products move through Draft -> In Review -> Scheduled -> Published ->
+-- Archived. The old free-form statuses are folded into the new ones.
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP NULL,
+    ADD COLUMN IF NOT EXISTS unpublish_at TIMESTAMP NULL,
+    ADD COLUMN IF NOT EXISTS approved_by BIGINT NULL,
+    ADD COLUMN IF NOT EXISTS approved_at TIMESTAMP NULL,
+    ADD COLUMN IF NOT EXISTS review_note TEXT NULL;
+
+UPDATE products SET status = 'Published' WHERE status ILIKE 'published';
+UPDATE products SET status = 'Draft'
+    WHERE status NOT IN ('Published', 'In Review', 'Scheduled', 'Archived');
+
+ALTER TABLE products ALTER COLUMN status SET DEFAULT 'Draft';
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_status_check;
+ALTER TABLE products
+    ADD CONSTRAINT products_status_check
+    CHECK (status IN ('Draft', 'In Review', 'Scheduled', 'Published', 'Archived'));
+
+CREATE INDEX IF NOT EXISTS idx_products_publish_at ON products (publish_at) WHERE status = 'Scheduled';
+CREATE INDEX IF NOT EXISTS idx_products_unpublish_at ON products (unpublish_at) WHERE status = 'Published';
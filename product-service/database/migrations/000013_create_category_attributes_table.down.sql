@@ -0,0 +1,3 @@
+ALTER TABLE products DROP COLUMN IF EXISTS attributes;
+
+DROP TABLE IF EXISTS category_attributes;
@@ -0,0 +1,22 @@
+-- Attribute schemas are defined per category and apply to the products of
+-- the category and all its subcategories. Product values are kept as a JSON
+-- object keyed by attribute code.
+CREATE TABLE IF NOT EXISTS category_attributes (
+    id SERIAL PRIMARY KEY,
+    category_id BIGINT NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(10) NOT NULL CHECK (type IN ('enum', 'number', 'boolean')),
+    options TEXT NULL,
+    unit VARCHAR(20) NULL,
+    is_required BOOLEAN NOT NULL DEFAULT FALSE,
+    is_filterable BOOLEAN NOT NULL DEFAULT TRUE,
+    position INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL,
+    UNIQUE (category_id, code)
+);
+
+CREATE INDEX idx_category_attributes_category_id ON category_attributes(category_id);
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS bundle_items;
+
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_bundle_pricing_check;
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_type_check;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS bundle_discount,
+    DROP COLUMN IF EXISTS bundle_pricing,
+    DROP COLUMN IF EXISTS type;
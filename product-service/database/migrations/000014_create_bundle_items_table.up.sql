@@ -0,0 +1,27 @@
+-- A bundle is a product made of other products. Its stock is derived from
+-- the stock of its components; its price is either set by hand (fixed) or
+-- the sum of the component prices minus a discount (computed).
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS type VARCHAR(10) NOT NULL DEFAULT 'simple',
+    ADD COLUMN IF NOT EXISTS bundle_pricing VARCHAR(10) NULL,
+    ADD COLUMN IF NOT EXISTS bundle_discount NUMERIC(5, 2) NOT NULL DEFAULT 0;
+
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_type_check;
+ALTER TABLE products
+    ADD CONSTRAINT products_type_check CHECK (type IN ('simple', 'bundle'));
+
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_bundle_pricing_check;
+ALTER TABLE products
+    ADD CONSTRAINT products_bundle_pricing_check
+    CHECK (bundle_pricing IS NULL OR bundle_pricing IN ('fixed', 'computed'));
+
+CREATE TABLE IF NOT EXISTS bundle_items (
+    id SERIAL PRIMARY KEY,
+    bundle_id BIGINT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    component_id BIGINT NOT NULL REFERENCES products(id),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (bundle_id, component_id)
+);
+
+CREATE INDEX idx_bundle_items_component_id ON bundle_items(component_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS product_recommendations;
+DROP TABLE IF EXISTS order_purchases;
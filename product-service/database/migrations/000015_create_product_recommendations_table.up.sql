@@ -0,0 +1,24 @@
+-- Order items received from order-service; kept for the recommendation
+-- window only and used to count which products are bought together.
+CREATE TABLE IF NOT EXISTS order_purchases (
+    order_id BIGINT NOT NULL,
+    product_id BIGINT NOT NULL,
+    quantity INT NOT NULL DEFAULT 1,
+    ordered_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (order_id, product_id)
+);
+
+CREATE INDEX idx_order_purchases_ordered_at ON order_purchases(ordered_at);
+CREATE INDEX idx_order_purchases_product_id ON order_purchases(product_id);
+
+-- The top related products of every product, rebuilt on a schedule.
+CREATE TABLE IF NOT EXISTS product_recommendations (
+    product_id BIGINT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    related_id BIGINT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    score INT NOT NULL,
+    rank INT NOT NULL,
+    computed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (product_id, related_id)
+);
+
+CREATE INDEX idx_product_recommendations_rank ON product_recommendations(product_id, rank);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_order_purchases_buyer_id;
+
+ALTER TABLE order_purchases DROP COLUMN IF EXISTS buyer_id;
@@ -0,0 +1,4 @@
+-- The buyer lets the home feed use the previous purchases of a customer.
+ALTER TABLE order_purchases ADD COLUMN IF NOT EXISTS buyer_id BIGINT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_order_purchases_buyer_id ON order_purchases(buyer_id, ordered_at);
@@ -0,0 +1,10 @@
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_quantity_check;
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_sell_by_check;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS max_quantity,
+    DROP COLUMN IF EXISTS min_quantity,
+    DROP COLUMN IF EXISTS quantity_step,
+    DROP COLUMN IF EXISTS quantity_unit,
+    DROP COLUMN IF EXISTS price_unit,
+    DROP COLUMN IF EXISTS sell_by;
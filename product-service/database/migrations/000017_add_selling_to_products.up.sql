@@ -0,0 +1,20 @@
+-- Selling rules of a product. Products sold by measure are ordered as an
+-- amount in quantity_unit (their stock is counted in it too) and priced per
+-- price_unit; estimated items are counted as items but priced by weight,
+-- and the final price follows the weight measured at packing.
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS sell_by VARCHAR(10) NOT NULL DEFAULT 'item',
+    ADD COLUMN IF NOT EXISTS price_unit VARCHAR(20) NULL,
+    ADD COLUMN IF NOT EXISTS quantity_unit VARCHAR(20) NULL,
+    ADD COLUMN IF NOT EXISTS quantity_step BIGINT NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS min_quantity BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS max_quantity BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_sell_by_check;
+ALTER TABLE products
+    ADD CONSTRAINT products_sell_by_check CHECK (sell_by IN ('item', 'measure', 'estimated'));
+
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_quantity_check;
+ALTER TABLE products
+    ADD CONSTRAINT products_quantity_check
+    CHECK (quantity_step > 0 AND min_quantity >= 0 AND (max_quantity = 0 OR max_quantity >= min_quantity));
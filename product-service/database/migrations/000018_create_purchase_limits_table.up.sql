@@ -0,0 +1,21 @@
+-- Caps on how much one customer buys of a product, or of all products of a
+-- category, within a rolling window of hours. Purchases are counted from
+-- purchase_reservations (000019), kept for the longest window a limit may
+-- have.
+CREATE TABLE IF NOT EXISTS purchase_limits (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    product_id BIGINT NULL,
+    category_slug VARCHAR(120) NULL,
+    max_quantity BIGINT NOT NULL,
+    window_hours INT NOT NULL DEFAULT 24,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL,
+    CONSTRAINT purchase_limits_target_check CHECK ((product_id IS NULL) <> (category_slug IS NULL)),
+    CONSTRAINT purchase_limits_quantity_check CHECK (max_quantity > 0 AND window_hours > 0)
+);
+
+CREATE INDEX idx_purchase_limits_product_id ON purchase_limits(product_id);
+CREATE INDEX idx_purchase_limits_category_slug ON purchase_limits(category_slug);
@@ -0,0 +1,14 @@
+-- What each customer ordered, recorded by order-service when the order is
+-- created and removed when it is cancelled. Purchase limits are counted from
+-- here rather than from order_purchases, which is only filled once the
+-- order event is consumed.
+CREATE TABLE IF NOT EXISTS purchase_reservations (
+    order_code VARCHAR(64) NOT NULL,
+    product_id BIGINT NOT NULL,
+    buyer_id BIGINT NOT NULL,
+    quantity BIGINT NOT NULL,
+    reserved_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (order_code, product_id)
+);
+
+CREATE INDEX idx_purchase_reservations_buyer_id ON purchase_reservations(buyer_id, reserved_at);
@@ -0,0 +1,2 @@
+-- The role names the segments were mapped from are not kept.
+SELECT 1;
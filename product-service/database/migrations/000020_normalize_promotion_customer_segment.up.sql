@@ -0,0 +1,5 @@
+-- Promotions used to be limited to a role name; the segments are now only
+-- guest or customer, and any role meant a signed-in customer.
+UPDATE promotions
+SET customer_segment = 'customer'
+WHERE customer_segment IS NOT NULL AND customer_segment <> '' AND customer_segment NOT IN ('guest', 'customer');
@@ -0,0 +1,4 @@
+ALTER TABLE products DROP COLUMN IF EXISTS rating_count;
+ALTER TABLE products DROP COLUMN IF EXISTS rating;
+
+DROP TABLE IF EXISTS product_ratings;
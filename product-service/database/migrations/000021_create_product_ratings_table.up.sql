@@ -0,0 +1,14 @@
+-- Stars a customer gives a product, one rating per customer that is replaced
+-- when they rate again. products carries the average and the number of
+-- ratings so the search index and the rating facet can use them.
+CREATE TABLE IF NOT EXISTS product_ratings (
+    product_id BIGINT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL,
+    PRIMARY KEY (product_id, user_id)
+);
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS rating NUMERIC(3,2) NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS rating_count INT NOT NULL DEFAULT 0;
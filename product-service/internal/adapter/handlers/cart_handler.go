@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"product-service/config"
 	"product-service/internal/adapter"
@@ -16,46 +17,45 @@ import (
 	"github.com/labstack/gommon/log"
 )
 
+const (
+	guestCartCookie = "cart_id"
+	guestCartHeader = "X-Cart-ID"
+)
+
+var errNoGuestCart = errors.New("cart id not found")
+
 type ICartHandler interface {
 	AddToCart(c echo.Context) error
+	SetQuantity(c echo.Context) error
 	GetCart(c echo.Context) error
 	RemoveFromCart(c echo.Context) error
 	RemoveAllCart(c echo.Context) error
+	MergeCart(c echo.Context) error
 }
 
 type CartHandler struct {
-	cartService    service.ICartService
-	ProductService service.IProductService
+	cartService service.ICartService
+	cfg         *config.Config
 }
 
 // RemoveAllCart implements [ICartHandler].
 func (ch *CartHandler) RemoveAllCart(c echo.Context) error {
 	var (
-		resp        = response.DefaultResponse{}
-		ctx         = c.Request().Context()
-		jwtUserData = entities.JwtUserData{}
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
 	)
 
-	user := c.Get("user").(string)
-	if user == "" {
-		log.Errorf("[CartHandler-1] RemoveFromCart: %s", "data token not found")
-		resp.Message = "data token not found"
-		resp.Data = nil
-		return c.JSON(http.StatusNotFound, resp)
-	}
-
-	err := json.Unmarshal([]byte(user), &jwtUserData)
+	owner, err := ch.restCartOwner(c, false)
 	if err != nil {
-		log.Errorf("[CartHandler-2] RemoveFromCart: %v", err)
+		log.Errorf("[CartHandler-1] RemoveAllCart: %v", err)
 		resp.Message = err.Error()
 		resp.Data = nil
 		return c.JSON(http.StatusBadRequest, resp)
 	}
 
-	userID := jwtUserData.UserID
-	err = ch.cartService.RemoveAllCart(ctx, userID)
+	err = ch.cartService.RemoveAllCart(ctx, owner)
 	if err != nil {
-		log.Errorf("[CartHandler-1] RemoveAllCart: %v", err)
+		log.Errorf("[CartHandler-2] RemoveAllCart: %v", err)
 		resp.Message = err.Error()
 		resp.Data = nil
 		return c.JSON(http.StatusInternalServerError, resp)
@@ -69,38 +69,35 @@ func (ch *CartHandler) RemoveAllCart(c echo.Context) error {
 // RemoveFromCart implements [ICartHandler].
 func (ch *CartHandler) RemoveFromCart(c echo.Context) error {
 	var (
-		resp        = response.DefaultResponse{}
-		ctx         = c.Request().Context()
-		jwtUserData = entities.JwtUserData{}
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
 	)
 
-	user := c.Get("user").(string)
-	if user == "" {
-		log.Errorf("[CartHandler-1] RemoveFromCart: %s", "data token not found")
-		resp.Message = "data token not found"
-		resp.Data = nil
-		return c.JSON(http.StatusNotFound, resp)
-	}
-
-	err := json.Unmarshal([]byte(user), &jwtUserData)
+	owner, err := ch.restCartOwner(c, false)
 	if err != nil {
-		log.Errorf("[CartHandler-2] RemoveFromCart: %v", err)
+		log.Errorf("[CartHandler-1] RemoveFromCart: %v", err)
 		resp.Message = err.Error()
 		resp.Data = nil
 		return c.JSON(http.StatusBadRequest, resp)
 	}
 
-	userID := jwtUserData.UserID
 	productID := c.QueryParam("product_id")
 	if productID == "" {
-		log.Errorf("[CartHandler-3] RemoveFromCart: %s", "product_id is required")
+		log.Errorf("[CartHandler-2] RemoveFromCart: %s", "product_id is required")
 		resp.Message = "product_id is required"
 		resp.Data = nil
 		return c.JSON(http.StatusBadRequest, resp)
 	}
 
 	prodID, err := conv.StringToInt64(productID)
-	err = ch.cartService.RemoveFromCart(ctx, userID, prodID)
+	if err != nil {
+		log.Errorf("[CartHandler-3] RemoveFromCart: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	err = ch.cartService.RemoveFromCart(ctx, owner, prodID)
 	if err != nil {
 		log.Errorf("[CartHandler-4] RemoveFromCart: %v", err)
 		resp.Message = err.Error()
@@ -116,60 +113,71 @@ func (ch *CartHandler) RemoveFromCart(c echo.Context) error {
 // GetCart implements [ICartHandler].
 func (ch *CartHandler) GetCart(c echo.Context) error {
 	var (
-		resp        = response.DefaultResponse{}
-		ctx         = c.Request().Context()
-		respList    = []response.CartResponse{}
-		jwtUserData = entities.JwtUserData{}
+		resp     = response.DefaultResponse{}
+		ctx      = c.Request().Context()
+		respList = []response.CartLineResponse{}
 	)
 
-	user := c.Get("user").(string)
-	if user == "" {
-		log.Errorf("[CartHandler-1] GetCart: %s", "data token not found")
-		resp.Message = "data token not found"
-		resp.Data = nil
-		return c.JSON(http.StatusNotFound, resp)
+	owner, err := ch.restCartOwner(c, false)
+	if errors.Is(err, errNoGuestCart) {
+		resp.Message = "success"
+		resp.Data = response.CartResponse{Items: respList}
+		return c.JSON(http.StatusOK, resp)
 	}
-
-	err := json.Unmarshal([]byte(user), &jwtUserData)
 	if err != nil {
-		log.Errorf("[CartHandler-2] GetCart: %v", err)
+		log.Errorf("[CartHandler-1] GetCart: %v", err)
 		resp.Message = err.Error()
 		resp.Data = nil
 		return c.JSON(http.StatusBadRequest, resp)
 	}
 
-	userID := jwtUserData.UserID
-	items, err := ch.cartService.GetCartByUserID(ctx, userID)
+	result, err := ch.cartService.GetCartDetail(ctx, owner)
 	if err != nil {
-		log.Errorf("[CartHandler-3] GetCart: %v", err)
+		log.Errorf("[CartHandler-2] GetCart: %v", err)
 		resp.Message = err.Error()
 		resp.Data = nil
 		return c.JSON(http.StatusInternalServerError, resp)
 	}
 
-	for _, item := range items {
-		product, err := ch.ProductService.GetByID(ctx, item.ProductID)
-		if err != nil {
-			log.Errorf("[CartHandler-4] GetCart: %v", err)
-			resp.Message = err.Error()
-			resp.Data = nil
-			return c.JSON(http.StatusInternalServerError, resp)
+	for _, line := range result.Items {
+		respWarnings := []response.CartWarningResponse{}
+		for _, warning := range line.Warnings {
+			respWarnings = append(respWarnings, response.CartWarningResponse{
+				Code:    warning.Code,
+				Message: warning.Message,
+			})
 		}
 
-		respList = append(respList, response.CartResponse{
-			ID:            item.ProductID,
-			ProductName:   product.Name,
-			ProductImage:  product.Image,
-			ProductStatus: product.Status,
-			SalePrice:     int64(product.SalePrice),
-			Quantity:      item.Quantity,
-			Unit:          product.Unit,
-			Weight:        int64(product.Weight),
+		respList = append(respList, response.CartLineResponse{
+			ID:            line.ProductID,
+			ProductName:   line.ProductName,
+			ProductImage:  line.ProductImage,
+			ProductStatus: line.ProductStatus,
+			SalePrice:     int64(line.UnitPrice),
+			PreviousPrice: int64(line.PreviousPrice),
+			Quantity:      line.Quantity,
+			LineTotal:     int64(line.LineTotal),
+			Stock:         line.Stock,
+			Available:     line.Available,
+			Unit:          line.Unit,
+			Weight:        line.Weight,
+			SellBy:        line.SellBy,
+			PriceUnit:     line.PriceUnit,
+			QuantityUnit:  line.QuantityUnit,
+			LineWeight:    line.LineWeight,
+			Warnings:      respWarnings,
 		})
 	}
 
 	resp.Message = "success"
-	resp.Data = respList
+	resp.Data = response.CartResponse{
+		Items:         respList,
+		TotalQuantity: result.TotalQuantity,
+		TotalWeight:   result.TotalWeight,
+		Subtotal:      int64(result.Subtotal),
+		HasWarnings:   result.HasWarnings,
+		HasEstimated:  result.HasEstimated,
+	}
 
 	return c.JSON(http.StatusOK, resp)
 }
@@ -177,10 +185,9 @@ func (ch *CartHandler) GetCart(c echo.Context) error {
 // AddToCart implements [ICartHandler].
 func (ch *CartHandler) AddToCart(c echo.Context) error {
 	var (
-		resp        = response.DefaultResponse{}
-		ctx         = c.Request().Context()
-		request     = request.CartRequest{}
-		jwtUserData = entities.JwtUserData{}
+		resp    = response.DefaultResponse{}
+		ctx     = c.Request().Context()
+		request = request.CartRequest{}
 	)
 
 	if err := c.Bind(&request); err != nil {
@@ -197,45 +204,252 @@ func (ch *CartHandler) AddToCart(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, resp)
 	}
 
-	user := c.Get("user").(string)
-	if user == "" {
-		log.Errorf("[CartHandler-3] AddToCart: %s", "data token not found")
-		resp.Message = "data token not found"
+	owner, err := ch.restCartOwner(c, true)
+	if err != nil {
+		log.Errorf("[CartHandler-3] AddToCart: %v", err)
+		resp.Message = err.Error()
 		resp.Data = nil
-		return c.JSON(http.StatusNotFound, resp)
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	reqEntity := entities.CartItem{
+		ProductID: request.ProductID,
+		Quantity:  request.Quantity,
+		Unit:      request.Unit,
 	}
 
-	err := json.Unmarshal([]byte(user), &jwtUserData)
+	err = ch.cartService.AddToCart(ctx, owner, reqEntity)
 	if err != nil {
 		log.Errorf("[CartHandler-4] AddToCart: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Product not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		var limitErr *service.PurchaseLimitError
+		if errors.As(err, &limitErr) {
+			resp.Message = err.Error()
+			resp.Data = purchaseLimitViolationsToResponse(limitErr.Violations)
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// SetQuantity implements [ICartHandler].
+func (ch *CartHandler) SetQuantity(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		req  = request.CartQuantityRequest{}
+	)
+
+	productID, err := conv.StringToInt64(c.Param("productId"))
+	if err != nil {
+		log.Errorf("[CartHandler-1] SetQuantity: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[CartHandler-2] SetQuantity: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Validate(req); err != nil {
+		log.Errorf("[CartHandler-3] SetQuantity: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	owner, err := ch.restCartOwner(c, true)
+	if err != nil {
+		log.Errorf("[CartHandler-4] SetQuantity: %v", err)
 		resp.Message = err.Error()
 		resp.Data = nil
 		return c.JSON(http.StatusBadRequest, resp)
 	}
 
-	userID := jwtUserData.UserID
 	reqEntity := entities.CartItem{
-		ProductID: request.ProductID,
-		Quantity:  request.Quantity,
+		ProductID: productID,
+		Quantity:  req.Quantity,
+		Unit:      req.Unit,
+	}
+
+	err = ch.cartService.SetQuantity(ctx, owner, reqEntity)
+	if err != nil {
+		log.Errorf("[CartHandler-5] SetQuantity: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Product not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		var limitErr *service.PurchaseLimitError
+		if errors.As(err, &limitErr) {
+			resp.Message = err.Error()
+			resp.Data = purchaseLimitViolationsToResponse(limitErr.Violations)
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+// MergeCart implements [ICartHandler]. It merges the guest cart named by the
+// X-Cart-ID header or cart_id cookie into the signed-in customer's cart, for
+// clients that keep the cart ID in the header; cookie clients get it merged
+// on their first signed-in cart request.
+func (ch *CartHandler) MergeCart(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	owner, err := ch.cartOwner(c, false)
+	if err != nil {
+		log.Errorf("[CartHandler-1] MergeCart: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	cartID := c.Request().Header.Get(guestCartHeader)
+	if cookie, err := c.Cookie(guestCartCookie); err == nil && cookie.Value != "" {
+		cartID = cookie.Value
+	}
+	if _, err := ch.cartService.VerifyGuestCartID(cartID); err != nil {
+		log.Errorf("[CartHandler-2] MergeCart: %v", err)
+		resp.Message = errNoGuestCart.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
 	}
 
-	err = ch.cartService.AddToCart(ctx, userID, reqEntity)
+	err = ch.cartService.MergeGuestCart(ctx, cartID, owner.UserID)
 	if err != nil {
-		log.Errorf("[CartHandler-5] AddToCart: %v", err)
+		log.Errorf("[CartHandler-3] MergeCart: %v", err)
 		resp.Message = err.Error()
 		resp.Data = nil
 		return c.JSON(http.StatusInternalServerError, resp)
 	}
+	ch.expireGuestCartCookie(c)
 
 	resp.Message = "success"
 	resp.Data = nil
-	return c.JSON(http.StatusCreated, resp)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// restCartOwner is cartOwner for the cart routes. A customer who signs in
+// while still holding the guest cart cookie gets that cart merged in first;
+// the cookie is dropped once merged, so this happens on one request only.
+func (ch *CartHandler) restCartOwner(c echo.Context, create bool) (entities.CartOwner, error) {
+	owner, err := ch.cartOwner(c, create)
+	if err != nil || owner.UserID == 0 {
+		return owner, err
+	}
+
+	if cookie, err := c.Cookie(guestCartCookie); err == nil && cookie.Value != "" {
+		ch.mergeGuestCart(c, cookie.Value, owner.UserID)
+	}
+
+	return owner, nil
+}
+
+// cartOwner resolves whose cart a request works on without changing any
+// cart. Routes behind CheckToken use the signed-in customer; guest routes use
+// the signed cart ID from the cart_id cookie or X-Cart-ID header, and issue a
+// new one when create is set and the visitor has none yet.
+func (ch *CartHandler) cartOwner(c echo.Context, create bool) (entities.CartOwner, error) {
+	cartID := c.Request().Header.Get(guestCartHeader)
+	if cookie, err := c.Cookie(guestCartCookie); err == nil && cookie.Value != "" {
+		cartID = cookie.Value
+	}
+
+	if user, _ := c.Get("user").(string); user != "" {
+		jwtUserData := entities.JwtUserData{}
+		if err := json.Unmarshal([]byte(user), &jwtUserData); err != nil {
+			return entities.CartOwner{}, err
+		}
+
+		return entities.CartOwner{UserID: jwtUserData.UserID}, nil
+	}
+
+	if cartID != "" {
+		guestID, err := ch.cartService.VerifyGuestCartID(cartID)
+		if err == nil {
+			c.Response().Header().Set(guestCartHeader, cartID)
+			return entities.CartOwner{GuestID: guestID}, nil
+		}
+		if !create {
+			return entities.CartOwner{}, err
+		}
+	}
+
+	if !create {
+		return entities.CartOwner{}, errNoGuestCart
+	}
+
+	cartID = ch.cartService.NewGuestCartID()
+	guestID, err := ch.cartService.VerifyGuestCartID(cartID)
+	if err != nil {
+		return entities.CartOwner{}, err
+	}
+
+	c.SetCookie(&http.Cookie{
+		Name:     guestCartCookie,
+		Value:    cartID,
+		Path:     "/",
+		MaxAge:   ch.cfg.App.CartIdleTTL,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	c.Response().Header().Set(guestCartHeader, cartID)
+
+	return entities.CartOwner{GuestID: guestID}, nil
+}
+
+// mergeGuestCart moves the guest cart into the customer's before the customer
+// cart is used, so it does not depend on the sign-in event being consumed.
+// The merge removes the guest cart, so doing it again is a no-op.
+func (ch *CartHandler) mergeGuestCart(c echo.Context, cartID string, userID int64) {
+	if err := ch.cartService.MergeGuestCart(c.Request().Context(), cartID, userID); err != nil {
+		log.Errorf("[CartHandler-1] mergeGuestCart: %v", err)
+		return
+	}
+
+	ch.expireGuestCartCookie(c)
+}
+
+// expireGuestCartCookie drops the cart_id cookie of a merged guest cart.
+func (ch *CartHandler) expireGuestCartCookie(c echo.Context) {
+	c.SetCookie(&http.Cookie{
+		Name:     guestCartCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
 }
 
-func NewCartHandler(e *echo.Echo, cfg *config.Config, cartService service.ICartService, productService service.IProductService) ICartHandler {
+func NewCartHandler(e *echo.Echo, cfg *config.Config, cartService service.ICartService) ICartHandler {
 	cartHandler := &CartHandler{
-		cartService:    cartService,
-		ProductService: productService,
+		cartService: cartService,
+		cfg:         cfg,
 	}
 
 	e.Use(middleware.Recover())
@@ -243,8 +457,17 @@ func NewCartHandler(e *echo.Echo, cfg *config.Config, cartService service.ICartS
 	authGroup := e.Group("/auth", mid.CheckToken())
 	authGroup.POST("/cart", cartHandler.AddToCart)
 	authGroup.GET("/cart", cartHandler.GetCart)
+	authGroup.PUT("/cart/items/:productId", cartHandler.SetQuantity)
 	authGroup.DELETE("/cart", cartHandler.RemoveFromCart)
 	authGroup.DELETE("/cart/all", cartHandler.RemoveAllCart)
+	authGroup.POST("/cart/merge", cartHandler.MergeCart)
+
+	guestGroup := e.Group("/guest")
+	guestGroup.POST("/cart", cartHandler.AddToCart)
+	guestGroup.GET("/cart", cartHandler.GetCart)
+	guestGroup.PUT("/cart/items/:productId", cartHandler.SetQuantity)
+	guestGroup.DELETE("/cart", cartHandler.RemoveFromCart)
+	guestGroup.DELETE("/cart/all", cartHandler.RemoveAllCart)
 
 	return cartHandler
 }
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/service"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+// catalogRecorder holds back a response so it can be cached and answered
+// with validators. Headers still go to the real response.
+type catalogRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *catalogRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *catalogRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+func (r *catalogRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+// catalogCached serves a public catalog endpoint from the cache of the
+// scope and answers conditional requests with 304 Not Modified. Responses
+// for a signed in customer, like the personal home feed, get an ETag but are
+// never stored. When Redis is down the request goes to the handler.
+func catalogCached(catalogCacheService service.ICatalogCacheService, scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			key := c.Request().URL.Path + "?" + c.Request().URL.Query().Encode()
+			shared := optionalUserID(c) == 0
+			store := shared
+
+			var version int64
+			if shared {
+				entry, v, err := catalogCacheService.Lookup(ctx, scope, key)
+				if err != nil {
+					log.Errorf("[CatalogCache-1] %s: %v", key, err)
+					store = false
+				}
+				if entry != nil {
+					return writeCatalogEntry(c, *entry, shared)
+				}
+				version = v
+			}
+
+			recorder := &catalogRecorder{header: c.Response().Header(), status: http.StatusOK}
+			writer := c.Response().Writer
+			c.Response().Writer = recorder
+			err := next(c)
+			c.Response().Writer = writer
+			c.Response().Committed = false
+			if err != nil {
+				return err
+			}
+
+			contentType := recorder.header.Get(echo.HeaderContentType)
+			if recorder.status != http.StatusOK {
+				return c.Blob(recorder.status, contentType, recorder.body.Bytes())
+			}
+
+			entry := service.NewCatalogCacheEntry(recorder.body.Bytes(), contentType)
+			if store {
+				if err := catalogCacheService.Store(ctx, scope, version, key, entry); err != nil {
+					log.Errorf("[CatalogCache-2] %s: %v", key, err)
+				}
+			}
+
+			return writeCatalogEntry(c, entry, shared)
+		}
+	}
+}
+
+// writeCatalogEntry sends the response, or 304 when the client already has
+// it. Clients have to revalidate every time, which costs them only the
+// headers while nothing changed.
+func writeCatalogEntry(c echo.Context, entry entities.CatalogCacheEntity, shared bool) error {
+	header := c.Response().Header()
+	header.Set("ETag", entry.ETag)
+	header.Set("Last-Modified", entry.LastModified.UTC().Format(http.TimeFormat))
+	header.Set("Vary", echo.HeaderAuthorization)
+	if shared {
+		header.Set("Cache-Control", "public, no-cache")
+	} else {
+		header.Set("Cache-Control", "private, no-cache")
+	}
+
+	if catalogNotModified(c.Request(), entry) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return c.Blob(http.StatusOK, entry.ContentType, entry.Body)
+}
+
+// catalogNotModified checks If-None-Match, or If-Modified-Since when the
+// client sent no ETag, as RFC 9110 asks.
+func catalogNotModified(req *http.Request, entry entities.CatalogCacheEntity) bool {
+	if match := req.Header.Get("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == entry.ETag {
+				return true
+			}
+		}
+		return false
+	}
+
+	since, err := http.ParseTime(req.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	return !entry.LastModified.After(since)
+}
@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"product-service/config"
+	"product-service/internal/adapter"
+	"product-service/internal/adapter/handlers/request"
+	"product-service/internal/adapter/handlers/response"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/service"
+	"product-service/utils/conv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+type ICategoryAttributeHandler interface {
+	GetAllAdmin(c echo.Context) error
+	CreateAdmin(c echo.Context) error
+	UpdateAdmin(c echo.Context) error
+	DeleteAdmin(c echo.Context) error
+
+	GetByCategorySlug(c echo.Context) error
+}
+
+type categoryAttributeHandler struct {
+	categoryAttributeService service.ICategoryAttributeService
+}
+
+// GetAllAdmin implements [ICategoryAttributeHandler].
+func (ca *categoryAttributeHandler) GetAllAdmin(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	categoryID, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[CategoryAttributeHandler-1] GetAllAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	results, err := ca.categoryAttributeService.GetByCategoryID(ctx, categoryID)
+	if err != nil {
+		log.Errorf("[CategoryAttributeHandler-2] GetAllAdmin: %v", err)
+		return categoryAttributeError(c, err)
+	}
+
+	resp.Message = "success"
+	resp.Data = toCategoryAttributeResponses(results)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CreateAdmin implements [ICategoryAttributeHandler].
+func (ca *categoryAttributeHandler) CreateAdmin(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		req  = request.CategoryAttributeRequest{}
+	)
+
+	categoryID, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[CategoryAttributeHandler-1] CreateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[CategoryAttributeHandler-2] CreateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Validate(req); err != nil {
+		log.Errorf("[CategoryAttributeHandler-3] CreateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	reqEntity := categoryAttributeRequestToEntity(req)
+	reqEntity.CategoryID = categoryID
+
+	result, err := ca.categoryAttributeService.Create(ctx, reqEntity)
+	if err != nil {
+		log.Errorf("[CategoryAttributeHandler-4] CreateAdmin: %v", err)
+		return categoryAttributeError(c, err)
+	}
+
+	resp.Message = "success"
+	resp.Data = toCategoryAttributeResponse(*result)
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// UpdateAdmin implements [ICategoryAttributeHandler]. The code and type of
+// an attribute cannot be changed; they are ignored when sent.
+func (ca *categoryAttributeHandler) UpdateAdmin(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		req  = request.CategoryAttributeRequest{}
+	)
+
+	categoryID, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[CategoryAttributeHandler-1] UpdateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	attributeID, err := conv.StringToInt64(c.Param("attributeId"))
+	if err != nil {
+		log.Errorf("[CategoryAttributeHandler-2] UpdateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[CategoryAttributeHandler-3] UpdateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Validate(req); err != nil {
+		log.Errorf("[CategoryAttributeHandler-4] UpdateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	reqEntity := categoryAttributeRequestToEntity(req)
+	reqEntity.ID = attributeID
+	reqEntity.CategoryID = categoryID
+
+	result, err := ca.categoryAttributeService.Update(ctx, reqEntity)
+	if err != nil {
+		log.Errorf("[CategoryAttributeHandler-5] UpdateAdmin: %v", err)
+		return categoryAttributeError(c, err)
+	}
+
+	resp.Message = "success"
+	resp.Data = toCategoryAttributeResponse(*result)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// DeleteAdmin implements [ICategoryAttributeHandler].
+func (ca *categoryAttributeHandler) DeleteAdmin(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	categoryID, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[CategoryAttributeHandler-1] DeleteAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	attributeID, err := conv.StringToInt64(c.Param("attributeId"))
+	if err != nil {
+		log.Errorf("[CategoryAttributeHandler-2] DeleteAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := ca.categoryAttributeService.Delete(ctx, categoryID, attributeID); err != nil {
+		log.Errorf("[CategoryAttributeHandler-3] DeleteAdmin: %v", err)
+		return categoryAttributeError(c, err)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetByCategorySlug implements [ICategoryAttributeHandler]. The shop uses it
+// to build the specification filters of a category page.
+func (ca *categoryAttributeHandler) GetByCategorySlug(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	results, err := ca.categoryAttributeService.GetByCategorySlug(ctx, c.Param("slug"))
+	if err != nil {
+		log.Errorf("[CategoryAttributeHandler-1] GetByCategorySlug: %v", err)
+		return categoryAttributeError(c, err)
+	}
+
+	filterable := []entities.CategoryAttributeEntity{}
+	for _, result := range results {
+		if result.IsFilterable {
+			filterable = append(filterable, result)
+		}
+	}
+
+	resp.Message = "success"
+	resp.Data = toCategoryAttributeResponses(filterable)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func categoryAttributeRequestToEntity(req request.CategoryAttributeRequest) entities.CategoryAttributeEntity {
+	isFilterable := true
+	if req.IsFilterable != nil {
+		isFilterable = *req.IsFilterable
+	}
+
+	return entities.CategoryAttributeEntity{
+		Code:         req.Code,
+		Name:         req.Name,
+		Type:         req.Type,
+		Options:      req.Options,
+		Unit:         req.Unit,
+		IsRequired:   req.IsRequired,
+		IsFilterable: isFilterable,
+		Position:     req.Position,
+	}
+}
+
+func toCategoryAttributeResponse(attribute entities.CategoryAttributeEntity) response.CategoryAttributeResponse {
+	return response.CategoryAttributeResponse{
+		ID:           attribute.ID,
+		CategoryID:   attribute.CategoryID,
+		Code:         attribute.Code,
+		Name:         attribute.Name,
+		Type:         attribute.Type,
+		Options:      attribute.Options,
+		Unit:         attribute.Unit,
+		IsRequired:   attribute.IsRequired,
+		IsFilterable: attribute.IsFilterable,
+		Position:     attribute.Position,
+		Inherited:    attribute.Inherited,
+	}
+}
+
+func toCategoryAttributeResponses(attributes []entities.CategoryAttributeEntity) []response.CategoryAttributeResponse {
+	respAttributes := []response.CategoryAttributeResponse{}
+	for _, attribute := range attributes {
+		respAttributes = append(respAttributes, toCategoryAttributeResponse(attribute))
+	}
+
+	return respAttributes
+}
+
+func toProductAttributeResponses(specifications []entities.ProductAttributeEntity) []response.ProductAttributeResponse {
+	respAttributes := []response.ProductAttributeResponse{}
+	for _, specification := range specifications {
+		respAttributes = append(respAttributes, response.ProductAttributeResponse(specification))
+	}
+
+	return respAttributes
+}
+
+func isProductAttributeError(err error) bool {
+	return errors.Is(err, service.ErrUnknownAttribute) ||
+		errors.Is(err, service.ErrInvalidAttributeValue) ||
+		errors.Is(err, service.ErrAttributeRequired)
+}
+
+func categoryAttributeError(c echo.Context, err error) error {
+	resp := response.DefaultResponse{Message: err.Error()}
+	switch {
+	case err.Error() == "404":
+		resp.Message = "Data not found"
+		return c.JSON(http.StatusNotFound, resp)
+	case errors.Is(err, service.ErrAttributeCodeTaken):
+		return c.JSON(http.StatusConflict, resp)
+	case errors.Is(err, service.ErrInvalidAttributeCode),
+		errors.Is(err, service.ErrInvalidAttributeType),
+		errors.Is(err, service.ErrAttributeOptionsRequired):
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+	return c.JSON(http.StatusInternalServerError, resp)
+}
+
+func NewCategoryAttributeHandler(e *echo.Echo, cfg *config.Config, categoryAttributeService service.ICategoryAttributeService) ICategoryAttributeHandler {
+	categoryAttributeHandler := &categoryAttributeHandler{
+		categoryAttributeService: categoryAttributeService,
+	}
+
+	mid := adapter.NewMiddlewareAdapter(cfg)
+	adminGroup := e.Group("/admin", mid.CheckToken())
+	adminGroup.GET("/categories/:id/attributes", categoryAttributeHandler.GetAllAdmin)
+	adminGroup.POST("/categories/:id/attributes", categoryAttributeHandler.CreateAdmin)
+	adminGroup.PUT("/categories/:id/attributes/:attributeId", categoryAttributeHandler.UpdateAdmin)
+	adminGroup.DELETE("/categories/:id/attributes/:attributeId", categoryAttributeHandler.DeleteAdmin)
+
+	categoryApp := e.Group("/categories")
+	categoryApp.GET("/:slug/attributes", categoryAttributeHandler.GetByCategorySlug)
+
+	return categoryAttributeHandler
+}
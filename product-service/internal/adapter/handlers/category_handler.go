@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"product-service/config"
@@ -27,6 +28,7 @@ type ICategoryHandler interface {
 
 	GetAllHome(c echo.Context) error
 	GetAllShop(c echo.Context) error
+	GetTree(c echo.Context) error
 }
 
 // struct
@@ -34,6 +36,47 @@ type categoryHandler struct {
 	categoryService service.ICategoryService
 }
 
+// GetTree implements [ICategoryHandler].
+func (ch *categoryHandler) GetTree(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	results, err := ch.categoryService.GetTree(ctx)
+	if err != nil {
+		log.Errorf("[CategoryHandler-1] GetTree: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Data not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = categoryTreeToResponse(results)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func categoryTreeToResponse(nodes []entities.CategoryTreeEntity) []response.CategoryTreeResponse {
+	resps := []response.CategoryTreeResponse{}
+	for _, node := range nodes {
+		resps = append(resps, response.CategoryTreeResponse{
+			ID:           node.ID,
+			Name:         node.Name,
+			Icon:         node.Icon,
+			Slug:         node.Slug,
+			ProductCount: node.ProductCount,
+			TotalProduct: node.TotalProduct,
+			Child:        categoryTreeToResponse(node.Children),
+		})
+	}
+	return resps
+}
+
 // GetAllHome implements [ICategoryHandler].
 func (ch *categoryHandler) GetAllHome(c echo.Context) error {
 	var (
@@ -122,14 +165,35 @@ func (ch *categoryHandler) Delete(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, resp)
 	}
 
-	err = ch.categoryService.DeleteCategory(ctx, id)
+	var reassignTo int64
+	if c.QueryParam("reassign_to") != "" {
+		reassignTo, err = conv.StringToInt64(c.QueryParam("reassign_to"))
+		if err != nil {
+			log.Errorf("[CategoryHandler-3] Delete: %v", err)
+			resp.Message = "reassign_to must be a category id"
+			resp.Data = nil
+			return c.JSON(http.StatusBadRequest, resp)
+		}
+	}
+
+	err = ch.categoryService.DeleteCategory(ctx, id, reassignTo)
 	if err != nil {
-		log.Errorf("[CategoryHandler-3] Delete: %v", err)
+		log.Errorf("[CategoryHandler-4] Delete: %v", err)
 		if err.Error() == "404" {
 			resp.Message = "Category not found"
 			resp.Data = nil
 			return c.JSON(http.StatusNotFound, resp)
 		}
+		if errors.Is(err, service.ErrCategoryHasProducts) || errors.Is(err, service.ErrCategoryHasChildren) {
+			resp.Message = err.Error()
+			resp.Data = nil
+			return c.JSON(http.StatusConflict, resp)
+		}
+		if errors.Is(err, service.ErrInvalidReassignTarget) {
+			resp.Message = err.Error()
+			resp.Data = nil
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		}
 		resp.Message = err.Error()
 		resp.Data = nil
 		return c.JSON(http.StatusInternalServerError, resp)
@@ -197,6 +261,11 @@ func (ch *categoryHandler) Update(c echo.Context) error {
 			resp.Data = nil
 			return c.JSON(http.StatusNotFound, resp)
 		}
+		if errors.Is(err, service.ErrCategoryCycle) || errors.Is(err, service.ErrParentCategoryNotFound) {
+			resp.Message = err.Error()
+			resp.Data = nil
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		}
 		resp.Message = err.Error()
 		resp.Data = nil
 		return c.JSON(http.StatusInternalServerError, resp)
@@ -288,6 +357,11 @@ func (ch *categoryHandler) Create(c echo.Context) error {
 			resp.Data = nil
 			return c.JSON(http.StatusConflict, resp)
 		}
+		if errors.Is(err, service.ErrParentCategoryNotFound) {
+			resp.Message = err.Error()
+			resp.Data = nil
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		}
 		resp.Message = err.Error()
 		resp.Data = nil
 		return c.JSON(http.StatusInternalServerError, resp)
@@ -446,7 +520,7 @@ func RekursifCategory(results []entities.CategoryEntity, parentID *int64, level
 	return resps
 }
 
-func NewCategoryHandler(e *echo.Echo, categoryService service.ICategoryService, cfg *config.Config) ICategoryHandler {
+func NewCategoryHandler(e *echo.Echo, categoryService service.ICategoryService, catalogCacheService service.ICatalogCacheService, cfg *config.Config) ICategoryHandler {
 	categoryHandler := &categoryHandler{
 		categoryService: categoryService,
 	}
@@ -462,8 +536,9 @@ func NewCategoryHandler(e *echo.Echo, categoryService service.ICategoryService,
 	adminGroup.DELETE("/categories/:id", categoryHandler.Delete)
 
 	categoryApp := e.Group("/categories")
-	categoryApp.GET("/home", categoryHandler.GetAllHome)
-	categoryApp.GET("/shop", categoryHandler.GetAllShop)
+	categoryApp.GET("/home", categoryHandler.GetAllHome, catalogCached(catalogCacheService, entities.CatalogScopeCategories))
+	categoryApp.GET("/shop", categoryHandler.GetAllShop, catalogCached(catalogCacheService, entities.CatalogScopeCategories))
+	categoryApp.GET("/tree", categoryHandler.GetTree)
 
 
 	return categoryHandler
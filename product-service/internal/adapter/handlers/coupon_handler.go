@@ -0,0 +1,598 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"product-service/config"
+	"product-service/internal/adapter"
+	"product-service/internal/adapter/handlers/request"
+	"product-service/internal/adapter/handlers/response"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/service"
+	"product-service/utils/conv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+type ICouponHandler interface {
+	GetAllAdmin(c echo.Context) error
+	GetByIDAdmin(c echo.Context) error
+	CreateAdmin(c echo.Context) error
+	GenerateAdmin(c echo.Context) error
+	UpdateAdmin(c echo.Context) error
+	DeleteAdmin(c echo.Context) error
+	GetRedemptionsAdmin(c echo.Context) error
+
+	ApplyToCart(c echo.Context) error
+	RemoveFromCart(c echo.Context) error
+	Redeem(c echo.Context) error
+	ReleaseRedemption(c echo.Context) error
+}
+
+type couponHandler struct {
+	couponService service.ICouponService
+}
+
+// GetAllAdmin implements [ICouponHandler].
+func (ch *couponHandler) GetAllAdmin(c echo.Context) error {
+	var (
+		resp        = response.DefaultResponseWithPaginations{}
+		ctx         = c.Request().Context()
+		respCoupons = []response.CouponResponse{}
+	)
+
+	orderBy := "created_at"
+	if c.QueryParam("order_by") != "" {
+		orderBy = c.QueryParam("order_by")
+	}
+	orderType := "desc"
+	if c.QueryParam("order_type") != "" {
+		orderType = c.QueryParam("order_type")
+	}
+
+	var page int64 = 1
+	if pageStr := c.QueryParam("page"); pageStr != "" {
+		page, _ = conv.StringToInt64(pageStr)
+		if page <= 0 {
+			page = 1
+		}
+	}
+
+	var perPage int64 = 10
+	if perPageStr := c.QueryParam("perPage"); perPageStr != "" {
+		perPage, _ = conv.StringToInt64(perPageStr)
+		if perPage <= 0 {
+			perPage = 10
+		}
+	}
+
+	reqEntity := entities.QueryStringEntity{
+		Search:    c.QueryParam("search"),
+		OrderBy:   orderBy,
+		OrderType: orderType,
+		Page:      int(page),
+		Limit:     int(perPage),
+	}
+
+	results, totalData, totalPage, err := ch.couponService.GetAll(ctx, reqEntity)
+	if err != nil {
+		log.Errorf("[CouponHandler-1] GetAllAdmin: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Data not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	for _, result := range results {
+		respCoupons = append(respCoupons, couponEntityToResponse(result))
+	}
+
+	resp.Message = "success"
+	resp.Data = respCoupons
+	resp.Pagination = &response.Pagination{
+		Page:       page,
+		TotalCount: totalData,
+		PerPage:    perPage,
+		TotalPage:  totalPage,
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetByIDAdmin implements [ICouponHandler].
+func (ch *couponHandler) GetByIDAdmin(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[CouponHandler-1] GetByIDAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	result, err := ch.couponService.GetByID(ctx, id)
+	if err != nil {
+		log.Errorf("[CouponHandler-2] GetByIDAdmin: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Data not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = couponEntityToResponse(*result)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CreateAdmin implements [ICouponHandler].
+func (ch *couponHandler) CreateAdmin(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		req  = request.CouponRequest{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[CouponHandler-1] CreateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Validate(req); err != nil {
+		log.Errorf("[CouponHandler-2] CreateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	err := ch.couponService.Create(ctx, couponRequestToEntity(req))
+	if err != nil {
+		log.Errorf("[CouponHandler-3] CreateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// GenerateAdmin implements [ICouponHandler].
+func (ch *couponHandler) GenerateAdmin(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		req  = request.CouponBulkRequest{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[CouponHandler-1] GenerateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Validate(req); err != nil {
+		log.Errorf("[CouponHandler-2] GenerateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	length := req.Length
+	if length == 0 {
+		length = 8
+	}
+
+	reqEntity := entities.CouponBulkEntity{
+		Prefix:   req.Prefix,
+		Quantity: req.Quantity,
+		Length:   length,
+		Coupon: entities.CouponEntity{
+			Name:             req.Name,
+			DiscountType:     req.DiscountType,
+			Value:            float64(req.Value),
+			MaxDiscount:      float64(req.MaxDiscount),
+			MinSpend:         float64(req.MinSpend),
+			UsageLimit:       req.UsageLimit,
+			PerCustomerLimit: req.PerCustomerLimit,
+			Status:           req.Status,
+			StartAt:          req.StartAt,
+			EndAt:            req.EndAt,
+		},
+	}
+
+	codes, err := ch.couponService.GenerateBulk(ctx, reqEntity)
+	if err != nil {
+		log.Errorf("[CouponHandler-3] GenerateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = response.CouponBulkResponse{Codes: codes}
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// UpdateAdmin implements [ICouponHandler].
+func (ch *couponHandler) UpdateAdmin(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		req  = request.CouponRequest{}
+	)
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[CouponHandler-1] UpdateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[CouponHandler-2] UpdateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Validate(req); err != nil {
+		log.Errorf("[CouponHandler-3] UpdateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	reqEntity := couponRequestToEntity(req)
+	reqEntity.ID = id
+
+	err = ch.couponService.Update(ctx, reqEntity)
+	if err != nil {
+		log.Errorf("[CouponHandler-4] UpdateAdmin: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Coupon not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+// DeleteAdmin implements [ICouponHandler].
+func (ch *couponHandler) DeleteAdmin(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[CouponHandler-1] DeleteAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	err = ch.couponService.Delete(ctx, id)
+	if err != nil {
+		log.Errorf("[CouponHandler-2] DeleteAdmin: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Coupon not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetRedemptionsAdmin implements [ICouponHandler].
+func (ch *couponHandler) GetRedemptionsAdmin(c echo.Context) error {
+	var (
+		resp            = response.DefaultResponse{}
+		ctx             = c.Request().Context()
+		respRedemptions = []response.CouponRedemptionResponse{}
+	)
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[CouponHandler-1] GetRedemptionsAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	results, err := ch.couponService.GetRedemptions(ctx, id)
+	if err != nil {
+		log.Errorf("[CouponHandler-2] GetRedemptionsAdmin: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Coupon not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	for _, result := range results {
+		respRedemptions = append(respRedemptions, couponRedemptionEntityToResponse(result))
+	}
+
+	resp.Message = "success"
+	resp.Data = respRedemptions
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ApplyToCart implements [ICouponHandler].
+func (ch *couponHandler) ApplyToCart(c echo.Context) error {
+	var (
+		resp        = response.DefaultResponse{}
+		ctx         = c.Request().Context()
+		req         = request.ApplyCouponRequest{}
+		jwtUserData = entities.JwtUserData{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[CouponHandler-1] ApplyToCart: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Validate(req); err != nil {
+		log.Errorf("[CouponHandler-2] ApplyToCart: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	user := c.Get("user").(string)
+	if user == "" {
+		log.Errorf("[CouponHandler-3] ApplyToCart: %s", "data token not found")
+		resp.Message = "data token not found"
+		resp.Data = nil
+		return c.JSON(http.StatusNotFound, resp)
+	}
+
+	err := json.Unmarshal([]byte(user), &jwtUserData)
+	if err != nil {
+		log.Errorf("[CouponHandler-4] ApplyToCart: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	result, err := ch.couponService.ApplyToCart(ctx, jwtUserData.UserID, entities.CustomerSegmentCustomer, req.Code)
+	if err != nil {
+		log.Errorf("[CouponHandler-5] ApplyToCart: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Coupon not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = response.CartCouponResponse{
+		Code:     result.Code,
+		Subtotal: int64(result.Subtotal),
+		Discount: int64(result.Discount),
+		Total:    int64(result.Total),
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// RemoveFromCart implements [ICouponHandler].
+func (ch *couponHandler) RemoveFromCart(c echo.Context) error {
+	var (
+		resp        = response.DefaultResponse{}
+		ctx         = c.Request().Context()
+		jwtUserData = entities.JwtUserData{}
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		log.Errorf("[CouponHandler-1] RemoveFromCart: %s", "data token not found")
+		resp.Message = "data token not found"
+		resp.Data = nil
+		return c.JSON(http.StatusNotFound, resp)
+	}
+
+	err := json.Unmarshal([]byte(user), &jwtUserData)
+	if err != nil {
+		log.Errorf("[CouponHandler-2] RemoveFromCart: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	err = ch.couponService.RemoveFromCart(ctx, jwtUserData.UserID)
+	if err != nil {
+		log.Errorf("[CouponHandler-3] RemoveFromCart: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+// Redeem implements [ICouponHandler]. It is called by order-service only,
+// while the order is being created.
+func (ch *couponHandler) Redeem(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		req  = request.RedeemCouponRequest{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[CouponHandler-1] Redeem: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Validate(req); err != nil {
+		log.Errorf("[CouponHandler-2] Redeem: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	result, err := ch.couponService.Redeem(ctx, req.BuyerID, req.Code, req.OrderCode, req.Subtotal)
+	if err != nil {
+		log.Errorf("[CouponHandler-3] Redeem: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Coupon not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = couponRedemptionEntityToResponse(*result)
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// ReleaseRedemption implements [ICouponHandler]. order-service calls it when
+// the order the coupon was redeemed for could not be saved.
+func (ch *couponHandler) ReleaseRedemption(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	buyerID, err := conv.StringToInt64(c.QueryParam("buyer_id"))
+	if err != nil || buyerID <= 0 {
+		log.Errorf("[CouponHandler-1] ReleaseRedemption: invalid buyer_id %q", c.QueryParam("buyer_id"))
+		resp.Message = "invalid buyer_id"
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	err = ch.couponService.ReleaseRedemption(ctx, buyerID, c.Param("orderCode"))
+	if err != nil {
+		log.Errorf("[CouponHandler-2] ReleaseRedemption: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Redemption not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+func couponRequestToEntity(req request.CouponRequest) entities.CouponEntity {
+	return entities.CouponEntity{
+		Code:             req.Code,
+		Name:             req.Name,
+		DiscountType:     req.DiscountType,
+		Value:            float64(req.Value),
+		MaxDiscount:      float64(req.MaxDiscount),
+		MinSpend:         float64(req.MinSpend),
+		UsageLimit:       req.UsageLimit,
+		PerCustomerLimit: req.PerCustomerLimit,
+		Status:           req.Status,
+		StartAt:          req.StartAt,
+		EndAt:            req.EndAt,
+	}
+}
+
+func couponEntityToResponse(result entities.CouponEntity) response.CouponResponse {
+	return response.CouponResponse{
+		ID:               result.ID,
+		Code:             result.Code,
+		Name:             result.Name,
+		DiscountType:     result.DiscountType,
+		Value:            int64(result.Value),
+		MaxDiscount:      int64(result.MaxDiscount),
+		MinSpend:         int64(result.MinSpend),
+		UsageLimit:       result.UsageLimit,
+		PerCustomerLimit: result.PerCustomerLimit,
+		UsedCount:        result.UsedCount,
+		Status:           result.Status,
+		StartAt:          result.StartAt,
+		EndAt:            result.EndAt,
+	}
+}
+
+func couponRedemptionEntityToResponse(result entities.CouponRedemptionEntity) response.CouponRedemptionResponse {
+	return response.CouponRedemptionResponse{
+		ID:        result.ID,
+		Code:      result.Code,
+		UserID:    result.UserID,
+		OrderCode: result.OrderCode,
+		Discount:  int64(result.Discount),
+		CreatedAt: result.CreatedAt,
+	}
+}
+
+func NewCouponHandler(e *echo.Echo, cfg *config.Config, couponService service.ICouponService) ICouponHandler {
+	couponHandler := &couponHandler{
+		couponService: couponService,
+	}
+
+	mid := adapter.NewMiddlewareAdapter(cfg)
+	adminGroup := e.Group("/admin", mid.CheckToken())
+	adminGroup.GET("/coupons", couponHandler.GetAllAdmin)
+	adminGroup.POST("/coupons", couponHandler.CreateAdmin)
+	adminGroup.POST("/coupons/generate", couponHandler.GenerateAdmin)
+	adminGroup.GET("/coupons/:id", couponHandler.GetByIDAdmin)
+	adminGroup.PUT("/coupons/:id", couponHandler.UpdateAdmin)
+	adminGroup.DELETE("/coupons/:id", couponHandler.DeleteAdmin)
+	adminGroup.GET("/coupons/:id/redemptions", couponHandler.GetRedemptionsAdmin)
+
+	authGroup := e.Group("/auth", mid.CheckToken())
+	authGroup.POST("/cart/coupon", couponHandler.ApplyToCart)
+	authGroup.DELETE("/cart/coupon", couponHandler.RemoveFromCart)
+
+	// only order-service redeems and releases coupons, never the customer
+	internalGroup := e.Group("/internal", mid.CheckServiceKey())
+	internalGroup.POST("/coupons/redeem", couponHandler.Redeem)
+	internalGroup.DELETE("/coupons/redeem/:orderCode", couponHandler.ReleaseRedemption)
+
+	return couponHandler
+}
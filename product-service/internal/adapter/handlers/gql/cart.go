@@ -0,0 +1,75 @@
+package gql
+
+import (
+	"context"
+	"product-service/internal/core/domain/entities"
+
+	"github.com/labstack/gommon/log"
+)
+
+type cartOwnerKey struct{}
+
+// WithCartOwner makes Query.cart resolve the cart of owner.
+func WithCartOwner(ctx context.Context, owner entities.CartOwner) context.Context {
+	return context.WithValue(ctx, cartOwnerKey{}, owner)
+}
+
+type cartResolver struct {
+	c entities.CartEntity
+}
+
+func (r *cartResolver) Items() []*cartLineResolver {
+	results := []*cartLineResolver{}
+	for _, line := range r.c.Items {
+		results = append(results, &cartLineResolver{l: line})
+	}
+
+	return results
+}
+
+func (r *cartResolver) TotalQuantity() int32 { return int32(r.c.TotalQuantity) }
+func (r *cartResolver) TotalWeight() int32   { return int32(r.c.TotalWeight) }
+func (r *cartResolver) Subtotal() float64    { return r.c.Subtotal }
+func (r *cartResolver) HasWarnings() bool    { return r.c.HasWarnings }
+func (r *cartResolver) HasEstimated() bool   { return r.c.HasEstimated }
+
+type cartLineResolver struct {
+	l entities.CartLineEntity
+}
+
+// Product resolves CartLine.product. Products that are no longer sold are
+// still shown, so the customer sees what the unavailable line was.
+func (r *cartLineResolver) Product(ctx context.Context) (*productResolver, error) {
+	product, err := loadersFrom(ctx).products.Load(ctx, r.l.ProductID)
+	if err != nil {
+		log.Errorf("[GraphQL-1] CartLine.product: %v", err)
+		return nil, err
+	}
+
+	if product == nil {
+		return nil, nil
+	}
+	return &productResolver{p: *product}, nil
+}
+
+func (r *cartLineResolver) Quantity() int32    { return int32(r.l.Quantity) }
+func (r *cartLineResolver) UnitPrice() float64 { return r.l.UnitPrice }
+func (r *cartLineResolver) LineTotal() float64 { return r.l.LineTotal }
+func (r *cartLineResolver) LineWeight() int32  { return int32(r.l.LineWeight) }
+func (r *cartLineResolver) Available() bool    { return r.l.Available }
+
+func (r *cartLineResolver) Warnings() []*cartWarningResolver {
+	results := []*cartWarningResolver{}
+	for _, warning := range r.l.Warnings {
+		results = append(results, &cartWarningResolver{w: warning})
+	}
+
+	return results
+}
+
+type cartWarningResolver struct {
+	w entities.CartWarningEntity
+}
+
+func (r *cartWarningResolver) Code() string    { return r.w.Code }
+func (r *cartWarningResolver) Message() string { return r.w.Message }
@@ -0,0 +1,76 @@
+package gql
+
+import (
+	"context"
+	"fmt"
+	"product-service/internal/core/domain/entities"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/labstack/gommon/log"
+)
+
+type categoryResolver struct {
+	c entities.CategoryEntity
+}
+
+func (r *categoryResolver) ID() graphql.ID      { return formatID(r.c.ID) }
+func (r *categoryResolver) Name() string        { return r.c.Name }
+func (r *categoryResolver) Slug() string        { return r.c.Slug }
+func (r *categoryResolver) Icon() string        { return r.c.Icon }
+func (r *categoryResolver) Description() string { return r.c.Description }
+
+// Parent resolves Category.parent.
+func (r *categoryResolver) Parent(ctx context.Context) (*categoryResolver, error) {
+	if r.c.ParentID == nil {
+		return nil, nil
+	}
+
+	parent, err := loadersFrom(ctx).categoriesByID.Load(ctx, *r.c.ParentID)
+	if err != nil {
+		log.Errorf("[GraphQL-1] Category.parent: %v", err)
+		return nil, err
+	}
+
+	if parent == nil {
+		return nil, nil
+	}
+	return &categoryResolver{c: *parent}, nil
+}
+
+// Children resolves Category.children.
+func (r *categoryResolver) Children(ctx context.Context) ([]*categoryResolver, error) {
+	children, err := loadersFrom(ctx).subcategories.Load(ctx, r.c.ID)
+	if err != nil {
+		log.Errorf("[GraphQL-1] Category.children: %v", err)
+		return nil, err
+	}
+
+	results := []*categoryResolver{}
+	for _, child := range children {
+		results = append(results, &categoryResolver{c: child})
+	}
+
+	return results, nil
+}
+
+// Products resolves Category.products. The lists of all categories in a
+// query are searched in one Elasticsearch request.
+func (r *categoryResolver) Products(ctx context.Context, args struct{ Limit int32 }) ([]*productResolver, error) {
+	if args.Limit < 1 || args.Limit > maxListSize {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxListSize)
+	}
+
+	key := categoryProductsKey{Slug: r.c.Slug, Limit: int(args.Limit)}
+	products, err := loadersFrom(ctx).categoryProducts.Load(ctx, key)
+	if err != nil {
+		log.Errorf("[GraphQL-1] Category.products: %v", err)
+		return nil, err
+	}
+
+	results := []*productResolver{}
+	for _, product := range products {
+		results = append(results, &productResolver{p: product})
+	}
+
+	return results, nil
+}
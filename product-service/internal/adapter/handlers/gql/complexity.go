@@ -0,0 +1,123 @@
+package gql
+
+import (
+	"math"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// defaultListSize is what a list without a limit is assumed to hold.
+const defaultListSize = 10
+
+// maxCost is where complexity stops counting, so that deep queries cannot
+// overflow it.
+const maxCost = math.MaxInt32
+
+// complexity estimates the work of the operation operationName: every field
+// costs 1 and the selections of a list count once per item. A list holds the
+// limit asked for, the number of ids, or defaultListSize. The limit of a
+// field that is not a list, like search, applies to the lists below it.
+// Queries that do not validate cost 0 so their errors come from Exec.
+func complexity(schema *ast.Schema, query string, operationName string, variables map[string]interface{}) int {
+	doc, errs := gqlparser.LoadQueryWithRules(schema, query, nil)
+	if len(errs) > 0 {
+		return 0
+	}
+
+	operation := doc.Operations.ForName(operationName)
+	if operation == nil {
+		return 0
+	}
+
+	return selectionComplexity(operation.SelectionSet, variables, 0, map[string]bool{})
+}
+
+func selectionComplexity(selections ast.SelectionSet, variables map[string]interface{}, limit int, visiting map[string]bool) int {
+	total := 0
+	for _, selection := range selections {
+		switch sel := selection.(type) {
+		case *ast.Field:
+			total += fieldComplexity(sel, variables, limit, visiting)
+		case *ast.InlineFragment:
+			total += selectionComplexity(sel.SelectionSet, variables, limit, visiting)
+		case *ast.FragmentSpread:
+			if sel.Definition == nil || visiting[sel.Name] {
+				continue
+			}
+			visiting[sel.Name] = true
+			total += selectionComplexity(sel.Definition.SelectionSet, variables, limit, visiting)
+			delete(visiting, sel.Name)
+		}
+		total = min(total, maxCost)
+	}
+
+	return total
+}
+
+func fieldComplexity(field *ast.Field, variables map[string]interface{}, limit int, visiting map[string]bool) int {
+	if field.Definition == nil || len(field.SelectionSet) == 0 {
+		return 1
+	}
+
+	ownLimit := intArgument(field, "limit", variables)
+	if field.Definition.Type.Elem == nil {
+		return min(1+selectionComplexity(field.SelectionSet, variables, ownLimit, visiting), maxCost)
+	}
+
+	size := defaultListSize
+	switch {
+	case ownLimit > 0:
+		size = ownLimit
+	case listArgumentLen(field, "ids", variables) > 0:
+		size = listArgumentLen(field, "ids", variables)
+	case limit > 0:
+		size = limit
+	}
+
+	children := selectionComplexity(field.SelectionSet, variables, 0, visiting)
+	if children >= maxCost/size {
+		return maxCost
+	}
+	return 1 + size*children
+}
+
+// argumentValue is the value of the argument name, or its default.
+func argumentValue(field *ast.Field, name string, variables map[string]interface{}) interface{} {
+	value := (*ast.Value)(nil)
+	if argument := field.Arguments.ForName(name); argument != nil {
+		value = argument.Value
+	} else if definition := field.Definition.Arguments.ForName(name); definition != nil {
+		value = definition.DefaultValue
+	}
+	if value == nil {
+		return nil
+	}
+
+	result, err := value.Value(variables)
+	if err != nil {
+		return nil
+	}
+	return result
+}
+
+func intArgument(field *ast.Field, name string, variables map[string]interface{}) int {
+	switch value := argumentValue(field, name, variables).(type) {
+	case int64:
+		return int(value)
+	case float64:
+		return int(value)
+	case int:
+		return value
+	}
+
+	return 0
+}
+
+func listArgumentLen(field *ast.Field, name string, variables map[string]interface{}) int {
+	if value, ok := argumentValue(field, name, variables).([]interface{}); ok {
+		return len(value)
+	}
+
+	return 0
+}
@@ -0,0 +1,64 @@
+package gql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestComplexity(t *testing.T) {
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: schemaSource})
+	if err != nil {
+		t.Fatalf("LoadSchema() error = %v", err)
+	}
+
+	deepVariants := "{ product(id: 1) { " + strings.Repeat("variants { ", 10) + "id" + strings.Repeat(" }", 10) + " } }"
+
+	tests := []struct {
+		name          string
+		query         string
+		operationName string
+		variables     map[string]interface{}
+		want          int
+	}{
+		{name: "fields cost one each", query: `{ product(id: 1) { id name } }`, want: 3},
+		{name: "a list without a limit", query: `{ categories { name } }`, want: 11},
+		{name: "a list of ids", query: `{ products(ids: [1, 2, 3]) { id name } }`, want: 7},
+		{
+			name:      "ids from variables",
+			query:     `query Products($ids: [ID!]!) { products(ids: $ids) { id } }`,
+			variables: map[string]interface{}{"ids": []interface{}{"1", "2", "3", "4"}},
+			want:      5,
+		},
+		{name: "the search limit sizes its items", query: `{ search(limit: 20) { items { id } page } }`, want: 23},
+		{name: "the default search limit", query: `{ search { items { id } } }`, want: 12},
+		{
+			name:      "a limit from variables",
+			query:     `query Search($limit: Int) { search(limit: $limit) { items { id } } }`,
+			variables: map[string]interface{}{"limit": float64(50)},
+			want:      52,
+		},
+		{name: "nested lists multiply", query: `{ categories { products(limit: 5) { id variants { id } } } }`, want: 611},
+		{name: "fragments", query: `{ product(id: 1) { ...card } } fragment card on Product { id name }`, want: 3},
+		{name: "inline fragments", query: `{ product(id: 1) { ... on Product { id } } }`, want: 2},
+		{
+			name:          "the named operation only",
+			query:         `query One { product(id: 1) { id } } query Many { categories { name } }`,
+			operationName: "Many",
+			want:          11,
+		},
+		{name: "stops counting at the cap", query: deepVariants, want: maxCost},
+		{name: "invalid query", query: `{ product(id: 1) { unknown } }`, want: 0},
+		{name: "unknown operation", query: `query One { product(id: 1) { id } }`, operationName: "Two", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := complexity(schema, tt.query, tt.operationName, tt.variables); got != tt.want {
+				t.Errorf("complexity() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package gql
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// loaderWait is how long a loader collects keys before it fetches them. The
+// fields of a list are resolved concurrently, so they land in one batch.
+const loaderWait = 2 * time.Millisecond
+
+// loaderMaxBatch caps the keys of one fetch; a full batch is fetched at once.
+const loaderMaxBatch = 100
+
+// Loader batches and caches lookups by key for one request. Keys asked for
+// within loaderWait of each other are fetched together, and every key is
+// fetched at most once.
+type Loader[K comparable, V any] struct {
+	ctx   context.Context
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	results map[K]*loaderResult[V]
+	batch   *loaderBatch[K, V]
+}
+
+type loaderResult[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type loaderBatch[K comparable, V any] struct {
+	keys    []K
+	results []*loaderResult[V]
+	timer   *time.Timer
+}
+
+// NewLoader returns a loader whose fetch runs with ctx, the request context.
+// fetch leaves keys it does not find out of the map; they load as the zero
+// value.
+func NewLoader[K comparable, V any](ctx context.Context, fetch func(ctx context.Context, keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{
+		ctx:     ctx,
+		fetch:   fetch,
+		results: map[K]*loaderResult[V]{},
+	}
+}
+
+// Load returns the value of key, waiting for the batch it is part of.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	result, ok := l.results[key]
+	if !ok {
+		result = &loaderResult[V]{done: make(chan struct{})}
+		l.results[key] = result
+		l.add(key, result)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-result.done:
+		return result.value, result.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// LoadMany loads the keys in one batch and returns the values found, in the
+// order of keys.
+func (l *Loader[K, V]) LoadMany(ctx context.Context, keys []K) ([]V, error) {
+	type loaded struct {
+		value V
+		err   error
+	}
+
+	out := make([]loaded, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out[i].value, out[i].err = l.Load(ctx, key)
+		}()
+	}
+	wg.Wait()
+
+	values := make([]V, 0, len(keys))
+	for _, val := range out {
+		if val.err != nil {
+			return nil, val.err
+		}
+		values = append(values, val.value)
+	}
+
+	return values, nil
+}
+
+// add puts key in the open batch, starting one if needed. l.mu is held.
+func (l *Loader[K, V]) add(key K, result *loaderResult[V]) {
+	if l.batch == nil {
+		batch := &loaderBatch[K, V]{}
+		batch.timer = time.AfterFunc(loaderWait, func() { l.dispatch(batch) })
+		l.batch = batch
+	}
+
+	l.batch.keys = append(l.batch.keys, key)
+	l.batch.results = append(l.batch.results, result)
+
+	if len(l.batch.keys) >= loaderMaxBatch {
+		batch := l.batch
+		l.batch = nil
+		if batch.timer.Stop() {
+			go l.dispatch(batch)
+		}
+	}
+}
+
+// dispatch fetches the keys of batch and hands out the results.
+func (l *Loader[K, V]) dispatch(batch *loaderBatch[K, V]) {
+	l.mu.Lock()
+	if l.batch == batch {
+		l.batch = nil
+	}
+	keys := batch.keys
+	l.mu.Unlock()
+
+	values, err := l.fetch(l.ctx, keys)
+	for i, key := range keys {
+		result := batch.results[i]
+		result.value, result.err = values[key], err
+		close(result.done)
+	}
+}
@@ -0,0 +1,136 @@
+package gql
+
+import (
+	"context"
+	"product-service/internal/core/domain/entities"
+)
+
+// categoryProductsKey asks for the first Limit products of a category.
+type categoryProductsKey struct {
+	Slug  string
+	Limit int
+}
+
+// loaders are the batching lookups of one request. Products, variants,
+// images and categories come from Postgres, category product lists from
+// Elasticsearch in one multi search.
+type loaders struct {
+	products         *Loader[int64, *entities.ProductEntity]
+	variants         *Loader[int64, []entities.ProductEntity]
+	images           *Loader[int64, []entities.ProductImageEntity]
+	categoriesBySlug *Loader[string, *entities.CategoryEntity]
+	categoriesByID   *Loader[int64, *entities.CategoryEntity]
+	subcategories    *Loader[int64, []entities.CategoryEntity]
+	categoryProducts *Loader[categoryProductsKey, []entities.ProductEntity]
+}
+
+func (r *Resolver) newLoaders(ctx context.Context) *loaders {
+	return &loaders{
+		products: NewLoader(ctx, func(ctx context.Context, ids []int64) (map[int64]*entities.ProductEntity, error) {
+			results, err := r.productService.GetByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+
+			products := map[int64]*entities.ProductEntity{}
+			for i := range results {
+				products[results[i].ID] = &results[i]
+			}
+			return products, nil
+		}),
+		variants: NewLoader(ctx, func(ctx context.Context, parentIDs []int64) (map[int64][]entities.ProductEntity, error) {
+			results, err := r.productService.GetVariants(ctx, parentIDs)
+			if err != nil {
+				return nil, err
+			}
+
+			variants := map[int64][]entities.ProductEntity{}
+			for _, result := range results {
+				variants[*result.ParentID] = append(variants[*result.ParentID], result)
+			}
+			return variants, nil
+		}),
+		images: NewLoader(ctx, func(ctx context.Context, productIDs []int64) (map[int64][]entities.ProductImageEntity, error) {
+			results, err := r.productImageService.GetByProductIDs(ctx, productIDs)
+			if err != nil {
+				return nil, err
+			}
+
+			images := map[int64][]entities.ProductImageEntity{}
+			for _, result := range results {
+				images[result.ProductID] = append(images[result.ProductID], result)
+			}
+			return images, nil
+		}),
+		categoriesBySlug: NewLoader(ctx, func(ctx context.Context, slugs []string) (map[string]*entities.CategoryEntity, error) {
+			results, err := r.categoryService.GetPublishedBySlugs(ctx, slugs)
+			if err != nil {
+				return nil, err
+			}
+
+			categories := map[string]*entities.CategoryEntity{}
+			for i := range results {
+				categories[results[i].Slug] = &results[i]
+			}
+			return categories, nil
+		}),
+		categoriesByID: NewLoader(ctx, func(ctx context.Context, ids []int64) (map[int64]*entities.CategoryEntity, error) {
+			results, err := r.categoryService.GetPublishedByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+
+			categories := map[int64]*entities.CategoryEntity{}
+			for i := range results {
+				categories[results[i].ID] = &results[i]
+			}
+			return categories, nil
+		}),
+		subcategories: NewLoader(ctx, func(ctx context.Context, parentIDs []int64) (map[int64][]entities.CategoryEntity, error) {
+			results, err := r.categoryService.GetPublishedByParentIDs(ctx, parentIDs)
+			if err != nil {
+				return nil, err
+			}
+
+			subcategories := map[int64][]entities.CategoryEntity{}
+			for _, result := range results {
+				subcategories[*result.ParentID] = append(subcategories[*result.ParentID], result)
+			}
+			return subcategories, nil
+		}),
+		categoryProducts: NewLoader(ctx, func(ctx context.Context, keys []categoryProductsKey) (map[categoryProductsKey][]entities.ProductEntity, error) {
+			queries := []entities.QueryStringProduct{}
+			for _, key := range keys {
+				queries = append(queries, entities.QueryStringProduct{
+					CategorySlug: key.Slug,
+					OrderBy:      "id",
+					OrderType:    "desc",
+					Page:         1,
+					Limit:        key.Limit,
+					Status:       entities.PublishedStatus,
+				})
+			}
+
+			results, err := r.productService.SearchProductsMulti(ctx, queries)
+			if err != nil {
+				return nil, err
+			}
+
+			products := map[categoryProductsKey][]entities.ProductEntity{}
+			for i, key := range keys {
+				products[key] = results[i].Products
+			}
+			return products, nil
+		}),
+	}
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
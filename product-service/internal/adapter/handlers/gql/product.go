@@ -0,0 +1,129 @@
+package gql
+
+import (
+	"context"
+	"product-service/internal/core/domain/entities"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/labstack/gommon/log"
+)
+
+type productResolver struct {
+	p entities.ProductEntity
+}
+
+// newPublishedProduct hides products customers may not see, like the REST
+// product detail does.
+func newPublishedProduct(product *entities.ProductEntity) *productResolver {
+	if product == nil || !product.IsPublished() {
+		return nil
+	}
+
+	return &productResolver{p: *product}
+}
+
+func (r *productResolver) ID() graphql.ID        { return formatID(r.p.ID) }
+func (r *productResolver) SKU() string           { return r.p.SKU }
+func (r *productResolver) Name() string          { return r.p.Name }
+func (r *productResolver) Description() string   { return r.p.Description }
+func (r *productResolver) Image() string         { return r.p.Image }
+func (r *productResolver) Type() string          { return r.p.Type }
+func (r *productResolver) Unit() string          { return r.p.Unit }
+func (r *productResolver) Weight() int32         { return int32(r.p.Weight) }
+func (r *productResolver) Stock() int32          { return int32(r.p.Stock) }
+func (r *productResolver) RegulerPrice() float64 { return r.p.RegulerPrice }
+func (r *productResolver) SalePrice() float64    { return r.p.SalePrice }
+func (r *productResolver) Price() float64        { return r.p.UnitPrice() }
+func (r *productResolver) Rating() float64       { return r.p.Rating }
+func (r *productResolver) RatingCount() int32    { return int32(r.p.RatingCount) }
+
+func (r *productResolver) Selling() *sellingResolver {
+	return &sellingResolver{s: r.p.Selling}
+}
+
+// Images resolves Product.images.
+func (r *productResolver) Images(ctx context.Context) ([]*productImageResolver, error) {
+	images, err := loadersFrom(ctx).images.Load(ctx, r.p.ID)
+	if err != nil {
+		log.Errorf("[GraphQL-1] Product.images: %v", err)
+		return nil, err
+	}
+
+	results := []*productImageResolver{}
+	for _, image := range images {
+		results = append(results, &productImageResolver{i: image})
+	}
+
+	return results, nil
+}
+
+// Category resolves Product.category.
+func (r *productResolver) Category(ctx context.Context) (*categoryResolver, error) {
+	if r.p.CategorySlug == "" {
+		return nil, nil
+	}
+
+	category, err := loadersFrom(ctx).categoriesBySlug.Load(ctx, r.p.CategorySlug)
+	if err != nil {
+		log.Errorf("[GraphQL-1] Product.category: %v", err)
+		return nil, err
+	}
+
+	if category == nil {
+		return nil, nil
+	}
+	return &categoryResolver{c: *category}, nil
+}
+
+// Parent resolves Product.parent, set for variants.
+func (r *productResolver) Parent(ctx context.Context) (*productResolver, error) {
+	if r.p.ParentID == nil {
+		return nil, nil
+	}
+
+	parent, err := loadersFrom(ctx).products.Load(ctx, *r.p.ParentID)
+	if err != nil {
+		log.Errorf("[GraphQL-1] Product.parent: %v", err)
+		return nil, err
+	}
+
+	return newPublishedProduct(parent), nil
+}
+
+// Variants resolves Product.variants.
+func (r *productResolver) Variants(ctx context.Context) ([]*productResolver, error) {
+	variants, err := loadersFrom(ctx).variants.Load(ctx, r.p.ID)
+	if err != nil {
+		log.Errorf("[GraphQL-1] Product.variants: %v", err)
+		return nil, err
+	}
+
+	results := []*productResolver{}
+	for i := range variants {
+		if resolver := newPublishedProduct(&variants[i]); resolver != nil {
+			results = append(results, resolver)
+		}
+	}
+
+	return results, nil
+}
+
+type productImageResolver struct {
+	i entities.ProductImageEntity
+}
+
+func (r *productImageResolver) ID() graphql.ID  { return formatID(r.i.ID) }
+func (r *productImageResolver) URL() string     { return r.i.ImageURL }
+func (r *productImageResolver) AltText() string { return r.i.AltText }
+func (r *productImageResolver) IsPrimary() bool { return r.i.IsPrimary }
+
+type sellingResolver struct {
+	s entities.SellingEntity
+}
+
+func (r *sellingResolver) SellBy() string       { return r.s.SellBy }
+func (r *sellingResolver) PriceUnit() string    { return r.s.PriceUnit }
+func (r *sellingResolver) QuantityUnit() string { return r.s.QuantityUnit }
+func (r *sellingResolver) QuantityStep() int32  { return int32(r.s.Step()) }
+func (r *sellingResolver) MinQuantity() int32   { return int32(r.s.MinQuantity) }
+func (r *sellingResolver) MaxQuantity() int32   { return int32(r.s.MaxQuantity) }
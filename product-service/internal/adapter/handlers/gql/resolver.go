@@ -0,0 +1,145 @@
+package gql
+
+import (
+	"context"
+	"fmt"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/service"
+	"strconv"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/labstack/gommon/log"
+)
+
+// maxListSize caps the products a single field returns: the ids of products,
+// the limit of search and of category products.
+const maxListSize = 50
+
+// Resolver resolves the Query type. Queries only read; unlike the REST
+// product detail, a product query does not count as a view.
+type Resolver struct {
+	productService      service.IProductService
+	productImageService service.IProductImageService
+	categoryService     service.ICategoryService
+	cartService         service.ICartService
+}
+
+func NewResolver(productService service.IProductService, productImageService service.IProductImageService, categoryService service.ICategoryService, cartService service.ICartService) *Resolver {
+	return &Resolver{
+		productService:      productService,
+		productImageService: productImageService,
+		categoryService:     categoryService,
+		cartService:         cartService,
+	}
+}
+
+// Product resolves Query.product.
+func (r *Resolver) Product(ctx context.Context, args struct{ ID graphql.ID }) (*productResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := loadersFrom(ctx).products.Load(ctx, id)
+	if err != nil {
+		log.Errorf("[GraphQL-1] Product: %v", err)
+		return nil, err
+	}
+
+	return newPublishedProduct(product), nil
+}
+
+// Products resolves Query.products.
+func (r *Resolver) Products(ctx context.Context, args struct{ IDs []graphql.ID }) ([]*productResolver, error) {
+	if len(args.IDs) > maxListSize {
+		return nil, fmt.Errorf("at most %d ids can be asked for at once", maxListSize)
+	}
+
+	ids := []int64{}
+	for _, val := range args.IDs {
+		id, err := parseID(val)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	products, err := loadersFrom(ctx).products.LoadMany(ctx, ids)
+	if err != nil {
+		log.Errorf("[GraphQL-1] Products: %v", err)
+		return nil, err
+	}
+
+	results := []*productResolver{}
+	for _, product := range products {
+		if resolver := newPublishedProduct(product); resolver != nil {
+			results = append(results, resolver)
+		}
+	}
+
+	return results, nil
+}
+
+// Categories resolves Query.categories.
+func (r *Resolver) Categories(ctx context.Context) ([]*categoryResolver, error) {
+	categories, err := r.categoryService.GetAllPublished(ctx)
+	if err != nil {
+		if err.Error() == "404" {
+			return []*categoryResolver{}, nil
+		}
+		log.Errorf("[GraphQL-1] Categories: %v", err)
+		return nil, err
+	}
+
+	results := []*categoryResolver{}
+	for _, category := range categories {
+		if category.ParentID == nil {
+			results = append(results, &categoryResolver{c: category})
+		}
+	}
+
+	return results, nil
+}
+
+// Category resolves Query.category.
+func (r *Resolver) Category(ctx context.Context, args struct{ Slug string }) (*categoryResolver, error) {
+	category, err := loadersFrom(ctx).categoriesBySlug.Load(ctx, args.Slug)
+	if err != nil {
+		log.Errorf("[GraphQL-1] Category: %v", err)
+		return nil, err
+	}
+
+	if category == nil {
+		return nil, nil
+	}
+	return &categoryResolver{c: *category}, nil
+}
+
+// Cart resolves Query.cart.
+func (r *Resolver) Cart(ctx context.Context) (*cartResolver, error) {
+	owner, ok := ctx.Value(cartOwnerKey{}).(entities.CartOwner)
+	if !ok {
+		return nil, nil
+	}
+
+	cart, err := r.cartService.GetCartDetail(ctx, owner)
+	if err != nil {
+		log.Errorf("[GraphQL-1] Cart: %v", err)
+		return nil, err
+	}
+
+	return &cartResolver{c: *cart}, nil
+}
+
+func parseID(id graphql.ID) (int64, error) {
+	value, err := strconv.ParseInt(string(id), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid id %q", id)
+	}
+
+	return value, nil
+}
+
+func formatID(id int64) graphql.ID {
+	return graphql.ID(strconv.FormatInt(id, 10))
+}
@@ -0,0 +1,57 @@
+package gql
+
+import (
+	"context"
+	_ "embed"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+//go:embed schema.graphql
+var schemaSource string
+
+// Schema is the executable catalog schema with its query limits.
+type Schema struct {
+	schema        *graphql.Schema
+	ast           *ast.Schema
+	resolver      *Resolver
+	maxComplexity int
+}
+
+func NewSchema(resolver *Resolver, maxDepth, maxComplexity int) (*Schema, error) {
+	schema, err := graphql.ParseSchema(schemaSource, resolver,
+		graphql.MaxDepth(maxDepth),
+		graphql.MaxParallelism(maxListSize),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	astSchema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: schemaSource})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Schema{
+		schema:        schema,
+		ast:           astSchema,
+		resolver:      resolver,
+		maxComplexity: maxComplexity,
+	}, nil
+}
+
+// Exec runs a query with loaders of its own, refusing it when its
+// complexity is above the limit.
+func (s *Schema) Exec(ctx context.Context, query, operationName string, variables map[string]interface{}) *graphql.Response {
+	if cost := complexity(s.ast, query, operationName, variables); cost > s.maxComplexity {
+		return &graphql.Response{Errors: []*gqlerrors.QueryError{
+			gqlerrors.Errorf("query complexity %d exceeds the limit of %d", cost, s.maxComplexity),
+		}}
+	}
+
+	ctx = withLoaders(ctx, s.resolver.newLoaders(ctx))
+	return s.schema.Exec(ctx, query, operationName, variables)
+}
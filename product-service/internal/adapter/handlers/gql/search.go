@@ -0,0 +1,188 @@
+package gql
+
+import (
+	"context"
+	"fmt"
+	"product-service/internal/core/domain/entities"
+
+	"github.com/labstack/gommon/log"
+)
+
+type productFilterInput struct {
+	Search     *string
+	Categories *[]string
+	Units      *[]string
+	MinPrice   *int32
+	MaxPrice   *int32
+	InStock    *bool
+	OnSale     *bool
+	MinRating  *float64
+	Attributes *[]attributeFilterInput
+}
+
+type attributeFilterInput struct {
+	Code   string
+	Values []string
+}
+
+type searchArgs struct {
+	Filter *productFilterInput
+	Sort   string
+	Page   int32
+	Limit  int32
+}
+
+// productSorts maps ProductSort onto the sort of the REST shop listing.
+var productSorts = map[string][2]string{
+	"NEWEST":     {"id", "desc"},
+	"PRICE_ASC":  {"reguler_price", "asc"},
+	"PRICE_DESC": {"reguler_price", "desc"},
+}
+
+// Search resolves Query.search.
+func (r *Resolver) Search(ctx context.Context, args searchArgs) (*productSearchResolver, error) {
+	if args.Limit < 1 || args.Limit > maxListSize {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxListSize)
+	}
+	if args.Page < 1 {
+		return nil, fmt.Errorf("page must be at least 1")
+	}
+
+	sort := productSorts[args.Sort]
+	query := entities.QueryStringProduct{
+		OrderBy:    sort[0],
+		OrderType:  sort[1],
+		Page:       int(args.Page),
+		Limit:      int(args.Limit),
+		Status:     entities.PublishedStatus,
+		Attributes: map[string][]string{},
+	}
+
+	if filter := args.Filter; filter != nil {
+		if filter.Search != nil {
+			query.Search = *filter.Search
+		}
+		if filter.Categories != nil {
+			query.CategorySlugs = *filter.Categories
+		}
+		if filter.Units != nil {
+			query.Units = *filter.Units
+		}
+		if filter.MinPrice != nil {
+			query.StartPrice = int64(*filter.MinPrice)
+		}
+		if filter.MaxPrice != nil {
+			query.EndPrice = int64(*filter.MaxPrice)
+		}
+		if filter.InStock != nil {
+			query.InStock = *filter.InStock
+		}
+		if filter.OnSale != nil {
+			query.OnSale = *filter.OnSale
+		}
+		if filter.MinRating != nil {
+			query.MinRating = *filter.MinRating
+		}
+		if filter.Attributes != nil {
+			for _, attribute := range *filter.Attributes {
+				query.Attributes[attribute.Code] = append(query.Attributes[attribute.Code], attribute.Values...)
+			}
+		}
+	}
+
+	result, err := r.productService.SearchProducts(ctx, query)
+	if err != nil {
+		log.Errorf("[GraphQL-1] Search: %v", err)
+		return nil, err
+	}
+
+	return &productSearchResolver{s: *result, page: args.Page}, nil
+}
+
+type productSearchResolver struct {
+	s    entities.ProductSearchEntity
+	page int32
+}
+
+func (r *productSearchResolver) Items() []*productResolver {
+	results := []*productResolver{}
+	for _, product := range r.s.Products {
+		results = append(results, &productResolver{p: product})
+	}
+
+	return results
+}
+
+func (r *productSearchResolver) Page() int32       { return r.page }
+func (r *productSearchResolver) TotalCount() int32 { return int32(r.s.TotalData) }
+func (r *productSearchResolver) TotalPage() int32  { return int32(r.s.TotalPage) }
+
+func (r *productSearchResolver) Facets() *productFacetsResolver {
+	return &productFacetsResolver{f: r.s.Facets}
+}
+
+type productFacetsResolver struct {
+	f entities.ProductFacetsEntity
+}
+
+func (r *productFacetsResolver) Categories() []*facetBucketResolver {
+	return newFacetBuckets(r.f.Categories)
+}
+func (r *productFacetsResolver) Units() []*facetBucketResolver  { return newFacetBuckets(r.f.Units) }
+func (r *productFacetsResolver) Prices() []*facetRangeResolver  { return newFacetRanges(r.f.Prices) }
+func (r *productFacetsResolver) Weights() []*facetRangeResolver { return newFacetRanges(r.f.Weights) }
+func (r *productFacetsResolver) Ratings() []*facetRangeResolver { return newFacetRanges(r.f.Ratings) }
+func (r *productFacetsResolver) OnSale() int32                  { return int32(r.f.OnSale) }
+func (r *productFacetsResolver) InStock() int32                 { return int32(r.f.InStock) }
+
+func (r *productFacetsResolver) Attributes() []*attributeFacetResolver {
+	results := []*attributeFacetResolver{}
+	for _, attribute := range r.f.Attributes {
+		results = append(results, &attributeFacetResolver{a: attribute})
+	}
+
+	return results
+}
+
+type facetBucketResolver struct {
+	b entities.FacetBucketEntity
+}
+
+func newFacetBuckets(buckets []entities.FacetBucketEntity) []*facetBucketResolver {
+	results := []*facetBucketResolver{}
+	for _, bucket := range buckets {
+		results = append(results, &facetBucketResolver{b: bucket})
+	}
+
+	return results
+}
+
+func (r *facetBucketResolver) Key() string   { return r.b.Key }
+func (r *facetBucketResolver) Label() string { return r.b.Label }
+func (r *facetBucketResolver) Count() int32  { return int32(r.b.Count) }
+
+type facetRangeResolver struct {
+	f entities.FacetRangeEntity
+}
+
+func newFacetRanges(ranges []entities.FacetRangeEntity) []*facetRangeResolver {
+	results := []*facetRangeResolver{}
+	for _, val := range ranges {
+		results = append(results, &facetRangeResolver{f: val})
+	}
+
+	return results
+}
+
+func (r *facetRangeResolver) Key() string    { return r.f.Key }
+func (r *facetRangeResolver) From() *float64 { return r.f.From }
+func (r *facetRangeResolver) To() *float64   { return r.f.To }
+func (r *facetRangeResolver) Count() int32   { return int32(r.f.Count) }
+
+type attributeFacetResolver struct {
+	a entities.AttributeFacetEntity
+}
+
+func (r *attributeFacetResolver) Code() string                   { return r.a.Code }
+func (r *attributeFacetResolver) Label() string                  { return r.a.Label }
+func (r *attributeFacetResolver) Values() []*facetBucketResolver { return newFacetBuckets(r.a.Values) }
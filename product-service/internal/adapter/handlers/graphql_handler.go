@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-service/config"
+	"product-service/internal/adapter"
+	"product-service/internal/adapter/handlers/gql"
+	"product-service/internal/adapter/handlers/response"
+	"product-service/internal/core/service"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+type IGraphQLHandler interface {
+	Query(c echo.Context) error
+}
+
+type graphQLHandler struct {
+	schema *gql.Schema
+	cart   *CartHandler
+}
+
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Query implements [IGraphQLHandler]. POST takes the query as JSON, GET as
+// the query, operationName and variables query params. The cart query
+// reads the cart of the signed in customer or of the guest cart.
+func (g *graphQLHandler) Query(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		req  = graphQLRequest{}
+	)
+
+	if c.Request().Method == http.MethodGet {
+		req.Query = c.QueryParam("query")
+		req.OperationName = c.QueryParam("operationName")
+		if variables := c.QueryParam("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				log.Errorf("[GraphQLHandler-1] Query: %v", err)
+				resp.Message = err.Error()
+				resp.Data = nil
+				return c.JSON(http.StatusBadRequest, resp)
+			}
+		}
+	} else if err := c.Bind(&req); err != nil {
+		log.Errorf("[GraphQLHandler-2] Query: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if req.Query == "" {
+		log.Errorf("[GraphQLHandler-3] Query: %s", "query is required")
+		resp.Message = "query is required"
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	// a missing or invalid cart id only leaves the cart null, the rest of
+	// the query still runs; queries never merge a guest cart
+	owner, err := g.cart.cartOwner(c, false)
+	if err == nil {
+		ctx = gql.WithCartOwner(ctx, owner)
+	} else if !errors.Is(err, errNoGuestCart) {
+		log.Infof("[GraphQLHandler-4] Query: %v", err)
+	}
+
+	return c.JSON(http.StatusOK, g.schema.Exec(ctx, req.Query, req.OperationName, req.Variables))
+}
+
+func NewGraphQLHandler(e *echo.Echo, cfg *config.Config, schema *gql.Schema, cartService service.ICartService) IGraphQLHandler {
+	graphQLHandler := &graphQLHandler{
+		schema: schema,
+		cart: &CartHandler{
+			cartService: cartService,
+			cfg:         cfg,
+		},
+	}
+
+	mid := adapter.NewMiddlewareAdapter(cfg)
+	e.GET("/graphql", graphQLHandler.Query, mid.OptionalToken())
+	e.POST("/graphql", graphQLHandler.Query, mid.OptionalToken())
+
+	return graphQLHandler
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"net/http"
+	"product-service/config"
+	"product-service/internal/adapter"
+	"product-service/internal/adapter/handlers/request"
+	"product-service/internal/adapter/handlers/response"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/service"
+	"product-service/utils/conv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+type IPriceHandler interface {
+	GetPriceLists(c echo.Context) error
+	CreatePriceList(c echo.Context) error
+	DeletePriceList(c echo.Context) error
+	GetPriceHistory(c echo.Context) error
+}
+
+type priceHandler struct {
+	priceService service.IPriceService
+}
+
+// GetPriceLists implements [IPriceHandler].
+func (p *priceHandler) GetPriceLists(c echo.Context) error {
+	var (
+		resp      = response.DefaultResponse{}
+		ctx       = c.Request().Context()
+		respLists = []response.PriceListResponse{}
+	)
+
+	productID, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[PriceHandler-1] GetPriceLists: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	results, err := p.priceService.GetPriceLists(ctx, productID)
+	if err != nil {
+		log.Errorf("[PriceHandler-2] GetPriceLists: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	for _, result := range results {
+		respLists = append(respLists, response.PriceListResponse{
+			ID:        result.ID,
+			Name:      result.Name,
+			SalePrice: int64(result.SalePrice),
+			StartAt:   result.StartAt,
+			EndAt:     result.EndAt,
+		})
+	}
+
+	resp.Message = "success"
+	resp.Data = respLists
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CreatePriceList implements [IPriceHandler].
+func (p *priceHandler) CreatePriceList(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		req  = request.PriceListRequest{}
+	)
+
+	productID, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[PriceHandler-1] CreatePriceList: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[PriceHandler-2] CreatePriceList: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Validate(req); err != nil {
+		log.Errorf("[PriceHandler-3] CreatePriceList: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	reqEntity := entities.PriceListEntity{
+		ProductID: productID,
+		Name:      req.Name,
+		SalePrice: float64(req.SalePrice),
+		StartAt:   req.StartAt,
+		EndAt:     req.EndAt,
+	}
+
+	err = p.priceService.CreatePriceList(ctx, reqEntity)
+	if err != nil {
+		log.Errorf("[PriceHandler-4] CreatePriceList: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Product not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// DeletePriceList implements [IPriceHandler].
+func (p *priceHandler) DeletePriceList(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	productID, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[PriceHandler-1] DeletePriceList: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	priceListID, err := conv.StringToInt64(c.Param("priceId"))
+	if err != nil {
+		log.Errorf("[PriceHandler-2] DeletePriceList: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	err = p.priceService.DeletePriceList(ctx, productID, priceListID)
+	if err != nil {
+		log.Errorf("[PriceHandler-3] DeletePriceList: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Price list not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetPriceHistory implements [IPriceHandler].
+func (p *priceHandler) GetPriceHistory(c echo.Context) error {
+	var (
+		resp        = response.DefaultResponse{}
+		ctx         = c.Request().Context()
+		respHistory = []response.PriceHistoryResponse{}
+	)
+
+	productID, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[PriceHandler-1] GetPriceHistory: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	var at *time.Time
+	if atStr := c.QueryParam("at"); atStr != "" {
+		parsed, err := time.Parse(time.RFC3339, atStr)
+		if err != nil {
+			log.Errorf("[PriceHandler-2] GetPriceHistory: %v", err)
+			resp.Message = "at must be an RFC3339 timestamp"
+			resp.Data = nil
+			return c.JSON(http.StatusBadRequest, resp)
+		}
+		at = &parsed
+	}
+
+	results, err := p.priceService.GetPriceHistory(ctx, productID, at)
+	if err != nil {
+		log.Errorf("[PriceHandler-3] GetPriceHistory: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	for _, result := range results {
+		respHistory = append(respHistory, response.PriceHistoryResponse{
+			ID:           result.ID,
+			PriceListID:  result.PriceListID,
+			RegulerPrice: int64(result.RegulerPrice),
+			SalePrice:    int64(result.SalePrice),
+			Source:       result.Source,
+			CreatedAt:    result.CreatedAt,
+		})
+	}
+
+	resp.Message = "success"
+	resp.Data = respHistory
+	return c.JSON(http.StatusOK, resp)
+}
+
+func NewPriceHandler(e *echo.Echo, cfg *config.Config, priceService service.IPriceService) IPriceHandler {
+	priceHandler := &priceHandler{
+		priceService: priceService,
+	}
+
+	mid := adapter.NewMiddlewareAdapter(cfg)
+	adminGroup := e.Group("/admin", mid.CheckToken())
+	adminGroup.GET("/products/:id/prices", priceHandler.GetPriceLists)
+	adminGroup.POST("/products/:id/prices", priceHandler.CreatePriceList)
+	adminGroup.DELETE("/products/:id/prices/:priceId", priceHandler.DeletePriceList)
+	adminGroup.GET("/products/:id/price-history", priceHandler.GetPriceHistory)
+
+	return priceHandler
+}
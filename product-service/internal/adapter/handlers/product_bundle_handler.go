@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"product-service/internal/adapter/handlers/request"
+	"product-service/internal/adapter/handlers/response"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/service"
+	"product-service/utils/conv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+// GetBundleAdmin implements [IProductHandler].
+func (p *productHandler) GetBundleAdmin(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[ProductHandler-1] GetBundleAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	result, err := p.productService.GetBundle(ctx, id)
+	if err != nil {
+		log.Errorf("[ProductHandler-2] GetBundleAdmin: %v", err)
+		return productBundleError(c, err)
+	}
+
+	resp.Message = "success"
+	resp.Data = toProductBundleResponse(result.Bundle)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// SetBundleAdmin implements [IProductHandler]. It turns the product into a
+// bundle or replaces the components of one.
+func (p *productHandler) SetBundleAdmin(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		req  = request.ProductBundleRequest{}
+	)
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[ProductHandler-1] SetBundleAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[ProductHandler-2] SetBundleAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Validate(req); err != nil {
+		log.Errorf("[ProductHandler-3] SetBundleAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	reqEntity := entities.BundleEntity{
+		Pricing:         req.Pricing,
+		DiscountPercent: req.DiscountPercent,
+		Items:           []entities.BundleItemEntity{},
+	}
+	for _, item := range req.Items {
+		reqEntity.Items = append(reqEntity.Items, entities.BundleItemEntity{
+			ComponentID: item.ProductID,
+			Quantity:    item.Quantity,
+		})
+	}
+
+	result, err := p.productService.SetBundle(ctx, id, reqEntity)
+	if err != nil {
+		log.Errorf("[ProductHandler-4] SetBundleAdmin: %v", err)
+		return productBundleError(c, err)
+	}
+
+	resp.Message = "success"
+	resp.Data = toProductBundleResponse(result.Bundle)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// RemoveBundleAdmin implements [IProductHandler]. The product stays as a
+// simple product with its last stock and price.
+func (p *productHandler) RemoveBundleAdmin(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[ProductHandler-1] RemoveBundleAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := p.productService.RemoveBundle(ctx, id); err != nil {
+		log.Errorf("[ProductHandler-2] RemoveBundleAdmin: %v", err)
+		return productBundleError(c, err)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+func productBundleError(c echo.Context, err error) error {
+	resp := response.DefaultResponse{Message: err.Error()}
+	switch {
+	case err.Error() == "404":
+		resp.Message = "Bundle not found"
+		return c.JSON(http.StatusNotFound, resp)
+	case errors.Is(err, service.ErrBundleInUse):
+		return c.JSON(http.StatusConflict, resp)
+	case errors.Is(err, service.ErrInvalidBundlePricing),
+		errors.Is(err, service.ErrInvalidBundleDiscount),
+		errors.Is(err, service.ErrBundleEmpty),
+		errors.Is(err, service.ErrInvalidBundleQuantity),
+		errors.Is(err, service.ErrBundleComponent),
+		errors.Is(err, service.ErrBundleVariant),
+		errors.Is(err, service.ErrInvalidSelling):
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+	return c.JSON(http.StatusInternalServerError, resp)
+}
+
+func toProductBundleResponse(bundle *entities.BundleEntity) *response.ProductBundleResponse {
+	if bundle == nil {
+		return nil
+	}
+
+	respBundle := &response.ProductBundleResponse{
+		Pricing:         bundle.Pricing,
+		DiscountPercent: bundle.DiscountPercent,
+		Items:           []response.ProductBundleItemResponse{},
+	}
+	for _, item := range bundle.Items {
+		respBundle.Items = append(respBundle.Items, response.ProductBundleItemResponse{
+			ProductID:    item.ComponentID,
+			ProductName:  item.Name,
+			ProductImage: item.Image,
+			Quantity:     item.Quantity,
+			Unit:         item.Unit,
+			Weight:       item.Weight,
+			RegulerPrice: int64(item.RegulerPrice),
+			SalePrice:    int64(item.SalePrice),
+			Stock:        item.Stock,
+			Available:    item.Available,
+		})
+	}
+
+	return respBundle
+}
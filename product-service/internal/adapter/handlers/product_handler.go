@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
 	"product-service/config"
 	"product-service/internal/adapter"
@@ -9,6 +11,8 @@ import (
 	"product-service/internal/core/domain/entities"
 	"product-service/internal/core/service"
 	"product-service/utils/conv"
+	"product-service/utils/esquery"
+	"strconv"
 	"strings"
 
 	"github.com/labstack/echo/v4"
@@ -23,6 +27,11 @@ type IProductHandler interface {
 	GetByIDAdmin(c echo.Context) error
 	EditAdmin(c echo.Context) error
 	DeleteAdmin(c echo.Context) error
+	ChangeStatusAdmin(c echo.Context) error
+	ApproveAdmin(c echo.Context) error
+	GetBundleAdmin(c echo.Context) error
+	SetBundleAdmin(c echo.Context) error
+	RemoveBundleAdmin(c echo.Context) error
 
 	GetAllHome(c echo.Context) error
 	GetAllShop(c echo.Context) error
@@ -32,14 +41,15 @@ type IProductHandler interface {
 
 // struct
 type productHandler struct {
-	productService service.IProductService
+	productService        service.IProductService
+	recommendationService service.IRecommendationService
 }
 
 func (p *productHandler) GetByIDs(c echo.Context) error {
 	var (
 		resp      = response.DefaultResponse{}
 		ctx       = c.Request().Context()
-		respLists = []response.ProductHomeListResponse{}
+		respLists = []response.ProductBulkResponse{}
 	)
 
 	idsStr := c.QueryParam("ids")
@@ -66,7 +76,7 @@ func (p *productHandler) GetByIDs(c echo.Context) error {
 		log.Errorf("[ProductHandler-3] GetByIDs: %v", err)
 		if err.Error() == "404" {
 			resp.Message = "No products found for the given IDs"
-			resp.Data = []response.ProductHomeListResponse{}
+			resp.Data = []response.ProductBulkResponse{}
 			return c.JSON(http.StatusNotFound, resp)
 		}
 		resp.Message = "internal server error"
@@ -74,13 +84,17 @@ func (p *productHandler) GetByIDs(c echo.Context) error {
 	}
 
 	for _, result := range results {
-		respLists = append(respLists, response.ProductHomeListResponse{
+		respLists = append(respLists, response.ProductBulkResponse{
 			ID:           result.ID,
 			ProductName:  result.Name,
 			ProductImage: result.Image,
 			SalePrice:    int64(result.SalePrice),
 			RegulerPrice: int64(result.RegulerPrice),
 			CategoryName: result.CategoryName,
+			Unit:         result.Unit,
+			Weight:       result.Weight,
+			Stock:        result.Stock,
+			Selling:      toProductSellingResponse(result.Selling),
 		})
 	}
 
@@ -112,7 +126,7 @@ func (p *productHandler) GetDetailHome(c echo.Context) error {
 		resp.Data = nil
 		return c.JSON(http.StatusBadRequest, resp)
 	}
-	result, err := p.productService.GetByID(ctx, id)
+	result, err := p.productService.GetDetail(ctx, id)
 	if err != nil {
 		log.Errorf("[ProductHandler-3] GetDetailHome: %v", err)
 		if err.Error() == "404" {
@@ -126,6 +140,22 @@ func (p *productHandler) GetDetailHome(c echo.Context) error {
 
 		return c.JSON(http.StatusInternalServerError, resp)
 	}
+
+	// products that are not published stay hidden from customers
+	if !result.IsPublished() {
+		log.Errorf("[ProductHandler-4] GetDetailHome: product %d is %s", result.ID, result.Status)
+		resp.Message = "Data not found"
+		resp.Data = nil
+		return c.JSON(http.StatusNotFound, resp)
+	}
+
+	// a failed view only costs the customer a recommendation
+	if userID := optionalUserID(c); userID != 0 {
+		if err := p.recommendationService.RecordView(ctx, userID, result.ID); err != nil {
+			log.Errorf("[ProductHandler-5] GetDetailHome: %v", err)
+		}
+	}
+
 	respDetail.ID = result.ID
 	respDetail.ProductName = result.Name
 	respDetail.CategoryName = result.CategoryName
@@ -133,6 +163,11 @@ func (p *productHandler) GetDetailHome(c echo.Context) error {
 	respDetail.Unit = result.Unit
 	respDetail.Weight = result.Weight
 	respDetail.Stock = result.Stock
+	respDetail.ProductType = result.Type
+	respDetail.Selling = toProductSellingResponse(result.Selling)
+	respDetail.Rating = result.Rating
+	respDetail.RatingCount = result.RatingCount
+	respDetail.Bundle = toProductBundleResponse(result.Bundle)
 	respDetail.RegulerPrice = int64(result.RegulerPrice)
 	respDetail.SalePrice = int64(result.SalePrice)
 	respDetail.ProductImage = result.Image
@@ -147,6 +182,9 @@ func (p *productHandler) GetDetailHome(c echo.Context) error {
 			Image:        child.Image,
 		})
 	}
+	respDetail.Images = toProductImageResponses(result.Images)
+	respDetail.Breadcrumbs = toBreadcrumbResponses(result.Breadcrumbs)
+	respDetail.Attributes = toProductAttributeResponses(result.Specifications)
 
 	resp.Message = "success"
 	resp.Data = respDetail
@@ -177,6 +215,10 @@ func (p *productHandler) GetAllShop(c echo.Context) error {
 			orderBy = "id"
 			orderType = "desc"
 		}
+		if c.QueryParam("orderBy") == "rating" {
+			orderBy = "rating"
+			orderType = "desc"
+		}
 	}
 	var page int64 = 1
 	if c.QueryParam("page") != "" {
@@ -191,23 +233,62 @@ func (p *productHandler) GetAllShop(c echo.Context) error {
 	if c.QueryParam("price") != "" {
 		price := strings.Split(c.QueryParam("price"), " - ")
 		startPrice, _ = conv.StringToInt64(price[0])
-		endPrice, _ = conv.StringToInt64(price[1])
+		if len(price) > 1 {
+			endPrice, _ = conv.StringToInt64(price[1])
+		}
+	}
+	categorySlugs := []string{}
+	for _, slug := range strings.Split(c.QueryParam("category"), ",") {
+		if slug = strings.TrimSpace(slug); slug != "" {
+			categorySlugs = append(categorySlugs, slug)
+		}
+	}
+	units := []string{}
+	for _, unit := range strings.Split(c.QueryParam("unit"), ",") {
+		if unit = strings.TrimSpace(unit); unit != "" {
+			units = append(units, unit)
+		}
+	}
+	var minRating float64
+	if c.QueryParam("min_rating") != "" {
+		minRating, _ = strconv.ParseFloat(c.QueryParam("min_rating"), 64)
+	}
+	// attribute filters look like attr.origin=lokal,impor
+	attributes := map[string][]string{}
+	for key, params := range c.QueryParams() {
+		code := strings.TrimPrefix(key, "attr.")
+		if code == key || code == "" {
+			continue
+		}
+		for _, param := range params {
+			for _, value := range strings.Split(param, ",") {
+				if value = strings.TrimSpace(value); value != "" {
+					attributes[code] = append(attributes[code], value)
+				}
+			}
+		}
 	}
 	reqEntity := entities.QueryStringProduct{
-		CategorySlug: c.QueryParam("category"),
-		OrderBy:      orderBy,
-		OrderType:    orderType,
-		Page:         int(page),
-		Limit:        int(perPage),
-		StartPrice:   startPrice,
-		EndPrice:     endPrice,
+		CategorySlugs: categorySlugs,
+		Units:         units,
+		OrderBy:       orderBy,
+		OrderType:     orderType,
+		Page:          int(page),
+		Limit:         int(perPage),
+		StartPrice:    startPrice,
+		EndPrice:      endPrice,
+		InStock:       c.QueryParam("in_stock") == "true",
+		OnSale:        c.QueryParam("on_sale") == "true",
+		MinRating:     minRating,
+		Attributes:    attributes,
+		Status:        entities.PublishedStatus,
 	}
 
 	if c.QueryParam("search") != "" {
 		reqEntity.Search = c.QueryParam("search")
 	}
 
-	results, totalData, totalPage, err := p.productService.SearchProducts(ctx, reqEntity)
+	result, err := p.productService.SearchProducts(ctx, reqEntity)
 	if err != nil {
 		log.Errorf("[ProductHandler-1] GetAllHome: %v", err)
 		if err.Error() == "404" {
@@ -216,19 +297,27 @@ func (p *productHandler) GetAllShop(c echo.Context) error {
 			return c.JSON(http.StatusNotFound, resp)
 		}
 
+		if errors.Is(err, esquery.ErrInvalidSort) {
+			resp.Message = err.Error()
+			resp.Data = nil
+			return c.JSON(http.StatusBadRequest, resp)
+		}
+
 		resp.Message = err.Error()
 		resp.Data = nil
 		return c.JSON(http.StatusInternalServerError, resp)
 	}
 
-	for _, result := range results {
+	for _, product := range result.Products {
 		respLists = append(respLists, response.ProductHomeListResponse{
-			ID:           result.ID,
-			ProductName:  result.Name,
-			ProductImage: result.Image,
-			SalePrice:    int64(result.SalePrice),
-			RegulerPrice: int64(result.RegulerPrice),
-			CategoryName: result.CategoryName,
+			ID:           product.ID,
+			ProductName:  product.Name,
+			ProductImage: product.Image,
+			SalePrice:    int64(product.SalePrice),
+			RegulerPrice: int64(product.RegulerPrice),
+			CategoryName: product.CategoryName,
+			Rating:       product.Rating,
+			RatingCount:  product.RatingCount,
 		})
 	}
 
@@ -236,43 +325,40 @@ func (p *productHandler) GetAllShop(c echo.Context) error {
 	resp.Data = respLists
 	resp.Pagination = &response.Pagination{
 		Page:       page,
-		TotalPage:  totalPage,
-		TotalCount: totalData,
+		TotalPage:  result.TotalPage,
+		TotalCount: result.TotalData,
 		PerPage:    perPage,
 	}
+	resp.Facets = productFacetsToResponse(result.Facets)
 	return c.JSON(http.StatusOK, resp)
 
 }
 
-// GetAllHome implements [IProductHandler].
+// GetAllHome implements [IProductHandler]. Signed in customers get a feed
+// built from what they viewed and bought; anonymous visitors get products on
+// promotion and best sellers.
 func (p *productHandler) GetAllHome(c echo.Context) error {
 	var (
 		resp      = response.DefaultResponse{}
 		ctx       = c.Request().Context()
 		respLists = []response.ProductHomeListResponse{}
+		err       error
 	)
 
-	orderBy := "created_at"
-	orderType := "desc"
-	var page int64 = 1
-	var perPage int64 = 5
-
-	reqEntity := entities.QueryStringProduct{
-		OrderBy:   orderBy,
-		OrderType: orderType,
-		Page:      int(page),
-		Limit:     int(perPage),
-	}
-
-	results, _, _, err := p.productService.GetAll(ctx, reqEntity)
-	if err != nil {
-		log.Errorf("[ProductHandler-1] GetAllHome: %v", err)
-		if err.Error() == "404" {
-			resp.Message = "Data not found"
+	limit := 0
+	if c.QueryParam("limit") != "" {
+		limit, err = strconv.Atoi(c.QueryParam("limit"))
+		if err != nil {
+			log.Errorf("[ProductHandler-1] GetAllHome: %v", err)
+			resp.Message = err.Error()
 			resp.Data = nil
-			return c.JSON(http.StatusNotFound, resp)
+			return c.JSON(http.StatusBadRequest, resp)
 		}
+	}
 
+	results, err := p.recommendationService.GetHomeFeed(ctx, optionalUserID(c), limit)
+	if err != nil {
+		log.Errorf("[ProductHandler-2] GetAllHome: %v", err)
 		resp.Message = err.Error()
 		resp.Data = nil
 		return c.JSON(http.StatusInternalServerError, resp)
@@ -280,12 +366,13 @@ func (p *productHandler) GetAllHome(c echo.Context) error {
 
 	for _, result := range results {
 		respLists = append(respLists, response.ProductHomeListResponse{
-			ID:           result.ID,
-			ProductName:  result.Name,
-			ProductImage: result.Image,
-			SalePrice:    int64(result.SalePrice),
-			RegulerPrice: int64(result.RegulerPrice),
-			CategoryName: result.CategoryName,
+			ID:           result.Product.ID,
+			ProductName:  result.Product.Name,
+			ProductImage: result.Product.Image,
+			SalePrice:    int64(result.Product.SalePrice),
+			RegulerPrice: int64(result.Product.RegulerPrice),
+			CategoryName: result.Product.CategoryName,
+			Reason:       result.Reason,
 		})
 	}
 
@@ -408,6 +495,8 @@ func (p *productHandler) EditAdmin(c echo.Context) error {
 		Stock:        req.VariantDetail[0].Stock,
 		Variant:      req.Variant,
 		Status:       req.Status,
+		Attributes:   req.Attributes,
+		Selling:      toSellingEntity(req.Selling),
 	}
 
 	productChilds := []entities.ProductEntity{}
@@ -430,6 +519,13 @@ func (p *productHandler) EditAdmin(c echo.Context) error {
 		log.Errorf("[ProductHandler-4] EditAdmin: %v", err)
 		resp.Message = err.Error()
 		resp.Data = nil
+		if err.Error() == "404" {
+			resp.Message = "Product not found"
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		if isProductStatusError(err) || isProductAttributeError(err) || errors.Is(err, service.ErrBundleVariant) || errors.Is(err, service.ErrInvalidSelling) {
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		}
 		return c.JSON(http.StatusInternalServerError, resp)
 	}
 
@@ -470,7 +566,7 @@ func (p *productHandler) GetByIDAdmin(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, resp)
 	}
 
-	result, err := p.productService.GetByID(ctx, id)
+	result, err := p.productService.GetDetail(ctx, id)
 	if err != nil {
 		log.Errorf("[ProductHandler-4] GetByIDAdmin: %v", err)
 		if err.Error() == "404" {
@@ -504,14 +600,24 @@ func (p *productHandler) GetByIDAdmin(c echo.Context) error {
 		CategorySlug:       result.CategorySlug,
 		CategoryName:       result.CategoryName,
 		ProductStatus:      result.Status,
+		PublishAt:          result.PublishAt,
+		UnpublishAt:        result.UnpublishAt,
+		ApprovedAt:         result.ApprovedAt,
+		ReviewNote:         result.ReviewNote,
 		ProductDescription: result.Description,
 		SalePrice:          int64(result.SalePrice),
 		RegulerPrice:       int64(result.RegulerPrice),
 		Unit:               result.Unit,
 		Weight:             result.Weight,
 		Stock:              result.Stock,
+		ProductType:        result.Type,
+		Selling:            toProductSellingResponse(result.Selling),
+		Bundle:             toProductBundleResponse(result.Bundle),
 		CreatedAt:          result.CreatedAt,
 		Child:              responseChilds,
+		Images:             toProductImageResponses(result.Images),
+		Breadcrumbs:        toBreadcrumbResponses(result.Breadcrumbs),
+		Attributes:         toProductAttributeResponses(result.Specifications),
 	}
 
 	resp.Message = "success"
@@ -562,6 +668,8 @@ func (p *productHandler) CreateAdmin(c echo.Context) error {
 		Stock:        req.VariantDetail[0].Stock,
 		Variant:      req.Variant,
 		Status:       req.Status,
+		Attributes:   req.Attributes,
+		Selling:      toSellingEntity(req.Selling),
 	}
 
 	productChilds := []entities.ProductEntity{}
@@ -584,6 +692,9 @@ func (p *productHandler) CreateAdmin(c echo.Context) error {
 		log.Errorf("[ProductHandler-4] CreateAdmin: %v", err)
 		resp.Message = err.Error()
 		resp.Data = nil
+		if isProductStatusError(err) || isProductAttributeError(err) || errors.Is(err, service.ErrInvalidSelling) {
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		}
 		return c.JSON(http.StatusInternalServerError, resp)
 	}
 
@@ -693,10 +804,243 @@ func (p *productHandler) GetAllAdmin(c echo.Context) error {
 	return c.JSON(http.StatusOK, resp)
 }
 
+// ChangeStatusAdmin implements [IProductHandler]. It makes the manual
+// lifecycle moves: submitting for review, rejecting with a note, cancelling a
+// schedule, archiving and restoring.
+func (p *productHandler) ChangeStatusAdmin(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		req  = request.ProductStatusRequest{}
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		log.Errorf("[ProductHandler-1] ChangeStatusAdmin: %s", "data token not found")
+		resp.Message = "data token not found"
+		resp.Data = nil
+		return c.JSON(http.StatusNotFound, resp)
+	}
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[ProductHandler-2] ChangeStatusAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[ProductHandler-3] ChangeStatusAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Validate(req); err != nil {
+		log.Errorf("[ProductHandler-4] ChangeStatusAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	result, err := p.productService.ChangeStatus(ctx, id, req.Status, req.Note)
+	if err != nil {
+		log.Errorf("[ProductHandler-5] ChangeStatusAdmin: %v", err)
+		return productLifecycleError(c, err)
+	}
+
+	resp.Message = "success"
+	resp.Data = toProductLifecycleResponse(*result)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ApproveAdmin implements [IProductHandler].
+func (p *productHandler) ApproveAdmin(c echo.Context) error {
+	var (
+		resp        = response.DefaultResponse{}
+		ctx         = c.Request().Context()
+		req         = request.ProductApproveRequest{}
+		jwtUserData = entities.JwtUserData{}
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		log.Errorf("[ProductHandler-1] ApproveAdmin: %s", "data token not found")
+		resp.Message = "data token not found"
+		resp.Data = nil
+		return c.JSON(http.StatusNotFound, resp)
+	}
+
+	if err := json.Unmarshal([]byte(user), &jwtUserData); err != nil {
+		log.Errorf("[ProductHandler-2] ApproveAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[ProductHandler-3] ApproveAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[ProductHandler-4] ApproveAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	result, err := p.productService.Approve(ctx, id, jwtUserData.UserID, req.PublishAt, req.UnpublishAt)
+	if err != nil {
+		log.Errorf("[ProductHandler-5] ApproveAdmin: %v", err)
+		return productLifecycleError(c, err)
+	}
+
+	resp.Message = "success"
+	resp.Data = toProductLifecycleResponse(*result)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// toSellingEntity maps the optional selling rules of a request; without
+// them an update keeps the stored rules and a new product is sold by item.
+func toSellingEntity(req *request.ProductSellingRequest) entities.SellingEntity {
+	if req == nil {
+		return entities.SellingEntity{}
+	}
+	return entities.SellingEntity{
+		SellBy:       req.SellBy,
+		PriceUnit:    req.PriceUnit,
+		QuantityUnit: req.QuantityUnit,
+		QuantityStep: req.QuantityStep,
+		MinQuantity:  req.MinQuantity,
+		MaxQuantity:  req.MaxQuantity,
+	}
+}
+
+func toProductSellingResponse(selling entities.SellingEntity) response.ProductSellingResponse {
+	return response.ProductSellingResponse{
+		SellBy:       selling.SellBy,
+		PriceUnit:    selling.PriceUnit,
+		QuantityUnit: selling.QuantityUnit,
+		QuantityStep: selling.Step(),
+		MinQuantity:  selling.MinQuantity,
+		MaxQuantity:  selling.MaxQuantity,
+	}
+}
+
+func isProductStatusError(err error) bool {
+	return errors.Is(err, service.ErrInvalidProductStatus) ||
+		errors.Is(err, service.ErrInvalidInitialStatus) ||
+		errors.Is(err, service.ErrInvalidStatusTransition) ||
+		errors.Is(err, service.ErrVariantStatus) ||
+		errors.Is(err, service.ErrInvalidSchedule)
+}
+
+func productLifecycleError(c echo.Context, err error) error {
+	resp := response.DefaultResponse{Message: err.Error()}
+	switch {
+	case err.Error() == "404":
+		resp.Message = "Product not found"
+		return c.JSON(http.StatusNotFound, resp)
+	case errors.Is(err, service.ErrInvalidStatusTransition):
+		return c.JSON(http.StatusConflict, resp)
+	case isProductStatusError(err):
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+	return c.JSON(http.StatusInternalServerError, resp)
+}
+
+func toProductLifecycleResponse(product entities.ProductEntity) response.ProductLifecycleResponse {
+	return response.ProductLifecycleResponse{
+		ID:            product.ID,
+		ProductStatus: product.Status,
+		PublishAt:     product.PublishAt,
+		UnpublishAt:   product.UnpublishAt,
+		ApprovedBy:    product.ApprovedBy,
+		ApprovedAt:    product.ApprovedAt,
+		ReviewNote:    product.ReviewNote,
+	}
+}
+
+func toProductImageResponses(images []entities.ProductImageEntity) []response.ProductImageResponse {
+	respImages := []response.ProductImageResponse{}
+	for _, image := range images {
+		respImages = append(respImages, response.ProductImageResponse{
+			ID:        image.ID,
+			ImageURL:  image.ImageURL,
+			AltText:   image.AltText,
+			Position:  image.Position,
+			IsPrimary: image.IsPrimary,
+		})
+	}
+
+	return respImages
+}
+
+func toBreadcrumbResponses(breadcrumbs []entities.BreadcrumbEntity) []response.BreadcrumbResponse {
+	respBreadcrumbs := []response.BreadcrumbResponse{}
+	for _, breadcrumb := range breadcrumbs {
+		respBreadcrumbs = append(respBreadcrumbs, response.BreadcrumbResponse{
+			ID:   breadcrumb.ID,
+			Name: breadcrumb.Name,
+			Slug: breadcrumb.Slug,
+		})
+	}
+
+	return respBreadcrumbs
+}
+
 // function
-func NewProductHandler(e *echo.Echo, cfg *config.Config, productService service.IProductService) IProductHandler {
+func productFacetsToResponse(facets entities.ProductFacetsEntity) response.ProductFacetsResponse {
+	resp := response.ProductFacetsResponse{
+		Categories: []response.FacetBucketResponse{},
+		Prices:     []response.FacetRangeResponse{},
+		Units:      []response.FacetBucketResponse{},
+		Weights:    []response.FacetRangeResponse{},
+		Ratings:    []response.FacetRangeResponse{},
+		OnSale:     facets.OnSale,
+		InStock:    facets.InStock,
+		Attributes: []response.AttributeFacetResponse{},
+	}
+
+	for _, val := range facets.Categories {
+		resp.Categories = append(resp.Categories, response.FacetBucketResponse(val))
+	}
+	for _, val := range facets.Prices {
+		resp.Prices = append(resp.Prices, response.FacetRangeResponse(val))
+	}
+	for _, val := range facets.Units {
+		resp.Units = append(resp.Units, response.FacetBucketResponse(val))
+	}
+	for _, val := range facets.Weights {
+		resp.Weights = append(resp.Weights, response.FacetRangeResponse(val))
+	}
+	for _, val := range facets.Ratings {
+		resp.Ratings = append(resp.Ratings, response.FacetRangeResponse(val))
+	}
+	for _, val := range facets.Attributes {
+		attribute := response.AttributeFacetResponse{
+			Code:   val.Code,
+			Label:  val.Label,
+			Values: []response.FacetBucketResponse{},
+		}
+		for _, bucket := range val.Values {
+			attribute.Values = append(attribute.Values, response.FacetBucketResponse(bucket))
+		}
+		resp.Attributes = append(resp.Attributes, attribute)
+	}
+
+	return resp
+}
+
+func NewProductHandler(e *echo.Echo, cfg *config.Config, productService service.IProductService, recommendationService service.IRecommendationService, catalogCacheService service.ICatalogCacheService) IProductHandler {
 	productHandler := &productHandler{
-		productService: productService,
+		productService:        productService,
+		recommendationService: recommendationService,
 	}
 
 	e.Use(middleware.Recover())
@@ -708,12 +1052,17 @@ func NewProductHandler(e *echo.Echo, cfg *config.Config, productService service.
 	adminGroup.GET("/products/:id", productHandler.GetByIDAdmin)
 	adminGroup.PUT("/products/:id", productHandler.EditAdmin)
 	adminGroup.DELETE("/products/:id", productHandler.DeleteAdmin)
+	adminGroup.POST("/products/:id/status", productHandler.ChangeStatusAdmin)
+	adminGroup.POST("/products/:id/approve", productHandler.ApproveAdmin)
+	adminGroup.GET("/products/:id/bundle", productHandler.GetBundleAdmin)
+	adminGroup.PUT("/products/:id/bundle", productHandler.SetBundleAdmin)
+	adminGroup.DELETE("/products/:id/bundle", productHandler.RemoveBundleAdmin)
 
 	homeProduct := e.Group("/products")
-	homeProduct.GET("/home", productHandler.GetAllHome)
-	homeProduct.GET("/shop", productHandler.GetAllShop)
+	homeProduct.GET("/home", productHandler.GetAllHome, mid.OptionalToken(), catalogCached(catalogCacheService, entities.CatalogScopeProducts))
+	homeProduct.GET("/shop", productHandler.GetAllShop, catalogCached(catalogCacheService, entities.CatalogScopeProducts))
 	homeProduct.GET("/home/bulk", productHandler.GetByIDs)
-	homeProduct.GET("/home/:id", productHandler.GetDetailHome)
+	homeProduct.GET("/home/:id", productHandler.GetDetailHome, mid.OptionalToken())
 
 	return productHandler
 }
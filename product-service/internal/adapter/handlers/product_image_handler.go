@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"product-service/config"
+	"product-service/internal/adapter"
+	"product-service/internal/adapter/handlers/request"
+	"product-service/internal/adapter/handlers/response"
+	"product-service/internal/adapter/storage"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/service"
+	"product-service/utils/conv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+type IProductImageHandler interface {
+	GetImages(c echo.Context) error
+	UploadImage(c echo.Context) error
+	ReorderImages(c echo.Context) error
+	SetPrimaryImage(c echo.Context) error
+	DeleteImage(c echo.Context) error
+}
+
+type productImageHandler struct {
+	productImageService service.IProductImageService
+	storageHandler      storage.ISupabase
+}
+
+// GetImages implements [IProductImageHandler].
+func (p *productImageHandler) GetImages(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	productID, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[ProductImageHandler-1] GetImages: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	results, err := p.productImageService.GetByProductID(ctx, productID)
+	if err != nil {
+		log.Errorf("[ProductImageHandler-2] GetImages: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Product not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = toProductImageResponses(results)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// UploadImage implements [IProductImageHandler].
+func (p *productImageHandler) UploadImage(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	productID, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[ProductImageHandler-1] UploadImage: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	file, err := c.FormFile("image")
+	if err != nil {
+		log.Errorf("[ProductImageHandler-2] UploadImage: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		log.Errorf("[ProductImageHandler-3] UploadImage: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+	defer src.Close()
+
+	url, err := storeImage(p.storageHandler, src, file.Filename)
+	if err != nil {
+		log.Errorf("[ProductImageHandler-4] UploadImage: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	reqEntity := entities.ProductImageEntity{
+		ProductID: productID,
+		ImageURL:  url,
+		AltText:   c.FormValue("alt_text"),
+		IsPrimary: c.FormValue("is_primary") == "true",
+	}
+
+	err = p.productImageService.AddImage(ctx, reqEntity)
+	if err != nil {
+		log.Errorf("[ProductImageHandler-5] UploadImage: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Product not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = map[string]string{"image_url": url}
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// ReorderImages implements [IProductImageHandler].
+func (p *productImageHandler) ReorderImages(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		req  = request.ReorderProductImageRequest{}
+	)
+
+	productID, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[ProductImageHandler-1] ReorderImages: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[ProductImageHandler-2] ReorderImages: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Validate(req); err != nil {
+		log.Errorf("[ProductImageHandler-3] ReorderImages: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	err = p.productImageService.Reorder(ctx, productID, req.ImageIDs)
+	if err != nil {
+		log.Errorf("[ProductImageHandler-4] ReorderImages: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Image not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		if errors.Is(err, service.ErrIncompleteImageOrder) {
+			resp.Message = err.Error()
+			resp.Data = nil
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+// SetPrimaryImage implements [IProductImageHandler].
+func (p *productImageHandler) SetPrimaryImage(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	productID, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[ProductImageHandler-1] SetPrimaryImage: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	imageID, err := conv.StringToInt64(c.Param("imageId"))
+	if err != nil {
+		log.Errorf("[ProductImageHandler-2] SetPrimaryImage: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	err = p.productImageService.SetPrimary(ctx, productID, imageID)
+	if err != nil {
+		log.Errorf("[ProductImageHandler-3] SetPrimaryImage: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Image not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+// DeleteImage implements [IProductImageHandler].
+func (p *productImageHandler) DeleteImage(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	productID, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[ProductImageHandler-1] DeleteImage: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	imageID, err := conv.StringToInt64(c.Param("imageId"))
+	if err != nil {
+		log.Errorf("[ProductImageHandler-2] DeleteImage: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	err = p.productImageService.Delete(ctx, productID, imageID)
+	if err != nil {
+		log.Errorf("[ProductImageHandler-3] DeleteImage: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Image not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+func NewProductImageHandler(e *echo.Echo, cfg *config.Config, productImageService service.IProductImageService, storageHandler storage.ISupabase) IProductImageHandler {
+	productImageHandler := &productImageHandler{
+		productImageService: productImageService,
+		storageHandler:      storageHandler,
+	}
+
+	mid := adapter.NewMiddlewareAdapter(cfg)
+	adminGroup := e.Group("/admin", mid.CheckToken())
+	adminGroup.GET("/products/:id/images", productImageHandler.GetImages)
+	adminGroup.POST("/products/:id/images", productImageHandler.UploadImage)
+	adminGroup.PUT("/products/:id/images/reorder", productImageHandler.ReorderImages)
+	adminGroup.PUT("/products/:id/images/:imageId/primary", productImageHandler.SetPrimaryImage)
+	adminGroup.DELETE("/products/:id/images/:imageId", productImageHandler.DeleteImage)
+
+	return productImageHandler
+}
@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"product-service/config"
+	"product-service/internal/adapter"
+	"product-service/internal/adapter/handlers/response"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/service"
+	"product-service/utils/conv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+// maxImportFileSize is the largest import file accepted, 10 MB.
+const maxImportFileSize = 10 << 20
+
+var exportContentTypes = map[string]string{
+	entities.ImportFormatCSV:  "text/csv; charset=utf-8",
+	entities.ImportFormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+type IProductImportHandler interface {
+	Import(c echo.Context) error
+	GetJob(c echo.Context) error
+	GetJobErrors(c echo.Context) error
+	Export(c echo.Context) error
+}
+
+type productImportHandler struct {
+	productImportService service.IProductImportService
+}
+
+// Import implements [IProductImportHandler].
+func (p *productImportHandler) Import(c echo.Context) error {
+	var (
+		resp        = response.DefaultResponse{}
+		ctx         = c.Request().Context()
+		jwtUserData = entities.JwtUserData{}
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		log.Errorf("[ProductImportHandler-1] Import: %s", "data token not found")
+		resp.Message = "data token not found"
+		resp.Data = nil
+		return c.JSON(http.StatusNotFound, resp)
+	}
+
+	err := json.Unmarshal([]byte(user), &jwtUserData)
+	if err != nil {
+		log.Errorf("[ProductImportHandler-2] Import: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		log.Errorf("[ProductImportHandler-3] Import: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	if file.Size > maxImportFileSize {
+		log.Errorf("[ProductImportHandler-4] Import: %s", "file too large")
+		resp.Message = "file may be at most 10 MB"
+		resp.Data = nil
+		return c.JSON(http.StatusRequestEntityTooLarge, resp)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		log.Errorf("[ProductImportHandler-5] Import: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, maxImportFileSize))
+	if err != nil {
+		log.Errorf("[ProductImportHandler-6] Import: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	result, err := p.productImportService.StartImport(ctx, jwtUserData.UserID, file.Filename, data)
+	if err != nil {
+		log.Errorf("[ProductImportHandler-7] Import: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		if errors.Is(err, service.ErrInvalidImportFile) {
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		}
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "import started"
+	resp.Data = importJobEntityToResponse(*result)
+	return c.JSON(http.StatusAccepted, resp)
+}
+
+// GetJob implements [IProductImportHandler].
+func (p *productImportHandler) GetJob(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[ProductImportHandler-1] GetJob: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	result, err := p.productImportService.GetJob(ctx, id)
+	if err != nil {
+		log.Errorf("[ProductImportHandler-2] GetJob: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Import job not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = importJobEntityToResponse(*result)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetJobErrors implements [IProductImportHandler]. It downloads the row
+// errors of a job as a spreadsheet.
+func (p *productImportHandler) GetJobErrors(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		body bytes.Buffer
+	)
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[ProductImportHandler-1] GetJobErrors: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	format := exportFormat(c)
+	if _, ok := exportContentTypes[format]; !ok {
+		log.Errorf("[ProductImportHandler-2] GetJobErrors: %v", service.ErrInvalidFormat)
+		resp.Message = service.ErrInvalidFormat.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	err = p.productImportService.WriteErrorReport(ctx, id, format, &body)
+	if err != nil {
+		log.Errorf("[ProductImportHandler-3] GetJobErrors: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Import job not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	return attachment(c, fmt.Sprintf("import-%d-errors.%s", id, format), format, body.Bytes())
+}
+
+// Export implements [IProductImportHandler].
+func (p *productImportHandler) Export(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		body bytes.Buffer
+	)
+
+	format := exportFormat(c)
+	if _, ok := exportContentTypes[format]; !ok {
+		log.Errorf("[ProductImportHandler-1] Export: %v", service.ErrInvalidFormat)
+		resp.Message = service.ErrInvalidFormat.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	err := p.productImportService.Export(ctx, format, &body)
+	if err != nil {
+		log.Errorf("[ProductImportHandler-2] Export: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	return attachment(c, fmt.Sprintf("products-%s.%s", time.Now().Format("20060102-150405"), format), format, body.Bytes())
+}
+
+func exportFormat(c echo.Context) string {
+	if c.QueryParam("format") == "" {
+		return entities.ImportFormatCSV
+	}
+	return c.QueryParam("format")
+}
+
+func attachment(c echo.Context, fileName, format string, data []byte) error {
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fileName))
+	return c.Blob(http.StatusOK, exportContentTypes[format], data)
+}
+
+func importJobEntityToResponse(job entities.ProductImportJobEntity) response.ProductImportJobResponse {
+	resp := response.ProductImportJobResponse{
+		ID:            job.ID,
+		FileName:      job.FileName,
+		Status:        job.Status,
+		TotalRows:     job.TotalRows,
+		ProcessedRows: job.ProcessedRows,
+		CreatedCount:  job.CreatedCount,
+		UpdatedCount:  job.UpdatedCount,
+		FailedCount:   job.FailedCount,
+		RowErrors:     []response.ProductImportRowErrorResponse{},
+		ErrorMessage:  job.ErrorMessage,
+		CreatedAt:     job.CreatedAt,
+		FinishedAt:    job.FinishedAt,
+	}
+
+	for _, rowError := range job.RowErrors {
+		resp.RowErrors = append(resp.RowErrors, response.ProductImportRowErrorResponse{
+			Row:     rowError.Row,
+			SKU:     rowError.SKU,
+			Field:   rowError.Field,
+			Message: rowError.Message,
+		})
+	}
+
+	return resp
+}
+
+func NewProductImportHandler(e *echo.Echo, cfg *config.Config, productImportService service.IProductImportService) IProductImportHandler {
+	productImportHandler := &productImportHandler{
+		productImportService: productImportService,
+	}
+
+	mid := adapter.NewMiddlewareAdapter(cfg)
+	adminGroup := e.Group("/admin", mid.CheckToken())
+	adminGroup.POST("/products/import", productImportHandler.Import)
+	adminGroup.GET("/products/import/:id", productImportHandler.GetJob)
+	adminGroup.GET("/products/import/:id/errors", productImportHandler.GetJobErrors)
+	adminGroup.GET("/products/export", productImportHandler.Export)
+
+	return productImportHandler
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"product-service/config"
+	"product-service/internal/adapter"
+	"product-service/internal/adapter/handlers/request"
+	"product-service/internal/adapter/handlers/response"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/service"
+	"product-service/utils/conv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+type IProductRatingHandler interface {
+	Rate(c echo.Context) error
+}
+
+type productRatingHandler struct {
+	productRatingService service.IProductRatingService
+}
+
+// Rate implements [IProductRatingHandler]. Rating a product again replaces
+// the stars the customer gave before.
+func (ph *productRatingHandler) Rate(c echo.Context) error {
+	var (
+		resp        = response.DefaultResponse{}
+		ctx         = c.Request().Context()
+		req         = request.ProductRatingRequest{}
+		jwtUserData = entities.JwtUserData{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[ProductRatingHandler-1] Rate: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Validate(req); err != nil {
+		log.Errorf("[ProductRatingHandler-2] Rate: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	user := c.Get("user").(string)
+	if user == "" {
+		log.Errorf("[ProductRatingHandler-3] Rate: %s", "data token not found")
+		resp.Message = "data token not found"
+		resp.Data = nil
+		return c.JSON(http.StatusNotFound, resp)
+	}
+
+	err := json.Unmarshal([]byte(user), &jwtUserData)
+	if err != nil {
+		log.Errorf("[ProductRatingHandler-4] Rate: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	productID, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[ProductRatingHandler-5] Rate: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	result, err := ph.productRatingService.Rate(ctx, productID, jwtUserData.UserID, req.Rating)
+	if err != nil {
+		log.Errorf("[ProductRatingHandler-6] Rate: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Product not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = response.ProductRatingResponse(*result)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func NewProductRatingHandler(e *echo.Echo, cfg *config.Config, productRatingService service.IProductRatingService) IProductRatingHandler {
+	productRatingHandler := &productRatingHandler{
+		productRatingService: productRatingService,
+	}
+
+	mid := adapter.NewMiddlewareAdapter(cfg)
+	authGroup := e.Group("/auth", mid.CheckToken())
+	authGroup.PUT("/products/:id/rating", productRatingHandler.Rate)
+
+	return productRatingHandler
+}
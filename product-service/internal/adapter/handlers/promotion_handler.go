@@ -0,0 +1,435 @@
+package handlers
+
+import (
+	"net/http"
+	"product-service/config"
+	"product-service/internal/adapter"
+	"product-service/internal/adapter/handlers/request"
+	"product-service/internal/adapter/handlers/response"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/service"
+	"product-service/utils/conv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+type IPromotionHandler interface {
+	GetAllAdmin(c echo.Context) error
+	GetByIDAdmin(c echo.Context) error
+	CreateAdmin(c echo.Context) error
+	UpdateAdmin(c echo.Context) error
+	DeleteAdmin(c echo.Context) error
+
+	PriceCart(c echo.Context) error
+}
+
+type promotionHandler struct {
+	promotionService service.IPromotionService
+}
+
+// GetAllAdmin implements [IPromotionHandler].
+func (p *promotionHandler) GetAllAdmin(c echo.Context) error {
+	var (
+		resp           = response.DefaultResponseWithPaginations{}
+		ctx            = c.Request().Context()
+		respPromotions = []response.PromotionResponse{}
+	)
+
+	orderBy := "created_at"
+	if c.QueryParam("order_by") != "" {
+		orderBy = c.QueryParam("order_by")
+	}
+	orderType := "desc"
+	if c.QueryParam("order_type") != "" {
+		orderType = c.QueryParam("order_type")
+	}
+
+	var page int64 = 1
+	if pageStr := c.QueryParam("page"); pageStr != "" {
+		page, _ = conv.StringToInt64(pageStr)
+		if page <= 0 {
+			page = 1
+		}
+	}
+
+	var perPage int64 = 10
+	if perPageStr := c.QueryParam("perPage"); perPageStr != "" {
+		perPage, _ = conv.StringToInt64(perPageStr)
+		if perPage <= 0 {
+			perPage = 10
+		}
+	}
+
+	reqEntity := entities.QueryStringEntity{
+		Search:    c.QueryParam("search"),
+		OrderBy:   orderBy,
+		OrderType: orderType,
+		Page:      int(page),
+		Limit:     int(perPage),
+	}
+
+	results, totalData, totalPage, err := p.promotionService.GetAll(ctx, reqEntity)
+	if err != nil {
+		log.Errorf("[PromotionHandler-1] GetAllAdmin: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Data not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	for _, result := range results {
+		respPromotions = append(respPromotions, promotionEntityToResponse(result))
+	}
+
+	resp.Message = "success"
+	resp.Data = respPromotions
+	resp.Pagination = &response.Pagination{
+		Page:       page,
+		TotalCount: totalData,
+		PerPage:    perPage,
+		TotalPage:  totalPage,
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetByIDAdmin implements [IPromotionHandler].
+func (p *promotionHandler) GetByIDAdmin(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[PromotionHandler-1] GetByIDAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	result, err := p.promotionService.GetByID(ctx, id)
+	if err != nil {
+		log.Errorf("[PromotionHandler-2] GetByIDAdmin: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Data not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = promotionEntityToResponse(*result)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CreateAdmin implements [IPromotionHandler].
+func (p *promotionHandler) CreateAdmin(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		req  = request.PromotionRequest{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[PromotionHandler-1] CreateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Validate(req); err != nil {
+		log.Errorf("[PromotionHandler-2] CreateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	err := p.promotionService.Create(ctx, promotionRequestToEntity(req))
+	if err != nil {
+		log.Errorf("[PromotionHandler-3] CreateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// UpdateAdmin implements [IPromotionHandler].
+func (p *promotionHandler) UpdateAdmin(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		req  = request.PromotionRequest{}
+	)
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[PromotionHandler-1] UpdateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[PromotionHandler-2] UpdateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Validate(req); err != nil {
+		log.Errorf("[PromotionHandler-3] UpdateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	reqEntity := promotionRequestToEntity(req)
+	reqEntity.ID = id
+
+	err = p.promotionService.Update(ctx, reqEntity)
+	if err != nil {
+		log.Errorf("[PromotionHandler-4] UpdateAdmin: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Promotion not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+// DeleteAdmin implements [IPromotionHandler].
+func (p *promotionHandler) DeleteAdmin(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[PromotionHandler-1] DeleteAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	err = p.promotionService.Delete(ctx, id)
+	if err != nil {
+		log.Errorf("[PromotionHandler-2] DeleteAdmin: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Promotion not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+// PriceCart implements [IPromotionHandler]. A signed-in customer also gets
+// the promotions of their segment; order-service prices orders here with the
+// customer's token.
+func (p *promotionHandler) PriceCart(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		req  = request.CartPriceRequest{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[PromotionHandler-1] PriceCart: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Validate(req); err != nil {
+		log.Errorf("[PromotionHandler-2] PriceCart: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	reqEntity := entities.CartPriceRequestEntity{
+		CustomerSegment: customerSegment(c),
+		ShippingFee:     float64(req.ShippingFee),
+	}
+	for _, item := range req.Items {
+		reqEntity.Items = append(reqEntity.Items, entities.CartItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	result, err := p.promotionService.PriceCart(ctx, reqEntity)
+	if err != nil {
+		log.Errorf("[PromotionHandler-3] PriceCart: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Product not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = cartPriceEntityToResponse(*result)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// customerSegment is the segment promotions are matched against: whether
+// the request is signed in or comes from a guest.
+func customerSegment(c echo.Context) string {
+	user, ok := c.Get("user").(string)
+	if !ok || user == "" {
+		return entities.CustomerSegmentGuest
+	}
+
+	return entities.CustomerSegmentCustomer
+}
+
+func promotionRequestToEntity(req request.PromotionRequest) entities.PromotionEntity {
+	tiers := []entities.PromotionTierEntity{}
+	for _, tier := range req.Tiers {
+		tiers = append(tiers, entities.PromotionTierEntity{
+			MinQuantity: tier.MinQuantity,
+			Value:       float64(tier.Value),
+		})
+	}
+
+	return entities.PromotionEntity{
+		Name:            req.Name,
+		Description:     req.Description,
+		Type:            req.Type,
+		Value:           float64(req.Value),
+		BuyQuantity:     req.BuyQuantity,
+		GetQuantity:     req.GetQuantity,
+		CategorySlug:    req.CategorySlug,
+		ProductID:       req.ProductID,
+		MinQuantity:     req.MinQuantity,
+		MinSubtotal:     float64(req.MinSubtotal),
+		CustomerSegment: req.CustomerSegment,
+		Priority:        req.Priority,
+		Stackable:       req.Stackable,
+		Status:          req.Status,
+		StartAt:         req.StartAt,
+		EndAt:           req.EndAt,
+		Tiers:           tiers,
+	}
+}
+
+func promotionEntityToResponse(result entities.PromotionEntity) response.PromotionResponse {
+	tiers := []response.PromotionTierResponse{}
+	for _, tier := range result.Tiers {
+		tiers = append(tiers, response.PromotionTierResponse{
+			MinQuantity: tier.MinQuantity,
+			Value:       int64(tier.Value),
+		})
+	}
+
+	return response.PromotionResponse{
+		ID:              result.ID,
+		Name:            result.Name,
+		Description:     result.Description,
+		Type:            result.Type,
+		Value:           int64(result.Value),
+		BuyQuantity:     result.BuyQuantity,
+		GetQuantity:     result.GetQuantity,
+		CategorySlug:    result.CategorySlug,
+		ProductID:       result.ProductID,
+		MinQuantity:     result.MinQuantity,
+		MinSubtotal:     int64(result.MinSubtotal),
+		CustomerSegment: result.CustomerSegment,
+		Priority:        result.Priority,
+		Stackable:       result.Stackable,
+		Status:          result.Status,
+		StartAt:         result.StartAt,
+		EndAt:           result.EndAt,
+		Tiers:           tiers,
+	}
+}
+
+func cartPriceEntityToResponse(result entities.CartPriceEntity) response.CartPriceResponse {
+	respItems := []response.CartPriceLineResponse{}
+	for _, item := range result.Items {
+		respItems = append(respItems, response.CartPriceLineResponse{
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			UnitPrice:   int64(item.UnitPrice),
+			Subtotal:    int64(item.Subtotal),
+			Discount:    int64(item.Discount),
+			Total:       int64(item.Total),
+			Promotions:  appliedPromotionsToResponse(item.Promotions),
+		})
+	}
+
+	return response.CartPriceResponse{
+		Items:            respItems,
+		Subtotal:         int64(result.Subtotal),
+		LineDiscount:     int64(result.LineDiscount),
+		OrderDiscount:    int64(result.OrderDiscount),
+		OrderPromotions:  appliedPromotionsToResponse(result.OrderPromotions),
+		ShippingFee:      int64(result.ShippingFee),
+		ShippingDiscount: int64(result.ShippingDiscount),
+		Total:            int64(result.Total),
+	}
+}
+
+func appliedPromotionsToResponse(applied []entities.AppliedPromotionEntity) []response.AppliedPromotionResponse {
+	respApplied := []response.AppliedPromotionResponse{}
+	for _, val := range applied {
+		respApplied = append(respApplied, response.AppliedPromotionResponse{
+			PromotionID: val.PromotionID,
+			Name:        val.Name,
+			Type:        val.Type,
+			Discount:    int64(val.Discount),
+		})
+	}
+
+	return respApplied
+}
+
+func NewPromotionHandler(e *echo.Echo, cfg *config.Config, promotionService service.IPromotionService) IPromotionHandler {
+	promotionHandler := &promotionHandler{
+		promotionService: promotionService,
+	}
+
+	mid := adapter.NewMiddlewareAdapter(cfg)
+	adminGroup := e.Group("/admin", mid.CheckToken())
+	adminGroup.GET("/promotions", promotionHandler.GetAllAdmin)
+	adminGroup.POST("/promotions", promotionHandler.CreateAdmin)
+	adminGroup.GET("/promotions/:id", promotionHandler.GetByIDAdmin)
+	adminGroup.PUT("/promotions/:id", promotionHandler.UpdateAdmin)
+	adminGroup.DELETE("/promotions/:id", promotionHandler.DeleteAdmin)
+
+	// the segment comes from the session, never from the request body
+	e.POST("/cart/price", promotionHandler.PriceCart, mid.OptionalToken())
+
+	return promotionHandler
+}
@@ -0,0 +1,471 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"product-service/config"
+	"product-service/internal/adapter"
+	"product-service/internal/adapter/handlers/request"
+	"product-service/internal/adapter/handlers/response"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/service"
+	"product-service/utils/conv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+type IPurchaseLimitHandler interface {
+	GetAllAdmin(c echo.Context) error
+	GetByIDAdmin(c echo.Context) error
+	CreateAdmin(c echo.Context) error
+	UpdateAdmin(c echo.Context) error
+	DeleteAdmin(c echo.Context) error
+
+	Check(c echo.Context) error
+	Reserve(c echo.Context) error
+	Release(c echo.Context) error
+}
+
+type purchaseLimitHandler struct {
+	purchaseLimitService service.IPurchaseLimitService
+}
+
+// GetAllAdmin implements [IPurchaseLimitHandler].
+func (p *purchaseLimitHandler) GetAllAdmin(c echo.Context) error {
+	var (
+		resp       = response.DefaultResponseWithPaginations{}
+		ctx        = c.Request().Context()
+		respLimits = []response.PurchaseLimitResponse{}
+	)
+
+	orderBy := "created_at"
+	if c.QueryParam("order_by") != "" {
+		orderBy = c.QueryParam("order_by")
+	}
+	orderType := "desc"
+	if c.QueryParam("order_type") != "" {
+		orderType = c.QueryParam("order_type")
+	}
+
+	var page int64 = 1
+	if pageStr := c.QueryParam("page"); pageStr != "" {
+		page, _ = conv.StringToInt64(pageStr)
+		if page <= 0 {
+			page = 1
+		}
+	}
+
+	var perPage int64 = 10
+	if perPageStr := c.QueryParam("perPage"); perPageStr != "" {
+		perPage, _ = conv.StringToInt64(perPageStr)
+		if perPage <= 0 {
+			perPage = 10
+		}
+	}
+
+	reqEntity := entities.QueryStringEntity{
+		Search:    c.QueryParam("search"),
+		OrderBy:   orderBy,
+		OrderType: orderType,
+		Page:      int(page),
+		Limit:     int(perPage),
+	}
+
+	results, totalData, totalPage, err := p.purchaseLimitService.GetAll(ctx, reqEntity)
+	if err != nil {
+		log.Errorf("[PurchaseLimitHandler-1] GetAllAdmin: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Data not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	for _, result := range results {
+		respLimits = append(respLimits, purchaseLimitEntityToResponse(result))
+	}
+
+	resp.Message = "success"
+	resp.Data = respLimits
+	resp.Pagination = &response.Pagination{
+		Page:       page,
+		TotalCount: totalData,
+		PerPage:    perPage,
+		TotalPage:  totalPage,
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetByIDAdmin implements [IPurchaseLimitHandler].
+func (p *purchaseLimitHandler) GetByIDAdmin(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[PurchaseLimitHandler-1] GetByIDAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	result, err := p.purchaseLimitService.GetByID(ctx, id)
+	if err != nil {
+		log.Errorf("[PurchaseLimitHandler-2] GetByIDAdmin: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Data not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = purchaseLimitEntityToResponse(*result)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CreateAdmin implements [IPurchaseLimitHandler].
+func (p *purchaseLimitHandler) CreateAdmin(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		req  = request.PurchaseLimitRequest{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[PurchaseLimitHandler-1] CreateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Validate(req); err != nil {
+		log.Errorf("[PurchaseLimitHandler-2] CreateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	err := p.purchaseLimitService.Create(ctx, purchaseLimitRequestToEntity(req))
+	if err != nil {
+		log.Errorf("[PurchaseLimitHandler-3] CreateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// UpdateAdmin implements [IPurchaseLimitHandler].
+func (p *purchaseLimitHandler) UpdateAdmin(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		req  = request.PurchaseLimitRequest{}
+	)
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[PurchaseLimitHandler-1] UpdateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[PurchaseLimitHandler-2] UpdateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Validate(req); err != nil {
+		log.Errorf("[PurchaseLimitHandler-3] UpdateAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	reqEntity := purchaseLimitRequestToEntity(req)
+	reqEntity.ID = id
+
+	err = p.purchaseLimitService.Update(ctx, reqEntity)
+	if err != nil {
+		log.Errorf("[PurchaseLimitHandler-4] UpdateAdmin: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Purchase limit not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+// DeleteAdmin implements [IPurchaseLimitHandler].
+func (p *purchaseLimitHandler) DeleteAdmin(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[PurchaseLimitHandler-1] DeleteAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	err = p.purchaseLimitService.Delete(ctx, id)
+	if err != nil {
+		log.Errorf("[PurchaseLimitHandler-2] DeleteAdmin: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Purchase limit not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+// Check implements [IPurchaseLimitHandler]. It tells the customer whether
+// the items may be bought; a 422 lists every limit they go over and what is
+// left of it.
+func (p *purchaseLimitHandler) Check(c echo.Context) error {
+	var (
+		resp        = response.DefaultResponse{}
+		ctx         = c.Request().Context()
+		req         = request.CheckPurchaseLimitRequest{}
+		jwtUserData = entities.JwtUserData{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[PurchaseLimitHandler-1] Check: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Validate(req); err != nil {
+		log.Errorf("[PurchaseLimitHandler-2] Check: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	user := c.Get("user").(string)
+	if user == "" {
+		log.Errorf("[PurchaseLimitHandler-3] Check: %s", "data token not found")
+		resp.Message = "data token not found"
+		resp.Data = nil
+		return c.JSON(http.StatusNotFound, resp)
+	}
+
+	err := json.Unmarshal([]byte(user), &jwtUserData)
+	if err != nil {
+		log.Errorf("[PurchaseLimitHandler-4] Check: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	items := []entities.CartItem{}
+	for _, item := range req.Items {
+		items = append(items, entities.CartItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	violations, err := p.purchaseLimitService.Check(ctx, jwtUserData.UserID, items)
+	if err != nil {
+		log.Errorf("[PurchaseLimitHandler-5] Check: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	if len(violations) > 0 {
+		limitErr := &service.PurchaseLimitError{Violations: violations}
+		resp.Message = limitErr.Error()
+		resp.Data = purchaseLimitViolationsToResponse(violations)
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+// Reserve implements [IPurchaseLimitHandler]. It is called by order-service
+// only, while the order is being created; a 422 lists every limit the order
+// goes over and what is left of it.
+func (p *purchaseLimitHandler) Reserve(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		req  = request.ReservePurchaseLimitRequest{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[PurchaseLimitHandler-1] Reserve: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Validate(req); err != nil {
+		log.Errorf("[PurchaseLimitHandler-2] Reserve: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	items := []entities.CartItem{}
+	for _, item := range req.Items {
+		items = append(items, entities.CartItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	violations, err := p.purchaseLimitService.Reserve(ctx, req.BuyerID, req.OrderCode, items)
+	if err != nil {
+		log.Errorf("[PurchaseLimitHandler-3] Reserve: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	if len(violations) > 0 {
+		limitErr := &service.PurchaseLimitError{Violations: violations}
+		resp.Message = limitErr.Error()
+		resp.Data = purchaseLimitViolationsToResponse(violations)
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+// Release implements [IPurchaseLimitHandler]. order-service calls it when
+// the order could not be saved or is cancelled.
+func (p *purchaseLimitHandler) Release(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	if err := p.purchaseLimitService.Release(ctx, c.Param("orderCode")); err != nil {
+		log.Errorf("[PurchaseLimitHandler-1] Release: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+func purchaseLimitRequestToEntity(req request.PurchaseLimitRequest) entities.PurchaseLimitEntity {
+	windowHours := req.WindowHours
+	if windowHours == 0 {
+		windowHours = 24
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	return entities.PurchaseLimitEntity{
+		Name:         req.Name,
+		ProductID:    req.ProductID,
+		CategorySlug: req.CategorySlug,
+		MaxQuantity:  req.MaxQuantity,
+		WindowHours:  windowHours,
+		IsActive:     isActive,
+	}
+}
+
+func purchaseLimitEntityToResponse(result entities.PurchaseLimitEntity) response.PurchaseLimitResponse {
+	return response.PurchaseLimitResponse{
+		ID:           result.ID,
+		Name:         result.Name,
+		ProductID:    result.ProductID,
+		CategorySlug: result.CategorySlug,
+		MaxQuantity:  result.MaxQuantity,
+		WindowHours:  result.WindowHours,
+		IsActive:     result.IsActive,
+		CreatedAt:    result.CreatedAt,
+	}
+}
+
+func purchaseLimitViolationsToResponse(violations []entities.PurchaseLimitViolationEntity) []response.PurchaseLimitViolationResponse {
+	respViolations := []response.PurchaseLimitViolationResponse{}
+	for _, val := range violations {
+		respViolations = append(respViolations, response.PurchaseLimitViolationResponse{
+			LimitID:            val.LimitID,
+			Name:               val.Name,
+			ProductID:          val.ProductID,
+			CategorySlug:       val.CategorySlug,
+			MaxQuantity:        val.MaxQuantity,
+			WindowHours:        val.WindowHours,
+			Purchased:          val.Purchased,
+			Requested:          val.Requested,
+			Remaining:          val.Remaining,
+			AffectedProductIDs: val.AffectedProductIDs,
+		})
+	}
+
+	return respViolations
+}
+
+func NewPurchaseLimitHandler(e *echo.Echo, cfg *config.Config, purchaseLimitService service.IPurchaseLimitService) IPurchaseLimitHandler {
+	purchaseLimitHandler := &purchaseLimitHandler{
+		purchaseLimitService: purchaseLimitService,
+	}
+
+	mid := adapter.NewMiddlewareAdapter(cfg)
+	adminGroup := e.Group("/admin", mid.CheckToken())
+	adminGroup.GET("/purchase-limits", purchaseLimitHandler.GetAllAdmin)
+	adminGroup.POST("/purchase-limits", purchaseLimitHandler.CreateAdmin)
+	adminGroup.GET("/purchase-limits/:id", purchaseLimitHandler.GetByIDAdmin)
+	adminGroup.PUT("/purchase-limits/:id", purchaseLimitHandler.UpdateAdmin)
+	adminGroup.DELETE("/purchase-limits/:id", purchaseLimitHandler.DeleteAdmin)
+
+	authGroup := e.Group("/auth", mid.CheckToken())
+	authGroup.POST("/purchase-limits/check", purchaseLimitHandler.Check)
+
+	internalGroup := e.Group("/internal", mid.CheckServiceKey())
+	internalGroup.POST("/purchase-limits/reservations", purchaseLimitHandler.Reserve)
+	internalGroup.DELETE("/purchase-limits/reservations/:orderCode", purchaseLimitHandler.Release)
+
+	return purchaseLimitHandler
+}
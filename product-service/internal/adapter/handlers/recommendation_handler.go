@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"product-service/config"
+	"product-service/internal/adapter"
+	"product-service/internal/adapter/handlers/response"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/service"
+	"product-service/utils/conv"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+type IRecommendationHandler interface {
+	GetRelated(c echo.Context) error
+	GetRecentlyViewed(c echo.Context) error
+	ClearRecentlyViewed(c echo.Context) error
+}
+
+type recommendationHandler struct {
+	recommendationService service.IRecommendationService
+}
+
+// GetRelated implements [IRecommendationHandler]. It lists the products
+// frequently bought together with the product, topped up with best sellers
+// of its category.
+func (r *recommendationHandler) GetRelated(c echo.Context) error {
+	var (
+		resp      = response.DefaultResponse{}
+		ctx       = c.Request().Context()
+		respLists = []response.RelatedProductResponse{}
+	)
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[RecommendationHandler-1] GetRelated: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	limit := 0
+	if c.QueryParam("limit") != "" {
+		limit, err = strconv.Atoi(c.QueryParam("limit"))
+		if err != nil {
+			log.Errorf("[RecommendationHandler-2] GetRelated: %v", err)
+			resp.Message = err.Error()
+			resp.Data = nil
+			return c.JSON(http.StatusBadRequest, resp)
+		}
+	}
+
+	results, err := r.recommendationService.GetRelated(ctx, id, limit)
+	if err != nil {
+		log.Errorf("[RecommendationHandler-3] GetRelated: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Data not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	for _, result := range results {
+		respLists = append(respLists, response.RelatedProductResponse{
+			ID:           result.Product.ID,
+			ProductName:  result.Product.Name,
+			ProductImage: result.Product.Image,
+			CategoryName: result.Product.CategoryName,
+			SalePrice:    int64(result.Product.SalePrice),
+			RegulerPrice: int64(result.Product.RegulerPrice),
+			Stock:        result.Product.Stock,
+			Reason:       result.Reason,
+		})
+	}
+
+	resp.Message = "success"
+	resp.Data = respLists
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetRecentlyViewed implements [IRecommendationHandler]. It lists the
+// products the customer viewed, latest first.
+func (r *recommendationHandler) GetRecentlyViewed(c echo.Context) error {
+	var (
+		resp        = response.DefaultResponse{}
+		ctx         = c.Request().Context()
+		jwtUserData = entities.JwtUserData{}
+		respLists   = []response.ProductHomeListResponse{}
+		err         error
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		log.Errorf("[RecommendationHandler-1] GetRecentlyViewed: %s", "data token not found")
+		resp.Message = "data token not found"
+		resp.Data = nil
+		return c.JSON(http.StatusNotFound, resp)
+	}
+
+	err = json.Unmarshal([]byte(user), &jwtUserData)
+	if err != nil {
+		log.Errorf("[RecommendationHandler-2] GetRecentlyViewed: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	limit := 0
+	if c.QueryParam("limit") != "" {
+		limit, err = strconv.Atoi(c.QueryParam("limit"))
+		if err != nil {
+			log.Errorf("[RecommendationHandler-3] GetRecentlyViewed: %v", err)
+			resp.Message = err.Error()
+			resp.Data = nil
+			return c.JSON(http.StatusBadRequest, resp)
+		}
+	}
+
+	results, err := r.recommendationService.GetRecentlyViewed(ctx, jwtUserData.UserID, limit)
+	if err != nil {
+		log.Errorf("[RecommendationHandler-4] GetRecentlyViewed: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	for _, result := range results {
+		respLists = append(respLists, response.ProductHomeListResponse{
+			ID:           result.ID,
+			ProductName:  result.Name,
+			ProductImage: result.Image,
+			CategoryName: result.CategoryName,
+			SalePrice:    int64(result.SalePrice),
+			RegulerPrice: int64(result.RegulerPrice),
+		})
+	}
+
+	resp.Message = "success"
+	resp.Data = respLists
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ClearRecentlyViewed implements [IRecommendationHandler].
+func (r *recommendationHandler) ClearRecentlyViewed(c echo.Context) error {
+	var (
+		resp        = response.DefaultResponse{}
+		ctx         = c.Request().Context()
+		jwtUserData = entities.JwtUserData{}
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		log.Errorf("[RecommendationHandler-1] ClearRecentlyViewed: %s", "data token not found")
+		resp.Message = "data token not found"
+		resp.Data = nil
+		return c.JSON(http.StatusNotFound, resp)
+	}
+
+	err := json.Unmarshal([]byte(user), &jwtUserData)
+	if err != nil {
+		log.Errorf("[RecommendationHandler-2] ClearRecentlyViewed: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := r.recommendationService.ClearRecentlyViewed(ctx, jwtUserData.UserID); err != nil {
+		log.Errorf("[RecommendationHandler-3] ClearRecentlyViewed: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+// optionalUserID returns the customer set by the OptionalToken middleware,
+// or 0 for an anonymous visitor.
+func optionalUserID(c echo.Context) int64 {
+	user, ok := c.Get("user").(string)
+	if !ok || user == "" {
+		return 0
+	}
+
+	jwtUserData := entities.JwtUserData{}
+	if err := json.Unmarshal([]byte(user), &jwtUserData); err != nil {
+		log.Errorf("[RecommendationHandler-1] optionalUserID: %v", err)
+		return 0
+	}
+
+	return jwtUserData.UserID
+}
+
+func NewRecommendationHandler(e *echo.Echo, cfg *config.Config, recommendationService service.IRecommendationService) IRecommendationHandler {
+	recommendationHandler := &recommendationHandler{
+		recommendationService: recommendationService,
+	}
+
+	homeProduct := e.Group("/products")
+	homeProduct.GET("/home/:id/related", recommendationHandler.GetRelated)
+
+	mid := adapter.NewMiddlewareAdapter(cfg)
+	authGroup := e.Group("/auth", mid.CheckToken())
+	authGroup.GET("/products/recently-viewed", recommendationHandler.GetRecentlyViewed)
+	authGroup.DELETE("/products/recently-viewed", recommendationHandler.ClearRecentlyViewed)
+
+	return recommendationHandler
+}
@@ -1,6 +1,12 @@
 package request
 
 type CartRequest struct {
-	ProductID int64 `json:"product_id" binding:"required"`
-	Quantity  int64 `json:"quantity" binding:"required"`
-}
\ No newline at end of file
+	ProductID int64  `json:"product_id" validate:"required"`
+	Quantity  int64  `json:"quantity" validate:"required,gt=0"`
+	Unit      string `json:"unit"`
+}
+
+type CartQuantityRequest struct {
+	Quantity int64  `json:"quantity" validate:"gte=0"`
+	Unit     string `json:"unit"`
+}
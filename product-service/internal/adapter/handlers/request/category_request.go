@@ -7,3 +7,14 @@ type CreateCategoryRequest struct {
 	Status      string `json:"status" validate:"required"`
 	ParentID    *int64 `json:"parent_id"`
 }
+
+type CategoryAttributeRequest struct {
+	Code         string   `json:"code"`
+	Name         string   `json:"name" validate:"required,max=100"`
+	Type         string   `json:"type"`
+	Options      []string `json:"options"`
+	Unit         string   `json:"unit" validate:"max=20"`
+	IsRequired   bool     `json:"is_required"`
+	IsFilterable *bool    `json:"is_filterable"`
+	Position     int      `json:"position"`
+}
@@ -0,0 +1,46 @@
+package request
+
+import "time"
+
+type CouponRequest struct {
+	Code             string     `json:"code" validate:"required,max=40"`
+	Name             string     `json:"name" validate:"required"`
+	DiscountType     string     `json:"discount_type" validate:"required,oneof=percentage fixed"`
+	Value            int64      `json:"value" validate:"required,gt=0"`
+	MaxDiscount      int64      `json:"max_discount" validate:"gte=0"`
+	MinSpend         int64      `json:"min_spend" validate:"gte=0"`
+	UsageLimit       int64      `json:"usage_limit" validate:"gte=0"`
+	PerCustomerLimit int64      `json:"per_customer_limit" validate:"gte=0"`
+	Status           string     `json:"status" validate:"required"`
+	StartAt          *time.Time `json:"start_at"`
+	EndAt            *time.Time `json:"end_at"`
+}
+
+type CouponBulkRequest struct {
+	Prefix           string     `json:"prefix" validate:"max=10"`
+	Quantity         int        `json:"quantity" validate:"required,min=1,max=1000"`
+	Length           int        `json:"length" validate:"omitempty,min=6,max=20"`
+	Name             string     `json:"name" validate:"required"`
+	DiscountType     string     `json:"discount_type" validate:"required,oneof=percentage fixed"`
+	Value            int64      `json:"value" validate:"required,gt=0"`
+	MaxDiscount      int64      `json:"max_discount" validate:"gte=0"`
+	MinSpend         int64      `json:"min_spend" validate:"gte=0"`
+	UsageLimit       int64      `json:"usage_limit" validate:"gte=0"`
+	PerCustomerLimit int64      `json:"per_customer_limit" validate:"gte=0"`
+	Status           string     `json:"status" validate:"required"`
+	StartAt          *time.Time `json:"start_at"`
+	EndAt            *time.Time `json:"end_at"`
+}
+
+type ApplyCouponRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// RedeemCouponRequest comes from order-service. Subtotal is the order after
+// promotions, priced by /cart/price for the buyer.
+type RedeemCouponRequest struct {
+	Code      string  `json:"code" validate:"required"`
+	OrderCode string  `json:"order_code" validate:"required"`
+	BuyerID   int64   `json:"buyer_id" validate:"required"`
+	Subtotal  float64 `json:"subtotal" validate:"gte=0"`
+}
@@ -0,0 +1,5 @@
+package request
+
+type ProductRatingRequest struct {
+	Rating int `json:"rating" validate:"required,min=1,max=5"`
+}
@@ -1,13 +1,36 @@
 package request
 
+import "time"
+
 type ProductRequest struct {
 	ProductName        string                 `json:"product_name" validate:"required"`
 	CategorySlug       string                 `json:"category_slug" validate:"required"`
 	Unit               string                 `json:"unit" validate:"required"`
 	Variant            int                    `json:"variant" validate:"required"`
 	ProductDescription string                 `json:"product_description" validate:"required"`
-	Status             string                 `json:"status" validate:"required"`
+	Status             string                 `json:"status"`
 	VariantDetail      []ProductDetailRequest `json:"variant_detail" validate:"required"`
+	Attributes         map[string]interface{} `json:"attributes"`
+	Selling            *ProductSellingRequest `json:"selling"`
+}
+
+type ProductSellingRequest struct {
+	SellBy       string `json:"sell_by" validate:"required,oneof=item measure estimated"`
+	PriceUnit    string `json:"price_unit"`
+	QuantityUnit string `json:"quantity_unit"`
+	QuantityStep int64  `json:"quantity_step" validate:"gte=0"`
+	MinQuantity  int64  `json:"min_quantity" validate:"gte=0"`
+	MaxQuantity  int64  `json:"max_quantity" validate:"gte=0"`
+}
+
+type ProductStatusRequest struct {
+	Status string `json:"status" validate:"required"`
+	Note   string `json:"note" validate:"max=1000"`
+}
+
+type ProductApproveRequest struct {
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
 }
 
 type ProductDetailRequest struct {
@@ -16,4 +39,26 @@ type ProductDetailRequest struct {
 	Weight       int    `json:"weight" validate:"required,number"`
 	SalePrice    int64  `json:"sale_price" validate:"required,number"`
 	RegulerPrice int64  `json:"reguler_price" validate:"required,number"`
-}
\ No newline at end of file
+}
+
+type ProductBundleRequest struct {
+	Pricing         string                     `json:"pricing" validate:"omitempty,oneof=fixed computed"`
+	DiscountPercent float64                    `json:"discount_percent" validate:"gte=0,lt=100"`
+	Items           []ProductBundleItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+type ProductBundleItemRequest struct {
+	ProductID int64 `json:"product_id" validate:"required"`
+	Quantity  int   `json:"quantity" validate:"required,min=1"`
+}
+
+type ReorderProductImageRequest struct {
+	ImageIDs []int64 `json:"image_ids" validate:"required,min=1"`
+}
+
+type PriceListRequest struct {
+	Name      string     `json:"name" validate:"required"`
+	SalePrice int64      `json:"sale_price" validate:"required,gt=0"`
+	StartAt   time.Time  `json:"start_at" validate:"required"`
+	EndAt     *time.Time `json:"end_at"`
+}
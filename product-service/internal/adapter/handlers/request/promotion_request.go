@@ -0,0 +1,38 @@
+package request
+
+import "time"
+
+type PromotionRequest struct {
+	Name            string                 `json:"name" validate:"required"`
+	Description     string                 `json:"description"`
+	Type            string                 `json:"type" validate:"required,oneof=percentage fixed buy_x_get_y tiered order_percentage order_fixed free_shipping"`
+	Value           int64                  `json:"value"`
+	BuyQuantity     int64                  `json:"buy_quantity"`
+	GetQuantity     int64                  `json:"get_quantity"`
+	CategorySlug    string                 `json:"category_slug"`
+	ProductID       *int64                 `json:"product_id"`
+	MinQuantity     int64                  `json:"min_quantity"`
+	MinSubtotal     int64                  `json:"min_subtotal"`
+	CustomerSegment string                 `json:"customer_segment" validate:"omitempty,oneof=guest customer"`
+	Priority        int                    `json:"priority"`
+	Stackable       bool                   `json:"stackable"`
+	Status          string                 `json:"status" validate:"required"`
+	StartAt         *time.Time             `json:"start_at"`
+	EndAt           *time.Time             `json:"end_at"`
+	Tiers           []PromotionTierRequest `json:"tiers"`
+}
+
+type PromotionTierRequest struct {
+	MinQuantity int64 `json:"min_quantity"`
+	Value       int64 `json:"value"`
+}
+
+type CartPriceRequest struct {
+	ShippingFee int64             `json:"shipping_fee"`
+	Items       []CartItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+type CartItemRequest struct {
+	ProductID int64 `json:"product_id" validate:"required"`
+	Quantity  int64 `json:"quantity" validate:"required,gt=0"`
+}
@@ -0,0 +1,22 @@
+package request
+
+type PurchaseLimitRequest struct {
+	Name         string `json:"name" validate:"required"`
+	ProductID    *int64 `json:"product_id"`
+	CategorySlug string `json:"category_slug"`
+	MaxQuantity  int64  `json:"max_quantity" validate:"required,gt=0"`
+	WindowHours  int    `json:"window_hours"`
+	IsActive     *bool  `json:"is_active"`
+}
+
+type CheckPurchaseLimitRequest struct {
+	Items []CartItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+// ReservePurchaseLimitRequest comes from order-service while it creates the
+// order of BuyerID.
+type ReservePurchaseLimitRequest struct {
+	OrderCode string            `json:"order_code" validate:"required"`
+	BuyerID   int64             `json:"buyer_id" validate:"required"`
+	Items     []CartItemRequest `json:"items" validate:"required,min=1,dive"`
+}
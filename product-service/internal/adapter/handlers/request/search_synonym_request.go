@@ -0,0 +1,5 @@
+package request
+
+type SynonymRequest struct {
+	Terms []string `json:"terms" validate:"required,min=2,dive,required"`
+}
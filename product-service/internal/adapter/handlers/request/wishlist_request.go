@@ -0,0 +1,7 @@
+package request
+
+type WishlistRequest struct {
+	ProductID int64  `json:"product_id" validate:"required"`
+	Quantity  int64  `json:"quantity" validate:"gte=0"`
+	List      string `json:"list" validate:"omitempty,oneof=wishlist saved_for_later"`
+}
@@ -1,12 +1,35 @@
 package response
 
 type CartResponse struct {
-	ID            int64  `json:"id"`
-	ProductName   string `json:"product_name"`
-	ProductImage  string `json:"product_image"`
-	ProductStatus string `json:"product_status"`
-	SalePrice     int64  `json:"sale_price"`
-	Quantity      int64  `json:"quantity"`
-	Unit          string `json:"unit"`
-	Weight        int64  `json:"weight"`
-}
\ No newline at end of file
+	Items         []CartLineResponse `json:"items"`
+	TotalQuantity int64              `json:"total_quantity"`
+	TotalWeight   int64              `json:"total_weight"`
+	Subtotal      int64              `json:"subtotal"`
+	HasWarnings   bool               `json:"has_warnings"`
+	HasEstimated  bool               `json:"has_estimated"`
+}
+
+type CartLineResponse struct {
+	ID            int64                 `json:"id"`
+	ProductName   string                `json:"product_name"`
+	ProductImage  string                `json:"product_image"`
+	ProductStatus string                `json:"product_status"`
+	SalePrice     int64                 `json:"sale_price"`
+	PreviousPrice int64                 `json:"previous_price"`
+	Quantity      int64                 `json:"quantity"`
+	LineTotal     int64                 `json:"line_total"`
+	Stock         int64                 `json:"stock"`
+	Available     bool                  `json:"available"`
+	Unit          string                `json:"unit"`
+	Weight        int64                 `json:"weight"`
+	SellBy        string                `json:"sell_by"`
+	PriceUnit     string                `json:"price_unit"`
+	QuantityUnit  string                `json:"quantity_unit"`
+	LineWeight    int64                 `json:"line_weight"`
+	Warnings      []CartWarningResponse `json:"warnings"`
+}
+
+type CartWarningResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
@@ -28,3 +28,33 @@ type CategoryListShopResponse struct {
 	Slug  string                     `json:"slug"`
 	Child []CategoryListShopResponse `json:"child"`
 }
+
+type CategoryTreeResponse struct {
+	ID           int64                  `json:"id"`
+	Name         string                 `json:"name"`
+	Icon         string                 `json:"icon"`
+	Slug         string                 `json:"slug"`
+	ProductCount int64                  `json:"product_count"`
+	TotalProduct int64                  `json:"total_product"`
+	Child        []CategoryTreeResponse `json:"child"`
+}
+
+type BreadcrumbResponse struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type CategoryAttributeResponse struct {
+	ID           int64    `json:"id"`
+	CategoryID   int64    `json:"category_id"`
+	Code         string   `json:"code"`
+	Name         string   `json:"name"`
+	Type         string   `json:"type"`
+	Options      []string `json:"options"`
+	Unit         string   `json:"unit"`
+	IsRequired   bool     `json:"is_required"`
+	IsFilterable bool     `json:"is_filterable"`
+	Position     int      `json:"position"`
+	Inherited    bool     `json:"inherited"`
+}
@@ -0,0 +1,39 @@
+package response
+
+import "time"
+
+type CouponResponse struct {
+	ID               int64      `json:"id"`
+	Code             string     `json:"code"`
+	Name             string     `json:"name"`
+	DiscountType     string     `json:"discount_type"`
+	Value            int64      `json:"value"`
+	MaxDiscount      int64      `json:"max_discount"`
+	MinSpend         int64      `json:"min_spend"`
+	UsageLimit       int64      `json:"usage_limit"`
+	PerCustomerLimit int64      `json:"per_customer_limit"`
+	UsedCount        int64      `json:"used_count"`
+	Status           string     `json:"status"`
+	StartAt          *time.Time `json:"start_at"`
+	EndAt            *time.Time `json:"end_at"`
+}
+
+type CouponBulkResponse struct {
+	Codes []string `json:"codes"`
+}
+
+type CouponRedemptionResponse struct {
+	ID        int64     `json:"id"`
+	Code      string    `json:"code,omitempty"`
+	UserID    int64     `json:"user_id"`
+	OrderCode string    `json:"order_code"`
+	Discount  int64     `json:"discount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CartCouponResponse struct {
+	Code     string `json:"code"`
+	Subtotal int64  `json:"subtotal"`
+	Discount int64  `json:"discount"`
+	Total    int64  `json:"total"`
+}
@@ -9,6 +9,7 @@ type DefaultResponseWithPaginations struct {
 	Message    string      `json:"message"`
 	Data       interface{} `json:"data"`
 	Pagination *Pagination `json:"pagination,omitempty"`
+	Facets     interface{} `json:"facets,omitempty"`
 }
 
 type Pagination struct {
@@ -24,5 +25,8 @@ type ProductHomeListResponse struct {
 	ProductImage string `json:"product_image"`
 	CategoryName string `json:"category_name"`
 	SalePrice    int64  `json:"sale_price"`
-	RegulerPrice int64  `json:"reguler_price"`
+	RegulerPrice int64   `json:"reguler_price"`
+	Rating       float64 `json:"rating"`
+	RatingCount  int64   `json:"rating_count"`
+	Reason       string  `json:"reason,omitempty"`
 }
\ No newline at end of file
@@ -0,0 +1,25 @@
+package response
+
+import "time"
+
+type ProductImportJobResponse struct {
+	ID            int64                           `json:"id"`
+	FileName      string                          `json:"file_name"`
+	Status        string                          `json:"status"`
+	TotalRows     int                             `json:"total_rows"`
+	ProcessedRows int                             `json:"processed_rows"`
+	CreatedCount  int                             `json:"created_count"`
+	UpdatedCount  int                             `json:"updated_count"`
+	FailedCount   int                             `json:"failed_count"`
+	RowErrors     []ProductImportRowErrorResponse `json:"row_errors"`
+	ErrorMessage  string                          `json:"error_message"`
+	CreatedAt     time.Time                       `json:"created_at"`
+	FinishedAt    *time.Time                      `json:"finished_at"`
+}
+
+type ProductImportRowErrorResponse struct {
+	Row     int    `json:"row"`
+	SKU     string `json:"sku"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
@@ -14,21 +14,84 @@ type ProductListResponse struct {
 }
 
 type ProductDetailResponse struct {
-	ID                 int64                  `json:"id"`
-	ProductName        string                 `json:"product_name"`
-	ParentID           int64                  `json:"parent_id"`
-	ProductImage       string                 `json:"product_image"`
-	CategoryName       string                 `json:"category_name"`
-	CategorySlug       string                 `json:"category_slug"`
-	ProductStatus      string                 `json:"product_status"`
-	ProductDescription string                 `json:"product_description"`
-	SalePrice          int64                  `json:"sale_price"`
-	RegulerPrice       int64                  `json:"reguler_price"`
-	CreatedAt          time.Time              `json:"created_at"`
-	Unit               string                 `json:"unit"`
-	Weight             int                    `json:"weight"`
-	Stock              int                    `json:"stock"`
-	Child              []ProductChildResponse `json:"child"`
+	ID                 int64                      `json:"id"`
+	ProductName        string                     `json:"product_name"`
+	ParentID           int64                      `json:"parent_id"`
+	ProductImage       string                     `json:"product_image"`
+	CategoryName       string                     `json:"category_name"`
+	CategorySlug       string                     `json:"category_slug"`
+	ProductStatus      string                     `json:"product_status"`
+	PublishAt          *time.Time                 `json:"publish_at"`
+	UnpublishAt        *time.Time                 `json:"unpublish_at"`
+	ApprovedAt         *time.Time                 `json:"approved_at"`
+	ReviewNote         string                     `json:"review_note"`
+	ProductDescription string                     `json:"product_description"`
+	SalePrice          int64                      `json:"sale_price"`
+	RegulerPrice       int64                      `json:"reguler_price"`
+	CreatedAt          time.Time                  `json:"created_at"`
+	Unit               string                     `json:"unit"`
+	Weight             int                        `json:"weight"`
+	Stock              int                        `json:"stock"`
+	ProductType        string                     `json:"product_type"`
+	Selling            ProductSellingResponse     `json:"selling"`
+	Bundle             *ProductBundleResponse     `json:"bundle"`
+	Child              []ProductChildResponse     `json:"child"`
+	Images             []ProductImageResponse     `json:"images"`
+	Breadcrumbs        []BreadcrumbResponse       `json:"breadcrumbs"`
+	Attributes         []ProductAttributeResponse `json:"attributes"`
+}
+
+// ProductBulkResponse carries what other services need to price and ship
+// order lines.
+type ProductBulkResponse struct {
+	ID           int64                  `json:"id"`
+	ProductName  string                 `json:"product_name"`
+	ProductImage string                 `json:"product_image"`
+	CategoryName string                 `json:"category_name"`
+	SalePrice    int64                  `json:"sale_price"`
+	RegulerPrice int64                  `json:"reguler_price"`
+	Unit         string                 `json:"unit"`
+	Weight       int                    `json:"weight"`
+	Stock        int                    `json:"stock"`
+	Selling      ProductSellingResponse `json:"selling"`
+}
+
+type ProductSellingResponse struct {
+	SellBy       string `json:"sell_by"`
+	PriceUnit    string `json:"price_unit"`
+	QuantityUnit string `json:"quantity_unit"`
+	QuantityStep int64  `json:"quantity_step"`
+	MinQuantity  int64  `json:"min_quantity"`
+	MaxQuantity  int64  `json:"max_quantity"`
+}
+
+type ProductLifecycleResponse struct {
+	ID            int64      `json:"id"`
+	ProductStatus string     `json:"product_status"`
+	PublishAt     *time.Time `json:"publish_at"`
+	UnpublishAt   *time.Time `json:"unpublish_at"`
+	ApprovedBy    *int64     `json:"approved_by"`
+	ApprovedAt    *time.Time `json:"approved_at"`
+	ReviewNote    string     `json:"review_note"`
+}
+
+type ProductBundleResponse struct {
+	Pricing         string                      `json:"pricing"`
+	DiscountPercent float64                     `json:"discount_percent"`
+	Items           []ProductBundleItemResponse `json:"items"`
+}
+
+type ProductBundleItemResponse struct {
+	ProductID    int64  `json:"product_id"`
+	ProductName  string `json:"product_name"`
+	ProductImage string `json:"product_image"`
+	Quantity     int    `json:"quantity"`
+	Unit         string `json:"unit"`
+	Weight       int    `json:"weight"`
+	RegulerPrice int64  `json:"reguler_price"`
+	SalePrice    int64  `json:"sale_price"`
+	Stock        int    `json:"stock"`
+	Available    int    `json:"available"`
 }
 
 type ProductChildResponse struct {
@@ -50,7 +113,23 @@ type ProductHomeDetailResponse struct {
 	RegulerPrice int64                      `json:"reguler_price"`
 	Stock        int                        `json:"stock"`
 	Weight       int                        `json:"weight"`
+	ProductType  string                     `json:"product_type"`
+	Selling      ProductSellingResponse     `json:"selling"`
+	Rating       float64                    `json:"rating"`
+	RatingCount  int64                      `json:"rating_count"`
+	Bundle       *ProductBundleResponse     `json:"bundle"`
 	Child        []ProductChildHomeResponse `json:"child"`
+	Images       []ProductImageResponse     `json:"images"`
+	Breadcrumbs  []BreadcrumbResponse       `json:"breadcrumbs"`
+	Attributes   []ProductAttributeResponse `json:"attributes"`
+}
+
+type ProductAttributeResponse struct {
+	Code  string      `json:"code"`
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+	Unit  string      `json:"unit"`
 }
 
 type ProductChildHomeResponse struct {
@@ -60,4 +139,76 @@ type ProductChildHomeResponse struct {
 	RegulerPrice int64  `json:"reguler_price"`
 	SalePrice    int64  `json:"sale_price"`
 	Image        string `json:"image"`
-}
\ No newline at end of file
+}
+
+type ProductImageResponse struct {
+	ID        int64  `json:"id"`
+	ImageURL  string `json:"image_url"`
+	AltText   string `json:"alt_text"`
+	Position  int    `json:"position"`
+	IsPrimary bool   `json:"is_primary"`
+}
+
+type PriceListResponse struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	SalePrice int64      `json:"sale_price"`
+	StartAt   time.Time  `json:"start_at"`
+	EndAt     *time.Time `json:"end_at"`
+}
+
+type PriceHistoryResponse struct {
+	ID           int64     `json:"id"`
+	PriceListID  *int64    `json:"price_list_id"`
+	RegulerPrice int64     `json:"reguler_price"`
+	SalePrice    int64     `json:"sale_price"`
+	Source       string    `json:"source"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type ProductFacetsResponse struct {
+	Categories []FacetBucketResponse    `json:"categories"`
+	Prices     []FacetRangeResponse     `json:"prices"`
+	Units      []FacetBucketResponse    `json:"units"`
+	Weights    []FacetRangeResponse     `json:"weights"`
+	Ratings    []FacetRangeResponse     `json:"ratings"`
+	OnSale     int64                    `json:"on_sale"`
+	InStock    int64                    `json:"in_stock"`
+	Attributes []AttributeFacetResponse `json:"attributes"`
+}
+
+type AttributeFacetResponse struct {
+	Code   string                `json:"code"`
+	Label  string                `json:"label"`
+	Values []FacetBucketResponse `json:"values"`
+}
+
+type FacetBucketResponse struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	Count int64  `json:"count"`
+}
+
+type FacetRangeResponse struct {
+	Key   string   `json:"key"`
+	From  *float64 `json:"from"`
+	To    *float64 `json:"to"`
+	Count int64    `json:"count"`
+}
+
+type RelatedProductResponse struct {
+	ID           int64  `json:"id"`
+	ProductName  string `json:"product_name"`
+	ProductImage string `json:"product_image"`
+	CategoryName string `json:"category_name"`
+	SalePrice    int64  `json:"sale_price"`
+	RegulerPrice int64  `json:"reguler_price"`
+	Stock        int    `json:"stock"`
+	Reason       string `json:"reason"`
+}
+
+type ProductRatingResponse struct {
+	ProductID   int64   `json:"product_id"`
+	Rating      float64 `json:"rating"`
+	RatingCount int64   `json:"rating_count"`
+}
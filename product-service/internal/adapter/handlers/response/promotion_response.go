@@ -0,0 +1,58 @@
+package response
+
+import "time"
+
+type PromotionResponse struct {
+	ID              int64                   `json:"id"`
+	Name            string                  `json:"name"`
+	Description     string                  `json:"description"`
+	Type            string                  `json:"type"`
+	Value           int64                   `json:"value"`
+	BuyQuantity     int64                   `json:"buy_quantity"`
+	GetQuantity     int64                   `json:"get_quantity"`
+	CategorySlug    string                  `json:"category_slug"`
+	ProductID       *int64                  `json:"product_id"`
+	MinQuantity     int64                   `json:"min_quantity"`
+	MinSubtotal     int64                   `json:"min_subtotal"`
+	CustomerSegment string                  `json:"customer_segment"`
+	Priority        int                     `json:"priority"`
+	Stackable       bool                    `json:"stackable"`
+	Status          string                  `json:"status"`
+	StartAt         *time.Time              `json:"start_at"`
+	EndAt           *time.Time              `json:"end_at"`
+	Tiers           []PromotionTierResponse `json:"tiers"`
+}
+
+type PromotionTierResponse struct {
+	MinQuantity int64 `json:"min_quantity"`
+	Value       int64 `json:"value"`
+}
+
+type CartPriceResponse struct {
+	Items            []CartPriceLineResponse    `json:"items"`
+	Subtotal         int64                      `json:"subtotal"`
+	LineDiscount     int64                      `json:"line_discount"`
+	OrderDiscount    int64                      `json:"order_discount"`
+	OrderPromotions  []AppliedPromotionResponse `json:"order_promotions"`
+	ShippingFee      int64                      `json:"shipping_fee"`
+	ShippingDiscount int64                      `json:"shipping_discount"`
+	Total            int64                      `json:"total"`
+}
+
+type CartPriceLineResponse struct {
+	ProductID   int64                      `json:"product_id"`
+	ProductName string                     `json:"product_name"`
+	Quantity    int64                      `json:"quantity"`
+	UnitPrice   int64                      `json:"unit_price"`
+	Subtotal    int64                      `json:"subtotal"`
+	Discount    int64                      `json:"discount"`
+	Total       int64                      `json:"total"`
+	Promotions  []AppliedPromotionResponse `json:"promotions"`
+}
+
+type AppliedPromotionResponse struct {
+	PromotionID int64  `json:"promotion_id"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Discount    int64  `json:"discount"`
+}
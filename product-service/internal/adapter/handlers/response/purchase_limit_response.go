@@ -0,0 +1,27 @@
+package response
+
+import "time"
+
+type PurchaseLimitResponse struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	ProductID    *int64    `json:"product_id"`
+	CategorySlug string    `json:"category_slug"`
+	MaxQuantity  int64     `json:"max_quantity"`
+	WindowHours  int       `json:"window_hours"`
+	IsActive     bool      `json:"is_active"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type PurchaseLimitViolationResponse struct {
+	LimitID            int64   `json:"limit_id"`
+	Name               string  `json:"name"`
+	ProductID          *int64  `json:"product_id"`
+	CategorySlug       string  `json:"category_slug"`
+	MaxQuantity        int64   `json:"max_quantity"`
+	WindowHours        int     `json:"window_hours"`
+	Purchased          int64   `json:"purchased"`
+	Requested          int64   `json:"requested"`
+	Remaining          int64   `json:"remaining"`
+	AffectedProductIDs []int64 `json:"affected_product_ids"`
+}
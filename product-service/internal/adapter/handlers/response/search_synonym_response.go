@@ -0,0 +1,9 @@
+package response
+
+import "time"
+
+type SynonymResponse struct {
+	ID        int64     `json:"id"`
+	Terms     []string  `json:"terms"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,23 @@
+package response
+
+type SuggestResponse struct {
+	Query      string                    `json:"query"`
+	Products   []SuggestProductResponse  `json:"products"`
+	Categories []SuggestCategoryResponse `json:"categories"`
+	DidYouMean string                    `json:"did_you_mean,omitempty"`
+}
+
+type SuggestProductResponse struct {
+	ID           int64  `json:"id"`
+	ProductName  string `json:"product_name"`
+	ProductImage string `json:"product_image"`
+	CategoryName string `json:"category_name"`
+	CategorySlug string `json:"category_slug"`
+	SalePrice    int64  `json:"sale_price"`
+	RegulerPrice int64  `json:"reguler_price"`
+}
+
+type SuggestCategoryResponse struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
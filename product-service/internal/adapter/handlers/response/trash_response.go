@@ -0,0 +1,25 @@
+package response
+
+import "time"
+
+type ProductTrashResponse struct {
+	ID            int64     `json:"id"`
+	ProductName   string    `json:"product_name"`
+	SKU           string    `json:"sku"`
+	ProductImage  string    `json:"product_image"`
+	CategorySlug  string    `json:"category_slug"`
+	ProductStatus string    `json:"product_status"`
+	DeletedAt     time.Time `json:"deleted_at"`
+	PurgeAt       time.Time `json:"purge_at"`
+}
+
+type CategoryTrashResponse struct {
+	ID        int64     `json:"id"`
+	ParentID  *int64    `json:"parent_id"`
+	Name      string    `json:"name"`
+	Icon      string    `json:"icon"`
+	Slug      string    `json:"slug"`
+	Status    string    `json:"status"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
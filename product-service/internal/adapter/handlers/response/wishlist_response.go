@@ -0,0 +1,21 @@
+package response
+
+import "time"
+
+type WishlistItemResponse struct {
+	ID            int64     `json:"id"`
+	ProductID     int64     `json:"product_id"`
+	List          string    `json:"list"`
+	Quantity      int64     `json:"quantity"`
+	ProductName   string    `json:"product_name"`
+	ProductImage  string    `json:"product_image"`
+	ProductStatus string    `json:"product_status"`
+	RegulerPrice  int64     `json:"reguler_price"`
+	SalePrice     int64     `json:"sale_price"`
+	Price         int64     `json:"price"`
+	Stock         int64     `json:"stock"`
+	Unit          string    `json:"unit"`
+	InStock       bool      `json:"in_stock"`
+	Available     bool      `json:"available"`
+	CreatedAt     time.Time `json:"created_at"`
+}
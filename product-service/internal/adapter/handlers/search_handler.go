@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"product-service/config"
+	"product-service/internal/adapter"
+	"product-service/internal/adapter/handlers/request"
+	"product-service/internal/adapter/handlers/response"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/service"
+	"product-service/utils/conv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+type ISearchHandler interface {
+	Suggest(c echo.Context) error
+
+	GetSynonymsAdmin(c echo.Context) error
+	GetSynonymByIdAdmin(c echo.Context) error
+	CreateSynonym(c echo.Context) error
+	UpdateSynonym(c echo.Context) error
+	DeleteSynonym(c echo.Context) error
+}
+
+type searchHandler struct {
+	searchService service.ISearchService
+}
+
+// Suggest implements [ISearchHandler].
+func (s *searchHandler) Suggest(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	limit := 8
+	if c.QueryParam("limit") != "" {
+		perPage, err := conv.StringToInt64(c.QueryParam("limit"))
+		if err != nil || perPage <= 0 || perPage > 20 {
+			log.Errorf("[SearchHandler-1] Suggest: %s", "limit must be between 1 and 20")
+			resp.Message = "limit must be between 1 and 20"
+			resp.Data = nil
+			return c.JSON(http.StatusBadRequest, resp)
+		}
+		limit = int(perPage)
+	}
+
+	result, err := s.searchService.Suggest(ctx, c.QueryParam("q"), limit)
+	if err != nil {
+		log.Errorf("[SearchHandler-2] Suggest: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	respSuggest := response.SuggestResponse{
+		Query:      result.Query,
+		Products:   []response.SuggestProductResponse{},
+		Categories: []response.SuggestCategoryResponse{},
+		DidYouMean: result.DidYouMean,
+	}
+	for _, product := range result.Products {
+		respSuggest.Products = append(respSuggest.Products, response.SuggestProductResponse{
+			ID:           product.ID,
+			ProductName:  product.Name,
+			ProductImage: product.Image,
+			CategoryName: product.CategoryName,
+			CategorySlug: product.CategorySlug,
+			SalePrice:    int64(product.SalePrice),
+			RegulerPrice: int64(product.RegulerPrice),
+		})
+	}
+	for _, category := range result.Categories {
+		respSuggest.Categories = append(respSuggest.Categories, response.SuggestCategoryResponse{
+			Slug: category.Slug,
+			Name: category.Name,
+		})
+	}
+
+	resp.Message = "success"
+	resp.Data = respSuggest
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetSynonymsAdmin implements [ISearchHandler].
+func (s *searchHandler) GetSynonymsAdmin(c echo.Context) error {
+	var (
+		resp     = response.DefaultResponse{}
+		ctx      = c.Request().Context()
+		respList = []response.SynonymResponse{}
+	)
+
+	results, err := s.searchService.GetSynonyms(ctx)
+	if err != nil {
+		log.Errorf("[SearchHandler-1] GetSynonymsAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	for _, val := range results {
+		respList = append(respList, synonymEntityToResponse(val))
+	}
+
+	resp.Message = "success"
+	resp.Data = respList
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetSynonymByIdAdmin implements [ISearchHandler].
+func (s *searchHandler) GetSynonymByIdAdmin(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[SearchHandler-1] GetSynonymByIdAdmin: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	result, err := s.searchService.GetSynonymByID(ctx, id)
+	if err != nil {
+		log.Errorf("[SearchHandler-2] GetSynonymByIdAdmin: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Synonym not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = synonymEntityToResponse(*result)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CreateSynonym implements [ISearchHandler].
+func (s *searchHandler) CreateSynonym(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		req  = request.SynonymRequest{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[SearchHandler-1] CreateSynonym: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Validate(req); err != nil {
+		log.Errorf("[SearchHandler-2] CreateSynonym: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	err := s.searchService.CreateSynonym(ctx, entities.SearchSynonymEntity{Terms: req.Terms})
+	if err != nil {
+		log.Errorf("[SearchHandler-3] CreateSynonym: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		if errors.Is(err, service.ErrInvalidSynonym) {
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		}
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// UpdateSynonym implements [ISearchHandler].
+func (s *searchHandler) UpdateSynonym(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		req  = request.SynonymRequest{}
+	)
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[SearchHandler-1] UpdateSynonym: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err = c.Bind(&req); err != nil {
+		log.Errorf("[SearchHandler-2] UpdateSynonym: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err = c.Validate(req); err != nil {
+		log.Errorf("[SearchHandler-3] UpdateSynonym: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	err = s.searchService.UpdateSynonym(ctx, entities.SearchSynonymEntity{ID: id, Terms: req.Terms})
+	if err != nil {
+		log.Errorf("[SearchHandler-4] UpdateSynonym: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Synonym not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		if errors.Is(err, service.ErrInvalidSynonym) {
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		}
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+// DeleteSynonym implements [ISearchHandler].
+func (s *searchHandler) DeleteSynonym(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[SearchHandler-1] DeleteSynonym: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	err = s.searchService.DeleteSynonym(ctx, id)
+	if err != nil {
+		log.Errorf("[SearchHandler-2] DeleteSynonym: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Synonym not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+func synonymEntityToResponse(val entities.SearchSynonymEntity) response.SynonymResponse {
+	return response.SynonymResponse{
+		ID:        val.ID,
+		Terms:     val.Terms,
+		CreatedAt: val.CreatedAt,
+	}
+}
+
+func NewSearchHandler(e *echo.Echo, cfg *config.Config, searchService service.ISearchService) ISearchHandler {
+	searchHandler := &searchHandler{
+		searchService: searchService,
+	}
+
+	homeProduct := e.Group("/products")
+	homeProduct.GET("/suggest", searchHandler.Suggest)
+
+	mid := adapter.NewMiddlewareAdapter(cfg)
+	adminGroup := e.Group("/admin", mid.CheckToken())
+	adminGroup.GET("/search/synonyms", searchHandler.GetSynonymsAdmin)
+	adminGroup.GET("/search/synonyms/:id", searchHandler.GetSynonymByIdAdmin)
+	adminGroup.POST("/search/synonyms", searchHandler.CreateSynonym)
+	adminGroup.PUT("/search/synonyms/:id", searchHandler.UpdateSynonym)
+	adminGroup.DELETE("/search/synonyms/:id", searchHandler.DeleteSynonym)
+
+	return searchHandler
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"product-service/config"
+	"product-service/internal/adapter"
+	"product-service/internal/adapter/handlers/response"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/service"
+	"product-service/utils/conv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+type ITrashHandler interface {
+	GetProducts(c echo.Context) error
+	RestoreProduct(c echo.Context) error
+	GetCategories(c echo.Context) error
+	RestoreCategory(c echo.Context) error
+}
+
+type trashHandler struct {
+	trashService service.ITrashService
+}
+
+// GetProducts implements [ITrashHandler].
+func (t *trashHandler) GetProducts(c echo.Context) error {
+	var (
+		resp         = response.DefaultResponseWithPaginations{}
+		ctx          = c.Request().Context()
+		respProducts = []response.ProductTrashResponse{}
+	)
+
+	query, page, perPage := trashQuery(c)
+	results, totalData, totalPage, err := t.trashService.GetProducts(ctx, query)
+	if err != nil {
+		log.Errorf("[TrashHandler-1] GetProducts: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Data not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	for _, val := range results {
+		respProducts = append(respProducts, response.ProductTrashResponse{
+			ID:            val.ID,
+			ProductName:   val.Name,
+			SKU:           val.SKU,
+			ProductImage:  val.Image,
+			CategorySlug:  val.CategorySlug,
+			ProductStatus: val.Status,
+			DeletedAt:     val.DeletedAt,
+			PurgeAt:       val.PurgeAt,
+		})
+	}
+
+	resp.Message = "success"
+	resp.Data = respProducts
+	resp.Pagination = &response.Pagination{
+		Page:       page,
+		TotalCount: totalData,
+		PerPage:    perPage,
+		TotalPage:  totalPage,
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// RestoreProduct implements [ITrashHandler].
+func (t *trashHandler) RestoreProduct(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[TrashHandler-1] RestoreProduct: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	err = t.trashService.RestoreProduct(ctx, id)
+	if err != nil {
+		log.Errorf("[TrashHandler-2] RestoreProduct: %v", err)
+		return trashError(c, err, "Product not found in trash")
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetCategories implements [ITrashHandler].
+func (t *trashHandler) GetCategories(c echo.Context) error {
+	var (
+		resp           = response.DefaultResponseWithPaginations{}
+		ctx            = c.Request().Context()
+		respCategories = []response.CategoryTrashResponse{}
+	)
+
+	query, page, perPage := trashQuery(c)
+	results, totalData, totalPage, err := t.trashService.GetCategories(ctx, query)
+	if err != nil {
+		log.Errorf("[TrashHandler-1] GetCategories: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Data not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	for _, val := range results {
+		respCategories = append(respCategories, response.CategoryTrashResponse{
+			ID:        val.ID,
+			ParentID:  val.ParentID,
+			Name:      val.Name,
+			Icon:      val.Image,
+			Slug:      val.Slug,
+			Status:    val.Status,
+			DeletedAt: val.DeletedAt,
+			PurgeAt:   val.PurgeAt,
+		})
+	}
+
+	resp.Message = "success"
+	resp.Data = respCategories
+	resp.Pagination = &response.Pagination{
+		Page:       page,
+		TotalCount: totalData,
+		PerPage:    perPage,
+		TotalPage:  totalPage,
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// RestoreCategory implements [ITrashHandler].
+func (t *trashHandler) RestoreCategory(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		log.Errorf("[TrashHandler-1] RestoreCategory: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	err = t.trashService.RestoreCategory(ctx, id)
+	if err != nil {
+		log.Errorf("[TrashHandler-2] RestoreCategory: %v", err)
+		return trashError(c, err, "Category not found in trash")
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+func trashQuery(c echo.Context) (entities.QueryStringEntity, int64, int64) {
+	var page int64 = 1
+	if pageStr := c.QueryParam("page"); pageStr != "" {
+		page, _ = conv.StringToInt64(pageStr)
+		if page <= 0 {
+			page = 1
+		}
+	}
+
+	var perPage int64 = 10
+	if perPageStr := c.QueryParam("perPage"); perPageStr != "" {
+		perPage, _ = conv.StringToInt64(perPageStr)
+		if perPage <= 0 {
+			perPage = 10
+		}
+	}
+
+	return entities.QueryStringEntity{
+		Search: c.QueryParam("search"),
+		Page:   int(page),
+		Limit:  int(perPage),
+	}, page, perPage
+}
+
+func trashError(c echo.Context, err error, notFound string) error {
+	resp := response.DefaultResponse{Message: err.Error()}
+	switch {
+	case err.Error() == "404":
+		resp.Message = notFound
+		return c.JSON(http.StatusNotFound, resp)
+	case errors.Is(err, service.ErrCategoryInTrash), errors.Is(err, service.ErrSKUTaken), errors.Is(err, service.ErrParentCategoryInTrash):
+		return c.JSON(http.StatusConflict, resp)
+	}
+	return c.JSON(http.StatusInternalServerError, resp)
+}
+
+func NewTrashHandler(e *echo.Echo, cfg *config.Config, trashService service.ITrashService) ITrashHandler {
+	trashHandler := &trashHandler{
+		trashService: trashService,
+	}
+
+	mid := adapter.NewMiddlewareAdapter(cfg)
+	adminGroup := e.Group("/admin", mid.CheckToken())
+	adminGroup.GET("/products/trash", trashHandler.GetProducts)
+	adminGroup.POST("/products/trash/:id/restore", trashHandler.RestoreProduct)
+	adminGroup.GET("/categories/trash", trashHandler.GetCategories)
+	adminGroup.POST("/categories/trash/:id/restore", trashHandler.RestoreCategory)
+
+	return trashHandler
+}
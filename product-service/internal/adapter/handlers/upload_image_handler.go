@@ -46,8 +46,7 @@ func (u *uploadImage) UploadImage(c echo.Context) error {
 	}
 
 	defer src.Close()
-	fileBuffer := new(bytes.Buffer)
-	_, err = io.Copy(fileBuffer, src)
+	url, err := storeImage(u.storageHandler, src, file.Filename)
 	if err != nil {
 		log.Errorf("[UploadImage-3] UploadImage: %v", err)
 		resp.Message = err.Error()
@@ -55,22 +54,24 @@ func (u *uploadImage) UploadImage(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, resp)
 	}
 
-	newFileName := fmt.Sprintf("%s_%d%s", uuid.New().String(), time.Now().Unix(), getExtension(file.Filename))
-	uploadPath := fmt.Sprintf("public/uploads/%s", newFileName)
-	url, err := u.storageHandler.UploadFile(uploadPath, fileBuffer)
-	if err != nil {
-		log.Errorf("[UploadImage-4] UploadImage: %v", err)
-		resp.Message = err.Error()
-		resp.Data = nil
-		return c.JSON(http.StatusInternalServerError, resp)
-	}
-
 	resp.Message = "Success"
 	resp.Data = map[string]string{"image_url": url}
 
 	return c.JSON(http.StatusOK, resp)
 }
 
+// storeImage uploads src under a unique name in public/uploads and returns its public URL.
+func storeImage(storageHandler storage.ISupabase, src io.Reader, fileName string) (string, error) {
+	fileBuffer := new(bytes.Buffer)
+	if _, err := io.Copy(fileBuffer, src); err != nil {
+		return "", err
+	}
+
+	newFileName := fmt.Sprintf("%s_%d%s", uuid.New().String(), time.Now().Unix(), getExtension(fileName))
+	uploadPath := fmt.Sprintf("public/uploads/%s", newFileName)
+	return storageHandler.UploadFile(uploadPath, fileBuffer)
+}
+
 func getExtension(fileName string) string {
 	ext := "." + fileName[len(fileName)-3:] // Ambil 3 karakter terakhir untuk ekstensi
 	if len(fileName) > 4 && fileName[len(fileName)-4] == '.' {
@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"product-service/config"
+	"product-service/internal/adapter"
+	"product-service/internal/adapter/handlers/request"
+	"product-service/internal/adapter/handlers/response"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/service"
+	"product-service/utils/conv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+type IWishlistHandler interface {
+	GetAll(c echo.Context) error
+	Add(c echo.Context) error
+	Remove(c echo.Context) error
+	MoveToCart(c echo.Context) error
+	SaveForLater(c echo.Context) error
+}
+
+type wishlistHandler struct {
+	wishlistService service.IWishlistService
+}
+
+// GetAll implements [IWishlistHandler].
+func (wh *wishlistHandler) GetAll(c echo.Context) error {
+	var (
+		resp        = response.DefaultResponse{}
+		ctx         = c.Request().Context()
+		jwtUserData = entities.JwtUserData{}
+		respList    = []response.WishlistItemResponse{}
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		log.Errorf("[WishlistHandler-1] GetAll: %s", "data token not found")
+		resp.Message = "data token not found"
+		resp.Data = nil
+		return c.JSON(http.StatusNotFound, resp)
+	}
+
+	err := json.Unmarshal([]byte(user), &jwtUserData)
+	if err != nil {
+		log.Errorf("[WishlistHandler-2] GetAll: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	results, err := wh.wishlistService.GetAll(ctx, jwtUserData.UserID, wishlistList(c.QueryParam("list")))
+	if err != nil {
+		log.Errorf("[WishlistHandler-3] GetAll: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	for _, val := range results {
+		respList = append(respList, wishlistEntityToResponse(val))
+	}
+
+	resp.Message = "success"
+	resp.Data = respList
+	return c.JSON(http.StatusOK, resp)
+}
+
+// Add implements [IWishlistHandler].
+func (wh *wishlistHandler) Add(c echo.Context) error {
+	var (
+		resp        = response.DefaultResponse{}
+		ctx         = c.Request().Context()
+		req         = request.WishlistRequest{}
+		jwtUserData = entities.JwtUserData{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		log.Errorf("[WishlistHandler-1] Add: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Validate(req); err != nil {
+		log.Errorf("[WishlistHandler-2] Add: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	user := c.Get("user").(string)
+	if user == "" {
+		log.Errorf("[WishlistHandler-3] Add: %s", "data token not found")
+		resp.Message = "data token not found"
+		resp.Data = nil
+		return c.JSON(http.StatusNotFound, resp)
+	}
+
+	err := json.Unmarshal([]byte(user), &jwtUserData)
+	if err != nil {
+		log.Errorf("[WishlistHandler-4] Add: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	err = wh.wishlistService.Add(ctx, entities.WishlistItemEntity{
+		UserID:    jwtUserData.UserID,
+		ProductID: req.ProductID,
+		List:      wishlistList(req.List),
+		Quantity:  req.Quantity,
+	})
+	if err != nil {
+		log.Errorf("[WishlistHandler-5] Add: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Product not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// Remove implements [IWishlistHandler].
+func (wh *wishlistHandler) Remove(c echo.Context) error {
+	var (
+		resp        = response.DefaultResponse{}
+		ctx         = c.Request().Context()
+		jwtUserData = entities.JwtUserData{}
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		log.Errorf("[WishlistHandler-1] Remove: %s", "data token not found")
+		resp.Message = "data token not found"
+		resp.Data = nil
+		return c.JSON(http.StatusNotFound, resp)
+	}
+
+	err := json.Unmarshal([]byte(user), &jwtUserData)
+	if err != nil {
+		log.Errorf("[WishlistHandler-2] Remove: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	productID, err := conv.StringToInt64(c.Param("productId"))
+	if err != nil {
+		log.Errorf("[WishlistHandler-3] Remove: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	err = wh.wishlistService.Remove(ctx, jwtUserData.UserID, productID, wishlistList(c.QueryParam("list")))
+	if err != nil {
+		log.Errorf("[WishlistHandler-4] Remove: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Wishlist item not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+// MoveToCart implements [IWishlistHandler].
+func (wh *wishlistHandler) MoveToCart(c echo.Context) error {
+	var (
+		resp        = response.DefaultResponse{}
+		ctx         = c.Request().Context()
+		jwtUserData = entities.JwtUserData{}
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		log.Errorf("[WishlistHandler-1] MoveToCart: %s", "data token not found")
+		resp.Message = "data token not found"
+		resp.Data = nil
+		return c.JSON(http.StatusNotFound, resp)
+	}
+
+	err := json.Unmarshal([]byte(user), &jwtUserData)
+	if err != nil {
+		log.Errorf("[WishlistHandler-2] MoveToCart: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	productID, err := conv.StringToInt64(c.Param("productId"))
+	if err != nil {
+		log.Errorf("[WishlistHandler-3] MoveToCart: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	err = wh.wishlistService.MoveToCart(ctx, jwtUserData.UserID, productID, wishlistList(c.QueryParam("list")))
+	if err != nil {
+		log.Errorf("[WishlistHandler-4] MoveToCart: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Wishlist item not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+// SaveForLater implements [IWishlistHandler].
+func (wh *wishlistHandler) SaveForLater(c echo.Context) error {
+	var (
+		resp        = response.DefaultResponse{}
+		ctx         = c.Request().Context()
+		jwtUserData = entities.JwtUserData{}
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		log.Errorf("[WishlistHandler-1] SaveForLater: %s", "data token not found")
+		resp.Message = "data token not found"
+		resp.Data = nil
+		return c.JSON(http.StatusNotFound, resp)
+	}
+
+	err := json.Unmarshal([]byte(user), &jwtUserData)
+	if err != nil {
+		log.Errorf("[WishlistHandler-2] SaveForLater: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	productID, err := conv.StringToInt64(c.Param("productId"))
+	if err != nil {
+		log.Errorf("[WishlistHandler-3] SaveForLater: %v", err)
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	err = wh.wishlistService.SaveForLater(ctx, jwtUserData.UserID, productID)
+	if err != nil {
+		log.Errorf("[WishlistHandler-4] SaveForLater: %v", err)
+		if err.Error() == "404" {
+			resp.Message = "Cart item not found"
+			resp.Data = nil
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = err.Error()
+		resp.Data = nil
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "success"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+// wishlistList defaults an empty list name to the wishlist itself.
+func wishlistList(list string) string {
+	if list == "" {
+		return entities.WishlistListWishlist
+	}
+	return list
+}
+
+func wishlistEntityToResponse(val entities.WishlistItemEntity) response.WishlistItemResponse {
+	resp := response.WishlistItemResponse{
+		ID:        val.ID,
+		ProductID: val.ProductID,
+		List:      val.List,
+		Quantity:  val.Quantity,
+		CreatedAt: val.CreatedAt,
+	}
+
+	if val.Product != nil {
+		resp.ProductName = val.Product.Name
+		resp.ProductImage = val.Product.Image
+		resp.ProductStatus = val.Product.Status
+		resp.RegulerPrice = int64(val.Product.RegulerPrice)
+		resp.SalePrice = int64(val.Product.SalePrice)
+		resp.Price = int64(val.Product.UnitPrice())
+		resp.Stock = int64(val.Product.Stock)
+		resp.Unit = val.Product.Unit
+		resp.InStock = val.Product.Stock > 0
+		resp.Available = val.Product.IsPublished() && val.Product.Stock > 0
+	}
+
+	return resp
+}
+
+func NewWishlistHandler(e *echo.Echo, cfg *config.Config, wishlistService service.IWishlistService) IWishlistHandler {
+	wishlistHandler := &wishlistHandler{
+		wishlistService: wishlistService,
+	}
+
+	mid := adapter.NewMiddlewareAdapter(cfg)
+	authGroup := e.Group("/auth", mid.CheckToken())
+	authGroup.GET("/wishlist", wishlistHandler.GetAll)
+	authGroup.POST("/wishlist", wishlistHandler.Add)
+	authGroup.DELETE("/wishlist/:productId", wishlistHandler.Remove)
+	authGroup.POST("/wishlist/:productId/move-to-cart", wishlistHandler.MoveToCart)
+	authGroup.POST("/cart/items/:productId/save-for-later", wishlistHandler.SaveForLater)
+
+	return wishlistHandler
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"product-service/config"
+	"product-service/internal/adapter/repository"
 	"product-service/internal/core/domain/entities"
 
 	"github.com/labstack/gommon/log"
@@ -59,6 +60,8 @@ func StartDeleteOrderConsumer() {
 		log.Errorf("[StartDeleteOrderConsumer-5] Failed initialize Elasticsearch client: %v", err)
 	}
 
+	catalogCacheRepo := repository.NewCatalogCacheRepository(config.NewConfig().NewRedisClient())
+
 	forever := make(chan bool)
 
 	go func() {
@@ -78,6 +81,10 @@ func StartDeleteOrderConsumer() {
 			}
 			
 			res.Body.Close()
+
+			if err := catalogCacheRepo.Invalidate(context.Background(), entities.CatalogScopeProducts); err != nil {
+				log.Errorf("[StartDeleteOrderConsumer-9] Error invalidating catalog cache: %v", err)
+			}
 		}
 	}()
 
@@ -135,6 +142,10 @@ func StartConsumer() {
 		log.Errorf("[StartConsumer-5] Failed initialize Elasticsearch client: %v", err)
 	}
 
+	// the shop lists come from the index, so cached lists are dropped once
+	// a product is indexed, not when it was saved
+	catalogCacheRepo := repository.NewCatalogCacheRepository(config.NewConfig().NewRedisClient())
+
 	forever := make(chan bool)
 	go func() {
 		for d := range msgs {
@@ -145,6 +156,22 @@ func StartConsumer() {
 				continue
 			}
 
+			// only published products belong in the index, any other state
+			// takes the product out of search
+			if !product.IsPublished() {
+				res, err := esClient.Delete("products", fmt.Sprintf("%d", product.ID))
+				if err != nil {
+					log.Errorf("[StartConsumer-11] Error deleting from Elasticsearch: %v", err)
+					continue
+				}
+				res.Body.Close()
+
+				if err := catalogCacheRepo.Invalidate(context.Background(), entities.CatalogScopeProducts); err != nil {
+					log.Errorf("[StartConsumer-12] Error invalidating catalog cache: %v", err)
+				}
+				continue
+			}
+
 			// Convert product struct ke JSON
 			productJSON, err := json.Marshal(product)
 			if err != nil {
@@ -167,7 +194,11 @@ func StartConsumer() {
 			
 			body, _ := io.ReadAll(res.Body)
 			res.Body.Close()
-			log.Infof("[StartConsumer-9] Product %d berhasil diindex ke Elasticsearch %v", product.ID, string(body))	
+			log.Infof("[StartConsumer-9] Product %d berhasil diindex ke Elasticsearch %v", product.ID, string(body))
+
+			if err := catalogCacheRepo.Invalidate(context.Background(), entities.CatalogScopeProducts); err != nil {
+				log.Errorf("[StartConsumer-12] Error invalidating catalog cache: %v", err)
+			}
 		}
 	}()
 
@@ -1,10 +1,11 @@
 package message
 
 import (
+	"context"
 	"encoding/json"
 	"product-service/config"
+	"product-service/internal/adapter/repository"
 	"product-service/internal/core/domain/entities"
-	"product-service/internal/core/domain/models"
 
 	"github.com/labstack/gommon/log"
 )
@@ -60,6 +61,9 @@ func StartUpdateStockConsumer() {
 		return
 	}
 
+	productRepo := repository.NewProductRepository(db.DB, nil)
+	publisher := NewPublishRabbitMQ(config.NewConfig())
+
 	log.Info("RabbitMQ Consumer started...")
 	for msg := range msgs {
 		var orderItem entities.PublishOrderItemEntity
@@ -68,22 +72,23 @@ func StartUpdateStockConsumer() {
 			continue
 		}
 
-		// Simulasi update stok
-		var product models.Product
-		if err := db.DB.First(&product, orderItem.ProductID).Error; err != nil {
-			log.Errorf("[StartUpdateStockConsumer-6] Failed to find product: %v", err)
+		// a bundle takes its stock from its components
+		bundleIDs, err := productRepo.DecrementStock(context.Background(), orderItem.ProductID, int(orderItem.Quantity))
+		if err != nil {
+			log.Errorf("[StartUpdateStockConsumer-6] Failed to update stock: %v", err)
 			continue
 		}
 
-		if product.Stock < int(orderItem.Quantity) {
-			log.Errorf("[StartUpdateStockConsumer-7] Stock not enough")
-			continue
-		}
+		for _, bundleID := range bundleIDs {
+			bundle, err := productRepo.GetByID(context.Background(), bundleID)
+			if err != nil {
+				log.Errorf("[StartUpdateStockConsumer-7] Failed to find bundle: %v", err)
+				continue
+			}
 
-		product.Stock -= int(orderItem.Quantity)
-		if err := db.DB.Save(&product).Error; err != nil {
-			log.Errorf("[StartUpdateStockConsumer-8] Failed to update stock: %v", err)
-			continue
+			if err := publisher.PublishProductToQueue(*bundle); err != nil {
+				log.Errorf("[StartUpdateStockConsumer-8] Failed to publish bundle: %v", err)
+			}
 		}
 		log.Printf("Mengurangi stok produk %d sebanyak %d", orderItem.ProductID, orderItem.Quantity)
 	}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"product-service/config"
 	"product-service/internal/core/domain/entities"
+	"product-service/utils"
 
 	"github.com/labstack/gommon/log"
 	"github.com/streadway/amqp"
@@ -13,6 +14,7 @@ import (
 type IPublishRabbitMQ interface {
 	PublishProductToQueue(product entities.ProductEntity) error
 	DeleteProductFromQueue(productID int64) error
+	PublishPushNotif(userID int64, subject, message string) error
 }
 
 type PublishRabbitMQ struct {
@@ -120,6 +122,65 @@ func (p *PublishRabbitMQ) PublishProductToQueue(product entities.ProductEntity)
 	return nil
 }
 
+// PublishPushNotif implements [IPublishRabbitMQ].
+func (p *PublishRabbitMQ) PublishPushNotif(userID int64, subject, message string) error {
+	conn, err := p.cfg.NewRabbitMQ()
+	if err != nil {
+		log.Errorf("[PublishPushNotif-1] Failed to connect to RabbitMQ: %v", err)
+		return err
+	}
+
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		log.Errorf("[PublishPushNotif-2] Failed to open a channel: %v", err)
+		return err
+	}
+
+	defer ch.Close()
+	q, err := ch.QueueDeclare(
+		utils.PUSH_NOTIF,
+		true,
+		false,
+		false,
+		false,
+		nil,
+	)
+
+	if err != nil {
+		log.Errorf("[PublishPushNotif-3] Failed to declare queue: %v", err)
+		return err
+	}
+
+	notification := map[string]interface{}{
+		"receiver_email":    "",
+		"message":           message,
+		"subject":           subject,
+		"receiver_id":       userID,
+		"notification_type": "PUSH",
+	}
+
+	data, _ := json.Marshal(notification)
+	err = ch.Publish(
+		"",
+		q.Name,
+		false,
+		false,
+		amqp.Publishing{
+			ContentType:  "application/json",
+			Body:         data,
+			DeliveryMode: amqp.Persistent,
+		},
+	)
+	if err != nil {
+		log.Errorf("[PublishPushNotif-4] Failed to publish message: %v", err)
+		return err
+	}
+
+	return nil
+}
+
 func NewPublishRabbitMQ(cfg *config.Config) IPublishRabbitMQ {
 	return &PublishRabbitMQ{
 		cfg: cfg,
@@ -1,6 +1,7 @@
 package adapter
 
 import (
+	"crypto/subtle"
 	"encoding/json"
 	"net/http"
 	"product-service/config"
@@ -16,6 +17,8 @@ import (
 
 type IMiddleware interface {
 	CheckToken() echo.MiddlewareFunc
+	OptionalToken() echo.MiddlewareFunc
+	CheckServiceKey() echo.MiddlewareFunc
 }
 
 type middlewareAdapter struct {
@@ -84,6 +87,62 @@ func (m *middlewareAdapter) CheckToken() echo.MiddlewareFunc {
 	}
 }
 
+// OptionalToken sets the user like CheckToken when the request carries a
+// valid token, and lets the request through anonymously otherwise.
+func (m *middlewareAdapter) OptionalToken() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			authHeader := c.Request().Header.Get("Authorization")
+			if authHeader == "" {
+				return next(c)
+			}
+
+			tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+			_, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+					return nil, jwt.ErrSignatureInvalid
+				}
+
+				return []byte(m.cfg.App.JwtSecretKey), nil
+			})
+			if err != nil {
+				log.Infof("[MiddlewareAdapter-1] OptionalToken: %s", err.Error())
+				return next(c)
+			}
+
+			redisConn := config.NewConfig().NewRedisClient()
+			getSession, err := redisConn.Get(c.Request().Context(), tokenString).Result()
+			if err != nil || len(getSession) == 0 {
+				log.Infof("[MiddlewareAdapter-2] OptionalToken: %s", "session not found")
+				return next(c)
+			}
+
+			c.Set("user", getSession)
+			return next(c)
+		}
+	}
+}
+
+// CheckServiceKey lets through only the other services, which send the shared
+// INTERNAL_API_KEY in X-Service-Key. Without a key configured nobody passes.
+func (m *middlewareAdapter) CheckServiceKey() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			respErr := response.DefaultResponse{}
+			serviceKey := c.Request().Header.Get("X-Service-Key")
+			if m.cfg.App.InternalApiKey == "" || subtle.ConstantTimeCompare([]byte(serviceKey), []byte(m.cfg.App.InternalApiKey)) != 1 {
+				log.Errorf("[MiddlewareAdapter-1] CheckServiceKey: %s", "invalid service key")
+				respErr.Message = "invalid service key"
+				respErr.Data = nil
+				return c.JSON(http.StatusUnauthorized, respErr)
+			}
+
+			return next(c)
+		}
+	}
+}
+
 func NewMiddlewareAdapter(cfg *config.Config) *middlewareAdapter {
 	return &middlewareAdapter{
 		cfg: cfg,
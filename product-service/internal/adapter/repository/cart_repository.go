@@ -5,30 +5,63 @@ import (
 	"encoding/json"
 	"fmt"
 	"product-service/internal/core/domain/entities"
+	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/labstack/gommon/log"
 )
 
+// Redis key scheme for carts, built only in this file:
+//
+//	cart:cart:<userID>    items of a signed-in customer
+//	cart:guest:<guestID>  items of an anonymous visitor
+//	cart:coupon:<userID>  coupon code applied to a customer cart
+//
+// Every read and write renews the idle TTL, so only abandoned carts expire.
 type ICartRepository interface {
-	AddToCart(ctx context.Context, userID string, items []entities.CartItem) error
-	GetCart(ctx context.Context, userID string) ([]entities.CartItem, error)
-	RemoveFromCart(ctx context.Context, userID int64, productID int64) error
-	RemoveAllCart(ctx context.Context, userID int64) error
+	GetCart(ctx context.Context, owner entities.CartOwner) ([]entities.CartItem, error)
+	SaveCart(ctx context.Context, owner entities.CartOwner, items []entities.CartItem) error
+	RemoveFromCart(ctx context.Context, owner entities.CartOwner, productID int64) error
+	RemoveCart(ctx context.Context, owner entities.CartOwner) error
+	MergeCart(ctx context.Context, from, to entities.CartOwner, merge func(fromItems, toItems []entities.CartItem) []entities.CartItem) error
+
+	SetCoupon(ctx context.Context, userID int64, code string) error
+	GetCoupon(ctx context.Context, userID int64) (string, error)
+	RemoveCoupon(ctx context.Context, userID int64) error
 }
 
+// cartMergeRetries is how often a merge is tried again when one of the carts
+// changes while it runs.
+const cartMergeRetries = 5
+
 type CartRepository struct {
-	Client *redis.Client
+	Client  *redis.Client
+	IdleTTL time.Duration
+}
+
+func cartKey(owner entities.CartOwner) string {
+	if owner.UserID == 0 {
+		return fmt.Sprintf("cart:guest:%s", owner.GuestID)
+	}
+	return fmt.Sprintf("cart:cart:%d", owner.UserID)
 }
 
-// RemoveAllCart implements [ICartRepository].
-func (c *CartRepository) RemoveAllCart(ctx context.Context, userID int64) error {
-	return c.Client.Del(ctx, fmt.Sprintf("cart:cart:%d", userID)).Err()
+func cartCouponKey(userID int64) string {
+	return fmt.Sprintf("cart:coupon:%d", userID)
+}
+
+// RemoveCart implements [ICartRepository].
+func (c *CartRepository) RemoveCart(ctx context.Context, owner entities.CartOwner) error {
+	keys := []string{cartKey(owner)}
+	if owner.UserID != 0 {
+		keys = append(keys, cartCouponKey(owner.UserID))
+	}
+	return c.Client.Del(ctx, keys...).Err()
 }
 
 // RemoveFromCart implements [ICartRepository].
-func (c *CartRepository) RemoveFromCart(ctx context.Context, userID int64, productID int64) error {
-	cart, err := c.GetCart(ctx, fmt.Sprintf("cart:%d", userID))
+func (c *CartRepository) RemoveFromCart(ctx context.Context, owner entities.CartOwner, productID int64) error {
+	cart, err := c.GetCart(ctx, owner)
 	if err != nil {
 		log.Errorf("[CartRedisRepository-1] RemoveFromCart: %v", err)
 		return err
@@ -41,18 +74,13 @@ func (c *CartRepository) RemoveFromCart(ctx context.Context, userID int64, produ
 		}
 	}
 
-	err = c.Client.Del(ctx, fmt.Sprintf("cart:cart:%d", userID)).Err()
-	if err != nil {
-		log.Errorf("[CartRedisRepository-2] RemoveFromCart: %v", err)
-		return err
-	}
-
-	return c.AddToCart(ctx, fmt.Sprintf("cart:%d", userID), newCart)
+	return c.SaveCart(ctx, owner, newCart)
 }
 
 // GetCart implements [ICartRepository].
-func (c *CartRepository) GetCart(ctx context.Context, userID string) ([]entities.CartItem, error) {
-	val, err := c.Client.Get(ctx, fmt.Sprintf("cart:%s", userID)).Result()
+func (c *CartRepository) GetCart(ctx context.Context, owner entities.CartOwner) ([]entities.CartItem, error) {
+	key := cartKey(owner)
+	val, err := c.Client.Get(ctx, key).Result()
 
 	if err == redis.Nil {
 		log.Infof("[CartRedisRepository-1] GetCart: Cart not found")
@@ -72,21 +100,135 @@ func (c *CartRepository) GetCart(ctx context.Context, userID string) ([]entities
 		return nil, err
 	}
 
+	if err := c.touch(ctx, key); err != nil {
+		log.Errorf("[CartRedisRepository-4] GetCart: %v", err)
+	}
+	if owner.UserID != 0 {
+		if err := c.touch(ctx, cartCouponKey(owner.UserID)); err != nil {
+			log.Errorf("[CartRedisRepository-5] GetCart: %v", err)
+		}
+	}
+
 	return items, nil
 }
 
-// AddToCart implements [ICartRepository].
-func (c *CartRepository) AddToCart(ctx context.Context, userID string, items []entities.CartItem) error {
+// SaveCart implements [ICartRepository]. An empty cart deletes the key.
+func (c *CartRepository) SaveCart(ctx context.Context, owner entities.CartOwner, items []entities.CartItem) error {
+	if len(items) == 0 {
+		return c.Client.Del(ctx, cartKey(owner)).Err()
+	}
+
 	data, err := json.Marshal(items)
 	if err != nil {
-		log.Errorf("[CartRedisRepository-1] AddToCart: %v", err)
+		log.Errorf("[CartRedisRepository-1] SaveCart: %v", err)
+		return err
+	}
+	return c.Client.Set(ctx, cartKey(owner), data, c.IdleTTL).Err()
+}
+
+// MergeCart implements [ICartRepository]. Both carts are watched, and the
+// merged cart is written and from removed in one transaction, tried again
+// when either cart changes in between. Once from is gone there is nothing
+// left to merge, so merging twice adds nothing twice.
+func (c *CartRepository) MergeCart(ctx context.Context, from, to entities.CartOwner, merge func(fromItems, toItems []entities.CartItem) []entities.CartItem) error {
+	fromKey, toKey := cartKey(from), cartKey(to)
+
+	txf := func(tx *redis.Tx) error {
+		fromItems, err := readCartItems(ctx, tx, fromKey)
+		if err != nil || len(fromItems) == 0 {
+			return err
+		}
+
+		toItems, err := readCartItems(ctx, tx, toKey)
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(merge(fromItems, toItems))
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, toKey, data, c.IdleTTL)
+			pipe.Del(ctx, fromKey)
+			return nil
+		})
 		return err
 	}
-	return c.Client.Set(ctx, fmt.Sprintf("cart:%s", userID), data, 0).Err()
+
+	var err error
+	for i := 0; i < cartMergeRetries; i++ {
+		err = c.Client.Watch(ctx, txf, fromKey, toKey)
+		if err != redis.TxFailedErr {
+			break
+		}
+	}
+	if err != nil {
+		log.Errorf("[CartRedisRepository-1] MergeCart: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// readCartItems reads the cart stored at key, none when it does not exist.
+func readCartItems(ctx context.Context, cmd redis.Cmdable, key string) ([]entities.CartItem, error) {
+	val, err := cmd.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var items []entities.CartItem
+	if err := json.Unmarshal([]byte(val), &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// SetCoupon implements [ICartRepository].
+func (c *CartRepository) SetCoupon(ctx context.Context, userID int64, code string) error {
+	return c.Client.Set(ctx, cartCouponKey(userID), code, c.IdleTTL).Err()
+}
+
+// GetCoupon implements [ICartRepository].
+func (c *CartRepository) GetCoupon(ctx context.Context, userID int64) (string, error) {
+	code, err := c.Client.Get(ctx, cartCouponKey(userID)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+
+	if err != nil {
+		log.Errorf("[CartRedisRepository-1] GetCoupon: %v", err)
+		return "", err
+	}
+
+	return code, nil
+}
+
+// RemoveCoupon implements [ICartRepository].
+func (c *CartRepository) RemoveCoupon(ctx context.Context, userID int64) error {
+	return c.Client.Del(ctx, cartCouponKey(userID)).Err()
+}
+
+// touch slides the idle TTL forward; carts written before the TTL existed
+// pick it up on their next read.
+func (c *CartRepository) touch(ctx context.Context, key string) error {
+	if c.IdleTTL <= 0 {
+		return nil
+	}
+	return c.Client.Expire(ctx, key, c.IdleTTL).Err()
 }
 
-func NewCartRepository(client *redis.Client) ICartRepository {
+// NewCartRepository creates the Redis cart store. An idleTTL of zero keeps
+// carts forever.
+func NewCartRepository(client *redis.Client, idleTTL time.Duration) ICartRepository {
 	return &CartRepository{
-		Client: client,
+		Client:  client,
+		IdleTTL: idleTTL,
 	}
 }
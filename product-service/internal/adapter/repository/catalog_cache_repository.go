@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"product-service/internal/core/domain/entities"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/labstack/gommon/log"
+)
+
+// Redis keys for the public catalog, built only in this file:
+//
+//	catalog:version:<scope>           bumped on every change in the scope
+//	catalog:<scope>:<version>:<key>   cached response of a request
+//
+// Bumping the version invalidates every response of the scope at once; the
+// old entries expire on their own.
+type ICatalogCacheRepository interface {
+	Version(ctx context.Context, scope string) (int64, error)
+	Get(ctx context.Context, scope string, version int64, key string) (*entities.CatalogCacheEntity, error)
+	Set(ctx context.Context, scope string, version int64, key string, entry entities.CatalogCacheEntity, ttl time.Duration) error
+	Invalidate(ctx context.Context, scopes ...string) error
+}
+
+type catalogCacheRepository struct {
+	client *redis.Client
+}
+
+// Version implements [ICatalogCacheRepository]. A scope that never changed
+// is at version 0.
+func (r *catalogCacheRepository) Version(ctx context.Context, scope string) (int64, error) {
+	version, err := r.client.Get(ctx, fmt.Sprintf("catalog:version:%s", scope)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+
+	if err != nil {
+		log.Errorf("[CatalogCacheRepository-1] Version: %v", err)
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// Get implements [ICatalogCacheRepository]. A cache miss returns nil without
+// an error.
+func (r *catalogCacheRepository) Get(ctx context.Context, scope string, version int64, key string) (*entities.CatalogCacheEntity, error) {
+	val, err := r.client.Get(ctx, catalogEntryKey(scope, version, key)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+
+	if err != nil {
+		log.Errorf("[CatalogCacheRepository-1] Get: %v", err)
+		return nil, err
+	}
+
+	var entry entities.CatalogCacheEntity
+	if err := json.Unmarshal(val, &entry); err != nil {
+		log.Errorf("[CatalogCacheRepository-2] Get: %v", err)
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// Set implements [ICatalogCacheRepository]. The version is the one read
+// before the response was built, so a response built while the scope
+// changed is stored where nobody looks for it.
+func (r *catalogCacheRepository) Set(ctx context.Context, scope string, version int64, key string, entry entities.CatalogCacheEntity, ttl time.Duration) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Errorf("[CatalogCacheRepository-1] Set: %v", err)
+		return err
+	}
+
+	if err := r.client.Set(ctx, catalogEntryKey(scope, version, key), data, ttl).Err(); err != nil {
+		log.Errorf("[CatalogCacheRepository-2] Set: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Invalidate implements [ICatalogCacheRepository].
+func (r *catalogCacheRepository) Invalidate(ctx context.Context, scopes ...string) error {
+	pipe := r.client.TxPipeline()
+	for _, scope := range scopes {
+		pipe.Incr(ctx, fmt.Sprintf("catalog:version:%s", scope))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Errorf("[CatalogCacheRepository-1] Invalidate: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func catalogEntryKey(scope string, version int64, key string) string {
+	return fmt.Sprintf("catalog:%s:%d:%s", scope, version, key)
+}
+
+func NewCatalogCacheRepository(client *redis.Client) ICatalogCacheRepository {
+	return &catalogCacheRepository{
+		client: client,
+	}
+}
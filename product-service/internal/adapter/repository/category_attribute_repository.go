@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/domain/models"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+)
+
+type ICategoryAttributeRepository interface {
+	GetByCategoryIDs(ctx context.Context, categoryIDs []int64) ([]entities.CategoryAttributeEntity, error)
+	GetByID(ctx context.Context, categoryID, id int64) (*entities.CategoryAttributeEntity, error)
+	Create(ctx context.Context, req entities.CategoryAttributeEntity) (int64, error)
+	Update(ctx context.Context, req entities.CategoryAttributeEntity) error
+	Delete(ctx context.Context, req entities.CategoryAttributeEntity, categorySlugs []string) ([]int64, error)
+}
+
+type categoryAttributeRepository struct {
+	db *gorm.DB
+}
+
+// GetByCategoryIDs implements [ICategoryAttributeRepository].
+func (c *categoryAttributeRepository) GetByCategoryIDs(ctx context.Context, categoryIDs []int64) ([]entities.CategoryAttributeEntity, error) {
+	results := []entities.CategoryAttributeEntity{}
+	if len(categoryIDs) == 0 {
+		return results, nil
+	}
+
+	modelAttributes := []models.CategoryAttribute{}
+	if err := c.db.WithContext(ctx).Where("category_id IN ?", categoryIDs).Order("position asc, id asc").Find(&modelAttributes).Error; err != nil {
+		log.Errorf("[CategoryAttributeRepository-1] GetByCategoryIDs: %v", err)
+		return nil, err
+	}
+
+	for _, val := range modelAttributes {
+		results = append(results, categoryAttributeModelToEntity(val))
+	}
+
+	return results, nil
+}
+
+// GetByID implements [ICategoryAttributeRepository].
+func (c *categoryAttributeRepository) GetByID(ctx context.Context, categoryID, id int64) (*entities.CategoryAttributeEntity, error) {
+	modelAttribute := models.CategoryAttribute{}
+	if err := c.db.WithContext(ctx).Where("id = ? AND category_id = ?", id, categoryID).First(&modelAttribute).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = errors.New("404")
+		}
+		log.Errorf("[CategoryAttributeRepository-1] GetByID: %v", err)
+		return nil, err
+	}
+
+	result := categoryAttributeModelToEntity(modelAttribute)
+	return &result, nil
+}
+
+// Create implements [ICategoryAttributeRepository].
+func (c *categoryAttributeRepository) Create(ctx context.Context, req entities.CategoryAttributeEntity) (int64, error) {
+	modelAttribute := models.CategoryAttribute{
+		CategoryID:   req.CategoryID,
+		Code:         req.Code,
+		Name:         req.Name,
+		Type:         req.Type,
+		Options:      attributeOptionsJSON(req.Options),
+		Unit:         req.Unit,
+		IsRequired:   req.IsRequired,
+		IsFilterable: req.IsFilterable,
+		Position:     req.Position,
+	}
+
+	// gorm skips false for a column with a default, so is_filterable is set
+	// explicitly
+	if err := c.db.WithContext(ctx).Select("*").Omit("id", "created_at", "updated_at").Create(&modelAttribute).Error; err != nil {
+		log.Errorf("[CategoryAttributeRepository-1] Create: %v", err)
+		return 0, err
+	}
+
+	return modelAttribute.ID, nil
+}
+
+// Update implements [ICategoryAttributeRepository]. The code and type stay
+// as they were created, since product values depend on them.
+func (c *categoryAttributeRepository) Update(ctx context.Context, req entities.CategoryAttributeEntity) error {
+	result := c.db.WithContext(ctx).Model(&models.CategoryAttribute{}).
+		Where("id = ? AND category_id = ?", req.ID, req.CategoryID).
+		Updates(map[string]interface{}{
+			"name":          req.Name,
+			"options":       attributeOptionsJSON(req.Options),
+			"unit":          req.Unit,
+			"is_required":   req.IsRequired,
+			"is_filterable": req.IsFilterable,
+			"position":      req.Position,
+			"updated_at":    time.Now(),
+		})
+	if result.Error != nil {
+		log.Errorf("[CategoryAttributeRepository-1] Update: %v", result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		err := errors.New("404")
+		log.Errorf("[CategoryAttributeRepository-2] Update: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Delete implements [ICategoryAttributeRepository]. The value of the
+// attribute is removed from the products in categorySlugs, the categories
+// the attribute applied to; it returns the ids of the top level products
+// that had one.
+func (c *categoryAttributeRepository) Delete(ctx context.Context, req entities.CategoryAttributeEntity, categorySlugs []string) ([]int64, error) {
+	productIDs := []int64{}
+
+	err := c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND category_id = ?", req.ID, req.CategoryID).Delete(&models.CategoryAttribute{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("404")
+		}
+
+		if len(categorySlugs) == 0 {
+			return nil
+		}
+
+		sqlProducts := tx.Model(&models.Product{}).
+			Where("category_slug IN ? AND jsonb_exists(attributes, ?)", categorySlugs, req.Code)
+		if err := sqlProducts.Session(&gorm.Session{}).Where("parent_id IS NULL").Pluck("id", &productIDs).Error; err != nil {
+			return err
+		}
+
+		return sqlProducts.Update("attributes", gorm.Expr("attributes - ?", req.Code)).Error
+	})
+	if err != nil {
+		log.Errorf("[CategoryAttributeRepository-1] Delete: %v", err)
+		return nil, err
+	}
+
+	return productIDs, nil
+}
+
+func categoryAttributeModelToEntity(val models.CategoryAttribute) entities.CategoryAttributeEntity {
+	options := []string{}
+	if val.Options != "" {
+		if err := json.Unmarshal([]byte(val.Options), &options); err != nil {
+			log.Errorf("[CategoryAttributeRepository-1] categoryAttributeModelToEntity: %v", err)
+		}
+	}
+
+	return entities.CategoryAttributeEntity{
+		ID:           val.ID,
+		CategoryID:   val.CategoryID,
+		Code:         val.Code,
+		Name:         val.Name,
+		Type:         val.Type,
+		Options:      options,
+		Unit:         val.Unit,
+		IsRequired:   val.IsRequired,
+		IsFilterable: val.IsFilterable,
+		Position:     val.Position,
+	}
+}
+
+// attributeOptionsJSON stores the options of an enum; other types have none.
+func attributeOptionsJSON(options []string) string {
+	if len(options) == 0 {
+		return ""
+	}
+
+	data, err := json.Marshal(options)
+	if err != nil {
+		log.Errorf("[CategoryAttributeRepository-1] attributeOptionsJSON: %v", err)
+		return ""
+	}
+	return string(data)
+}
+
+func NewCategoryAttributeRepository(db *gorm.DB) ICategoryAttributeRepository {
+	return &categoryAttributeRepository{
+		db: db,
+	}
+}
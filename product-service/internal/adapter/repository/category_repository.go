@@ -7,6 +7,7 @@ import (
 	"math"
 	"product-service/internal/core/domain/entities"
 	"product-service/internal/core/domain/models"
+	"time"
 
 	"github.com/labstack/gommon/log"
 	"gorm.io/gorm"
@@ -19,9 +20,15 @@ type ICategoryRepository interface {
 	GetCategoryBySlug(ctx context.Context, slug string) (*entities.CategoryEntity, error)
 	CreateCategory(ctx context.Context, req entities.CategoryEntity) error
 	UpdateCategory(ctx context.Context, req entities.CategoryEntity) error
-	DeleteCategory(ctx context.Context, id int64) error
+	DeleteCategory(ctx context.Context, id int64, reassignSlug string) error
 
 	GetAllPublished(ctx context.Context) ([]entities.CategoryEntity, error)
+	GetPublishedBySlugs(ctx context.Context, slugs []string) ([]entities.CategoryEntity, error)
+	GetPublishedByIDs(ctx context.Context, ids []int64) ([]entities.CategoryEntity, error)
+	GetPublishedByParentIDs(ctx context.Context, parentIDs []int64) ([]entities.CategoryEntity, error)
+	GetAllNodes(ctx context.Context) ([]entities.CategoryEntity, error)
+	CountPublishedProducts(ctx context.Context) (map[string]int64, error)
+	GetIndexedProductIDs(ctx context.Context, slug string) ([]int64, error)
 }
 
 type categoryRepository struct {
@@ -59,6 +66,104 @@ func (c *categoryRepository) GetAllPublished(ctx context.Context) ([]entities.Ca
 
 }
 
+// GetPublishedBySlugs implements [ICategoryRepository].
+func (c *categoryRepository) GetPublishedBySlugs(ctx context.Context, slugs []string) ([]entities.CategoryEntity, error) {
+	return c.getPublishedIn(ctx, "slug", slugs)
+}
+
+// GetPublishedByIDs implements [ICategoryRepository].
+func (c *categoryRepository) GetPublishedByIDs(ctx context.Context, ids []int64) ([]entities.CategoryEntity, error) {
+	return c.getPublishedIn(ctx, "id", ids)
+}
+
+// GetPublishedByParentIDs implements [ICategoryRepository]. It returns the
+// published subcategories of all the parents, ordered by name.
+func (c *categoryRepository) GetPublishedByParentIDs(ctx context.Context, parentIDs []int64) ([]entities.CategoryEntity, error) {
+	return c.getPublishedIn(ctx, "parent_id", parentIDs)
+}
+
+// getPublishedIn returns the published categories whose column is one of
+// values. Unlike GetAllPublished it finds nothing without an error.
+func (c *categoryRepository) getPublishedIn(ctx context.Context, column string, values interface{}) ([]entities.CategoryEntity, error) {
+	modelCategories := []models.Category{}
+
+	if err := c.db.WithContext(ctx).Where("status = ?", true).Where(column+" IN (?)", values).Order("name asc").Find(&modelCategories).Error; err != nil {
+		log.Errorf("[CategoryRepository-1] getPublishedIn: %v", err)
+		return nil, err
+	}
+
+	entitiesCat := []entities.CategoryEntity{}
+	for _, val := range modelCategories {
+		entitiesCat = append(entitiesCat, entities.CategoryEntity{
+			ID:          val.ID,
+			ParentID:    val.ParentID,
+			Name:        val.Name,
+			Icon:        val.Icon,
+			Status:      entities.PublishedStatus,
+			Slug:        val.Slug,
+			Description: val.Description,
+		})
+	}
+
+	return entitiesCat, nil
+}
+
+// GetAllNodes implements [ICategoryRepository]. It returns every category,
+// published or not, with just the fields needed to walk the hierarchy.
+func (c *categoryRepository) GetAllNodes(ctx context.Context) ([]entities.CategoryEntity, error) {
+	modelCategories := []models.Category{}
+
+	if err := c.db.WithContext(ctx).Select("id, parent_id, name, icon, slug, status").Order("name asc").Find(&modelCategories).Error; err != nil {
+		log.Errorf("[CategoryRepository-1] GetAllNodes: %v", err)
+		return nil, err
+	}
+
+	entitiesCat := []entities.CategoryEntity{}
+	for _, val := range modelCategories {
+		status := entities.PublishedStatus
+		if !val.Status {
+			status = entities.UnpublishedStatus
+		}
+
+		entitiesCat = append(entitiesCat, entities.CategoryEntity{
+			ID:       val.ID,
+			ParentID: val.ParentID,
+			Name:     val.Name,
+			Icon:     val.Icon,
+			Status:   status,
+			Slug:     val.Slug,
+		})
+	}
+
+	return entitiesCat, nil
+}
+
+// CountPublishedProducts implements [ICategoryRepository]. It counts the
+// published top level products of each category, keyed by category slug.
+func (c *categoryRepository) CountPublishedProducts(ctx context.Context) (map[string]int64, error) {
+	rows := []struct {
+		CategorySlug string
+		Total        int64
+	}{}
+
+	err := c.db.WithContext(ctx).Model(&models.Product{}).
+		Select("category_slug, COUNT(*) AS total").
+		Where("parent_id IS NULL AND status ILIKE ?", entities.PublishedStatus).
+		Group("category_slug").
+		Scan(&rows).Error
+	if err != nil {
+		log.Errorf("[CategoryRepository-1] CountPublishedProducts: %v", err)
+		return nil, err
+	}
+
+	counts := map[string]int64{}
+	for _, row := range rows {
+		counts[row.CategorySlug] = row.Total
+	}
+
+	return counts, nil
+}
+
 // CreateCategory implements [ICategoryRepository].
 func (c *categoryRepository) CreateCategory(ctx context.Context, req entities.CategoryEntity) error {
 	status := true
@@ -87,33 +192,69 @@ func (c *categoryRepository) CreateCategory(ctx context.Context, req entities.Ca
 	return nil
 }
 
-// DeleteCategory implements [ICategoryRepository].
-func (c *categoryRepository) DeleteCategory(ctx context.Context, id int64) error {
-	categoryMdl := models.Category{}
+// DeleteCategory implements [ICategoryRepository]. Products of the category,
+// including those in the trash, are moved to reassignSlug first; without one
+// a category that still has products is not deleted and "304" is returned.
+func (c *categoryRepository) DeleteCategory(ctx context.Context, id int64, reassignSlug string) error {
+	return c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		categoryMdl := models.Category{}
+		if err := tx.Where("id = ?", id).First(&categoryMdl).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = errors.New("404")
+				log.Errorf("[CategoryRepository-1] DeleteCategory: %v", err)
+				return err
+			}
+			log.Errorf("[CategoryRepository-2] DeleteCategory: %v", err)
+			return err
+		}
 
-	// find product by category slug
-	if err := c.db.WithContext(ctx).Preload("Products").Where("id = ?", id).First(&categoryMdl).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			err = errors.New("404")
-			log.Errorf("[CategoryRepository-1] DeleteCategory: %v", err)
+		if reassignSlug != "" {
+			err := tx.Unscoped().Model(&models.Product{}).
+				Where("category_slug = ?", categoryMdl.Slug).
+				Updates(map[string]interface{}{"category_slug": reassignSlug, "updated_at": time.Now()}).Error
+			if err != nil {
+				log.Errorf("[CategoryRepository-3] DeleteCategory: %v", err)
+				return err
+			}
+		}
+
+		var countProduct int64
+		if err := tx.Unscoped().Model(&models.Product{}).Where("category_slug = ?", categoryMdl.Slug).Count(&countProduct).Error; err != nil {
+			log.Errorf("[CategoryRepository-4] DeleteCategory: %v", err)
 			return err
 		}
-		log.Errorf("[CategoryRepository-2] DeleteCategory: %v", err)
-		return err
-	}
 
-	if len(categoryMdl.Products) > 0 {
-		err := errors.New("304")
-		log.Errorf("[CategoryRepository-3] DeleteCategory: %v", "category has products")
-		return err
-	}
+		if countProduct > 0 {
+			err := errors.New("304")
+			log.Errorf("[CategoryRepository-5] DeleteCategory: %v", "category has products")
+			return err
+		}
 
-	if err := c.db.WithContext(ctx).Delete(&categoryMdl).Error; err != nil {
-		log.Errorf("[CategoryRepository-1] DeleteCategory: %v", err)
-		return err
+		if err := tx.Delete(&categoryMdl).Error; err != nil {
+			log.Errorf("[CategoryRepository-6] DeleteCategory: %v", err)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// GetIndexedProductIDs implements [ICategoryRepository]. It returns the ids
+// of the search documents holding products of the category; variants live in
+// the document of their parent.
+func (c *categoryRepository) GetIndexedProductIDs(ctx context.Context, slug string) ([]int64, error) {
+	productIDs := []int64{}
+
+	err := c.db.WithContext(ctx).Model(&models.Product{}).
+		Where("category_slug = ?", slug).
+		Distinct().
+		Pluck("COALESCE(parent_id, id)", &productIDs).Error
+	if err != nil {
+		log.Errorf("[CategoryRepository-1] GetIndexedProductIDs: %v", err)
+		return nil, err
 	}
 
-	return nil
+	return productIDs, nil
 }
 
 // GetAllCategory implements [ICategoryRepository].
@@ -256,6 +397,7 @@ func (c *categoryRepository) UpdateCategory(ctx context.Context, req entities.Ca
 		status = false
 	}
 
+	oldSlug := categoryMdl.Slug
 	categoryMdl.ParentID = req.ParentID
 	categoryMdl.Name = req.Name
 	categoryMdl.Icon = req.Icon
@@ -263,7 +405,22 @@ func (c *categoryRepository) UpdateCategory(ctx context.Context, req entities.Ca
 	categoryMdl.Slug = req.Slug
 	categoryMdl.Description = req.Description
 
-	err = c.db.WithContext(ctx).Save(&categoryMdl).Error
+	// products follow a new slug through ON UPDATE CASCADE; promotions and
+	// purchase limits have no foreign key and are moved here, those in the
+	// trash too so they still match once restored
+	err = c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&categoryMdl).Error; err != nil {
+			return err
+		}
+
+		if oldSlug == categoryMdl.Slug {
+			return nil
+		}
+		if err := tx.Unscoped().Model(&models.Promotion{}).Where("category_slug = ?", oldSlug).Update("category_slug", categoryMdl.Slug).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&models.PurchaseLimit{}).Where("category_slug = ?", oldSlug).Update("category_slug", categoryMdl.Slug).Error
+	})
 	if err != nil {
 		log.Errorf("[CategoryRepository-3] UpdateCategory: %v", err)
 		return err
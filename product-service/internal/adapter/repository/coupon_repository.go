@@ -0,0 +1,372 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/domain/models"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ICouponRepository interface {
+	GetAll(ctx context.Context, query entities.QueryStringEntity) ([]entities.CouponEntity, int64, int64, error)
+	GetByID(ctx context.Context, id int64) (*entities.CouponEntity, error)
+	GetByCode(ctx context.Context, code string) (*entities.CouponEntity, error)
+	GetExistingCodes(ctx context.Context, codes []string) ([]string, error)
+	Create(ctx context.Context, req entities.CouponEntity) error
+	CreateBulk(ctx context.Context, req []entities.CouponEntity) error
+	Update(ctx context.Context, req entities.CouponEntity) error
+	Delete(ctx context.Context, id int64) error
+
+	CountRedemptions(ctx context.Context, couponID, userID int64) (int64, error)
+	GetRedemptions(ctx context.Context, couponID int64) ([]entities.CouponRedemptionEntity, error)
+	Redeem(ctx context.Context, code string, userID int64, orderCode string, subtotal float64) (*entities.CouponRedemptionEntity, error)
+	ReleaseRedemption(ctx context.Context, userID int64, orderCode string) error
+}
+
+type couponRepository struct {
+	db *gorm.DB
+}
+
+// GetAll implements [ICouponRepository].
+func (c *couponRepository) GetAll(ctx context.Context, query entities.QueryStringEntity) ([]entities.CouponEntity, int64, int64, error) {
+	modelCoupons := []models.Coupon{}
+	var countData int64
+
+	order := fmt.Sprintf("%s %s", query.OrderBy, query.OrderType)
+	offset := (query.Page - 1) * query.Limit
+
+	sqlMain := c.db.WithContext(ctx).
+		Where("code ILIKE ? OR name ILIKE ?", "%"+query.Search+"%", "%"+query.Search+"%")
+	if err := sqlMain.Model(&modelCoupons).Count(&countData).Error; err != nil {
+		log.Errorf("[CouponRepository-1] GetAll: %v", err)
+		return nil, 0, 0, err
+	}
+
+	totalPage := int(math.Ceil(float64(countData) / float64(query.Limit)))
+	if err := sqlMain.Order(order).Limit(query.Limit).Offset(offset).Find(&modelCoupons).Error; err != nil {
+		log.Errorf("[CouponRepository-2] GetAll: %v", err)
+		return nil, 0, 0, err
+	}
+
+	if len(modelCoupons) == 0 {
+		err := errors.New("404")
+		log.Infof("[CouponRepository-3] GetAll: No coupon found")
+		return nil, 0, 0, err
+	}
+
+	respCoupons := []entities.CouponEntity{}
+	for _, val := range modelCoupons {
+		respCoupons = append(respCoupons, couponModelToEntity(val))
+	}
+
+	return respCoupons, countData, int64(totalPage), nil
+}
+
+// GetByID implements [ICouponRepository].
+func (c *couponRepository) GetByID(ctx context.Context, id int64) (*entities.CouponEntity, error) {
+	modelCoupon := models.Coupon{}
+	if err := c.db.WithContext(ctx).Where("id = ?", id).First(&modelCoupon).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = errors.New("404")
+		}
+		log.Errorf("[CouponRepository-1] GetByID: %v", err)
+		return nil, err
+	}
+
+	result := couponModelToEntity(modelCoupon)
+	return &result, nil
+}
+
+// GetByCode implements [ICouponRepository].
+func (c *couponRepository) GetByCode(ctx context.Context, code string) (*entities.CouponEntity, error) {
+	modelCoupon := models.Coupon{}
+	if err := c.db.WithContext(ctx).Where("code = ?", code).First(&modelCoupon).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = errors.New("404")
+		}
+		log.Errorf("[CouponRepository-1] GetByCode: %v", err)
+		return nil, err
+	}
+
+	result := couponModelToEntity(modelCoupon)
+	return &result, nil
+}
+
+// GetExistingCodes implements [ICouponRepository].
+func (c *couponRepository) GetExistingCodes(ctx context.Context, codes []string) ([]string, error) {
+	existing := []string{}
+	if len(codes) == 0 {
+		return existing, nil
+	}
+
+	if err := c.db.WithContext(ctx).Model(&models.Coupon{}).Where("code IN ?", codes).Pluck("code", &existing).Error; err != nil {
+		log.Errorf("[CouponRepository-1] GetExistingCodes: %v", err)
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+// Create implements [ICouponRepository].
+func (c *couponRepository) Create(ctx context.Context, req entities.CouponEntity) error {
+	modelCoupon := couponEntityToModel(req)
+	if err := c.db.WithContext(ctx).Create(&modelCoupon).Error; err != nil {
+		log.Errorf("[CouponRepository-1] Create: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// CreateBulk implements [ICouponRepository].
+func (c *couponRepository) CreateBulk(ctx context.Context, req []entities.CouponEntity) error {
+	modelCoupons := []models.Coupon{}
+	for _, val := range req {
+		modelCoupons = append(modelCoupons, couponEntityToModel(val))
+	}
+
+	if err := c.db.WithContext(ctx).CreateInBatches(&modelCoupons, 500).Error; err != nil {
+		log.Errorf("[CouponRepository-1] CreateBulk: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Update implements [ICouponRepository].
+func (c *couponRepository) Update(ctx context.Context, req entities.CouponEntity) error {
+	modelCoupon := models.Coupon{}
+	if err := c.db.WithContext(ctx).Where("id = ?", req.ID).First(&modelCoupon).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = errors.New("404")
+		}
+		log.Errorf("[CouponRepository-1] Update: %v", err)
+		return err
+	}
+
+	updated := couponEntityToModel(req)
+	updated.ID = modelCoupon.ID
+	updated.UsedCount = modelCoupon.UsedCount
+	updated.CreatedAt = modelCoupon.CreatedAt
+
+	if err := c.db.WithContext(ctx).Save(&updated).Error; err != nil {
+		log.Errorf("[CouponRepository-2] Update: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Delete implements [ICouponRepository].
+func (c *couponRepository) Delete(ctx context.Context, id int64) error {
+	result := c.db.WithContext(ctx).Where("id = ?", id).Delete(&models.Coupon{})
+	if result.Error != nil {
+		log.Errorf("[CouponRepository-1] Delete: %v", result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		err := errors.New("404")
+		log.Errorf("[CouponRepository-2] Delete: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// CountRedemptions implements [ICouponRepository].
+func (c *couponRepository) CountRedemptions(ctx context.Context, couponID int64, userID int64) (int64, error) {
+	var count int64
+	if err := c.db.WithContext(ctx).Model(&models.CouponRedemption{}).
+		Where("coupon_id = ? AND user_id = ?", couponID, userID).
+		Count(&count).Error; err != nil {
+		log.Errorf("[CouponRepository-1] CountRedemptions: %v", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetRedemptions implements [ICouponRepository].
+func (c *couponRepository) GetRedemptions(ctx context.Context, couponID int64) ([]entities.CouponRedemptionEntity, error) {
+	modelRedemptions := []models.CouponRedemption{}
+	if err := c.db.WithContext(ctx).Where("coupon_id = ?", couponID).Order("created_at desc").Find(&modelRedemptions).Error; err != nil {
+		log.Errorf("[CouponRepository-1] GetRedemptions: %v", err)
+		return nil, err
+	}
+
+	respRedemptions := []entities.CouponRedemptionEntity{}
+	for _, val := range modelRedemptions {
+		respRedemptions = append(respRedemptions, entities.CouponRedemptionEntity{
+			ID:        val.ID,
+			CouponID:  val.CouponID,
+			UserID:    val.UserID,
+			OrderCode: val.OrderCode,
+			Discount:  val.Discount,
+			CreatedAt: val.CreatedAt,
+		})
+	}
+
+	return respRedemptions, nil
+}
+
+// Redeem implements [ICouponRepository]. The coupon row is locked for the
+// whole transaction, so concurrent checkouts with the same code are
+// serialized and the usage limits are checked against committed counts.
+func (c *couponRepository) Redeem(ctx context.Context, code string, userID int64, orderCode string, subtotal float64) (*entities.CouponRedemptionEntity, error) {
+	var result *entities.CouponRedemptionEntity
+	err := c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		modelCoupon := models.Coupon{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("code = ?", code).First(&modelCoupon).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = errors.New("404")
+			}
+			log.Errorf("[CouponRepository-1] Redeem: %v", err)
+			return err
+		}
+
+		existing := models.CouponRedemption{}
+		err := tx.Where("coupon_id = ? AND order_code = ?", modelCoupon.ID, orderCode).First(&existing).Error
+		if err == nil {
+			// the order already holds this coupon, e.g. a retried checkout
+			result = couponRedemptionModelToEntity(existing, modelCoupon.Code)
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Errorf("[CouponRepository-2] Redeem: %v", err)
+			return err
+		}
+
+		var userRedemptions int64
+		if err := tx.Model(&models.CouponRedemption{}).Where("coupon_id = ? AND user_id = ?", modelCoupon.ID, userID).Count(&userRedemptions).Error; err != nil {
+			log.Errorf("[CouponRepository-3] Redeem: %v", err)
+			return err
+		}
+
+		coupon := couponModelToEntity(modelCoupon)
+		if err := coupon.Check(time.Now(), subtotal, userRedemptions); err != nil {
+			log.Errorf("[CouponRepository-4] Redeem: %v", err)
+			return err
+		}
+
+		redemption := models.CouponRedemption{
+			CouponID:  modelCoupon.ID,
+			UserID:    userID,
+			OrderCode: orderCode,
+			Discount:  coupon.Discount(subtotal),
+		}
+		if err := tx.Create(&redemption).Error; err != nil {
+			log.Errorf("[CouponRepository-5] Redeem: %v", err)
+			return err
+		}
+
+		if err := tx.Model(&modelCoupon).UpdateColumn("used_count", gorm.Expr("used_count + 1")).Error; err != nil {
+			log.Errorf("[CouponRepository-6] Redeem: %v", err)
+			return err
+		}
+
+		result = couponRedemptionModelToEntity(redemption, modelCoupon.Code)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ReleaseRedemption implements [ICouponRepository].
+func (c *couponRepository) ReleaseRedemption(ctx context.Context, userID int64, orderCode string) error {
+	return c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		modelRedemptions := []models.CouponRedemption{}
+		if err := tx.Where("user_id = ? AND order_code = ?", userID, orderCode).Find(&modelRedemptions).Error; err != nil {
+			log.Errorf("[CouponRepository-1] ReleaseRedemption: %v", err)
+			return err
+		}
+
+		if len(modelRedemptions) == 0 {
+			err := errors.New("404")
+			log.Errorf("[CouponRepository-2] ReleaseRedemption: %v", err)
+			return err
+		}
+
+		for _, val := range modelRedemptions {
+			if err := tx.Delete(&val).Error; err != nil {
+				log.Errorf("[CouponRepository-3] ReleaseRedemption: %v", err)
+				return err
+			}
+
+			if err := tx.Model(&models.Coupon{}).Where("id = ? AND used_count > 0", val.CouponID).
+				UpdateColumn("used_count", gorm.Expr("used_count - 1")).Error; err != nil {
+				log.Errorf("[CouponRepository-4] ReleaseRedemption: %v", err)
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func couponModelToEntity(val models.Coupon) entities.CouponEntity {
+	status := entities.PublishedStatus
+	if !val.Status {
+		status = entities.UnpublishedStatus
+	}
+
+	return entities.CouponEntity{
+		ID:               val.ID,
+		Code:             val.Code,
+		Name:             val.Name,
+		DiscountType:     val.DiscountType,
+		Value:            val.Value,
+		MaxDiscount:      val.MaxDiscount,
+		MinSpend:         val.MinSpend,
+		UsageLimit:       val.UsageLimit,
+		PerCustomerLimit: val.PerCustomerLimit,
+		UsedCount:        val.UsedCount,
+		Status:           status,
+		StartAt:          val.StartAt,
+		EndAt:            val.EndAt,
+	}
+}
+
+func couponEntityToModel(req entities.CouponEntity) models.Coupon {
+	return models.Coupon{
+		Code:             req.Code,
+		Name:             req.Name,
+		DiscountType:     req.DiscountType,
+		Value:            req.Value,
+		MaxDiscount:      req.MaxDiscount,
+		MinSpend:         req.MinSpend,
+		UsageLimit:       req.UsageLimit,
+		PerCustomerLimit: req.PerCustomerLimit,
+		Status:           req.Status != entities.UnpublishedStatus,
+		StartAt:          req.StartAt,
+		EndAt:            req.EndAt,
+	}
+}
+
+func couponRedemptionModelToEntity(val models.CouponRedemption, code string) *entities.CouponRedemptionEntity {
+	return &entities.CouponRedemptionEntity{
+		ID:        val.ID,
+		CouponID:  val.CouponID,
+		Code:      code,
+		UserID:    val.UserID,
+		OrderCode: val.OrderCode,
+		Discount:  val.Discount,
+		CreatedAt: val.CreatedAt,
+	}
+}
+
+func NewCouponRepository(db *gorm.DB) ICouponRepository {
+	return &couponRepository{
+		db: db,
+	}
+}
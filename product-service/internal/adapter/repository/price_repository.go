@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/domain/models"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+)
+
+type IPriceRepository interface {
+	GetPriceLists(ctx context.Context, productID int64) ([]entities.PriceListEntity, error)
+	CreatePriceList(ctx context.Context, req entities.PriceListEntity) (int64, error)
+	DeletePriceList(ctx context.Context, productID, priceListID int64) error
+	GetPriceHistory(ctx context.Context, productID int64, at *time.Time) ([]entities.PriceHistoryEntity, error)
+	ApplyScheduledPrices(ctx context.Context, now time.Time) ([]int64, error)
+}
+
+type priceRepository struct {
+	db *gorm.DB
+}
+
+// GetPriceLists implements [IPriceRepository].
+func (p *priceRepository) GetPriceLists(ctx context.Context, productID int64) ([]entities.PriceListEntity, error) {
+	modelPriceLists := []models.PriceList{}
+	if err := p.db.WithContext(ctx).Where("product_id = ?", productID).Order("start_at desc").Find(&modelPriceLists).Error; err != nil {
+		log.Errorf("[PriceRepository-1] GetPriceLists: %v", err)
+		return nil, err
+	}
+
+	respPriceLists := []entities.PriceListEntity{}
+	for _, val := range modelPriceLists {
+		respPriceLists = append(respPriceLists, entities.PriceListEntity{
+			ID:        val.ID,
+			ProductID: val.ProductID,
+			Name:      val.Name,
+			SalePrice: val.SalePrice,
+			StartAt:   val.StartAt,
+			EndAt:     val.EndAt,
+		})
+	}
+
+	return respPriceLists, nil
+}
+
+// CreatePriceList implements [IPriceRepository].
+func (p *priceRepository) CreatePriceList(ctx context.Context, req entities.PriceListEntity) (int64, error) {
+	modelProduct := models.Product{}
+	if err := p.db.WithContext(ctx).Select("id").First(&modelProduct, "id = ?", req.ProductID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = errors.New("404")
+		}
+		log.Errorf("[PriceRepository-1] CreatePriceList: %v", err)
+		return 0, err
+	}
+
+	modelPriceList := models.PriceList{
+		ProductID: req.ProductID,
+		Name:      req.Name,
+		SalePrice: req.SalePrice,
+		StartAt:   req.StartAt,
+		EndAt:     req.EndAt,
+	}
+
+	if err := p.db.WithContext(ctx).Create(&modelPriceList).Error; err != nil {
+		log.Errorf("[PriceRepository-2] CreatePriceList: %v", err)
+		return 0, err
+	}
+
+	return modelPriceList.ID, nil
+}
+
+// DeletePriceList implements [IPriceRepository].
+func (p *priceRepository) DeletePriceList(ctx context.Context, productID, priceListID int64) error {
+	result := p.db.WithContext(ctx).Where("id = ? AND product_id = ?", priceListID, productID).Delete(&models.PriceList{})
+	if result.Error != nil {
+		log.Errorf("[PriceRepository-1] DeletePriceList: %v", result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		err := errors.New("404")
+		log.Errorf("[PriceRepository-2] DeletePriceList: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetPriceHistory implements [IPriceRepository].
+func (p *priceRepository) GetPriceHistory(ctx context.Context, productID int64, at *time.Time) ([]entities.PriceHistoryEntity, error) {
+	modelHistories := []models.PriceHistory{}
+	sqlMain := p.db.WithContext(ctx).Where("product_id = ?", productID).Order("created_at desc, id desc")
+
+	// with a point in time only the price that was effective at that moment is returned
+	if at != nil {
+		sqlMain = sqlMain.Where("created_at <= ?", *at).Limit(1)
+	}
+
+	if err := sqlMain.Find(&modelHistories).Error; err != nil {
+		log.Errorf("[PriceRepository-1] GetPriceHistory: %v", err)
+		return nil, err
+	}
+
+	respHistories := []entities.PriceHistoryEntity{}
+	for _, val := range modelHistories {
+		respHistories = append(respHistories, entities.PriceHistoryEntity{
+			ID:           val.ID,
+			ProductID:    val.ProductID,
+			PriceListID:  val.PriceListID,
+			RegulerPrice: val.RegulerPrice,
+			SalePrice:    val.SalePrice,
+			Source:       val.Source,
+			CreatedAt:    val.CreatedAt,
+		})
+	}
+
+	return respHistories, nil
+}
+
+// ApplyScheduledPrices implements [IPriceRepository].
+// The active price list that started last wins; products without an active
+// list fall back to their base sale price. It returns the changed product IDs.
+func (p *priceRepository) ApplyScheduledPrices(ctx context.Context, now time.Time) ([]int64, error) {
+	activeLists := []models.PriceList{}
+	err := p.db.WithContext(ctx).
+		Where("start_at <= ? AND (end_at IS NULL OR end_at > ?)", now, now).
+		Order("product_id asc, start_at desc, id desc").
+		Find(&activeLists).Error
+	if err != nil {
+		log.Errorf("[PriceRepository-1] ApplyScheduledPrices: %v", err)
+		return nil, err
+	}
+
+	winners := map[int64]models.PriceList{}
+	productIDs := []int64{}
+	for _, val := range activeLists {
+		if _, ok := winners[val.ProductID]; ok {
+			continue
+		}
+		winners[val.ProductID] = val
+		productIDs = append(productIDs, val.ProductID)
+	}
+
+	modelProducts := []models.Product{}
+	sqlMain := p.db.WithContext(ctx).Where("price_list_id IS NOT NULL")
+	if len(productIDs) > 0 {
+		sqlMain = sqlMain.Or("id IN (?)", productIDs)
+	}
+	if err := sqlMain.Find(&modelProducts).Error; err != nil {
+		log.Errorf("[PriceRepository-2] ApplyScheduledPrices: %v", err)
+		return nil, err
+	}
+
+	changedIDs := []int64{}
+	for _, product := range modelProducts {
+		salePrice := product.BaseSalePrice
+		var priceListID *int64
+		if winner, ok := winners[product.ID]; ok {
+			salePrice = winner.SalePrice
+			priceListID = &winner.ID
+		}
+
+		if salePrice == product.SalePrice && equalInt64Pointer(priceListID, product.PriceListID) {
+			continue
+		}
+
+		err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			err := tx.Model(&models.Product{}).Where("id = ?", product.ID).Updates(map[string]interface{}{
+				"sale_price":    salePrice,
+				"price_list_id": priceListID,
+			}).Error
+			if err != nil {
+				return err
+			}
+
+			return recordPriceHistory(tx, product.ID, priceListID, product.RegulerPrice, salePrice, entities.PriceSourceSchedule)
+		})
+		if err != nil {
+			log.Errorf("[PriceRepository-3] ApplyScheduledPrices: %v", err)
+			return changedIDs, err
+		}
+
+		changedIDs = append(changedIDs, product.ID)
+	}
+
+	return changedIDs, nil
+}
+
+func recordPriceHistory(tx *gorm.DB, productID int64, priceListID *int64, regulerPrice, salePrice float64, source string) error {
+	history := models.PriceHistory{
+		ProductID:    productID,
+		PriceListID:  priceListID,
+		RegulerPrice: regulerPrice,
+		SalePrice:    salePrice,
+		Source:       source,
+	}
+
+	if err := tx.Create(&history).Error; err != nil {
+		log.Errorf("[PriceRepository-1] recordPriceHistory: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func equalInt64Pointer(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func NewPriceRepository(db *gorm.DB) IPriceRepository {
+	return &priceRepository{
+		db: db,
+	}
+}
@@ -0,0 +1,337 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/domain/models"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrStockNotEnough = errors.New("stock not enough")
+
+// GetBundleIDs implements [IProductRepository]. It returns the bundles that
+// have productID as a component.
+func (p *productRepository) GetBundleIDs(ctx context.Context, productID int64) ([]int64, error) {
+	bundleIDs := []int64{}
+	err := p.db.WithContext(ctx).Model(&models.BundleItem{}).
+		Joins("JOIN products ON products.id = bundle_items.bundle_id AND products.deleted_at IS NULL").
+		Where("bundle_items.component_id = ?", productID).
+		Distinct().
+		Pluck("bundle_items.bundle_id", &bundleIDs).Error
+	if err != nil {
+		log.Errorf("[ProductRepository-1] GetBundleIDs: %v", err)
+		return nil, err
+	}
+
+	return bundleIDs, nil
+}
+
+// SetBundle implements [IProductRepository]. It turns the product into a
+// bundle of the given components, replacing an earlier composition, and
+// derives its stock and, if computed, its price right away.
+func (p *productRepository) SetBundle(ctx context.Context, bundleID int64, bundle entities.BundleEntity) error {
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Product{}).Where("id = ?", bundleID).Updates(map[string]interface{}{
+			"type":            entities.ProductTypeBundle,
+			"bundle_pricing":  bundle.Pricing,
+			"bundle_discount": bundle.DiscountPercent,
+			"updated_at":      time.Now(),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("404")
+		}
+
+		if err := tx.Where("bundle_id = ?", bundleID).Delete(&models.BundleItem{}).Error; err != nil {
+			return err
+		}
+
+		modelItems := []models.BundleItem{}
+		for _, item := range bundle.Items {
+			modelItems = append(modelItems, models.BundleItem{
+				BundleID:    bundleID,
+				ComponentID: item.ComponentID,
+				Quantity:    item.Quantity,
+			})
+		}
+		if err := tx.Omit("Component").Create(&modelItems).Error; err != nil {
+			return err
+		}
+
+		return refreshBundle(tx, bundleID)
+	})
+	if err != nil {
+		log.Errorf("[ProductRepository-1] SetBundle: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// RemoveBundle implements [IProductRepository]. The product becomes a
+// simple product again and keeps its last stock and price.
+func (p *productRepository) RemoveBundle(ctx context.Context, bundleID int64) error {
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Product{}).
+			Where("id = ? AND type = ?", bundleID, entities.ProductTypeBundle).
+			Updates(map[string]interface{}{
+				"type":            entities.ProductTypeSimple,
+				"bundle_pricing":  nil,
+				"bundle_discount": 0,
+				"updated_at":      time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("404")
+		}
+
+		return tx.Where("bundle_id = ?", bundleID).Delete(&models.BundleItem{}).Error
+	})
+	if err != nil {
+		log.Errorf("[ProductRepository-1] RemoveBundle: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// RefreshBundles implements [IProductRepository]. It derives stock and
+// computed prices again for the bundles that contain any of componentIDs and
+// returns the ids of those bundles.
+func (p *productRepository) RefreshBundles(ctx context.Context, componentIDs []int64) ([]int64, error) {
+	if len(componentIDs) == 0 {
+		return []int64{}, nil
+	}
+
+	var bundleIDs []int64
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		bundleIDs, err = refreshBundlesOf(tx, componentIDs)
+		return err
+	})
+	if err != nil {
+		log.Errorf("[ProductRepository-1] RefreshBundles: %v", err)
+		return nil, err
+	}
+
+	return bundleIDs, nil
+}
+
+// DecrementStock implements [IProductRepository]. Selling a bundle takes
+// its quantity of every component; all rows are locked, so either every
+// component has enough stock or nothing changes. It returns the bundles
+// whose derived stock changed.
+func (p *productRepository) DecrementStock(ctx context.Context, productID int64, quantity int) ([]int64, error) {
+	var bundleIDs []int64
+
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		modelProduct := models.Product{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&modelProduct, "id = ?", productID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = errors.New("404")
+			}
+			return err
+		}
+
+		needed := map[int64]int{productID: quantity}
+		if modelProduct.Type == entities.ProductTypeBundle {
+			modelItems := []models.BundleItem{}
+			if err := tx.Where("bundle_id = ?", productID).Find(&modelItems).Error; err != nil {
+				return err
+			}
+			if len(modelItems) == 0 {
+				return ErrStockNotEnough
+			}
+
+			needed = map[int64]int{}
+			for _, item := range modelItems {
+				needed[item.ComponentID] += item.Quantity * quantity
+			}
+		}
+
+		componentIDs := []int64{}
+		for componentID := range needed {
+			componentIDs = append(componentIDs, componentID)
+		}
+
+		modelComponents := []models.Product{}
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", componentIDs).
+			Order("id asc").
+			Find(&modelComponents).Error
+		if err != nil {
+			return err
+		}
+		if len(modelComponents) != len(componentIDs) {
+			return ErrStockNotEnough
+		}
+
+		for _, component := range modelComponents {
+			if component.Stock < needed[component.ID] {
+				return ErrStockNotEnough
+			}
+		}
+
+		for _, component := range modelComponents {
+			err := tx.Model(&models.Product{}).Where("id = ?", component.ID).
+				Update("stock", gorm.Expr("stock - ?", needed[component.ID])).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		bundleIDs, err = refreshBundlesOf(tx, componentIDs)
+		return err
+	})
+	if err != nil {
+		log.Errorf("[ProductRepository-1] DecrementStock: %v", err)
+		return nil, err
+	}
+
+	return bundleIDs, nil
+}
+
+// getBundle loads the composition of a bundle with the current state of
+// its components. A component that was deleted shows with no stock.
+func (p *productRepository) getBundle(ctx context.Context, modelProduct models.Product) (*entities.BundleEntity, error) {
+	modelItems := []models.BundleItem{}
+	err := p.db.WithContext(ctx).Preload("Component").
+		Where("bundle_id = ?", modelProduct.ID).
+		Order("id asc").
+		Find(&modelItems).Error
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &entities.BundleEntity{
+		Pricing:         entities.BundlePricingFixed,
+		DiscountPercent: modelProduct.BundleDiscount,
+		Items:           []entities.BundleItemEntity{},
+	}
+	if modelProduct.BundlePricing != nil {
+		bundle.Pricing = *modelProduct.BundlePricing
+	}
+
+	for _, item := range modelItems {
+		bundle.Items = append(bundle.Items, entities.BundleItemEntity{
+			ComponentID:  item.ComponentID,
+			Quantity:     item.Quantity,
+			Name:         item.Component.Name,
+			Image:        item.Component.Image,
+			Weight:       item.Component.Weight,
+			Unit:         item.Component.Unit,
+			RegulerPrice: item.Component.RegulerPrice,
+			SalePrice:    item.Component.SalePrice,
+			Stock:        item.Component.Stock,
+			Available:    componentAvailable(item),
+		})
+	}
+
+	return bundle, nil
+}
+
+// refreshBundlesOf refreshes every bundle that contains one of componentIDs
+// or one of their variants.
+func refreshBundlesOf(tx *gorm.DB, componentIDs []int64) ([]int64, error) {
+	bundleIDs := []int64{}
+	variantIDs := tx.Session(&gorm.Session{NewDB: true}).Unscoped().Model(&models.Product{}).
+		Select("id").Where("parent_id IN ?", componentIDs)
+	err := tx.Model(&models.BundleItem{}).
+		Where("component_id IN ? OR component_id IN (?)", componentIDs, variantIDs).
+		Distinct().
+		Order("bundle_id asc").
+		Pluck("bundle_id", &bundleIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, bundleID := range bundleIDs {
+		if err := refreshBundle(tx, bundleID); err != nil {
+			return nil, err
+		}
+	}
+
+	return bundleIDs, nil
+}
+
+// refreshBundle derives the stock of a bundle from its components, the
+// lowest number of bundles any component is enough for. A bundle with a
+// computed price also gets the sum of its component prices, with the
+// discount taken off the sale price.
+func refreshBundle(tx *gorm.DB, bundleID int64) error {
+	modelBundle := models.Product{}
+	if err := tx.First(&modelBundle, "id = ?", bundleID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	modelItems := []models.BundleItem{}
+	if err := tx.Preload("Component").Where("bundle_id = ?", bundleID).Find(&modelItems).Error; err != nil {
+		return err
+	}
+
+	stock := 0
+	var regulerPrice, salePrice float64
+	for i, item := range modelItems {
+		if available := componentAvailable(item); i == 0 || available < stock {
+			stock = available
+		}
+
+		unitPrice := item.Component.SalePrice
+		if unitPrice <= 0 {
+			unitPrice = item.Component.RegulerPrice
+		}
+		regulerPrice += item.Component.RegulerPrice * float64(item.Quantity)
+		salePrice += unitPrice * float64(item.Quantity)
+	}
+
+	updates := map[string]interface{}{"stock": stock}
+	if isComputedBundle(modelBundle) {
+		salePrice = math.Round(salePrice * (100 - modelBundle.BundleDiscount) / 100)
+		updates["reguler_price"] = regulerPrice
+		updates["base_sale_price"] = salePrice
+
+		currentSalePrice := modelBundle.SalePrice
+		// an active price list keeps its sale price until the scheduler ends it
+		if modelBundle.PriceListID == nil {
+			updates["sale_price"] = salePrice
+			currentSalePrice = salePrice
+		}
+
+		if regulerPrice != modelBundle.RegulerPrice || salePrice != modelBundle.BaseSalePrice {
+			err := recordPriceHistory(tx, bundleID, modelBundle.PriceListID, regulerPrice, currentSalePrice, entities.PriceSourceBundle)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Model(&models.Product{}).Where("id = ?", bundleID).Updates(updates).Error
+}
+
+// componentAvailable is how many bundles a component is enough for. Deleted
+// components and components that are not published are not available.
+func componentAvailable(item models.BundleItem) int {
+	if item.Component.ID == 0 || item.Component.Status != entities.ProductStatusPublished || item.Quantity <= 0 {
+		return 0
+	}
+	return item.Component.Stock / item.Quantity
+}
+
+func isComputedBundle(modelProduct models.Product) bool {
+	return modelProduct.Type == entities.ProductTypeBundle &&
+		modelProduct.BundlePricing != nil && *modelProduct.BundlePricing == entities.BundlePricingComputed
+}
@@ -0,0 +1,289 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"product-service/internal/core/domain/entities"
+	"product-service/utils/esquery"
+	"sort"
+)
+
+// priceFacetInterval is the width of a price histogram bucket in Rupiah.
+const priceFacetInterval = 10000
+
+const (
+	facetCategory = "category"
+	facetPrice    = "price"
+	facetUnit     = "unit"
+	facetWeight   = "weight"
+	facetRating   = "rating"
+	facetOnSale   = "on_sale"
+	facetInStock  = "in_stock"
+
+	// facetAttributePrefix prefixes the attribute code in the facet name of
+	// an attribute, which keeps it apart from the fixed facets.
+	facetAttributePrefix = "attr."
+)
+
+// facetFilters holds the active filter of every facet, keyed by facet name.
+type facetFilters map[string]esquery.Query
+
+func productFacetFilters(query entities.QueryStringProduct) facetFilters {
+	filters := facetFilters{}
+
+	categorySlugs := query.CategorySlugs
+	if query.CategorySlug != "" {
+		categorySlugs = append(categorySlugs, query.CategorySlug)
+	}
+	if len(categorySlugs) > 0 {
+		filters[facetCategory] = esquery.Terms("category_slug.keyword", categorySlugs)
+	}
+
+	if query.StartPrice > 0 || query.EndPrice > 0 {
+		var gte, lte interface{}
+		if query.StartPrice > 0 {
+			gte = query.StartPrice
+		}
+		if query.EndPrice > 0 {
+			lte = query.EndPrice
+		}
+		filters[facetPrice] = esquery.Range("reguler_price", gte, lte)
+	}
+
+	if len(query.Units) > 0 {
+		filters[facetUnit] = esquery.Terms("unit.keyword", query.Units)
+	}
+
+	if query.MinRating > 0 {
+		filters[facetRating] = esquery.Range("rating", query.MinRating, nil)
+	}
+
+	if query.OnSale {
+		filters[facetOnSale] = esquery.Range("sale_price", 1, nil)
+	}
+
+	if query.InStock {
+		filters[facetInStock] = esquery.Range("stock", 1, nil)
+	}
+
+	for code, values := range query.Attributes {
+		if len(values) > 0 {
+			filters[facetAttributePrefix+code] = esquery.Terms("attributes."+code, values)
+		}
+	}
+
+	return filters
+}
+
+// except combines every filter but the one of the named facet; an empty name
+// keeps them all.
+func (f facetFilters) except(name string) esquery.Query {
+	names := []string{}
+	for key := range f {
+		if key != name {
+			names = append(names, key)
+		}
+	}
+	// a stable body lets Elasticsearch reuse its request cache
+	sort.Strings(names)
+
+	boolQuery := esquery.Bool{}
+	for _, key := range names {
+		boolQuery.Filter = append(boolQuery.Filter, f[key])
+	}
+	return boolQuery.Query()
+}
+
+// aggs builds one filter aggregation per facet, each wrapping the facet's own
+// aggregation named "values". attributeCodes adds a terms facet for each of
+// these attributes.
+func (f facetFilters) aggs(attributeCodes []string) map[string]interface{} {
+	values := map[string]interface{}{
+		facetCategory: map[string]interface{}{
+			"terms": map[string]interface{}{"field": "category_slug.keyword", "size": 50},
+			"aggs": map[string]interface{}{
+				"label": map[string]interface{}{
+					"terms": map[string]interface{}{"field": "category_name.keyword", "size": 1},
+				},
+			},
+		},
+		facetPrice: map[string]interface{}{
+			"histogram": map[string]interface{}{
+				"field":         "reguler_price",
+				"interval":      priceFacetInterval,
+				"min_doc_count": 1,
+			},
+		},
+		facetUnit: map[string]interface{}{
+			"terms": map[string]interface{}{"field": "unit.keyword", "size": 20},
+		},
+		facetWeight: map[string]interface{}{
+			"range": map[string]interface{}{
+				"field": "weight",
+				"ranges": []map[string]interface{}{
+					{"key": "0-250", "to": 250},
+					{"key": "250-500", "from": 250, "to": 500},
+					{"key": "500-1000", "from": 500, "to": 1000},
+					{"key": "1000+", "from": 1000},
+				},
+			},
+		},
+		// the buckets overlap the way the min_rating filter does, a product
+		// rated 4.5 is in all four; products nobody rated are in none
+		facetRating: map[string]interface{}{
+			"range": map[string]interface{}{
+				"field": "rating",
+				"ranges": []map[string]interface{}{
+					{"key": "4+", "from": 4},
+					{"key": "3+", "from": 3},
+					{"key": "2+", "from": 2},
+					{"key": "1+", "from": 1},
+				},
+			},
+		},
+		facetOnSale:  map[string]interface{}{"filter": esquery.Range("sale_price", 1, nil)},
+		facetInStock: map[string]interface{}{"filter": esquery.Range("stock", 1, nil)},
+	}
+
+	for _, code := range attributeCodes {
+		values[facetAttributePrefix+code] = map[string]interface{}{
+			"terms": map[string]interface{}{"field": "attributes." + code, "size": 50},
+		}
+	}
+
+	aggs := map[string]interface{}{}
+	for name, value := range values {
+		aggs[name] = map[string]interface{}{
+			"filter": f.except(name),
+			"aggs":   map[string]interface{}{"values": value},
+		}
+	}
+	return aggs
+}
+
+type facetBucket struct {
+	Key      interface{} `json:"key"`
+	From     *float64    `json:"from"`
+	To       *float64    `json:"to"`
+	DocCount int64       `json:"doc_count"`
+	Label    struct {
+		Buckets []struct {
+			Key string `json:"key"`
+		} `json:"buckets"`
+	} `json:"label"`
+}
+
+type facetAgg struct {
+	Values struct {
+		DocCount int64         `json:"doc_count"`
+		Buckets  []facetBucket `json:"buckets"`
+	} `json:"values"`
+}
+
+type productFacetAggs struct {
+	Category facetAgg `json:"category"`
+	Price    facetAgg `json:"price"`
+	Unit     facetAgg `json:"unit"`
+	Weight   facetAgg `json:"weight"`
+	Rating   facetAgg `json:"rating"`
+	OnSale   facetAgg `json:"on_sale"`
+	InStock  facetAgg `json:"in_stock"`
+}
+
+func (a productFacetAggs) toEntity() entities.ProductFacetsEntity {
+	facets := entities.ProductFacetsEntity{
+		Categories: []entities.FacetBucketEntity{},
+		Prices:     []entities.FacetRangeEntity{},
+		Units:      []entities.FacetBucketEntity{},
+		Weights:    []entities.FacetRangeEntity{},
+		Ratings:    []entities.FacetRangeEntity{},
+		OnSale:     a.OnSale.Values.DocCount,
+		InStock:    a.InStock.Values.DocCount,
+	}
+
+	for _, bucket := range a.Category.Values.Buckets {
+		category := entities.FacetBucketEntity{
+			Key:   fmt.Sprint(bucket.Key),
+			Label: fmt.Sprint(bucket.Key),
+			Count: bucket.DocCount,
+		}
+		if len(bucket.Label.Buckets) > 0 {
+			category.Label = bucket.Label.Buckets[0].Key
+		}
+		facets.Categories = append(facets.Categories, category)
+	}
+
+	for _, bucket := range a.Price.Values.Buckets {
+		from, ok := bucket.Key.(float64)
+		if !ok {
+			continue
+		}
+		to := from + priceFacetInterval
+		facets.Prices = append(facets.Prices, entities.FacetRangeEntity{
+			Key:   fmt.Sprintf("%.0f-%.0f", from, to),
+			From:  &from,
+			To:    &to,
+			Count: bucket.DocCount,
+		})
+	}
+
+	for _, bucket := range a.Unit.Values.Buckets {
+		facets.Units = append(facets.Units, entities.FacetBucketEntity{
+			Key:   fmt.Sprint(bucket.Key),
+			Label: fmt.Sprint(bucket.Key),
+			Count: bucket.DocCount,
+		})
+	}
+
+	for _, bucket := range a.Weight.Values.Buckets {
+		facets.Weights = append(facets.Weights, facetRangeEntity(bucket))
+	}
+
+	for _, bucket := range a.Rating.Values.Buckets {
+		facets.Ratings = append(facets.Ratings, facetRangeEntity(bucket))
+	}
+
+	return facets
+}
+
+// attributeFacets reads the attribute facets out of the aggregations of a
+// search, in the order of codes. The label is the code; the caller knows the
+// attribute names.
+func attributeFacets(aggregations json.RawMessage, codes []string) ([]entities.AttributeFacetEntity, error) {
+	facets := []entities.AttributeFacetEntity{}
+	if len(codes) == 0 || len(aggregations) == 0 {
+		return facets, nil
+	}
+
+	byName := map[string]facetAgg{}
+	if err := json.Unmarshal(aggregations, &byName); err != nil {
+		return nil, err
+	}
+
+	for _, code := range codes {
+		facet := entities.AttributeFacetEntity{
+			Code:   code,
+			Label:  code,
+			Values: []entities.FacetBucketEntity{},
+		}
+		for _, bucket := range byName[facetAttributePrefix+code].Values.Buckets {
+			facet.Values = append(facet.Values, entities.FacetBucketEntity{
+				Key:   fmt.Sprint(bucket.Key),
+				Label: fmt.Sprint(bucket.Key),
+				Count: bucket.DocCount,
+			})
+		}
+		facets = append(facets, facet)
+	}
+
+	return facets, nil
+}
+
+func facetRangeEntity(bucket facetBucket) entities.FacetRangeEntity {
+	return entities.FacetRangeEntity{
+		Key:   fmt.Sprint(bucket.Key),
+		From:  bucket.From,
+		To:    bucket.To,
+		Count: bucket.DocCount,
+	}
+}
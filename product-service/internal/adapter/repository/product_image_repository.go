@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/domain/models"
+
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrIncompleteImageOrder = errors.New("image_ids must list every image of the product once")
+
+type IProductImageRepository interface {
+	GetByProductID(ctx context.Context, productID int64) ([]entities.ProductImageEntity, error)
+	GetByProductIDs(ctx context.Context, productIDs []int64) ([]entities.ProductImageEntity, error)
+	Create(ctx context.Context, req entities.ProductImageEntity) (int64, error)
+	Reorder(ctx context.Context, productID int64, imageIDs []int64) error
+	SetPrimary(ctx context.Context, productID, imageID int64) error
+	Delete(ctx context.Context, productID, imageID int64) error
+}
+
+type productImageRepository struct {
+	db *gorm.DB
+}
+
+// GetByProductID implements [IProductImageRepository].
+func (p *productImageRepository) GetByProductID(ctx context.Context, productID int64) ([]entities.ProductImageEntity, error) {
+	modelImages := []models.ProductImage{}
+	if err := p.db.WithContext(ctx).Where("product_id = ?", productID).Order("position asc, id asc").Find(&modelImages).Error; err != nil {
+		log.Errorf("[ProductImageRepository-1] GetByProductID: %v", err)
+		return nil, err
+	}
+
+	respImages := []entities.ProductImageEntity{}
+	for _, val := range modelImages {
+		respImages = append(respImages, productImageModelToEntity(val))
+	}
+
+	return respImages, nil
+}
+
+// GetByProductIDs implements [IProductImageRepository]. It returns the
+// images of all the products in one query, each product's in display order.
+func (p *productImageRepository) GetByProductIDs(ctx context.Context, productIDs []int64) ([]entities.ProductImageEntity, error) {
+	if len(productIDs) == 0 {
+		return []entities.ProductImageEntity{}, nil
+	}
+
+	modelImages := []models.ProductImage{}
+	if err := p.db.WithContext(ctx).Where("product_id IN (?)", productIDs).Order("product_id asc, position asc, id asc").Find(&modelImages).Error; err != nil {
+		log.Errorf("[ProductImageRepository-1] GetByProductIDs: %v", err)
+		return nil, err
+	}
+
+	respImages := []entities.ProductImageEntity{}
+	for _, val := range modelImages {
+		respImages = append(respImages, productImageModelToEntity(val))
+	}
+
+	return respImages, nil
+}
+
+// Create implements [IProductImageRepository].
+func (p *productImageRepository) Create(ctx context.Context, req entities.ProductImageEntity) (int64, error) {
+	modelImage := models.ProductImage{
+		ProductID: req.ProductID,
+		ImageURL:  req.ImageURL,
+		AltText:   req.AltText,
+	}
+
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// locking the product keeps concurrent uploads from taking the same
+		// position
+		modelProduct := models.Product{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&modelProduct, "id = ?", req.ProductID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = errors.New("404")
+			}
+			log.Errorf("[ProductImageRepository-1] Create: %v", err)
+			return err
+		}
+
+		// the count can be below the last position after a delete, so the new
+		// image goes after the highest one
+		gallery := struct {
+			CountImages  int64
+			NextPosition int
+		}{}
+		if err := tx.Model(&models.ProductImage{}).
+			Select("COUNT(*) AS count_images, COALESCE(MAX(position) + 1, 0) AS next_position").
+			Where("product_id = ?", req.ProductID).
+			Scan(&gallery).Error; err != nil {
+			log.Errorf("[ProductImageRepository-2] Create: %v", err)
+			return err
+		}
+
+		modelImage.Position = gallery.NextPosition
+		modelImage.IsPrimary = req.IsPrimary || gallery.CountImages == 0
+		if modelImage.IsPrimary {
+			if err := tx.Model(&models.ProductImage{}).Where("product_id = ?", req.ProductID).Update("is_primary", false).Error; err != nil {
+				log.Errorf("[ProductImageRepository-3] Create: %v", err)
+				return err
+			}
+		}
+
+		if err := tx.Create(&modelImage).Error; err != nil {
+			log.Errorf("[ProductImageRepository-4] Create: %v", err)
+			return err
+		}
+
+		if modelImage.IsPrimary {
+			return syncPrimaryImage(tx, req.ProductID, modelImage.ImageURL)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return modelImage.ID, nil
+}
+
+// Reorder implements [IProductImageRepository]. imageIDs must hold every
+// image of the product once, so no two images end up at the same position.
+func (p *productImageRepository) Reorder(ctx context.Context, productID int64, imageIDs []int64) error {
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		currentIDs := []int64{}
+		if err := tx.Model(&models.ProductImage{}).Clauses(clause.Locking{Strength: "UPDATE"}).Where("product_id = ?", productID).Pluck("id", &currentIDs).Error; err != nil {
+			log.Errorf("[ProductImageRepository-1] Reorder: %v", err)
+			return err
+		}
+
+		gallery := map[int64]bool{}
+		for _, id := range currentIDs {
+			gallery[id] = true
+		}
+
+		seen := map[int64]bool{}
+		for _, id := range imageIDs {
+			if !gallery[id] {
+				log.Errorf("[ProductImageRepository-2] Reorder: %s", "image does not belong to product")
+				return errors.New("404")
+			}
+			seen[id] = true
+		}
+
+		if len(seen) != len(imageIDs) || len(seen) != len(currentIDs) {
+			log.Errorf("[ProductImageRepository-3] Reorder: %v", ErrIncompleteImageOrder)
+			return ErrIncompleteImageOrder
+		}
+
+		for position, imageID := range imageIDs {
+			if err := tx.Model(&models.ProductImage{}).Where("id = ?", imageID).Update("position", position).Error; err != nil {
+				log.Errorf("[ProductImageRepository-4] Reorder: %v", err)
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// SetPrimary implements [IProductImageRepository].
+func (p *productImageRepository) SetPrimary(ctx context.Context, productID, imageID int64) error {
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		modelImage := models.ProductImage{}
+		if err := tx.Where("id = ? AND product_id = ?", imageID, productID).First(&modelImage).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = errors.New("404")
+			}
+			log.Errorf("[ProductImageRepository-1] SetPrimary: %v", err)
+			return err
+		}
+
+		if err := tx.Model(&models.ProductImage{}).Where("product_id = ?", productID).Update("is_primary", false).Error; err != nil {
+			log.Errorf("[ProductImageRepository-2] SetPrimary: %v", err)
+			return err
+		}
+
+		if err := tx.Model(&modelImage).Update("is_primary", true).Error; err != nil {
+			log.Errorf("[ProductImageRepository-3] SetPrimary: %v", err)
+			return err
+		}
+
+		return syncPrimaryImage(tx, productID, modelImage.ImageURL)
+	})
+}
+
+// Delete implements [IProductImageRepository].
+func (p *productImageRepository) Delete(ctx context.Context, productID, imageID int64) error {
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		modelImage := models.ProductImage{}
+		if err := tx.Where("id = ? AND product_id = ?", imageID, productID).First(&modelImage).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = errors.New("404")
+			}
+			log.Errorf("[ProductImageRepository-1] Delete: %v", err)
+			return err
+		}
+
+		if err := tx.Delete(&modelImage).Error; err != nil {
+			log.Errorf("[ProductImageRepository-2] Delete: %v", err)
+			return err
+		}
+
+		if !modelImage.IsPrimary {
+			return nil
+		}
+
+		// promote the next image in the gallery so products.image stays filled
+		nextImage := models.ProductImage{}
+		err := tx.Where("product_id = ?", productID).Order("position asc, id asc").First(&nextImage).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			log.Errorf("[ProductImageRepository-3] Delete: %v", err)
+			return err
+		}
+
+		if err := tx.Model(&nextImage).Update("is_primary", true).Error; err != nil {
+			log.Errorf("[ProductImageRepository-4] Delete: %v", err)
+			return err
+		}
+
+		return syncPrimaryImage(tx, productID, nextImage.ImageURL)
+	})
+}
+
+// syncPrimaryImage keeps products.image equal to the primary gallery image
+// for clients that still read the single image field.
+func syncPrimaryImage(tx *gorm.DB, productID int64, imageURL string) error {
+	if err := tx.Model(&models.Product{}).Where("id = ?", productID).Update("image", imageURL).Error; err != nil {
+		log.Errorf("[ProductImageRepository-1] syncPrimaryImage: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func productImageModelToEntity(val models.ProductImage) entities.ProductImageEntity {
+	return entities.ProductImageEntity{
+		ID:        val.ID,
+		ProductID: val.ProductID,
+		ImageURL:  val.ImageURL,
+		AltText:   val.AltText,
+		Position:  val.Position,
+		IsPrimary: val.IsPrimary,
+	}
+}
+
+func NewProductImageRepository(db *gorm.DB) IProductImageRepository {
+	return &productImageRepository{
+		db: db,
+	}
+}
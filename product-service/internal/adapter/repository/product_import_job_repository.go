@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/domain/models"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+)
+
+type IProductImportJobRepository interface {
+	Create(ctx context.Context, req entities.ProductImportJobEntity) (int64, error)
+	GetByID(ctx context.Context, id int64) (*entities.ProductImportJobEntity, error)
+	Update(ctx context.Context, req entities.ProductImportJobEntity) error
+	FailUnfinished(ctx context.Context, message string) (int64, error)
+}
+
+type productImportJobRepository struct {
+	db *gorm.DB
+}
+
+// Create implements [IProductImportJobRepository].
+func (p *productImportJobRepository) Create(ctx context.Context, req entities.ProductImportJobEntity) (int64, error) {
+	modelJob := models.ProductImportJob{
+		UserID:    req.UserID,
+		FileName:  req.FileName,
+		Status:    req.Status,
+		TotalRows: req.TotalRows,
+	}
+
+	if err := p.db.WithContext(ctx).Create(&modelJob).Error; err != nil {
+		log.Errorf("[ProductImportJobRepository-1] Create: %v", err)
+		return 0, err
+	}
+
+	return modelJob.ID, nil
+}
+
+// GetByID implements [IProductImportJobRepository].
+func (p *productImportJobRepository) GetByID(ctx context.Context, id int64) (*entities.ProductImportJobEntity, error) {
+	modelJob := models.ProductImportJob{}
+	if err := p.db.WithContext(ctx).Where("id = ?", id).First(&modelJob).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = errors.New("404")
+		}
+		log.Errorf("[ProductImportJobRepository-1] GetByID: %v", err)
+		return nil, err
+	}
+
+	rowErrors := []entities.ImportRowErrorEntity{}
+	if modelJob.RowErrors != "" {
+		if err := json.Unmarshal([]byte(modelJob.RowErrors), &rowErrors); err != nil {
+			log.Errorf("[ProductImportJobRepository-2] GetByID: %v", err)
+			return nil, err
+		}
+	}
+
+	return &entities.ProductImportJobEntity{
+		ID:            modelJob.ID,
+		UserID:        modelJob.UserID,
+		FileName:      modelJob.FileName,
+		Status:        modelJob.Status,
+		TotalRows:     modelJob.TotalRows,
+		ProcessedRows: modelJob.ProcessedRows,
+		CreatedCount:  modelJob.CreatedCount,
+		UpdatedCount:  modelJob.UpdatedCount,
+		FailedCount:   modelJob.FailedCount,
+		RowErrors:     rowErrors,
+		ErrorMessage:  modelJob.ErrorMessage,
+		CreatedAt:     modelJob.CreatedAt,
+		FinishedAt:    modelJob.FinishedAt,
+	}, nil
+}
+
+// Update implements [IProductImportJobRepository]. It saves the progress and
+// outcome of the job; the file name and owner never change.
+func (p *productImportJobRepository) Update(ctx context.Context, req entities.ProductImportJobEntity) error {
+	rowErrors := ""
+	if len(req.RowErrors) > 0 {
+		data, err := json.Marshal(req.RowErrors)
+		if err != nil {
+			log.Errorf("[ProductImportJobRepository-1] Update: %v", err)
+			return err
+		}
+		rowErrors = string(data)
+	}
+
+	err := p.db.WithContext(ctx).Model(&models.ProductImportJob{}).Where("id = ?", req.ID).Updates(map[string]interface{}{
+		"status":         req.Status,
+		"total_rows":     req.TotalRows,
+		"processed_rows": req.ProcessedRows,
+		"created_count":  req.CreatedCount,
+		"updated_count":  req.UpdatedCount,
+		"failed_count":   req.FailedCount,
+		"row_errors":     rowErrors,
+		"error_message":  req.ErrorMessage,
+		"finished_at":    req.FinishedAt,
+		"updated_at":     time.Now(),
+	}).Error
+	if err != nil {
+		log.Errorf("[ProductImportJobRepository-2] Update: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// FailUnfinished implements [IProductImportJobRepository]. Jobs run inside the
+// API process, so those still pending or processing at startup were cut off
+// by a restart.
+func (p *productImportJobRepository) FailUnfinished(ctx context.Context, message string) (int64, error) {
+	result := p.db.WithContext(ctx).Model(&models.ProductImportJob{}).
+		Where("status IN ?", []string{entities.ImportStatusPending, entities.ImportStatusProcessing}).
+		Updates(map[string]interface{}{
+			"status":        entities.ImportStatusFailed,
+			"error_message": message,
+			"finished_at":   time.Now(),
+			"updated_at":    time.Now(),
+		})
+	if result.Error != nil {
+		log.Errorf("[ProductImportJobRepository-1] FailUnfinished: %v", result.Error)
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func NewProductImportJobRepository(db *gorm.DB) IProductImportJobRepository {
+	return &productImportJobRepository{
+		db: db,
+	}
+}
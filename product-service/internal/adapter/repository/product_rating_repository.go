@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/domain/models"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IProductRatingRepository interface {
+	Rate(ctx context.Context, productID, userID int64, rating int) (*entities.ProductRatingEntity, error)
+}
+
+type productRatingRepository struct {
+	db *gorm.DB
+}
+
+// Rate implements [IProductRatingRepository]. The rating of userID replaces
+// the one they gave before, and the average and count on the product are
+// recomputed in the same transaction. The product row is locked meanwhile,
+// so customers rating at the same time cannot overwrite each other's count.
+func (p *productRatingRepository) Rate(ctx context.Context, productID, userID int64, rating int) (*entities.ProductRatingEntity, error) {
+	var result *entities.ProductRatingEntity
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		modelProduct := models.Product{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", productID).First(&modelProduct).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = errors.New("404")
+			}
+			log.Errorf("[ProductRatingRepository-1] Rate: %v", err)
+			return err
+		}
+
+		now := time.Now()
+		modelRating := models.ProductRating{
+			ProductID: productID,
+			UserID:    userID,
+			Rating:    rating,
+			UpdatedAt: &now,
+		}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "product_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rating", "updated_at"}),
+		}).Create(&modelRating).Error; err != nil {
+			log.Errorf("[ProductRatingRepository-2] Rate: %v", err)
+			return err
+		}
+
+		summary := entities.ProductRatingEntity{ProductID: productID}
+		if err := tx.Model(&models.ProductRating{}).
+			Select("COALESCE(ROUND(AVG(rating), 2), 0) AS rating, COUNT(*) AS rating_count").
+			Where("product_id = ?", productID).
+			Scan(&summary).Error; err != nil {
+			log.Errorf("[ProductRatingRepository-3] Rate: %v", err)
+			return err
+		}
+
+		if err := tx.Exec("UPDATE products SET rating = ?, rating_count = ?, updated_at = ? WHERE id = ?",
+			summary.Rating, summary.RatingCount, now, productID).Error; err != nil {
+			log.Errorf("[ProductRatingRepository-4] Rate: %v", err)
+			return err
+		}
+
+		result = &summary
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func NewProductRatingRepository(db *gorm.DB) IProductRatingRepository {
+	return &productRatingRepository{
+		db: db,
+	}
+}
@@ -1,19 +1,22 @@
 package repository
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"product-service/internal/core/domain/entities"
 	"product-service/internal/core/domain/models"
-	"strconv"
-	"strings"
+	"product-service/utils/esquery"
+	"time"
 
 	"github.com/elastic/go-elasticsearch/v7"
 	"github.com/labstack/gommon/log"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // interface
@@ -25,7 +28,18 @@ type IProductRepository interface {
 	Create(ctx context.Context, req entities.ProductEntity) (int64, error)
 	Update(ctx context.Context, req entities.ProductEntity) error
 	Delete(ctx context.Context, productID int64) error
-	SearchProducts(ctx context.Context, query entities.QueryStringProduct) ([]entities.ProductEntity, int64, int64, error)
+	SearchProducts(ctx context.Context, query entities.QueryStringProduct) (*entities.ProductSearchEntity, error)
+	SearchProductsMulti(ctx context.Context, queries []entities.QueryStringProduct) ([]*entities.ProductSearchEntity, error)
+	GetVariants(ctx context.Context, parentIDs []int64) ([]entities.ProductEntity, error)
+	GetForIndex(ctx context.Context, afterID int64, limit int, publishedOnly bool) ([]entities.ProductEntity, error)
+	GetBySKU(ctx context.Context, sku string) (*entities.ProductEntity, error)
+	UpdateStatus(ctx context.Context, req entities.ProductEntity) error
+	ApplySchedule(ctx context.Context, now time.Time) ([]int64, []int64, error)
+	GetBundleIDs(ctx context.Context, productID int64) ([]int64, error)
+	SetBundle(ctx context.Context, bundleID int64, bundle entities.BundleEntity) error
+	RemoveBundle(ctx context.Context, bundleID int64) error
+	RefreshBundles(ctx context.Context, componentIDs []int64) ([]int64, error)
+	DecrementStock(ctx context.Context, productID int64, quantity int) ([]int64, error)
 }
 
 // struct
@@ -55,6 +69,7 @@ func (p *productRepository) GetByIDs(ctx context.Context, productIDs []int64) ([
 			ID:           val.ID,
 			CategorySlug: val.CategorySlug,
 			ParentID:     val.ParentID,
+			SKU:          skuValue(val.SKU),
 			Name:         val.Name,
 			Image:        val.Image,
 			Description:  val.Description,
@@ -64,8 +79,15 @@ func (p *productRepository) GetByIDs(ctx context.Context, productIDs []int64) ([
 			Weight:       val.Weight,
 			Stock:        val.Stock,
 			Variant:      val.Variant,
+			Type:         val.Type,
+			Selling:      sellingEntity(val),
+			Rating:       val.Rating,
+			RatingCount:  val.RatingCount,
 			Status:       val.Status,
+			PublishAt:    val.PublishAt,
+			UnpublishAt:  val.UnpublishAt,
 			CategoryName: val.Category.Name,
+			Attributes:   attributesMap(val.Attributes),
 			CreatedAt:    val.CreatedAt,
 		})
 	}
@@ -73,142 +95,272 @@ func (p *productRepository) GetByIDs(ctx context.Context, productIDs []int64) ([
 	return respProducts, nil
 }
 
-// SearchProducts implements [IProductRepository].
-func (p *productRepository) SearchProducts(ctx context.Context, query entities.QueryStringProduct) ([]entities.ProductEntity, int64, int64, error) {
-	var mainQueries []string
-	var filterQueries []string
-	from := (query.Page - 1) * query.Limit
-
-	sortField := "id"
-	if query.OrderBy != "" {
-		sortField = query.OrderBy
+// GetVariants implements [IProductRepository]. It returns the variants of
+// all the parents in one query, ordered by ID.
+func (p *productRepository) GetVariants(ctx context.Context, parentIDs []int64) ([]entities.ProductEntity, error) {
+	if len(parentIDs) == 0 {
+		return []entities.ProductEntity{}, nil
 	}
 
-	// Menentukan urutan sorting (asc atau desc)
-	sortOrder := "asc"
-	if query.OrderType == "desc" {
-		sortOrder = "desc"
+	modelProducts := []models.Product{}
+	if err := p.db.WithContext(ctx).Preload("Category").Where("parent_id IN (?)", parentIDs).Order("id asc").Find(&modelProducts).Error; err != nil {
+		log.Errorf("[ProductRepository-1] GetVariants: %v", err)
+		return nil, err
 	}
 
-	// Menyusun bagian sort query
-	sortQuery := fmt.Sprintf(`{ "%s": "%s" }`, sortField, sortOrder)
-
-	if query.CategorySlug != "" {
-		filterQueries = append(filterQueries, fmt.Sprintf(`{ "term": { "category_slug.keyword": "%s" } }`, query.CategorySlug))
+	respProducts := []entities.ProductEntity{}
+	for _, val := range modelProducts {
+		respProducts = append(respProducts, entities.ProductEntity{
+			ID:           val.ID,
+			CategorySlug: val.CategorySlug,
+			ParentID:     val.ParentID,
+			SKU:          skuValue(val.SKU),
+			Name:         val.Name,
+			Image:        val.Image,
+			Description:  val.Description,
+			RegulerPrice: val.RegulerPrice,
+			SalePrice:    val.SalePrice,
+			Unit:         val.Unit,
+			Weight:       val.Weight,
+			Stock:        val.Stock,
+			Variant:      val.Variant,
+			Type:         val.Type,
+			Selling:      sellingEntity(val),
+			Rating:       val.Rating,
+			RatingCount:  val.RatingCount,
+			Status:       val.Status,
+			CategoryName: val.Category.Name,
+			Attributes:   attributesMap(val.Attributes),
+			CreatedAt:    val.CreatedAt,
+		})
 	}
 
-	if query.StartPrice > 0 && query.EndPrice > 0 {
-		filterQueries = append(filterQueries, fmt.Sprintf(`{ "range": { "reguler_price": { "gte": %d, "lte": %d } } }`, query.StartPrice, query.EndPrice))
-	}
+	return respProducts, nil
+}
 
-	if query.Search != "" {
-		mainQueries = append(mainQueries, fmt.Sprintf(`{ "multi_match": { "query": "%s", "fields": ["name", "description", "category_name"] } }`, query.Search))
+// SearchProducts implements [IProductRepository].
+func (p *productRepository) SearchProducts(ctx context.Context, query entities.QueryStringProduct) (*entities.ProductSearchEntity, error) {
+	body, err := productSearchBody(query)
+	if err != nil {
+		log.Errorf("[ProductRepository-1] SearchProducts: %v", err)
+		return nil, err
 	}
 
-	// Query Elasticsearch dengan filtering dan pagination
-	mainQuery := fmt.Sprintf(`{
-		"from": %d,
-		"size": %d,
-		"query": {
-			"bool": {
-				"must": [
-					%s
-				],
-				"filter": [ 
-					%s
-				]
-			}
-		},
-		"sort": [
-			%s
-		]
-	}`, from, query.Limit, strings.Join(mainQueries, ","), strings.Join(filterQueries, ","), sortQuery)
-
 	// Query Elasticsearch dengan filtering dan pagination
 	// Kirim query ke Elasticsearch
 	res, err := p.esClient.Search(
 		p.esClient.Search.WithContext(ctx),
 		p.esClient.Search.WithIndex("products"),
-		p.esClient.Search.WithBody(strings.NewReader(mainQuery)),
+		p.esClient.Search.WithBody(body),
 		p.esClient.Search.WithPretty(),
 	)
 
 	if err != nil {
 		log.Printf("Error searching Elasticsearch: %s", err)
-		return nil, 0, 0, err
+		return nil, err
 	}
 	defer res.Body.Close()
 
-	// Decode response
-	var result map[string]interface{}
+	if res.IsError() {
+		err = fmt.Errorf("elasticsearch search failed: %s", res.Status())
+		log.Errorf("[ProductRepository-2] SearchProducts: %v", err)
+		return nil, err
+	}
+
+	var result productSearchResponse
 	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
 		log.Printf("Error decoding response: %s", err)
-		return nil, 0, 0, err
+		return nil, err
 	}
-	
-	// Ambil total data
-	totalData := 0
-	var totalPage int64
-	products := []entities.ProductEntity{}
-
-	if hits, ok := result["hits"].(map[string]interface{}); ok {
-		// Ambil total data
-		if total, ok := hits["total"].(map[string]interface{}); ok {
-			if value, ok := total["value"].(float64); ok {
-				totalData = int(value)
-			}
+
+	search, err := result.toEntity(query)
+	if err != nil {
+		log.Errorf("[ProductRepository-3] SearchProducts: %v", err)
+		return nil, err
+	}
+
+	return search, nil
+}
+
+// SearchProductsMulti implements [IProductRepository]. It runs the searches
+// in one multi search request and returns the results in the same order.
+func (p *productRepository) SearchProductsMulti(ctx context.Context, queries []entities.QueryStringProduct) ([]*entities.ProductSearchEntity, error) {
+	if len(queries) == 0 {
+		return []*entities.ProductSearchEntity{}, nil
+	}
+
+	var body bytes.Buffer
+	for _, query := range queries {
+		searchBody, err := productSearchBody(query)
+		if err != nil {
+			log.Errorf("[ProductRepository-1] SearchProductsMulti: %v", err)
+			return nil, err
 		}
 
-		// Hitung total halaman
-		if query.Limit > 0 {
-			totalPage = int64(math.Ceil(float64(totalData) / float64(query.Limit)))
+		body.WriteString(`{"index":"products"}` + "\n")
+		if _, err := body.ReadFrom(searchBody); err != nil {
+			log.Errorf("[ProductRepository-2] SearchProductsMulti: %v", err)
+			return nil, err
 		}
+		body.WriteString("\n")
+	}
 
-		// Parsing hasil pencarian ke struct domain.Product
-		if hits, ok := hits["hits"].([]interface{}); ok {
-			for _, hit := range hits {
-				if source, ok := hit.(map[string]interface{})["_source"]; ok {
-					data, _ := json.Marshal(source)
-					var product entities.ProductEntity
-					json.Unmarshal(data, &product)
-					products = append(products, product)
-				}
-			}
+	res, err := p.esClient.Msearch(&body, p.esClient.Msearch.WithContext(ctx))
+	if err != nil {
+		log.Errorf("[ProductRepository-3] SearchProductsMulti: %v", err)
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		err = fmt.Errorf("elasticsearch multi search failed: %s", res.Status())
+		log.Errorf("[ProductRepository-4] SearchProductsMulti: %v", err)
+		return nil, err
+	}
+
+	var result struct {
+		Responses []struct {
+			productSearchResponse
+			Error json.RawMessage `json:"error"`
+		} `json:"responses"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		log.Errorf("[ProductRepository-5] SearchProductsMulti: %v", err)
+		return nil, err
+	}
+
+	if len(result.Responses) != len(queries) {
+		err = fmt.Errorf("elasticsearch multi search returned %d responses for %d searches", len(result.Responses), len(queries))
+		log.Errorf("[ProductRepository-6] SearchProductsMulti: %v", err)
+		return nil, err
+	}
+
+	searches := make([]*entities.ProductSearchEntity, len(queries))
+	for i, response := range result.Responses {
+		if len(response.Error) > 0 {
+			err = fmt.Errorf("elasticsearch search failed: %s", response.Error)
+			log.Errorf("[ProductRepository-7] SearchProductsMulti: %v", err)
+			return nil, err
+		}
+
+		searches[i], err = response.toEntity(queries[i])
+		if err != nil {
+			log.Errorf("[ProductRepository-8] SearchProductsMulti: %v", err)
+			return nil, err
 		}
 	}
 
-	return products, int64(totalData), int64(totalPage), nil
+	return searches, nil
 }
 
-// Delete implements [IProductRepository].
-func (p *productRepository) Delete(ctx context.Context, productID int64) error {
-	modelProduct := models.Product{}
-	if err := p.db.WithContext(ctx).Preload("Childs").First(&modelProduct, "id = ?", productID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			err = errors.New("404")
+// productSearchResponse is the part of an Elasticsearch search response that
+// product search reads.
+type productSearchResponse struct {
+	Hits struct {
+		Total struct {
+			Value int64 `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			Source entities.ProductEntity `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+	Aggregations json.RawMessage `json:"aggregations"`
+}
+
+func (r productSearchResponse) toEntity(query entities.QueryStringProduct) (*entities.ProductSearchEntity, error) {
+	aggs := productFacetAggs{}
+	if len(r.Aggregations) > 0 {
+		if err := json.Unmarshal(r.Aggregations, &aggs); err != nil {
+			return nil, err
 		}
-		log.Errorf("[ProductRepository-1] Delete: %v", err)
-		return err
 	}
 
-	if err := p.db.WithContext(ctx).Select("Childs").Delete(&modelProduct).Error; err != nil {
-		log.Errorf("[ProductRepository-2] Delete: %v", err)
-		return err
+	search := &entities.ProductSearchEntity{
+		Products:  []entities.ProductEntity{},
+		Facets:    aggs.toEntity(),
+		TotalData: r.Hits.Total.Value,
 	}
 
-	res, err := p.esClient.Delete(
-		"products",
-		strconv.Itoa(int(productID)),
-		p.esClient.Delete.WithRefresh("true"),
-	)
+	var err error
+	search.Facets.Attributes, err = attributeFacets(r.Aggregations, query.AttributeFacets)
+	if err != nil {
+		return nil, err
+	}
+
+	// Hitung total halaman
+	if query.Limit > 0 {
+		search.TotalPage = int64(math.Ceil(float64(search.TotalData) / float64(query.Limit)))
+	}
+
+	for _, hit := range r.Hits.Hits {
+		search.Products = append(search.Products, hit.Source)
+	}
+
+	return search, nil
+}
+
+// productSortFields are the fields product search may be sorted by.
+var productSortFields = esquery.SortFields{
+	"id":            "id",
+	"created_at":    "created_at",
+	"name":          "name.keyword",
+	"reguler_price": "reguler_price",
+	"sale_price":    "sale_price",
+	"stock":         "stock",
+	"rating":        "rating",
+}
 
+// productSearchBody turns the search parameters into an Elasticsearch body.
+// The free text and status go into the query; filters that have a facet go
+// into the post filter, so each facet can leave its own filter out.
+func productSearchBody(query entities.QueryStringProduct) (io.Reader, error) {
+	orderBy := query.OrderBy
+	if orderBy == "" {
+		orderBy = "id"
+	}
+	sort, err := productSortFields.Sort(orderBy, query.OrderType)
 	if err != nil {
-		log.Errorf("[ProductRepository-3] Delete: %v", err)
-		return err
+		return nil, err
 	}
 
-	defer res.Body.Close()
-	log.Infof("[ProductRepository-4] Delete Product Elasticsearch: %d", productID)
+	boolQuery := esquery.Bool{}
+	if search := esquery.CleanText(query.Search); search != "" {
+		boolQuery.Must = append(boolQuery.Must, esquery.MultiMatch(search, "name", "description", "category_name"))
+	}
+
+	if query.Status != "" {
+		boolQuery.Filter = append(boolQuery.Filter, esquery.Match("status", query.Status))
+	}
+
+	filters := productFacetFilters(query)
+	return esquery.Search{
+		From:       (query.Page - 1) * query.Limit,
+		Size:       query.Limit,
+		Query:      boolQuery.Query(),
+		PostFilter: filters.except(""),
+		Aggs:       filters.aggs(query.AttributeFacets),
+		Sort:       []esquery.Sort{sort},
+	}.Reader()
+}
+
+// Delete implements [IProductRepository]. The product and its variants get
+// the same deleted_at, which is how a restore from the trash tells them
+// apart from variants deleted earlier. Removing the search document is left
+// to the delete queue.
+func (p *productRepository) Delete(ctx context.Context, productID int64) error {
+	result := p.db.WithContext(ctx).Model(&models.Product{}).
+		Where("id = ? OR parent_id = ?", productID, productID).
+		Update("deleted_at", time.Now())
+	if result.Error != nil {
+		log.Errorf("[ProductRepository-1] Delete: %v", result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		err := errors.New("404")
+		log.Errorf("[ProductRepository-2] Delete: %v", err)
+		return err
+	}
 
 	return nil
 }
@@ -227,22 +379,59 @@ func (p *productRepository) Update(ctx context.Context, req entities.ProductEnti
 
 	modelProduct.CategorySlug = req.CategorySlug
 	modelProduct.ParentID = req.ParentID
+	// requests without a SKU keep the one set by an import
+	if req.SKU != "" {
+		modelProduct.SKU = skuPointer(req.SKU)
+	}
 	modelProduct.Name = req.Name
 	modelProduct.Image = req.Image
 	modelProduct.Description = req.Description
+	// a bundle with a computed price keeps the price of its components
+	if isComputedBundle(modelProduct) {
+		req.RegulerPrice = modelProduct.RegulerPrice
+		req.SalePrice = modelProduct.BaseSalePrice
+	}
+	priceChanged := modelProduct.RegulerPrice != req.RegulerPrice || modelProduct.BaseSalePrice != req.SalePrice
 	modelProduct.RegulerPrice = req.RegulerPrice
-	modelProduct.SalePrice = req.SalePrice
+	modelProduct.BaseSalePrice = req.SalePrice
+	// an active price list keeps its sale price until the scheduler ends it
+	if modelProduct.PriceListID == nil {
+		modelProduct.SalePrice = req.SalePrice
+	}
 	modelProduct.Unit = req.Unit
 	modelProduct.Weight = req.Weight
-	modelProduct.Stock = req.Stock
+	// requests without selling rules, like imports, keep the stored ones
+	if req.Selling.SellBy != "" {
+		applySelling(&modelProduct, req.Selling)
+	}
+	// the stock of a bundle follows its components
+	if modelProduct.Type != entities.ProductTypeBundle {
+		modelProduct.Stock = req.Stock
+	}
 	modelProduct.Variant = req.Variant
 	modelProduct.Status = req.Status
+	// requests without attributes, like imports, keep the stored values
+	if req.Attributes != nil {
+		modelProduct.Attributes = attributesJSON(req.Attributes)
+	}
 
 	if err := p.db.Save(&modelProduct).Error; err != nil {
 		log.Errorf("[ProductRepository-2] Update: %v", err)
 		return err
 	}
 
+	if err := p.db.Model(&models.ProductImage{}).Where("product_id = ? AND is_primary", modelProduct.ID).Update("image_url", req.Image).Error; err != nil {
+		log.Errorf("[ProductRepository-3] Update: %v", err)
+		return err
+	}
+
+	if priceChanged {
+		if err := recordPriceHistory(p.db, modelProduct.ID, modelProduct.PriceListID, modelProduct.RegulerPrice, modelProduct.SalePrice, entities.PriceSourceManual); err != nil {
+			log.Errorf("[ProductRepository-3] Update: %v", err)
+			return err
+		}
+	}
+
 	if len(req.Child) > 0 {
 		if err := p.db.Where("parent_id = ?", modelProduct.ID).Delete(&models.Product{}).Error; err != nil {
 			log.Errorf("[ProductRepository-3] Update: %v", err)
@@ -253,19 +442,21 @@ func (p *productRepository) Update(ctx context.Context, req entities.ProductEnti
 
 		for _, val := range req.Child {
 			modelProductChild = append(modelProductChild, models.Product{
-				CategorySlug: req.CategorySlug,
-				ParentID:     &modelProduct.ID,
-				Name:         req.Name,
-				Image:        val.Image,
-				Description:  req.Description,
-				RegulerPrice: val.RegulerPrice,
-				SalePrice:    val.SalePrice,
-				Unit:         req.Unit,
-				Weight:       val.Weight,
-				Stock:        val.Stock,
-				Variant:      req.Variant,
-				Status:       req.Status,
+				CategorySlug:  req.CategorySlug,
+				ParentID:      &modelProduct.ID,
+				Name:          req.Name,
+				Image:         val.Image,
+				Description:   req.Description,
+				RegulerPrice:  val.RegulerPrice,
+				SalePrice:     val.SalePrice,
+				BaseSalePrice: val.SalePrice,
+				Unit:          req.Unit,
+				Weight:        val.Weight,
+				Stock:         val.Stock,
+				Variant:       req.Variant,
+				Status:        req.Status,
 			})
+			applySelling(&modelProductChild[len(modelProductChild)-1], sellingEntity(modelProduct))
 		}
 
 		if err := p.db.Create(&modelProductChild).Error; err != nil {
@@ -281,7 +472,9 @@ func (p *productRepository) Update(ctx context.Context, req entities.ProductEnti
 func (p *productRepository) GetByID(ctx context.Context, productID int64) (*entities.ProductEntity, error) {
 	modelProduct := models.Product{}
 
-	if err := p.db.WithContext(ctx).Preload("Category").First(&modelProduct, "id = ?", productID).Error; err != nil {
+	if err := p.db.WithContext(ctx).Preload("Category").Preload("Images", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position asc, id asc")
+	}).First(&modelProduct, "id = ?", productID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			err = errors.New("404")
 		}
@@ -304,6 +497,7 @@ func (p *productRepository) GetByID(ctx context.Context, productID int64) (*enti
 			ID:           val.ID,
 			CategorySlug: val.CategorySlug,
 			ParentID:     val.ParentID,
+			SKU:          skuValue(val.SKU),
 			Name:         val.Name,
 			Image:        val.Image,
 			Description:  val.Description,
@@ -313,6 +507,10 @@ func (p *productRepository) GetByID(ctx context.Context, productID int64) (*enti
 			Weight:       val.Weight,
 			Stock:        val.Stock,
 			Variant:      val.Variant,
+			Type:         val.Type,
+			Selling:      sellingEntity(val),
+			Rating:       val.Rating,
+			RatingCount:  val.RatingCount,
 			Status:       val.Status,
 			CategoryName: val.Category.Name,
 			Child:        childEntities,
@@ -320,10 +518,25 @@ func (p *productRepository) GetByID(ctx context.Context, productID int64) (*enti
 		})
 	}
 
+	imageEntities := []entities.ProductImageEntity{}
+	for _, val := range modelProduct.Images {
+		imageEntities = append(imageEntities, productImageModelToEntity(val))
+	}
+
+	var bundle *entities.BundleEntity
+	if modelProduct.Type == entities.ProductTypeBundle {
+		bundle, err = p.getBundle(ctx, modelProduct)
+		if err != nil {
+			log.Errorf("[ProductRepository-3] GetByID: %v", err)
+			return nil, err
+		}
+	}
+
 	return &entities.ProductEntity{
 		ID:           modelProduct.ID,
 		CategorySlug: modelProduct.CategorySlug,
 		ParentID:     modelProduct.ParentID,
+		SKU:          skuValue(modelProduct.SKU),
 		Name:         modelProduct.Name,
 		Image:        modelProduct.Image,
 		Description:  modelProduct.Description,
@@ -333,52 +546,78 @@ func (p *productRepository) GetByID(ctx context.Context, productID int64) (*enti
 		Weight:       modelProduct.Weight,
 		Stock:        modelProduct.Stock,
 		Variant:      modelProduct.Variant,
+		Type:         modelProduct.Type,
+		Selling:      sellingEntity(modelProduct),
+		Rating:       modelProduct.Rating,
+		RatingCount:  modelProduct.RatingCount,
 		Status:       modelProduct.Status,
+		PublishAt:    modelProduct.PublishAt,
+		UnpublishAt:  modelProduct.UnpublishAt,
+		ApprovedBy:   modelProduct.ApprovedBy,
+		ApprovedAt:   modelProduct.ApprovedAt,
+		ReviewNote:   modelProduct.ReviewNote,
 		CategoryName: modelProduct.Category.Name,
+		Attributes:   attributesMap(modelProduct.Attributes),
 		Child:        childEntities,
+		Images:       imageEntities,
 		CreatedAt:    modelProduct.CreatedAt,
+		Bundle:       bundle,
 	}, nil
 }
 
 // Create implements [IProductRepository].
 func (p *productRepository) Create(ctx context.Context, req entities.ProductEntity) (int64, error) {
 	modelProduct := models.Product{
-		CategorySlug: req.CategorySlug,
-		ParentID:     req.ParentID,
-		Name:         req.Name,
-		Image:        req.Image,
-		Description:  req.Description,
-		RegulerPrice: req.RegulerPrice,
-		SalePrice:    req.SalePrice,
-		Unit:         req.Unit,
-		Weight:       req.Weight,
-		Stock:        req.Stock,
-		Variant:      req.Variant,
-		Status:       req.Status,
+		CategorySlug:  req.CategorySlug,
+		ParentID:      req.ParentID,
+		SKU:           skuPointer(req.SKU),
+		Name:          req.Name,
+		Image:         req.Image,
+		Description:   req.Description,
+		RegulerPrice:  req.RegulerPrice,
+		SalePrice:     req.SalePrice,
+		BaseSalePrice: req.SalePrice,
+		Unit:          req.Unit,
+		Weight:        req.Weight,
+		Stock:         req.Stock,
+		Variant:       req.Variant,
+		Status:        req.Status,
+		Attributes:    attributesJSON(req.Attributes),
+		Images: []models.ProductImage{
+			{ImageURL: req.Image, IsPrimary: true},
+		},
 	}
+	applySelling(&modelProduct, req.Selling)
 
 	if err := p.db.Create(&modelProduct).Error; err != nil {
 		log.Errorf("[ProductRepository-1] Create: %v", err)
 		return 0, err
 	}
 
+	if err := recordPriceHistory(p.db, modelProduct.ID, nil, modelProduct.RegulerPrice, modelProduct.SalePrice, entities.PriceSourceCreate); err != nil {
+		log.Errorf("[ProductRepository-2] Create: %v", err)
+		return 0, err
+	}
+
 	if len(req.Child) > 0 {
 		modelProductChild := []models.Product{}
 		for _, val := range req.Child {
 			modelProductChild = append(modelProductChild, models.Product{
-				CategorySlug: req.CategorySlug,
-				ParentID:     &modelProduct.ID,
-				Name:         req.Name,
-				Image:        val.Image,
-				Description:  req.Description,
-				RegulerPrice: val.RegulerPrice,
-				SalePrice:    val.SalePrice,
-				Unit:         req.Unit,
-				Weight:       val.Weight,
-				Stock:        val.Stock,
-				Variant:      req.Variant,
-				Status:       req.Status,
+				CategorySlug:  req.CategorySlug,
+				ParentID:      &modelProduct.ID,
+				Name:          req.Name,
+				Image:         val.Image,
+				Description:   req.Description,
+				RegulerPrice:  val.RegulerPrice,
+				SalePrice:     val.SalePrice,
+				BaseSalePrice: val.SalePrice,
+				Unit:          req.Unit,
+				Weight:        val.Weight,
+				Stock:         val.Stock,
+				Variant:       req.Variant,
+				Status:        req.Status,
 			})
+			applySelling(&modelProductChild[len(modelProductChild)-1], sellingEntity(modelProduct))
 		}
 
 		if err := p.db.Create(&modelProductChild).Error; err != nil {
@@ -390,6 +629,99 @@ func (p *productRepository) Create(ctx context.Context, req entities.ProductEnti
 	return modelProduct.ID, nil
 }
 
+// GetForIndex implements [IProductRepository]. It returns the next batch of
+// products after afterID in the shape they are indexed in: top level products
+// only, each with its variants and images. publishedOnly leaves out the
+// products that do not belong in the search index.
+func (p *productRepository) GetForIndex(ctx context.Context, afterID int64, limit int, publishedOnly bool) ([]entities.ProductEntity, error) {
+	modelProducts := []models.Product{}
+
+	sqlMain := p.db.WithContext(ctx)
+	if publishedOnly {
+		sqlMain = sqlMain.Where("status = ?", entities.ProductStatusPublished)
+	}
+
+	err := sqlMain.
+		Preload("Category").
+		Preload("Images", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position asc, id asc")
+		}).
+		Preload("Childs", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id asc")
+		}).
+		Preload("Childs.Category").
+		Where("parent_id IS NULL AND id > ?", afterID).
+		Order("id asc").
+		Limit(limit).
+		Find(&modelProducts).Error
+	if err != nil {
+		log.Errorf("[ProductRepository-1] GetForIndex: %v", err)
+		return nil, err
+	}
+
+	respProducts := []entities.ProductEntity{}
+	for _, val := range modelProducts {
+		childEntities := []entities.ProductEntity{}
+		for _, child := range val.Childs {
+			childEntities = append(childEntities, entities.ProductEntity{
+				ID:           child.ID,
+				CategorySlug: child.CategorySlug,
+				ParentID:     child.ParentID,
+				SKU:          skuValue(child.SKU),
+				Name:         child.Name,
+				Image:        child.Image,
+				Description:  child.Description,
+				RegulerPrice: child.RegulerPrice,
+				SalePrice:    child.SalePrice,
+				Unit:         child.Unit,
+				Weight:       child.Weight,
+				Stock:        child.Stock,
+				Variant:      child.Variant,
+				Type:         child.Type,
+				Selling:      sellingEntity(child),
+				Status:       child.Status,
+				CategoryName: child.Category.Name,
+				CreatedAt:    child.CreatedAt,
+			})
+		}
+
+		imageEntities := []entities.ProductImageEntity{}
+		for _, image := range val.Images {
+			imageEntities = append(imageEntities, productImageModelToEntity(image))
+		}
+
+		respProducts = append(respProducts, entities.ProductEntity{
+			ID:           val.ID,
+			CategorySlug: val.CategorySlug,
+			ParentID:     val.ParentID,
+			SKU:          skuValue(val.SKU),
+			Name:         val.Name,
+			Image:        val.Image,
+			Description:  val.Description,
+			RegulerPrice: val.RegulerPrice,
+			SalePrice:    val.SalePrice,
+			Unit:         val.Unit,
+			Weight:       val.Weight,
+			Stock:        val.Stock,
+			Variant:      val.Variant,
+			Type:         val.Type,
+			Selling:      sellingEntity(val),
+			Rating:       val.Rating,
+			RatingCount:  val.RatingCount,
+			Status:       val.Status,
+			PublishAt:    val.PublishAt,
+			UnpublishAt:  val.UnpublishAt,
+			CategoryName: val.Category.Name,
+			Attributes:   attributesMap(val.Attributes),
+			Child:        childEntities,
+			Images:       imageEntities,
+			CreatedAt:    val.CreatedAt,
+		})
+	}
+
+	return respProducts, nil
+}
+
 // GetAll implements [IProductRepository].
 func (p *productRepository) GetAll(ctx context.Context, query entities.QueryStringProduct) ([]entities.ProductEntity, int64, int64, error) {
 	modelProducts := []models.Product{}
@@ -442,6 +774,7 @@ func (p *productRepository) GetAll(ctx context.Context, query entities.QueryStri
 			ID:           val.ID,
 			CategorySlug: val.CategorySlug,
 			ParentID:     val.ParentID,
+			SKU:          skuValue(val.SKU),
 			Name:         val.Name,
 			Image:        val.Image,
 			Description:  val.Description,
@@ -451,8 +784,15 @@ func (p *productRepository) GetAll(ctx context.Context, query entities.QueryStri
 			Weight:       val.Weight,
 			Stock:        val.Stock,
 			Variant:      val.Variant,
+			Type:         val.Type,
+			Selling:      sellingEntity(val),
+			Rating:       val.Rating,
+			RatingCount:  val.RatingCount,
 			Status:       val.Status,
+			PublishAt:    val.PublishAt,
+			UnpublishAt:  val.UnpublishAt,
 			CategoryName: val.Category.Name,
+			Attributes:   attributesMap(val.Attributes),
 			CreatedAt:    val.CreatedAt,
 		})
 	}
@@ -460,6 +800,163 @@ func (p *productRepository) GetAll(ctx context.Context, query entities.QueryStri
 	return respProducts, countData, int64(totalPage), nil
 }
 
+// GetBySKU implements [IProductRepository].
+func (p *productRepository) GetBySKU(ctx context.Context, sku string) (*entities.ProductEntity, error) {
+	modelProduct := models.Product{}
+	if err := p.db.WithContext(ctx).Select("id").Where("sku = ?", sku).First(&modelProduct).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = errors.New("404")
+		}
+		log.Errorf("[ProductRepository-1] GetBySKU: %v", err)
+		return nil, err
+	}
+
+	return p.GetByID(ctx, modelProduct.ID)
+}
+
+// UpdateStatus implements [IProductRepository]. It saves the lifecycle fields
+// of a top level product; its variants take over the status.
+func (p *productRepository) UpdateStatus(ctx context.Context, req entities.ProductEntity) error {
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Product{}).Where("id = ?", req.ID).Updates(map[string]interface{}{
+			"status":       req.Status,
+			"publish_at":   req.PublishAt,
+			"unpublish_at": req.UnpublishAt,
+			"approved_by":  req.ApprovedBy,
+			"approved_at":  req.ApprovedAt,
+			"review_note":  req.ReviewNote,
+			"updated_at":   time.Now(),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("404")
+		}
+
+		return tx.Model(&models.Product{}).Where("parent_id = ?", req.ID).Update("status", req.Status).Error
+	})
+	if err != nil {
+		log.Errorf("[ProductRepository-1] UpdateStatus: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// ApplySchedule implements [IProductRepository]. Scheduled products whose
+// publish_at has passed are published and published products whose
+// unpublish_at has passed are archived. It returns the ids of both groups.
+func (p *productRepository) ApplySchedule(ctx context.Context, now time.Time) ([]int64, []int64, error) {
+	publishedIDs, err := p.moveDue(ctx, entities.ProductStatusScheduled, entities.ProductStatusPublished, "publish_at", now)
+	if err != nil {
+		log.Errorf("[ProductRepository-1] ApplySchedule: %v", err)
+		return nil, nil, err
+	}
+
+	archivedIDs, err := p.moveDue(ctx, entities.ProductStatusPublished, entities.ProductStatusArchived, "unpublish_at", now)
+	if err != nil {
+		log.Errorf("[ProductRepository-2] ApplySchedule: %v", err)
+		return publishedIDs, nil, err
+	}
+
+	return publishedIDs, archivedIDs, nil
+}
+
+// moveDue moves the top level products in status from whose timestamp column
+// has passed to status to, together with their variants. Rows are locked so
+// two workers never move the same product twice.
+func (p *productRepository) moveDue(ctx context.Context, from, to, column string, now time.Time) ([]int64, error) {
+	productIDs := []int64{}
+
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Product{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("parent_id IS NULL AND status = ? AND "+column+" <= ?", from, now).
+			Order("id asc").
+			Pluck("id", &productIDs).Error
+		if err != nil || len(productIDs) == 0 {
+			return err
+		}
+
+		return tx.Model(&models.Product{}).
+			Where("id IN ? OR parent_id IN ?", productIDs, productIDs).
+			Updates(map[string]interface{}{"status": to, "updated_at": now}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return productIDs, nil
+}
+
+// skuPointer stores an empty SKU as NULL, which the unique index ignores.
+func skuPointer(sku string) *string {
+	if sku == "" {
+		return nil
+	}
+	return &sku
+}
+
+func skuValue(sku *string) string {
+	if sku == nil {
+		return ""
+	}
+	return *sku
+}
+
+// attributesJSON encodes attribute values for the jsonb column; no values is
+// an empty object.
+func attributesJSON(attributes map[string]interface{}) string {
+	if len(attributes) == 0 {
+		return "{}"
+	}
+
+	data, err := json.Marshal(attributes)
+	if err != nil {
+		log.Errorf("[ProductRepository-1] attributesJSON: %v", err)
+		return "{}"
+	}
+	return string(data)
+}
+
+func sellingEntity(modelProduct models.Product) entities.SellingEntity {
+	return entities.SellingEntity{
+		SellBy:       modelProduct.SellBy,
+		PriceUnit:    modelProduct.PriceUnit,
+		QuantityUnit: modelProduct.QuantityUnit,
+		QuantityStep: modelProduct.QuantityStep,
+		MinQuantity:  modelProduct.MinQuantity,
+		MaxQuantity:  modelProduct.MaxQuantity,
+	}
+}
+
+// applySelling copies the selling rules into the model; no rules sell the
+// product by item.
+func applySelling(modelProduct *models.Product, selling entities.SellingEntity) {
+	if selling.SellBy == "" {
+		selling = entities.SellingEntity{SellBy: entities.SellByItem}
+	}
+	modelProduct.SellBy = selling.SellBy
+	modelProduct.PriceUnit = selling.PriceUnit
+	modelProduct.QuantityUnit = selling.QuantityUnit
+	modelProduct.QuantityStep = selling.Step()
+	modelProduct.MinQuantity = selling.MinQuantity
+	modelProduct.MaxQuantity = selling.MaxQuantity
+}
+
+func attributesMap(data string) map[string]interface{} {
+	attributes := map[string]interface{}{}
+	if data == "" {
+		return attributes
+	}
+
+	if err := json.Unmarshal([]byte(data), &attributes); err != nil {
+		log.Errorf("[ProductRepository-1] attributesMap: %v", err)
+	}
+	return attributes
+}
+
 func NewProductRepository(db *gorm.DB, es *elasticsearch.Client) IProductRepository {
 	return &productRepository{
 		db:       db,
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/domain/models"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+)
+
+type IPromotionRepository interface {
+	GetAll(ctx context.Context, query entities.QueryStringEntity) ([]entities.PromotionEntity, int64, int64, error)
+	GetByID(ctx context.Context, id int64) (*entities.PromotionEntity, error)
+	Create(ctx context.Context, req entities.PromotionEntity) error
+	Update(ctx context.Context, req entities.PromotionEntity) error
+	Delete(ctx context.Context, id int64) error
+
+	GetActive(ctx context.Context, now time.Time) ([]entities.PromotionEntity, error)
+}
+
+type promotionRepository struct {
+	db *gorm.DB
+}
+
+// GetActive implements [IPromotionRepository].
+func (p *promotionRepository) GetActive(ctx context.Context, now time.Time) ([]entities.PromotionEntity, error) {
+	modelPromotions := []models.Promotion{}
+	err := p.db.WithContext(ctx).Preload("Tiers", func(db *gorm.DB) *gorm.DB {
+		return db.Order("min_quantity asc")
+	}).
+		Where("status = ?", true).
+		Where("start_at IS NULL OR start_at <= ?", now).
+		Where("end_at IS NULL OR end_at > ?", now).
+		Order("priority desc, id asc").
+		Find(&modelPromotions).Error
+	if err != nil {
+		log.Errorf("[PromotionRepository-1] GetActive: %v", err)
+		return nil, err
+	}
+
+	respPromotions := []entities.PromotionEntity{}
+	for _, val := range modelPromotions {
+		respPromotions = append(respPromotions, promotionModelToEntity(val))
+	}
+
+	return respPromotions, nil
+}
+
+// GetAll implements [IPromotionRepository].
+func (p *promotionRepository) GetAll(ctx context.Context, query entities.QueryStringEntity) ([]entities.PromotionEntity, int64, int64, error) {
+	modelPromotions := []models.Promotion{}
+	var countData int64
+
+	order := fmt.Sprintf("%s %s", query.OrderBy, query.OrderType)
+	offset := (query.Page - 1) * query.Limit
+
+	sqlMain := p.db.WithContext(ctx).Preload("Tiers").
+		Where("name ILIKE ? OR type ILIKE ?", "%"+query.Search+"%", "%"+query.Search+"%")
+	if err := sqlMain.Model(&modelPromotions).Count(&countData).Error; err != nil {
+		log.Errorf("[PromotionRepository-1] GetAll: %v", err)
+		return nil, 0, 0, err
+	}
+
+	totalPage := int(math.Ceil(float64(countData) / float64(query.Limit)))
+	if err := sqlMain.Order(order).Limit(query.Limit).Offset(offset).Find(&modelPromotions).Error; err != nil {
+		log.Errorf("[PromotionRepository-2] GetAll: %v", err)
+		return nil, 0, 0, err
+	}
+
+	if len(modelPromotions) == 0 {
+		err := errors.New("404")
+		log.Infof("[PromotionRepository-3] GetAll: No promotion found")
+		return nil, 0, 0, err
+	}
+
+	respPromotions := []entities.PromotionEntity{}
+	for _, val := range modelPromotions {
+		respPromotions = append(respPromotions, promotionModelToEntity(val))
+	}
+
+	return respPromotions, countData, int64(totalPage), nil
+}
+
+// GetByID implements [IPromotionRepository].
+func (p *promotionRepository) GetByID(ctx context.Context, id int64) (*entities.PromotionEntity, error) {
+	modelPromotion := models.Promotion{}
+	if err := p.db.WithContext(ctx).Preload("Tiers").Where("id = ?", id).First(&modelPromotion).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = errors.New("404")
+		}
+		log.Errorf("[PromotionRepository-1] GetByID: %v", err)
+		return nil, err
+	}
+
+	result := promotionModelToEntity(modelPromotion)
+	return &result, nil
+}
+
+// Create implements [IPromotionRepository].
+func (p *promotionRepository) Create(ctx context.Context, req entities.PromotionEntity) error {
+	modelPromotion := promotionEntityToModel(req)
+	if err := p.db.WithContext(ctx).Create(&modelPromotion).Error; err != nil {
+		log.Errorf("[PromotionRepository-1] Create: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Update implements [IPromotionRepository].
+func (p *promotionRepository) Update(ctx context.Context, req entities.PromotionEntity) error {
+	modelPromotion := models.Promotion{}
+	if err := p.db.WithContext(ctx).Where("id = ?", req.ID).First(&modelPromotion).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = errors.New("404")
+		}
+		log.Errorf("[PromotionRepository-1] Update: %v", err)
+		return err
+	}
+
+	updated := promotionEntityToModel(req)
+	updated.ID = modelPromotion.ID
+	updated.CreatedAt = modelPromotion.CreatedAt
+
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("promotion_id = ?", updated.ID).Delete(&models.PromotionTier{}).Error; err != nil {
+			log.Errorf("[PromotionRepository-2] Update: %v", err)
+			return err
+		}
+
+		if err := tx.Save(&updated).Error; err != nil {
+			log.Errorf("[PromotionRepository-3] Update: %v", err)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// Delete implements [IPromotionRepository].
+func (p *promotionRepository) Delete(ctx context.Context, id int64) error {
+	result := p.db.WithContext(ctx).Where("id = ?", id).Delete(&models.Promotion{})
+	if result.Error != nil {
+		log.Errorf("[PromotionRepository-1] Delete: %v", result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		err := errors.New("404")
+		log.Errorf("[PromotionRepository-2] Delete: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func promotionModelToEntity(val models.Promotion) entities.PromotionEntity {
+	status := entities.PublishedStatus
+	if !val.Status {
+		status = entities.UnpublishedStatus
+	}
+
+	tiers := []entities.PromotionTierEntity{}
+	for _, tier := range val.Tiers {
+		tiers = append(tiers, entities.PromotionTierEntity{
+			MinQuantity: tier.MinQuantity,
+			Value:       tier.Value,
+		})
+	}
+
+	return entities.PromotionEntity{
+		ID:              val.ID,
+		Name:            val.Name,
+		Description:     val.Description,
+		Type:            val.Type,
+		Value:           val.Value,
+		BuyQuantity:     val.BuyQuantity,
+		GetQuantity:     val.GetQuantity,
+		CategorySlug:    val.CategorySlug,
+		ProductID:       val.ProductID,
+		MinQuantity:     val.MinQuantity,
+		MinSubtotal:     val.MinSubtotal,
+		CustomerSegment: val.CustomerSegment,
+		Priority:        val.Priority,
+		Stackable:       val.Stackable,
+		Status:          status,
+		StartAt:         val.StartAt,
+		EndAt:           val.EndAt,
+		Tiers:           tiers,
+	}
+}
+
+func promotionEntityToModel(req entities.PromotionEntity) models.Promotion {
+	tiers := []models.PromotionTier{}
+	for _, tier := range req.Tiers {
+		tiers = append(tiers, models.PromotionTier{
+			MinQuantity: tier.MinQuantity,
+			Value:       tier.Value,
+		})
+	}
+
+	return models.Promotion{
+		Name:            req.Name,
+		Description:     req.Description,
+		Type:            req.Type,
+		Value:           req.Value,
+		BuyQuantity:     req.BuyQuantity,
+		GetQuantity:     req.GetQuantity,
+		CategorySlug:    req.CategorySlug,
+		ProductID:       req.ProductID,
+		MinQuantity:     req.MinQuantity,
+		MinSubtotal:     req.MinSubtotal,
+		CustomerSegment: req.CustomerSegment,
+		Priority:        req.Priority,
+		Stackable:       req.Stackable,
+		Status:          req.Status != entities.UnpublishedStatus,
+		StartAt:         req.StartAt,
+		EndAt:           req.EndAt,
+		Tiers:           tiers,
+	}
+}
+
+func NewPromotionRepository(db *gorm.DB) IPromotionRepository {
+	return &promotionRepository{
+		db: db,
+	}
+}
@@ -0,0 +1,344 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/domain/models"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IPurchaseLimitRepository interface {
+	GetAll(ctx context.Context, query entities.QueryStringEntity) ([]entities.PurchaseLimitEntity, int64, int64, error)
+	GetByID(ctx context.Context, id int64) (*entities.PurchaseLimitEntity, error)
+	Create(ctx context.Context, req entities.PurchaseLimitEntity) error
+	Update(ctx context.Context, req entities.PurchaseLimitEntity) error
+	Delete(ctx context.Context, id int64) error
+
+	GetActive(ctx context.Context, productIDs []int64, categorySlugs []string) ([]entities.PurchaseLimitEntity, error)
+	GetPurchased(ctx context.Context, buyerID int64, limit entities.PurchaseLimitEntity, since time.Time) (int64, error)
+	Reserve(ctx context.Context, buyerID int64, orderCode string, items []entities.CartItem, limits []entities.PurchaseLimitViolationEntity, expiredBefore time.Time) ([]entities.PurchaseLimitViolationEntity, error)
+	Release(ctx context.Context, orderCode string) error
+}
+
+type purchaseLimitRepository struct {
+	db *gorm.DB
+}
+
+// GetActive implements [IPurchaseLimitRepository]. It returns the active
+// limits on any of the products or categories.
+func (p *purchaseLimitRepository) GetActive(ctx context.Context, productIDs []int64, categorySlugs []string) ([]entities.PurchaseLimitEntity, error) {
+	modelLimits := []models.PurchaseLimit{}
+	if len(productIDs) == 0 && len(categorySlugs) == 0 {
+		return []entities.PurchaseLimitEntity{}, nil
+	}
+
+	target := p.db.WithContext(ctx)
+	switch {
+	case len(productIDs) == 0:
+		target = target.Where("category_slug IN ?", categorySlugs)
+	case len(categorySlugs) == 0:
+		target = target.Where("product_id IN ?", productIDs)
+	default:
+		target = target.Where("product_id IN ? OR category_slug IN ?", productIDs, categorySlugs)
+	}
+
+	err := p.db.WithContext(ctx).
+		Where("is_active = ?", true).
+		Where(target).
+		Order("id asc").
+		Find(&modelLimits).Error
+	if err != nil {
+		log.Errorf("[PurchaseLimitRepository-1] GetActive: %v", err)
+		return nil, err
+	}
+
+	results := []entities.PurchaseLimitEntity{}
+	for _, val := range modelLimits {
+		results = append(results, purchaseLimitModelToEntity(val))
+	}
+
+	return results, nil
+}
+
+// GetPurchased implements [IPurchaseLimitRepository]. It adds up what the
+// buyer ordered since the given time of the products the limit covers; a
+// limit on a product covers its variants too.
+func (p *purchaseLimitRepository) GetPurchased(ctx context.Context, buyerID int64, limit entities.PurchaseLimitEntity, since time.Time) (int64, error) {
+	purchased, err := sumReserved(p.db.WithContext(ctx), buyerID, limit, since)
+	if err != nil {
+		log.Errorf("[PurchaseLimitRepository-1] GetPurchased: %v", err)
+		return 0, err
+	}
+
+	return purchased, nil
+}
+
+// Reserve implements [IPurchaseLimitRepository]. The limits are locked for
+// the whole transaction, so concurrent orders under the same limit are
+// serialized and each is counted against the ones committed before it. The
+// items are only recorded when no limit is exceeded; otherwise the limits
+// gone over are returned. Reservations older than expiredBefore no longer
+// count for any limit and are removed.
+func (p *purchaseLimitRepository) Reserve(ctx context.Context, buyerID int64, orderCode string, items []entities.CartItem, limits []entities.PurchaseLimitViolationEntity, expiredBefore time.Time) ([]entities.PurchaseLimitViolationEntity, error) {
+	violations := []entities.PurchaseLimitViolationEntity{}
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(limits) > 0 {
+			limitIDs := []int64{}
+			for _, val := range limits {
+				limitIDs = append(limitIDs, val.LimitID)
+			}
+
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", limitIDs).
+				Order("id asc").Find(&[]models.PurchaseLimit{}).Error; err != nil {
+				log.Errorf("[PurchaseLimitRepository-1] Reserve: %v", err)
+				return err
+			}
+		}
+
+		var reserved int64
+		if err := tx.Model(&models.PurchaseReservation{}).Where("order_code = ?", orderCode).Count(&reserved).Error; err != nil {
+			log.Errorf("[PurchaseLimitRepository-2] Reserve: %v", err)
+			return err
+		}
+		if reserved > 0 {
+			// the order is already counted, e.g. a retried checkout
+			return nil
+		}
+
+		now := time.Now()
+		for _, val := range limits {
+			purchased, err := sumReserved(tx, buyerID, val.Limit(), now.Add(-val.Limit().Window()))
+			if err != nil {
+				log.Errorf("[PurchaseLimitRepository-3] Reserve: %v", err)
+				return err
+			}
+
+			if val.Exceeds(purchased) {
+				violations = append(violations, val)
+			}
+		}
+		if len(violations) > 0 {
+			return nil
+		}
+
+		if err := tx.Where("buyer_id = ? AND reserved_at < ?", buyerID, expiredBefore).
+			Delete(&models.PurchaseReservation{}).Error; err != nil {
+			log.Errorf("[PurchaseLimitRepository-4] Reserve: %v", err)
+			return err
+		}
+
+		quantities := map[int64]int64{}
+		modelReservations := []models.PurchaseReservation{}
+		for _, item := range items {
+			if _, ok := quantities[item.ProductID]; !ok {
+				modelReservations = append(modelReservations, models.PurchaseReservation{
+					OrderCode:  orderCode,
+					ProductID:  item.ProductID,
+					BuyerID:    buyerID,
+					ReservedAt: now,
+				})
+			}
+			quantities[item.ProductID] += item.Quantity
+		}
+		for i := range modelReservations {
+			modelReservations[i].Quantity = quantities[modelReservations[i].ProductID]
+		}
+
+		if len(modelReservations) == 0 {
+			return nil
+		}
+
+		if err := tx.Create(&modelReservations).Error; err != nil {
+			log.Errorf("[PurchaseLimitRepository-5] Reserve: %v", err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return violations, nil
+}
+
+// Release implements [IPurchaseLimitRepository]. Releasing an order that
+// holds nothing is not an error, so cancelling is safe to retry.
+func (p *purchaseLimitRepository) Release(ctx context.Context, orderCode string) error {
+	if err := p.db.WithContext(ctx).Where("order_code = ?", orderCode).Delete(&models.PurchaseReservation{}).Error; err != nil {
+		log.Errorf("[PurchaseLimitRepository-1] Release: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// sumReserved adds up the reservations of the buyer since the given time of
+// the products the limit covers.
+func sumReserved(db *gorm.DB, buyerID int64, limit entities.PurchaseLimitEntity, since time.Time) (int64, error) {
+	sqlMain := db.Model(&models.PurchaseReservation{}).
+		Joins("JOIN products ON products.id = purchase_reservations.product_id").
+		Where("purchase_reservations.buyer_id = ? AND purchase_reservations.reserved_at >= ?", buyerID, since)
+	if limit.ProductID != nil {
+		sqlMain = sqlMain.Where("(products.id = ? OR products.parent_id = ?)", *limit.ProductID, *limit.ProductID)
+	} else {
+		sqlMain = sqlMain.Where("products.category_slug = ?", limit.CategorySlug)
+	}
+
+	var purchased int64
+	if err := sqlMain.Select("COALESCE(SUM(purchase_reservations.quantity), 0)").Scan(&purchased).Error; err != nil {
+		return 0, err
+	}
+
+	return purchased, nil
+}
+
+// GetAll implements [IPurchaseLimitRepository].
+func (p *purchaseLimitRepository) GetAll(ctx context.Context, query entities.QueryStringEntity) ([]entities.PurchaseLimitEntity, int64, int64, error) {
+	modelLimits := []models.PurchaseLimit{}
+	var countData int64
+
+	order := fmt.Sprintf("%s %s", query.OrderBy, query.OrderType)
+	offset := (query.Page - 1) * query.Limit
+
+	sqlMain := p.db.WithContext(ctx).
+		Where("name ILIKE ? OR category_slug ILIKE ?", "%"+query.Search+"%", "%"+query.Search+"%")
+	if err := sqlMain.Model(&modelLimits).Count(&countData).Error; err != nil {
+		log.Errorf("[PurchaseLimitRepository-1] GetAll: %v", err)
+		return nil, 0, 0, err
+	}
+
+	totalPage := int(math.Ceil(float64(countData) / float64(query.Limit)))
+	if err := sqlMain.Order(order).Limit(query.Limit).Offset(offset).Find(&modelLimits).Error; err != nil {
+		log.Errorf("[PurchaseLimitRepository-2] GetAll: %v", err)
+		return nil, 0, 0, err
+	}
+
+	if len(modelLimits) == 0 {
+		err := errors.New("404")
+		log.Infof("[PurchaseLimitRepository-3] GetAll: No purchase limit found")
+		return nil, 0, 0, err
+	}
+
+	results := []entities.PurchaseLimitEntity{}
+	for _, val := range modelLimits {
+		results = append(results, purchaseLimitModelToEntity(val))
+	}
+
+	return results, countData, int64(totalPage), nil
+}
+
+// GetByID implements [IPurchaseLimitRepository].
+func (p *purchaseLimitRepository) GetByID(ctx context.Context, id int64) (*entities.PurchaseLimitEntity, error) {
+	modelLimit := models.PurchaseLimit{}
+	if err := p.db.WithContext(ctx).Where("id = ?", id).First(&modelLimit).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = errors.New("404")
+		}
+		log.Errorf("[PurchaseLimitRepository-1] GetByID: %v", err)
+		return nil, err
+	}
+
+	result := purchaseLimitModelToEntity(modelLimit)
+	return &result, nil
+}
+
+// Create implements [IPurchaseLimitRepository].
+func (p *purchaseLimitRepository) Create(ctx context.Context, req entities.PurchaseLimitEntity) error {
+	modelLimit := purchaseLimitEntityToModel(req)
+	if err := p.db.WithContext(ctx).Create(&modelLimit).Error; err != nil {
+		log.Errorf("[PurchaseLimitRepository-1] Create: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Update implements [IPurchaseLimitRepository].
+func (p *purchaseLimitRepository) Update(ctx context.Context, req entities.PurchaseLimitEntity) error {
+	modelLimit := models.PurchaseLimit{}
+	if err := p.db.WithContext(ctx).Where("id = ?", req.ID).First(&modelLimit).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = errors.New("404")
+		}
+		log.Errorf("[PurchaseLimitRepository-1] Update: %v", err)
+		return err
+	}
+
+	updated := purchaseLimitEntityToModel(req)
+	updated.ID = modelLimit.ID
+	updated.CreatedAt = modelLimit.CreatedAt
+	now := time.Now()
+	updated.UpdatedAt = &now
+
+	if err := p.db.WithContext(ctx).Save(&updated).Error; err != nil {
+		log.Errorf("[PurchaseLimitRepository-2] Update: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Delete implements [IPurchaseLimitRepository].
+func (p *purchaseLimitRepository) Delete(ctx context.Context, id int64) error {
+	result := p.db.WithContext(ctx).Where("id = ?", id).Delete(&models.PurchaseLimit{})
+	if result.Error != nil {
+		log.Errorf("[PurchaseLimitRepository-1] Delete: %v", result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		err := errors.New("404")
+		log.Errorf("[PurchaseLimitRepository-2] Delete: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func purchaseLimitModelToEntity(val models.PurchaseLimit) entities.PurchaseLimitEntity {
+	categorySlug := ""
+	if val.CategorySlug != nil {
+		categorySlug = *val.CategorySlug
+	}
+
+	return entities.PurchaseLimitEntity{
+		ID:           val.ID,
+		Name:         val.Name,
+		ProductID:    val.ProductID,
+		CategorySlug: categorySlug,
+		MaxQuantity:  val.MaxQuantity,
+		WindowHours:  val.WindowHours,
+		IsActive:     val.IsActive,
+		CreatedAt:    val.CreatedAt,
+	}
+}
+
+func purchaseLimitEntityToModel(req entities.PurchaseLimitEntity) models.PurchaseLimit {
+	var categorySlug *string
+	if req.CategorySlug != "" {
+		categorySlug = &req.CategorySlug
+	}
+
+	return models.PurchaseLimit{
+		Name:         req.Name,
+		ProductID:    req.ProductID,
+		CategorySlug: categorySlug,
+		MaxQuantity:  req.MaxQuantity,
+		WindowHours:  req.WindowHours,
+		IsActive:     req.IsActive,
+	}
+}
+
+func NewPurchaseLimitRepository(db *gorm.DB) IPurchaseLimitRepository {
+	return &purchaseLimitRepository{
+		db: db,
+	}
+}
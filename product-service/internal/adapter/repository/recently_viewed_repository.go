@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	// recentlyViewedCap is how many products are remembered per customer;
+	// older views are dropped.
+	recentlyViewedCap = 50
+	// recentlyViewedTTL removes the list of a customer who stopped visiting.
+	recentlyViewedTTL = 90 * 24 * time.Hour
+)
+
+// Redis keys for product views, built only in this file:
+//
+//	recently_viewed:<userID>  sorted set of product IDs scored by view time
+type IRecentlyViewedRepository interface {
+	Add(ctx context.Context, userID, productID int64, at time.Time) error
+	Get(ctx context.Context, userID int64, limit int) ([]int64, error)
+	Clear(ctx context.Context, userID int64) error
+}
+
+type recentlyViewedRepository struct {
+	client *redis.Client
+}
+
+// Add implements [IRecentlyViewedRepository]. Viewing a product again moves
+// it to the front.
+func (r *recentlyViewedRepository) Add(ctx context.Context, userID, productID int64, at time.Time) error {
+	key := recentlyViewedKey(userID)
+	pipe := r.client.TxPipeline()
+	pipe.ZAdd(ctx, key, &redis.Z{Score: float64(at.UnixMilli()), Member: productID})
+	pipe.ZRemRangeByRank(ctx, key, 0, -recentlyViewedCap-1)
+	pipe.Expire(ctx, key, recentlyViewedTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Errorf("[RecentlyViewedRepository-1] Add: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Get implements [IRecentlyViewedRepository]. It returns the product IDs
+// latest view first.
+func (r *recentlyViewedRepository) Get(ctx context.Context, userID int64, limit int) ([]int64, error) {
+	if limit <= 0 || limit > recentlyViewedCap {
+		limit = recentlyViewedCap
+	}
+
+	members, err := r.client.ZRevRange(ctx, recentlyViewedKey(userID), 0, int64(limit-1)).Result()
+	if err != nil {
+		log.Errorf("[RecentlyViewedRepository-1] Get: %v", err)
+		return nil, err
+	}
+
+	productIDs := []int64{}
+	for _, member := range members {
+		productID, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			log.Errorf("[RecentlyViewedRepository-2] Get: %v", err)
+			continue
+		}
+		productIDs = append(productIDs, productID)
+	}
+
+	return productIDs, nil
+}
+
+// Clear implements [IRecentlyViewedRepository].
+func (r *recentlyViewedRepository) Clear(ctx context.Context, userID int64) error {
+	if err := r.client.Del(ctx, recentlyViewedKey(userID)).Err(); err != nil {
+		log.Errorf("[RecentlyViewedRepository-1] Clear: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func recentlyViewedKey(userID int64) string {
+	return fmt.Sprintf("recently_viewed:%d", userID)
+}
+
+func NewRecentlyViewedRepository(client *redis.Client) IRecentlyViewedRepository {
+	return &recentlyViewedRepository{
+		client: client,
+	}
+}
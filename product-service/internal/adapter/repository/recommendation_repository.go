@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/domain/models"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IRecommendationRepository interface {
+	RecordOrder(ctx context.Context, order entities.OrderEventEntity) error
+	Compute(ctx context.Context, since time.Time, topN int) (int64, error)
+	GetRelatedIDs(ctx context.Context, productID int64, limit int) ([]int64, error)
+	GetRelatedToIDs(ctx context.Context, productIDs, excludeIDs []int64, limit int) ([]int64, error)
+	GetBestSellerIDs(ctx context.Context, categorySlugs []string, since time.Time, excludeIDs []int64, limit int) ([]int64, error)
+	GetPurchasedIDs(ctx context.Context, buyerID int64, limit int) ([]int64, error)
+}
+
+type recommendationRepository struct {
+	db *gorm.DB
+}
+
+// RecordOrder implements [IRecommendationRepository]. An order that is
+// delivered twice is stored once.
+func (r *recommendationRepository) RecordOrder(ctx context.Context, order entities.OrderEventEntity) error {
+	orderedAt := order.CreatedAt
+	if orderedAt.IsZero() {
+		orderedAt = time.Now()
+	}
+
+	var buyerID *int64
+	if order.BuyerID != 0 {
+		buyerID = &order.BuyerID
+	}
+
+	quantities := map[int64]int64{}
+	modelPurchases := []models.OrderPurchase{}
+	for _, item := range order.OrderItems {
+		if _, ok := quantities[item.ProductID]; !ok {
+			modelPurchases = append(modelPurchases, models.OrderPurchase{
+				OrderID:   order.ID,
+				ProductID: item.ProductID,
+				BuyerID:   buyerID,
+				OrderedAt: orderedAt,
+			})
+		}
+		quantities[item.ProductID] += item.Quantity
+	}
+	for i := range modelPurchases {
+		modelPurchases[i].Quantity = quantities[modelPurchases[i].ProductID]
+	}
+
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&modelPurchases).Error; err != nil {
+		log.Errorf("[RecommendationRepository-1] RecordOrder: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Compute implements [IRecommendationRepository]. It counts, for every pair
+// of products, the orders since the given time that contain both, and keeps
+// the topN pairs of each product. Variants count as their product. Purchases
+// older than the window are dropped.
+func (r *recommendationRepository) Compute(ctx context.Context, since time.Time, topN int) (int64, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("ordered_at < ?", since).Delete(&models.OrderPurchase{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&models.ProductRecommendation{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(`
+			WITH items AS (
+				SELECT DISTINCT order_purchases.order_id, COALESCE(products.parent_id, products.id) AS product_id
+				FROM order_purchases
+				JOIN products ON products.id = order_purchases.product_id
+				WHERE order_purchases.ordered_at >= ?
+			), pairs AS (
+				SELECT a.product_id, b.product_id AS related_id, COUNT(*) AS score
+				FROM items a
+				JOIN items b ON b.order_id = a.order_id AND b.product_id <> a.product_id
+				GROUP BY a.product_id, b.product_id
+			), ranked AS (
+				SELECT product_id, related_id, score,
+					ROW_NUMBER() OVER (PARTITION BY product_id ORDER BY score DESC, related_id ASC) AS rank
+				FROM pairs
+			)
+			INSERT INTO product_recommendations (product_id, related_id, score, rank, computed_at)
+			SELECT product_id, related_id, score, rank, ?
+			FROM ranked
+			WHERE rank <= ?`, since, time.Now(), topN)
+		if result.Error != nil {
+			return result.Error
+		}
+		count = result.RowsAffected
+
+		return nil
+	})
+	if err != nil {
+		log.Errorf("[RecommendationRepository-1] Compute: %v", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetRelatedIDs implements [IRecommendationRepository]. Only published
+// products are returned, best match first.
+func (r *recommendationRepository) GetRelatedIDs(ctx context.Context, productID int64, limit int) ([]int64, error) {
+	relatedIDs := []int64{}
+	err := r.db.WithContext(ctx).Model(&models.ProductRecommendation{}).
+		Joins("JOIN products ON products.id = product_recommendations.related_id").
+		Where("product_recommendations.product_id = ?", productID).
+		Where("products.deleted_at IS NULL AND products.status = ?", entities.ProductStatusPublished).
+		Order("product_recommendations.rank asc").
+		Limit(limit).
+		Pluck("product_recommendations.related_id", &relatedIDs).Error
+	if err != nil {
+		log.Errorf("[RecommendationRepository-1] GetRelatedIDs: %v", err)
+		return nil, err
+	}
+
+	return relatedIDs, nil
+}
+
+// GetRelatedToIDs implements [IRecommendationRepository]. It merges the
+// related products of several products, adding up their scores.
+func (r *recommendationRepository) GetRelatedToIDs(ctx context.Context, productIDs, excludeIDs []int64, limit int) ([]int64, error) {
+	relatedIDs := []int64{}
+	if len(productIDs) == 0 {
+		return relatedIDs, nil
+	}
+
+	sqlMain := r.db.WithContext(ctx).Model(&models.ProductRecommendation{}).
+		Joins("JOIN products ON products.id = product_recommendations.related_id").
+		Where("product_recommendations.product_id IN ?", productIDs).
+		Where("products.deleted_at IS NULL AND products.status = ?", entities.ProductStatusPublished)
+	if len(excludeIDs) > 0 {
+		sqlMain = sqlMain.Where("product_recommendations.related_id NOT IN ?", excludeIDs)
+	}
+
+	err := sqlMain.
+		Group("product_recommendations.related_id").
+		Order("SUM(product_recommendations.score) desc, product_recommendations.related_id asc").
+		Limit(limit).
+		Pluck("product_recommendations.related_id", &relatedIDs).Error
+	if err != nil {
+		log.Errorf("[RecommendationRepository-1] GetRelatedToIDs: %v", err)
+		return nil, err
+	}
+
+	return relatedIDs, nil
+}
+
+// GetBestSellerIDs implements [IRecommendationRepository]. Published top
+// level products of the categories, or of all categories when none are
+// given, are ranked by the quantity sold since the given time, their
+// variants included; products that did not sell follow, newest first.
+func (r *recommendationRepository) GetBestSellerIDs(ctx context.Context, categorySlugs []string, since time.Time, excludeIDs []int64, limit int) ([]int64, error) {
+	sold := r.db.Model(&models.OrderPurchase{}).
+		Select("COALESCE(products.parent_id, products.id) AS product_id, SUM(order_purchases.quantity) AS quantity").
+		Joins("JOIN products ON products.id = order_purchases.product_id").
+		Where("order_purchases.ordered_at >= ?", since).
+		Group("COALESCE(products.parent_id, products.id)")
+
+	sqlMain := r.db.WithContext(ctx).Model(&models.Product{}).
+		Joins("LEFT JOIN (?) sold ON sold.product_id = products.id", sold).
+		Where("products.parent_id IS NULL AND products.status = ?", entities.ProductStatusPublished)
+	if len(categorySlugs) > 0 {
+		sqlMain = sqlMain.Where("products.category_slug IN ?", categorySlugs)
+	}
+	if len(excludeIDs) > 0 {
+		sqlMain = sqlMain.Where("products.id NOT IN ?", excludeIDs)
+	}
+
+	productIDs := []int64{}
+	err := sqlMain.
+		Order("COALESCE(sold.quantity, 0) desc, products.created_at desc, products.id desc").
+		Limit(limit).
+		Pluck("products.id", &productIDs).Error
+	if err != nil {
+		log.Errorf("[RecommendationRepository-1] GetBestSellerIDs: %v", err)
+		return nil, err
+	}
+
+	return productIDs, nil
+}
+
+// GetPurchasedIDs implements [IRecommendationRepository]. It returns the top
+// level products the buyer ordered within the window, latest first.
+func (r *recommendationRepository) GetPurchasedIDs(ctx context.Context, buyerID int64, limit int) ([]int64, error) {
+	productIDs := []int64{}
+	err := r.db.WithContext(ctx).Model(&models.OrderPurchase{}).
+		Joins("JOIN products ON products.id = order_purchases.product_id").
+		Where("order_purchases.buyer_id = ?", buyerID).
+		Group("COALESCE(products.parent_id, products.id)").
+		Order("MAX(order_purchases.ordered_at) desc, COALESCE(products.parent_id, products.id) asc").
+		Limit(limit).
+		Pluck("COALESCE(products.parent_id, products.id)", &productIDs).Error
+	if err != nil {
+		log.Errorf("[RecommendationRepository-1] GetPurchasedIDs: %v", err)
+		return nil, err
+	}
+
+	return productIDs, nil
+}
+
+func NewRecommendationRepository(db *gorm.DB) IRecommendationRepository {
+	return &recommendationRepository{
+		db: db,
+	}
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/labstack/gommon/log"
+)
+
+// reindexLockKey holds the token of the process reindexing the products, so
+// the reindex command and the server never load and delete versions at the
+// same time.
+const reindexLockKey = "lock:reindex:products"
+
+var (
+	// reindexUnlockScript deletes the lock only while it is still held with
+	// the token, never one taken over after it expired.
+	reindexUnlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+	// reindexRefreshScript extends the lock only while it is still held with
+	// the token.
+	reindexRefreshScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+)
+
+type IReindexLockRepository interface {
+	Acquire(ctx context.Context, ttl time.Duration) (string, bool, error)
+	Refresh(ctx context.Context, token string, ttl time.Duration) (bool, error)
+	Release(ctx context.Context, token string) error
+}
+
+type reindexLockRepository struct {
+	client *redis.Client
+}
+
+// Acquire implements [IReindexLockRepository]. It returns the token the lock
+// is held with, and false when another process holds it.
+func (r *reindexLockRepository) Acquire(ctx context.Context, ttl time.Duration) (string, bool, error) {
+	token := uuid.NewString()
+
+	ok, err := r.client.SetNX(ctx, reindexLockKey, token, ttl).Result()
+	if err != nil {
+		log.Errorf("[ReindexLockRepository-1] Acquire: %v", err)
+		return "", false, err
+	}
+
+	return token, ok, nil
+}
+
+// Refresh implements [IReindexLockRepository]. It returns false when the
+// lock expired and is no longer held with token.
+func (r *reindexLockRepository) Refresh(ctx context.Context, token string, ttl time.Duration) (bool, error) {
+	n, err := reindexRefreshScript.Run(ctx, r.client, []string{reindexLockKey}, token, ttl.Milliseconds()).Int64()
+	if err != nil {
+		log.Errorf("[ReindexLockRepository-1] Refresh: %v", err)
+		return false, err
+	}
+
+	return n == 1, nil
+}
+
+// Release implements [IReindexLockRepository].
+func (r *reindexLockRepository) Release(ctx context.Context, token string) error {
+	if err := reindexUnlockScript.Run(ctx, r.client, []string{reindexLockKey}, token).Err(); err != nil {
+		log.Errorf("[ReindexLockRepository-1] Release: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func NewReindexLockRepository(client *redis.Client) IReindexLockRepository {
+	return &reindexLockRepository{
+		client: client,
+	}
+}
@@ -0,0 +1,388 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"product-service/internal/core/domain/entities"
+	"product-service/utils/esquery"
+	"strconv"
+	"time"
+
+	"github.com/labstack/gommon/log"
+)
+
+// checksumPageSize is how many documents Checksums reads per request.
+const checksumPageSize = 1000
+
+// productIndexVersion names a new version of the product index. The template
+// pattern products* covers it.
+func productIndexVersion(now time.Time) string {
+	return ProductIndex + "_v" + now.UTC().Format("20060102150405")
+}
+
+// createVersion creates an empty versioned index from the template, with the
+// alias already pointing at it when withAlias is set.
+func (s *searchRepository) createVersion(ctx context.Context, withAlias bool) (string, error) {
+	index := productIndexVersion(time.Now())
+
+	body := map[string]interface{}{}
+	if withAlias {
+		body["aliases"] = map[string]interface{}{ProductIndex: map[string]interface{}{}}
+	} else {
+		// a bulk load is much faster without refreshing in between
+		body["settings"] = map[string]interface{}{"index": map[string]interface{}{"refresh_interval": "-1"}}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := s.esClient.Indices.Create(index,
+		s.esClient.Indices.Create.WithContext(ctx),
+		s.esClient.Indices.Create.WithBody(bytes.NewReader(data)),
+	)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return "", fmt.Errorf("create index %s: %s", index, res.Status())
+	}
+	return index, nil
+}
+
+// CreateVersion implements [ISearchRepository]. The new index is not visible
+// to searches until [ISearchRepository.SwapAlias] points the alias at it.
+func (s *searchRepository) CreateVersion(ctx context.Context) (string, error) {
+	index, err := s.createVersion(ctx, false)
+	if err != nil {
+		log.Errorf("[SearchRepository-1] CreateVersion: %v", err)
+		return "", err
+	}
+
+	return index, nil
+}
+
+// FinishVersion implements [ISearchRepository]. It turns refreshing back on
+// after a bulk load and makes every loaded document searchable.
+func (s *searchRepository) FinishVersion(ctx context.Context, index string) error {
+	body := bytes.NewReader([]byte(`{"index":{"refresh_interval":null}}`))
+	res, err := s.esClient.Indices.PutSettings(body,
+		s.esClient.Indices.PutSettings.WithContext(ctx),
+		s.esClient.Indices.PutSettings.WithIndex(index),
+	)
+	if err != nil {
+		log.Errorf("[SearchRepository-1] FinishVersion: %v", err)
+		return err
+	}
+	res.Body.Close()
+	if res.IsError() {
+		err = fmt.Errorf("update settings %s: %s", index, res.Status())
+		log.Errorf("[SearchRepository-2] FinishVersion: %v", err)
+		return err
+	}
+
+	res, err = s.esClient.Indices.Refresh(
+		s.esClient.Indices.Refresh.WithContext(ctx),
+		s.esClient.Indices.Refresh.WithIndex(index),
+	)
+	if err != nil {
+		log.Errorf("[SearchRepository-3] FinishVersion: %v", err)
+		return err
+	}
+	res.Body.Close()
+	if res.IsError() {
+		err = fmt.Errorf("refresh %s: %s", index, res.Status())
+		log.Errorf("[SearchRepository-4] FinishVersion: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Versions implements [ISearchRepository].
+func (s *searchRepository) Versions(ctx context.Context) ([]string, error) {
+	res, err := s.esClient.Indices.Get([]string{ProductIndex + "_v*"},
+		s.esClient.Indices.Get.WithContext(ctx),
+	)
+	if err != nil {
+		log.Errorf("[SearchRepository-1] Versions: %v", err)
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		err = fmt.Errorf("get indices %s_v*: %s", ProductIndex, res.Status())
+		log.Errorf("[SearchRepository-2] Versions: %v", err)
+		return nil, err
+	}
+
+	indices := map[string]json.RawMessage{}
+	if err := json.NewDecoder(res.Body).Decode(&indices); err != nil {
+		log.Errorf("[SearchRepository-3] Versions: %v", err)
+		return nil, err
+	}
+
+	versions := []string{}
+	for index := range indices {
+		versions = append(versions, index)
+	}
+	return versions, nil
+}
+
+// SwapAlias implements [ISearchRepository]. Adding the alias to the new index
+// and removing it from the old ones is a single atomic request, so searches
+// never see an empty or missing index. A plain index still occupying the
+// alias name is deleted in the same request.
+func (s *searchRepository) SwapAlias(ctx context.Context, index string) error {
+	actions := []map[string]interface{}{}
+
+	res, err := s.esClient.Indices.GetAlias(
+		s.esClient.Indices.GetAlias.WithContext(ctx),
+		s.esClient.Indices.GetAlias.WithName(ProductIndex),
+	)
+	if err != nil {
+		log.Errorf("[SearchRepository-1] SwapAlias: %v", err)
+		return err
+	}
+
+	aliased := map[string]json.RawMessage{}
+	if res.StatusCode != 404 {
+		err = json.NewDecoder(res.Body).Decode(&aliased)
+	}
+	res.Body.Close()
+	if err != nil {
+		log.Errorf("[SearchRepository-2] SwapAlias: %v", err)
+		return err
+	}
+
+	for old := range aliased {
+		if old != index {
+			actions = append(actions, map[string]interface{}{
+				"remove": map[string]interface{}{"index": old, "alias": ProductIndex},
+			})
+		}
+	}
+
+	if len(aliased) == 0 {
+		res, err = s.esClient.Indices.Exists([]string{ProductIndex}, s.esClient.Indices.Exists.WithContext(ctx))
+		if err != nil {
+			log.Errorf("[SearchRepository-3] SwapAlias: %v", err)
+			return err
+		}
+		res.Body.Close()
+
+		if res.StatusCode == 200 {
+			actions = append(actions, map[string]interface{}{
+				"remove_index": map[string]interface{}{"index": ProductIndex},
+			})
+		}
+	}
+
+	actions = append(actions, map[string]interface{}{
+		"add": map[string]interface{}{"index": index, "alias": ProductIndex},
+	})
+
+	body, err := json.Marshal(map[string]interface{}{"actions": actions})
+	if err != nil {
+		log.Errorf("[SearchRepository-4] SwapAlias: %v", err)
+		return err
+	}
+
+	res, err = s.esClient.Indices.UpdateAliases(bytes.NewReader(body),
+		s.esClient.Indices.UpdateAliases.WithContext(ctx),
+	)
+	if err != nil {
+		log.Errorf("[SearchRepository-5] SwapAlias: %v", err)
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		err = fmt.Errorf("update aliases %s: %s", ProductIndex, res.Status())
+		log.Errorf("[SearchRepository-6] SwapAlias: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteIndices implements [ISearchRepository].
+func (s *searchRepository) DeleteIndices(ctx context.Context, indices []string) error {
+	if len(indices) == 0 {
+		return nil
+	}
+
+	res, err := s.esClient.Indices.Delete(indices,
+		s.esClient.Indices.Delete.WithContext(ctx),
+		s.esClient.Indices.Delete.WithIgnoreUnavailable(true),
+	)
+	if err != nil {
+		log.Errorf("[SearchRepository-1] DeleteIndices: %v", err)
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		err = fmt.Errorf("delete indices %v: %s", indices, res.Status())
+		log.Errorf("[SearchRepository-2] DeleteIndices: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		ID     string          `json:"_id"`
+		Status int             `json:"status"`
+		Error  json.RawMessage `json:"error"`
+	} `json:"items"`
+}
+
+// BulkIndex implements [ISearchRepository]. Published products are written
+// in full, while unpublished ones and deleteIDs are removed, in one bulk
+// request; deleting a document that is already gone is not an error.
+func (s *searchRepository) BulkIndex(ctx context.Context, index string, products []entities.ProductEntity, deleteIDs []int64) error {
+	if len(products) == 0 && len(deleteIDs) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, product := range products {
+		if !product.IsPublished() {
+			deleteIDs = append(deleteIDs, product.ID)
+			continue
+		}
+
+		action := map[string]interface{}{"index": map[string]interface{}{"_id": strconv.FormatInt(product.ID, 10)}}
+		if err := encoder.Encode(action); err != nil {
+			log.Errorf("[SearchRepository-1] BulkIndex: %v", err)
+			return err
+		}
+		if err := encoder.Encode(product); err != nil {
+			log.Errorf("[SearchRepository-1] BulkIndex: %v", err)
+			return err
+		}
+	}
+	for _, id := range deleteIDs {
+		action := map[string]interface{}{"delete": map[string]interface{}{"_id": strconv.FormatInt(id, 10)}}
+		if err := encoder.Encode(action); err != nil {
+			log.Errorf("[SearchRepository-1] BulkIndex: %v", err)
+			return err
+		}
+	}
+
+	res, err := s.esClient.Bulk(&body,
+		s.esClient.Bulk.WithContext(ctx),
+		s.esClient.Bulk.WithIndex(index),
+	)
+	if err != nil {
+		log.Errorf("[SearchRepository-2] BulkIndex: %v", err)
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		err = fmt.Errorf("bulk %s: %s", index, res.Status())
+		log.Errorf("[SearchRepository-3] BulkIndex: %v", err)
+		return err
+	}
+
+	var result bulkResponse
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		log.Errorf("[SearchRepository-4] BulkIndex: %v", err)
+		return err
+	}
+	if !result.Errors {
+		return nil
+	}
+
+	failed := 0
+	var firstErr string
+	for _, item := range result.Items {
+		for action, val := range item {
+			if val.Status < 300 || (action == "delete" && val.Status == 404) {
+				continue
+			}
+			if failed == 0 {
+				firstErr = fmt.Sprintf("document %s: %s", val.ID, val.Error)
+			}
+			failed++
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+
+	err = fmt.Errorf("bulk %s: %d documents failed, first %s", index, failed, firstErr)
+	log.Errorf("[SearchRepository-5] BulkIndex: %v", err)
+	return err
+}
+
+type checksumResponse struct {
+	Hits struct {
+		Hits []struct {
+			Source entities.ProductEntity `json:"_source"`
+			Sort   []interface{}          `json:"sort"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// Checksums implements [ISearchRepository]. It pages through every document of
+// the index by id and returns the checksum of each, keyed by product id.
+func (s *searchRepository) Checksums(ctx context.Context, index string) (map[int64]string, error) {
+	checksums := map[int64]string{}
+
+	var searchAfter []interface{}
+	for {
+		body, err := esquery.Search{
+			Size:        checksumPageSize,
+			Sort:        []esquery.Sort{{Field: "id"}},
+			SearchAfter: searchAfter,
+		}.Reader()
+		if err != nil {
+			log.Errorf("[SearchRepository-1] Checksums: %v", err)
+			return nil, err
+		}
+
+		res, err := s.esClient.Search(
+			s.esClient.Search.WithContext(ctx),
+			s.esClient.Search.WithIndex(index),
+			s.esClient.Search.WithBody(body),
+		)
+		if err != nil {
+			log.Errorf("[SearchRepository-2] Checksums: %v", err)
+			return nil, err
+		}
+
+		if res.IsError() {
+			res.Body.Close()
+			err = fmt.Errorf("search %s: %s", index, res.Status())
+			log.Errorf("[SearchRepository-3] Checksums: %v", err)
+			return nil, err
+		}
+
+		var result checksumResponse
+		err = json.NewDecoder(res.Body).Decode(&result)
+		res.Body.Close()
+		if err != nil {
+			log.Errorf("[SearchRepository-4] Checksums: %v", err)
+			return nil, err
+		}
+
+		for _, hit := range result.Hits.Hits {
+			checksums[hit.Source.ID] = hit.Source.IndexChecksum()
+		}
+
+		if len(result.Hits.Hits) < checksumPageSize {
+			return checksums, nil
+		}
+		searchAfter = result.Hits.Hits[len(result.Hits.Hits)-1].Sort
+	}
+}
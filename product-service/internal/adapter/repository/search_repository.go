@@ -0,0 +1,395 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"product-service/internal/core/domain/entities"
+	"product-service/utils/esquery"
+	"strings"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/labstack/gommon/log"
+)
+
+// ProductIndex is the alias product documents are indexed and searched
+// through. It points at one versioned index, e.g. products_v20240101120000;
+// older deployments may still have a plain index with this name until the
+// first reindex replaces it.
+const ProductIndex = "products"
+
+// suggestTimeout keeps autocomplete responsive; Elasticsearch returns what it
+// found so far instead of waiting for slow shards.
+const suggestTimeout = 40 * time.Millisecond
+
+// productIndexTemplate applies the product settings and mappings to every
+// index named products*, including the versions created by a reindex.
+const productIndexTemplate = "products_template"
+
+type ISearchRepository interface {
+	EnsureIndex(ctx context.Context, synonymRules []string) (bool, error)
+	PutIndexTemplate(ctx context.Context, synonymRules []string) error
+
+	CreateVersion(ctx context.Context) (string, error)
+	FinishVersion(ctx context.Context, index string) error
+	Versions(ctx context.Context) ([]string, error)
+	SwapAlias(ctx context.Context, index string) error
+	DeleteIndices(ctx context.Context, indices []string) error
+	BulkIndex(ctx context.Context, index string, products []entities.ProductEntity, deleteIDs []int64) error
+	Checksums(ctx context.Context, index string) (map[int64]string, error)
+	Suggest(ctx context.Context, text string, size int) (*entities.SuggestEntity, error)
+}
+
+type searchRepository struct {
+	esClient *elasticsearch.Client
+}
+
+// productSynonymFilter holds the admin managed synonyms. Inline synonyms
+// cannot be reloaded on a live index, so a change is applied by a reindex.
+func productSynonymFilter(synonymRules []string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":     "synonym_graph",
+		"synonyms": synonymRules,
+		"lenient":  true,
+	}
+}
+
+// productIndexSettings defines the Indonesian analyzers: lowercase, folding of
+// accents, Indonesian stopwords and stemming, plus synonyms at search time.
+func productIndexSettings(synonymRules []string) map[string]interface{} {
+	return map[string]interface{}{
+		"analysis": map[string]interface{}{
+			"filter": map[string]interface{}{
+				"indonesian_stop":    map[string]interface{}{"type": "stop", "stopwords": "_indonesian_"},
+				"indonesian_stemmer": map[string]interface{}{"type": "stemmer", "language": "indonesian"},
+				"product_synonyms":   productSynonymFilter(synonymRules),
+			},
+			"analyzer": map[string]interface{}{
+				"indonesian_index": map[string]interface{}{
+					"tokenizer": "standard",
+					"filter":    []string{"lowercase", "asciifolding", "indonesian_stop", "indonesian_stemmer"},
+				},
+				"indonesian_search": map[string]interface{}{
+					"tokenizer": "standard",
+					"filter":    []string{"lowercase", "asciifolding", "product_synonyms", "indonesian_stop", "indonesian_stemmer"},
+				},
+			},
+		},
+	}
+}
+
+// indonesianText is a text field analyzed in Indonesian with the keyword and
+// autocomplete sub-fields the queries rely on.
+func indonesianText(withSuggest bool) map[string]interface{} {
+	fields := map[string]interface{}{
+		"keyword": map[string]interface{}{"type": "keyword", "ignore_above": 256},
+	}
+	if withSuggest {
+		fields["suggest"] = map[string]interface{}{"type": "search_as_you_type"}
+	}
+
+	return map[string]interface{}{
+		"type":            "text",
+		"analyzer":        "indonesian_index",
+		"search_analyzer": "indonesian_search",
+		"fields":          fields,
+	}
+}
+
+// productIndexMappings maps every field of the product document explicitly.
+// Text fields keep the .keyword sub-field dynamic mapping used to create.
+// Attribute values are mapped dynamically, all as keywords, so new
+// attributes can be filtered on without changing the mapping; an index
+// created before attributes existed needs a reindex to pick this up.
+func productIndexMappings() map[string]interface{} {
+	keywordText := map[string]interface{}{
+		"type": "text",
+		"fields": map[string]interface{}{
+			"keyword": map[string]interface{}{"type": "keyword", "ignore_above": 256},
+		},
+	}
+
+	return map[string]interface{}{
+		"dynamic_templates": []map[string]interface{}{
+			{"attributes": map[string]interface{}{
+				"path_match": "attributes.*",
+				"mapping":    map[string]interface{}{"type": "keyword"},
+			}},
+		},
+		"properties": map[string]interface{}{
+			"id":            map[string]interface{}{"type": "long"},
+			"parent_id":     map[string]interface{}{"type": "long"},
+			"sku":           map[string]interface{}{"type": "keyword"},
+			"category_slug": keywordText,
+			"category_name": indonesianText(true),
+			"name":          indonesianText(true),
+			"description":   indonesianText(false),
+			"image":         map[string]interface{}{"type": "keyword", "index": false},
+			"reguler_price": map[string]interface{}{"type": "double"},
+			"sale_price":    map[string]interface{}{"type": "double"},
+			"unit":          keywordText,
+			"weight":        map[string]interface{}{"type": "integer"},
+			"stock":         map[string]interface{}{"type": "integer"},
+			"rating":        map[string]interface{}{"type": "float"},
+			"rating_count":  map[string]interface{}{"type": "integer"},
+			"variant":       map[string]interface{}{"type": "integer"},
+			"type":          map[string]interface{}{"type": "keyword"},
+			"status":        keywordText,
+			"publish_at":    map[string]interface{}{"type": "date"},
+			"unpublish_at":  map[string]interface{}{"type": "date"},
+			"created_at":    map[string]interface{}{"type": "date"},
+			"attributes":    map[string]interface{}{"type": "object"},
+			"selling": map[string]interface{}{
+				"properties": map[string]interface{}{
+					"sell_by":       map[string]interface{}{"type": "keyword"},
+					"price_unit":    map[string]interface{}{"type": "keyword"},
+					"quantity_unit": map[string]interface{}{"type": "keyword"},
+					"quantity_step": map[string]interface{}{"type": "long"},
+					"min_quantity":  map[string]interface{}{"type": "long"},
+					"max_quantity":  map[string]interface{}{"type": "long"},
+				},
+			},
+			// variants and images are only displayed, never searched
+			"child":  map[string]interface{}{"type": "object", "enabled": false},
+			"images": map[string]interface{}{"type": "object", "enabled": false},
+		},
+	}
+}
+
+// PutIndexTemplate implements [ISearchRepository]. It stores the product
+// settings and mappings, synonyms included, as a composable index template;
+// only indices created afterwards, i.e. the next version, pick them up.
+func (s *searchRepository) PutIndexTemplate(ctx context.Context, synonymRules []string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"index_patterns": []string{ProductIndex + "*"},
+		"template": map[string]interface{}{
+			"settings": productIndexSettings(synonymRules),
+			"mappings": productIndexMappings(),
+		},
+	})
+	if err != nil {
+		log.Errorf("[SearchRepository-1] PutIndexTemplate: %v", err)
+		return err
+	}
+
+	res, err := s.esClient.Indices.PutIndexTemplate(productIndexTemplate, bytes.NewReader(body),
+		s.esClient.Indices.PutIndexTemplate.WithContext(ctx),
+	)
+	if err != nil {
+		log.Errorf("[SearchRepository-2] PutIndexTemplate: %v", err)
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		err = fmt.Errorf("put index template %s: %s", productIndexTemplate, res.Status())
+		log.Errorf("[SearchRepository-3] PutIndexTemplate: %v", err)
+		return err
+	}
+	return nil
+}
+
+// EnsureIndex implements [ISearchRepository]. The index template is always
+// refreshed and a missing index is created from it as the first version
+// behind the alias. It reports whether the live index lacks the analyzers of
+// the template, as an index created by dynamic mapping does; only a reindex
+// into a new version fixes that.
+func (s *searchRepository) EnsureIndex(ctx context.Context, synonymRules []string) (bool, error) {
+	if err := s.PutIndexTemplate(ctx, synonymRules); err != nil {
+		log.Errorf("[SearchRepository-1] EnsureIndex: %v", err)
+		return false, err
+	}
+
+	res, err := s.esClient.Indices.Exists([]string{ProductIndex}, s.esClient.Indices.Exists.WithContext(ctx))
+	if err != nil {
+		log.Errorf("[SearchRepository-2] EnsureIndex: %v", err)
+		return false, err
+	}
+	res.Body.Close()
+
+	if res.StatusCode == 404 {
+		if _, err := s.createVersion(ctx, true); err != nil {
+			log.Errorf("[SearchRepository-3] EnsureIndex: %v", err)
+			return false, err
+		}
+		return false, nil
+	}
+
+	res, err = s.esClient.Indices.GetSettings(
+		s.esClient.Indices.GetSettings.WithContext(ctx),
+		s.esClient.Indices.GetSettings.WithIndex(ProductIndex),
+		s.esClient.Indices.GetSettings.WithName("index.analysis.analyzer.indonesian_search"),
+	)
+	if err != nil {
+		log.Errorf("[SearchRepository-4] EnsureIndex: %v", err)
+		return false, err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		err = fmt.Errorf("get settings %s: %s", ProductIndex, res.Status())
+		log.Errorf("[SearchRepository-5] EnsureIndex: %v", err)
+		return false, err
+	}
+
+	indexSettings := map[string]struct {
+		Settings map[string]interface{} `json:"settings"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&indexSettings); err != nil {
+		log.Errorf("[SearchRepository-6] EnsureIndex: %v", err)
+		return false, err
+	}
+
+	for _, index := range indexSettings {
+		if len(index.Settings) == 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type suggestResponse struct {
+	Hits struct {
+		Hits []struct {
+			Source entities.SuggestProductEntity `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+	Aggregations struct {
+		Categories struct {
+			Buckets []struct {
+				Key      string `json:"key"`
+				Category struct {
+					Hits struct {
+						Hits []struct {
+							Source struct {
+								CategoryName string `json:"category_name"`
+							} `json:"_source"`
+						} `json:"hits"`
+					} `json:"hits"`
+				} `json:"category"`
+			} `json:"buckets"`
+		} `json:"categories"`
+	} `json:"aggregations"`
+	Suggest struct {
+		DidYouMean []struct {
+			Text    string `json:"text"`
+			Options []struct {
+				Text string `json:"text"`
+			} `json:"options"`
+		} `json:"did_you_mean"`
+	} `json:"suggest"`
+}
+
+// Suggest implements [ISearchRepository]. Products are matched as you type on
+// product and category names, with a fuzzy match on the name for typos. The
+// term suggester runs along and is only used when nothing matched.
+func (s *searchRepository) Suggest(ctx context.Context, text string, size int) (*entities.SuggestEntity, error) {
+	boolQuery := esquery.Bool{
+		Should: []esquery.Query{
+			esquery.BoolPrefix(text,
+				"name.suggest", "name.suggest._2gram", "name.suggest._3gram",
+				"category_name.suggest", "category_name.suggest._2gram", "category_name.suggest._3gram",
+			),
+			esquery.FuzzyMatch("name", text),
+		},
+		Filter:             []esquery.Query{esquery.Match("status", entities.PublishedStatus)},
+		MinimumShouldMatch: 1,
+	}
+
+	body, err := esquery.Search{
+		Size:   size,
+		Query:  boolQuery.Query(),
+		Source: []string{"id", "name", "image", "category_name", "category_slug", "reguler_price", "sale_price"},
+		Aggs: map[string]interface{}{
+			"categories": map[string]interface{}{
+				"terms": map[string]interface{}{"field": "category_slug.keyword", "size": 3},
+				"aggs": map[string]interface{}{
+					"category": map[string]interface{}{
+						"top_hits": map[string]interface{}{"size": 1, "_source": []string{"category_name"}},
+					},
+				},
+			},
+		},
+		Suggest: map[string]interface{}{
+			"text": text,
+			"did_you_mean": map[string]interface{}{
+				"term": map[string]interface{}{
+					"field":           "name",
+					"suggest_mode":    "popular",
+					"min_word_length": 3,
+				},
+			},
+		},
+	}.Reader()
+	if err != nil {
+		log.Errorf("[SearchRepository-1] Suggest: %v", err)
+		return nil, err
+	}
+
+	res, err := s.esClient.Search(
+		s.esClient.Search.WithContext(ctx),
+		s.esClient.Search.WithIndex(ProductIndex),
+		s.esClient.Search.WithBody(body),
+		s.esClient.Search.WithTimeout(suggestTimeout),
+	)
+	if err != nil {
+		log.Errorf("[SearchRepository-2] Suggest: %v", err)
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		err = fmt.Errorf("elasticsearch suggest failed: %s", res.Status())
+		log.Errorf("[SearchRepository-3] Suggest: %v", err)
+		return nil, err
+	}
+
+	var result suggestResponse
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		log.Errorf("[SearchRepository-4] Suggest: %v", err)
+		return nil, err
+	}
+
+	suggest := &entities.SuggestEntity{
+		Query:      text,
+		Products:   []entities.SuggestProductEntity{},
+		Categories: []entities.SuggestCategoryEntity{},
+	}
+	for _, hit := range result.Hits.Hits {
+		suggest.Products = append(suggest.Products, hit.Source)
+	}
+
+	for _, bucket := range result.Aggregations.Categories.Buckets {
+		category := entities.SuggestCategoryEntity{Slug: bucket.Key}
+		if hits := bucket.Category.Hits.Hits; len(hits) > 0 {
+			category.Name = hits[0].Source.CategoryName
+		}
+		suggest.Categories = append(suggest.Categories, category)
+	}
+
+	if len(suggest.Products) == 0 {
+		words := []string{}
+		changed := false
+		for _, token := range result.Suggest.DidYouMean {
+			if len(token.Options) > 0 {
+				words = append(words, token.Options[0].Text)
+				changed = true
+				continue
+			}
+			words = append(words, token.Text)
+		}
+		if changed {
+			suggest.DidYouMean = strings.Join(words, " ")
+		}
+	}
+
+	return suggest, nil
+}
+
+func NewSearchRepository(esClient *elasticsearch.Client) ISearchRepository {
+	return &searchRepository{
+		esClient: esClient,
+	}
+}
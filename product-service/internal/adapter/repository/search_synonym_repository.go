@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/domain/models"
+	"strings"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+)
+
+type ISearchSynonymRepository interface {
+	GetAll(ctx context.Context) ([]entities.SearchSynonymEntity, error)
+	GetByID(ctx context.Context, id int64) (*entities.SearchSynonymEntity, error)
+	Create(ctx context.Context, req entities.SearchSynonymEntity) error
+	Update(ctx context.Context, req entities.SearchSynonymEntity) error
+	Delete(ctx context.Context, id int64) error
+}
+
+type searchSynonymRepository struct {
+	db *gorm.DB
+}
+
+// GetAll implements [ISearchSynonymRepository].
+func (s *searchSynonymRepository) GetAll(ctx context.Context) ([]entities.SearchSynonymEntity, error) {
+	modelSynonyms := []models.SearchSynonym{}
+	if err := s.db.WithContext(ctx).Order("id ASC").Find(&modelSynonyms).Error; err != nil {
+		log.Errorf("[SearchSynonymRepository-1] GetAll: %v", err)
+		return nil, err
+	}
+
+	respSynonyms := []entities.SearchSynonymEntity{}
+	for _, val := range modelSynonyms {
+		respSynonyms = append(respSynonyms, searchSynonymModelToEntity(val))
+	}
+
+	return respSynonyms, nil
+}
+
+// GetByID implements [ISearchSynonymRepository].
+func (s *searchSynonymRepository) GetByID(ctx context.Context, id int64) (*entities.SearchSynonymEntity, error) {
+	modelSynonym := models.SearchSynonym{}
+	if err := s.db.WithContext(ctx).Where("id = ?", id).First(&modelSynonym).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = errors.New("404")
+		}
+		log.Errorf("[SearchSynonymRepository-1] GetByID: %v", err)
+		return nil, err
+	}
+
+	result := searchSynonymModelToEntity(modelSynonym)
+	return &result, nil
+}
+
+// Create implements [ISearchSynonymRepository].
+func (s *searchSynonymRepository) Create(ctx context.Context, req entities.SearchSynonymEntity) error {
+	modelSynonym := models.SearchSynonym{
+		Terms: strings.Join(req.Terms, ","),
+	}
+
+	if err := s.db.WithContext(ctx).Create(&modelSynonym).Error; err != nil {
+		log.Errorf("[SearchSynonymRepository-1] Create: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Update implements [ISearchSynonymRepository].
+func (s *searchSynonymRepository) Update(ctx context.Context, req entities.SearchSynonymEntity) error {
+	modelSynonym := models.SearchSynonym{}
+	if err := s.db.WithContext(ctx).Where("id = ?", req.ID).First(&modelSynonym).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = errors.New("404")
+		}
+		log.Errorf("[SearchSynonymRepository-1] Update: %v", err)
+		return err
+	}
+
+	now := time.Now()
+	modelSynonym.Terms = strings.Join(req.Terms, ",")
+	modelSynonym.UpdatedAt = &now
+	if err := s.db.WithContext(ctx).Save(&modelSynonym).Error; err != nil {
+		log.Errorf("[SearchSynonymRepository-2] Update: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Delete implements [ISearchSynonymRepository].
+func (s *searchSynonymRepository) Delete(ctx context.Context, id int64) error {
+	result := s.db.WithContext(ctx).Where("id = ?", id).Delete(&models.SearchSynonym{})
+	if result.Error != nil {
+		log.Errorf("[SearchSynonymRepository-1] Delete: %v", result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		err := errors.New("404")
+		log.Infof("[SearchSynonymRepository-2] Delete: synonym not found")
+		return err
+	}
+
+	return nil
+}
+
+func searchSynonymModelToEntity(val models.SearchSynonym) entities.SearchSynonymEntity {
+	return entities.SearchSynonymEntity{
+		ID:        val.ID,
+		Terms:     strings.Split(val.Terms, ","),
+		CreatedAt: val.CreatedAt,
+	}
+}
+
+func NewSearchSynonymRepository(db *gorm.DB) ISearchSynonymRepository {
+	return &searchSynonymRepository{
+		db: db,
+	}
+}
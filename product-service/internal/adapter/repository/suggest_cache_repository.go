@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"product-service/internal/core/domain/entities"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	// suggestCacheTTL is short so new products show up in autocomplete soon.
+	suggestCacheTTL = 5 * time.Minute
+	// suggestHitWindow is how long a prefix is remembered after its last lookup.
+	suggestHitWindow = 10 * time.Minute
+)
+
+// Redis keys for autocomplete, built only in this file:
+//
+//	suggest:hits:<limit>:<prefix>    lookups of a prefix in the hit window
+//	suggest:result:<limit>:<prefix>  cached suggestions of a popular prefix
+type ISuggestCacheRepository interface {
+	Get(ctx context.Context, prefix string, limit int) (*entities.SuggestEntity, error)
+	Set(ctx context.Context, prefix string, limit int, suggest entities.SuggestEntity) error
+	Hit(ctx context.Context, prefix string, limit int) (int64, error)
+	Flush(ctx context.Context) error
+}
+
+type suggestCacheRepository struct {
+	client *redis.Client
+}
+
+// Get implements [ISuggestCacheRepository]. A cache miss returns nil without
+// an error.
+func (s *suggestCacheRepository) Get(ctx context.Context, prefix string, limit int) (*entities.SuggestEntity, error) {
+	val, err := s.client.Get(ctx, fmt.Sprintf("suggest:result:%d:%s", limit, prefix)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+
+	if err != nil {
+		log.Errorf("[SuggestCacheRepository-1] Get: %v", err)
+		return nil, err
+	}
+
+	var suggest entities.SuggestEntity
+	if err := json.Unmarshal([]byte(val), &suggest); err != nil {
+		log.Errorf("[SuggestCacheRepository-2] Get: %v", err)
+		return nil, err
+	}
+
+	return &suggest, nil
+}
+
+// Set implements [ISuggestCacheRepository].
+func (s *suggestCacheRepository) Set(ctx context.Context, prefix string, limit int, suggest entities.SuggestEntity) error {
+	data, err := json.Marshal(suggest)
+	if err != nil {
+		log.Errorf("[SuggestCacheRepository-1] Set: %v", err)
+		return err
+	}
+
+	return s.client.Set(ctx, fmt.Sprintf("suggest:result:%d:%s", limit, prefix), data, suggestCacheTTL).Err()
+}
+
+// Hit implements [ISuggestCacheRepository]. It counts a lookup of the prefix
+// and returns how often it was looked up since it was last idle for the
+// whole hit window.
+func (s *suggestCacheRepository) Hit(ctx context.Context, prefix string, limit int) (int64, error) {
+	key := fmt.Sprintf("suggest:hits:%d:%s", limit, prefix)
+	pipe := s.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, suggestHitWindow)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Errorf("[SuggestCacheRepository-1] Hit: %v", err)
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
+
+// Flush implements [ISuggestCacheRepository]. It drops every cached result;
+// hit counters are kept so popular prefixes are cached again right away.
+func (s *suggestCacheRepository) Flush(ctx context.Context) error {
+	iter := s.client.Scan(ctx, 0, "suggest:result:*", 100).Iterator()
+	keys := []string{}
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		log.Errorf("[SuggestCacheRepository-1] Flush: %v", err)
+		return err
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+	return s.client.Del(ctx, keys...).Err()
+}
+
+func NewSuggestCacheRepository(client *redis.Client) ISuggestCacheRepository {
+	return &suggestCacheRepository{
+		client: client,
+	}
+}
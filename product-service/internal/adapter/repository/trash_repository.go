@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/domain/models"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+)
+
+type ITrashRepository interface {
+	GetProducts(ctx context.Context, query entities.QueryStringEntity) ([]entities.TrashItemEntity, int64, int64, error)
+	GetProductByID(ctx context.Context, id int64) (*entities.TrashItemEntity, error)
+	RestoreProduct(ctx context.Context, id int64, deletedAt time.Time) error
+
+	GetCategories(ctx context.Context, query entities.QueryStringEntity) ([]entities.TrashItemEntity, int64, int64, error)
+	GetCategoryByID(ctx context.Context, id int64) (*entities.TrashItemEntity, error)
+	RestoreCategory(ctx context.Context, id int64, parentID *int64) error
+
+	Purge(ctx context.Context, before time.Time) (*entities.TrashPurgeEntity, error)
+}
+
+type trashRepository struct {
+	db *gorm.DB
+}
+
+// GetProducts implements [ITrashRepository]. Only deleted top level products
+// are listed; their variants are restored and purged with them.
+func (t *trashRepository) GetProducts(ctx context.Context, query entities.QueryStringEntity) ([]entities.TrashItemEntity, int64, int64, error) {
+	modelProducts := []models.Product{}
+	var countData int64
+
+	sqlMain := t.db.WithContext(ctx).Unscoped().Model(&models.Product{}).
+		Where("deleted_at IS NOT NULL AND parent_id IS NULL")
+	if query.Search != "" {
+		sqlMain = sqlMain.Where("name ILIKE ? OR sku ILIKE ?", "%"+query.Search+"%", "%"+query.Search+"%")
+	}
+
+	if err := sqlMain.Count(&countData).Error; err != nil {
+		log.Errorf("[TrashRepository-1] GetProducts: %v", err)
+		return nil, 0, 0, err
+	}
+
+	offset := (query.Page - 1) * query.Limit
+	if err := sqlMain.Order("deleted_at desc, id desc").Limit(query.Limit).Offset(offset).Find(&modelProducts).Error; err != nil {
+		log.Errorf("[TrashRepository-2] GetProducts: %v", err)
+		return nil, 0, 0, err
+	}
+
+	if len(modelProducts) == 0 {
+		log.Infof("[TrashRepository-3] GetProducts: No product found")
+		return nil, 0, 0, errors.New("404")
+	}
+
+	results := []entities.TrashItemEntity{}
+	for _, val := range modelProducts {
+		results = append(results, productTrashItem(val))
+	}
+
+	totalPage := int64(math.Ceil(float64(countData) / float64(query.Limit)))
+	return results, countData, totalPage, nil
+}
+
+// GetProductByID implements [ITrashRepository].
+func (t *trashRepository) GetProductByID(ctx context.Context, id int64) (*entities.TrashItemEntity, error) {
+	modelProduct := models.Product{}
+	err := t.db.WithContext(ctx).Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL AND parent_id IS NULL", id).
+		First(&modelProduct).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = errors.New("404")
+		}
+		log.Errorf("[TrashRepository-1] GetProductByID: %v", err)
+		return nil, err
+	}
+
+	result := productTrashItem(modelProduct)
+	return &result, nil
+}
+
+// RestoreProduct implements [ITrashRepository]. Variants come back only if
+// they were deleted together with the product; variants replaced by an
+// earlier edit stay in the trash.
+func (t *trashRepository) RestoreProduct(ctx context.Context, id int64, deletedAt time.Time) error {
+	result := t.db.WithContext(ctx).Unscoped().Model(&models.Product{}).
+		Where("(id = ? OR parent_id = ?) AND deleted_at = ?", id, id, deletedAt).
+		Updates(map[string]interface{}{"deleted_at": nil, "updated_at": time.Now()})
+	if result.Error != nil {
+		log.Errorf("[TrashRepository-1] RestoreProduct: %v", result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		err := errors.New("404")
+		log.Errorf("[TrashRepository-2] RestoreProduct: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetCategories implements [ITrashRepository].
+func (t *trashRepository) GetCategories(ctx context.Context, query entities.QueryStringEntity) ([]entities.TrashItemEntity, int64, int64, error) {
+	modelCategories := []models.Category{}
+	var countData int64
+
+	sqlMain := t.db.WithContext(ctx).Unscoped().Model(&models.Category{}).Where("deleted_at IS NOT NULL")
+	if query.Search != "" {
+		sqlMain = sqlMain.Where("name ILIKE ? OR slug ILIKE ?", "%"+query.Search+"%", "%"+query.Search+"%")
+	}
+
+	if err := sqlMain.Count(&countData).Error; err != nil {
+		log.Errorf("[TrashRepository-1] GetCategories: %v", err)
+		return nil, 0, 0, err
+	}
+
+	offset := (query.Page - 1) * query.Limit
+	if err := sqlMain.Order("deleted_at desc, id desc").Limit(query.Limit).Offset(offset).Find(&modelCategories).Error; err != nil {
+		log.Errorf("[TrashRepository-2] GetCategories: %v", err)
+		return nil, 0, 0, err
+	}
+
+	if len(modelCategories) == 0 {
+		log.Infof("[TrashRepository-3] GetCategories: No category found")
+		return nil, 0, 0, errors.New("404")
+	}
+
+	results := []entities.TrashItemEntity{}
+	for _, val := range modelCategories {
+		results = append(results, categoryTrashItem(val))
+	}
+
+	totalPage := int64(math.Ceil(float64(countData) / float64(query.Limit)))
+	return results, countData, totalPage, nil
+}
+
+// GetCategoryByID implements [ITrashRepository].
+func (t *trashRepository) GetCategoryByID(ctx context.Context, id int64) (*entities.TrashItemEntity, error) {
+	modelCategory := models.Category{}
+	err := t.db.WithContext(ctx).Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&modelCategory).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = errors.New("404")
+		}
+		log.Errorf("[TrashRepository-1] GetCategoryByID: %v", err)
+		return nil, err
+	}
+
+	result := categoryTrashItem(modelCategory)
+	return &result, nil
+}
+
+// RestoreCategory implements [ITrashRepository]. parentID replaces the
+// stored parent, so a category whose parent is gone can come back at the
+// top level.
+func (t *trashRepository) RestoreCategory(ctx context.Context, id int64, parentID *int64) error {
+	result := t.db.WithContext(ctx).Unscoped().Model(&models.Category{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "parent_id": parentID, "updated_at": time.Now()})
+	if result.Error != nil {
+		log.Errorf("[TrashRepository-1] RestoreCategory: %v", result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		err := errors.New("404")
+		log.Errorf("[TrashRepository-2] RestoreCategory: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Purge implements [ITrashRepository]. It permanently deletes products and
+// categories that were deleted before the given time. Images, price lists
+// and wishlist entries of a product go with it through ON DELETE CASCADE; a
+// category that products still point to is kept.
+func (t *trashRepository) Purge(ctx context.Context, before time.Time) (*entities.TrashPurgeEntity, error) {
+	result := &entities.TrashPurgeEntity{}
+
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// a component stays while a bundle that is not purged still uses it
+		deleted := tx.Unscoped().
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+			Where("NOT EXISTS (SELECT 1 FROM bundle_items JOIN products bundles ON bundles.id = bundle_items.bundle_id "+
+				"WHERE bundle_items.component_id = products.id AND (bundles.deleted_at IS NULL OR bundles.deleted_at >= ?))", before).
+			Delete(&models.Product{})
+		if deleted.Error != nil {
+			return deleted.Error
+		}
+		result.Products = deleted.RowsAffected
+
+		deleted = tx.Unscoped().
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+			Where("NOT EXISTS (SELECT 1 FROM products WHERE products.category_slug = categories.slug)").
+			Delete(&models.Category{})
+		if deleted.Error != nil {
+			return deleted.Error
+		}
+		result.Categories = deleted.RowsAffected
+
+		return nil
+	})
+	if err != nil {
+		log.Errorf("[TrashRepository-1] Purge: %v", err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func productTrashItem(val models.Product) entities.TrashItemEntity {
+	return entities.TrashItemEntity{
+		ID:           val.ID,
+		ParentID:     val.ParentID,
+		Name:         val.Name,
+		SKU:          skuValue(val.SKU),
+		Image:        val.Image,
+		CategorySlug: val.CategorySlug,
+		Status:       val.Status,
+		DeletedAt:    val.DeletedAt.Time,
+	}
+}
+
+func categoryTrashItem(val models.Category) entities.TrashItemEntity {
+	status := entities.PublishedStatus
+	if !val.Status {
+		status = entities.UnpublishedStatus
+	}
+
+	return entities.TrashItemEntity{
+		ID:        val.ID,
+		ParentID:  val.ParentID,
+		Name:      val.Name,
+		Slug:      val.Slug,
+		Image:     val.Icon,
+		Status:    status,
+		DeletedAt: val.DeletedAt.Time,
+	}
+}
+
+func NewTrashRepository(db *gorm.DB) ITrashRepository {
+	return &trashRepository{
+		db: db,
+	}
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/domain/models"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IWishlistRepository interface {
+	GetByUser(ctx context.Context, userID int64, list string) ([]entities.WishlistItemEntity, error)
+	GetItem(ctx context.Context, userID, productID int64, list string) (*entities.WishlistItemEntity, error)
+	GetByProduct(ctx context.Context, productID int64) ([]entities.WishlistItemEntity, error)
+	Upsert(ctx context.Context, req entities.WishlistItemEntity) error
+	Delete(ctx context.Context, userID, productID int64, list string) error
+	UpdateLastSeen(ctx context.Context, ids []int64, price float64, inStock bool) error
+}
+
+type wishlistRepository struct {
+	db *gorm.DB
+}
+
+// GetByUser implements [IWishlistRepository].
+func (w *wishlistRepository) GetByUser(ctx context.Context, userID int64, list string) ([]entities.WishlistItemEntity, error) {
+	modelItems := []models.WishlistItem{}
+	if err := w.db.WithContext(ctx).
+		Where("user_id = ? AND list = ?", userID, list).
+		Order("created_at DESC").
+		Find(&modelItems).Error; err != nil {
+		log.Errorf("[WishlistRepository-1] GetByUser: %v", err)
+		return nil, err
+	}
+
+	respItems := []entities.WishlistItemEntity{}
+	for _, val := range modelItems {
+		respItems = append(respItems, wishlistModelToEntity(val))
+	}
+
+	return respItems, nil
+}
+
+// GetItem implements [IWishlistRepository].
+func (w *wishlistRepository) GetItem(ctx context.Context, userID, productID int64, list string) (*entities.WishlistItemEntity, error) {
+	modelItem := models.WishlistItem{}
+	if err := w.db.WithContext(ctx).
+		Where("user_id = ? AND product_id = ? AND list = ?", userID, productID, list).
+		First(&modelItem).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = errors.New("404")
+		}
+		log.Errorf("[WishlistRepository-1] GetItem: %v", err)
+		return nil, err
+	}
+
+	result := wishlistModelToEntity(modelItem)
+	return &result, nil
+}
+
+// GetByProduct implements [IWishlistRepository].
+func (w *wishlistRepository) GetByProduct(ctx context.Context, productID int64) ([]entities.WishlistItemEntity, error) {
+	modelItems := []models.WishlistItem{}
+	if err := w.db.WithContext(ctx).Where("product_id = ?", productID).Find(&modelItems).Error; err != nil {
+		log.Errorf("[WishlistRepository-1] GetByProduct: %v", err)
+		return nil, err
+	}
+
+	respItems := []entities.WishlistItemEntity{}
+	for _, val := range modelItems {
+		respItems = append(respItems, wishlistModelToEntity(val))
+	}
+
+	return respItems, nil
+}
+
+// Upsert implements [IWishlistRepository]. Saving a product that is already
+// on the list only updates its quantity and last seen price and stock.
+func (w *wishlistRepository) Upsert(ctx context.Context, req entities.WishlistItemEntity) error {
+	now := time.Now()
+	modelItem := models.WishlistItem{
+		UserID:      req.UserID,
+		ProductID:   req.ProductID,
+		List:        req.List,
+		Quantity:    req.Quantity,
+		LastPrice:   req.LastPrice,
+		LastInStock: req.LastInStock,
+		UpdatedAt:   &now,
+	}
+
+	if err := w.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "product_id"}, {Name: "list"}},
+		DoUpdates: clause.AssignmentColumns([]string{"quantity", "last_price", "last_in_stock", "updated_at"}),
+	}).Create(&modelItem).Error; err != nil {
+		log.Errorf("[WishlistRepository-1] Upsert: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Delete implements [IWishlistRepository].
+func (w *wishlistRepository) Delete(ctx context.Context, userID, productID int64, list string) error {
+	result := w.db.WithContext(ctx).
+		Where("user_id = ? AND product_id = ? AND list = ?", userID, productID, list).
+		Delete(&models.WishlistItem{})
+	if result.Error != nil {
+		log.Errorf("[WishlistRepository-1] Delete: %v", result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		err := errors.New("404")
+		log.Infof("[WishlistRepository-2] Delete: wishlist item not found")
+		return err
+	}
+
+	return nil
+}
+
+// UpdateLastSeen implements [IWishlistRepository].
+func (w *wishlistRepository) UpdateLastSeen(ctx context.Context, ids []int64, price float64, inStock bool) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := w.db.WithContext(ctx).Model(&models.WishlistItem{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"last_price":    price,
+			"last_in_stock": inStock,
+			"updated_at":    time.Now(),
+		}).Error; err != nil {
+		log.Errorf("[WishlistRepository-1] UpdateLastSeen: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func wishlistModelToEntity(val models.WishlistItem) entities.WishlistItemEntity {
+	return entities.WishlistItemEntity{
+		ID:          val.ID,
+		UserID:      val.UserID,
+		ProductID:   val.ProductID,
+		List:        val.List,
+		Quantity:    val.Quantity,
+		LastPrice:   val.LastPrice,
+		LastInStock: val.LastInStock,
+		CreatedAt:   val.CreatedAt,
+	}
+}
+
+func NewWishlistRepository(db *gorm.DB) IWishlistRepository {
+	return &wishlistRepository{
+		db: db,
+	}
+}
@@ -7,10 +7,7 @@ import (
 	"os/signal"
 	"product-service/config"
 	"product-service/internal/adapter/handlers"
-	"product-service/internal/adapter/message"
-	"product-service/internal/adapter/repository"
-	"product-service/internal/adapter/storage"
-	"product-service/internal/core/service"
+	"product-service/internal/adapter/handlers/gql"
 	"syscall"
 	"time"
 
@@ -23,31 +20,18 @@ import (
 
 func RunServer() {
 	cfg := config.NewConfig()
-	db, err := cfg.ConnectionPostgres()
+	d, err := newDependencies(cfg)
 	if err != nil {
 		log.Fatalf("[RunServer-1] %v", err)
 		return
 	}
 
-	elasticInit, err := cfg.InitElastic()
+	graphQLSchema, err := gql.NewSchema(gql.NewResolver(d.productService, d.productImageService, d.categoryService, d.cartService), graphQLMaxDepth(cfg), graphQLMaxComplexity(cfg))
 	if err != nil {
-		log.Fatalf("[RunServer-2] %v", err)
+		log.Fatalf("[RunServer-6] %v", err)
 		return
 	}
 
-	storageHandler := storage.NewSupabase(cfg)
-
-	publisherRabbitMQ := message.NewPublishRabbitMQ(cfg)
-
-	categoryRepo := repository.NewCategoryRepository(db.DB)
-	productRepo := repository.NewProductRepository(db.DB, elasticInit)
-	cartRepo := repository.NewCartRepository(cfg.NewRedisClient())
-	
-
-	categoryService := service.NewCategoryService(categoryRepo)
-	productService := service.NewProductService(productRepo, categoryRepo, publisherRabbitMQ)
-	cartService := service.NewCartService(cartRepo)
-
 	e := echo.New()
 	e.Use(middleware.CORS())
 
@@ -59,10 +43,32 @@ func RunServer() {
 		return c.String(200, "OK")
 	})
 
-	handlers.NewCategoryHandler(e, categoryService, cfg)
-	handlers.NewProductHandler(e, cfg, productService)
-	handlers.NewUploadImage(e, cfg, storageHandler)
-	handlers.NewCartHandler(e, cfg, cartService, productService)
+	handlers.NewCategoryHandler(e, d.categoryService, d.catalogCacheService, cfg)
+	handlers.NewProductHandler(e, cfg, d.productService, d.recommendationService, d.catalogCacheService)
+	handlers.NewProductRatingHandler(e, cfg, d.productRatingService)
+	handlers.NewUploadImage(e, cfg, d.storage)
+	handlers.NewCartHandler(e, cfg, d.cartService)
+	handlers.NewProductImageHandler(e, cfg, d.productImageService, d.storage)
+	handlers.NewPriceHandler(e, cfg, d.priceService)
+	handlers.NewPromotionHandler(e, cfg, d.promotionService)
+	handlers.NewPurchaseLimitHandler(e, cfg, d.purchaseLimitService)
+	handlers.NewCouponHandler(e, cfg, d.couponService)
+	handlers.NewWishlistHandler(e, cfg, d.wishlistService)
+	handlers.NewSearchHandler(e, cfg, d.searchService)
+	handlers.NewProductImportHandler(e, cfg, d.productImportService)
+	handlers.NewTrashHandler(e, cfg, d.trashService)
+	handlers.NewCategoryAttributeHandler(e, cfg, d.categoryAttributeService)
+	handlers.NewRecommendationHandler(e, cfg, d.recommendationService)
+	handlers.NewGraphQLHandler(e, cfg, graphQLSchema, d.cartService)
+
+	if err := d.searchService.EnsureIndex(context.Background()); err != nil {
+		log.Printf("[RunServer-4] %v", err)
+	}
+
+	// jobs that were running when the service stopped will never finish
+	if err := d.productImportService.FailUnfinished(context.Background()); err != nil {
+		log.Printf("[RunServer-5] %v", err)
+	}
 
 	go func() {
 		if cfg.App.AppPort == "" {
@@ -85,4 +91,82 @@ func RunServer() {
 	defer cancel()
 
 	e.Shutdown(ctx)
-}
\ No newline at end of file
+}
+
+// cartIdleTTL defaults to 30 days so abandoned carts eventually expire; the
+// config is updated too, keeping the guest cart cookie in step with Redis.
+func cartIdleTTL(cfg *config.Config) time.Duration {
+	if cfg.App.CartIdleTTL <= 0 {
+		cfg.App.CartIdleTTL = int((30 * 24 * time.Hour).Seconds())
+	}
+	return time.Duration(cfg.App.CartIdleTTL) * time.Second
+}
+
+// cartSecret signs guest cart IDs, falling back to the JWT secret.
+func cartSecret(cfg *config.Config) string {
+	if cfg.App.CartSecret != "" {
+		return cfg.App.CartSecret
+	}
+	return cfg.App.JwtSecretKey
+}
+
+// trashRetention is how long deleted products and categories stay in the
+// trash before the purge worker removes them, 30 days by default.
+func trashRetention(cfg *config.Config) time.Duration {
+	if cfg.App.TrashRetentionDays <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return time.Duration(cfg.App.TrashRetentionDays) * 24 * time.Hour
+}
+
+// catalogCacheTTL bounds how long a cached public catalog response lives,
+// 10 minutes by default. Changes invalidate the cache earlier.
+func catalogCacheTTL(cfg *config.Config) time.Duration {
+	if cfg.App.CatalogCacheTTL <= 0 {
+		return 10 * time.Minute
+	}
+	return time.Duration(cfg.App.CatalogCacheTTL) * time.Second
+}
+
+// recommendationWindow is how far back orders count for the bought
+// together recommendations and best sellers, 90 days by default.
+func recommendationWindow(cfg *config.Config) time.Duration {
+	if cfg.App.RecommendationWindowDays <= 0 {
+		return 90 * 24 * time.Hour
+	}
+	return time.Duration(cfg.App.RecommendationWindowDays) * 24 * time.Hour
+}
+
+// recommendationTopN is how many related products are kept per product.
+func recommendationTopN(cfg *config.Config) int {
+	if cfg.App.RecommendationTopN <= 0 {
+		return 10
+	}
+	return cfg.App.RecommendationTopN
+}
+
+// purchaseLimitMaxWindow is the longest window a purchase limit may have,
+// and so how long purchase reservations are kept, 90 days by default.
+func purchaseLimitMaxWindow(cfg *config.Config) time.Duration {
+	if cfg.App.PurchaseLimitMaxWindowHours <= 0 {
+		return 90 * 24 * time.Hour
+	}
+	return time.Duration(cfg.App.PurchaseLimitMaxWindowHours) * time.Hour
+}
+
+// graphQLMaxDepth is how deeply GraphQL queries may nest, 8 by default.
+func graphQLMaxDepth(cfg *config.Config) int {
+	if cfg.App.GraphQLMaxDepth <= 0 {
+		return 8
+	}
+	return cfg.App.GraphQLMaxDepth
+}
+
+// graphQLMaxComplexity bounds the estimated fields a GraphQL query resolves,
+// 1000 by default.
+func graphQLMaxComplexity(cfg *config.Config) int {
+	if cfg.App.GraphQLMaxComplexity <= 0 {
+		return 1000
+	}
+	return cfg.App.GraphQLMaxComplexity
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"product-service/config"
+	"product-service/internal/core/domain/entities"
+)
+
+// RunCartMergeConsumer merges guest carts into customer carts for every
+// sign-in event published by user-service.
+func RunCartMergeConsumer() {
+	cfg := config.NewConfig()
+	d, err := newDependencies(cfg)
+	if err != nil {
+		log.Fatalf("[RunCartMergeConsumer-1] %v", err)
+		return
+	}
+
+	conn, err := cfg.NewRabbitMQ()
+	if err != nil {
+		log.Fatalf("[RunCartMergeConsumer-2] %v", err)
+		return
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		log.Fatalf("[RunCartMergeConsumer-3] %v", err)
+		return
+	}
+	defer ch.Close()
+
+	queueName := cfg.PublisherName.CartMerge
+	if queueName == "" {
+		queueName = "cart_merge"
+	}
+
+	q, err := ch.QueueDeclare(queueName, true, false, false, false, nil)
+	if err != nil {
+		log.Fatalf("[RunCartMergeConsumer-4] %v", err)
+		return
+	}
+
+	msgs, err := ch.Consume(q.Name, "", true, false, false, false, nil)
+	if err != nil {
+		log.Fatalf("[RunCartMergeConsumer-5] %v", err)
+		return
+	}
+
+	log.Print("[RunCartMergeConsumer-6] Waiting for sign-in events...")
+	for msg := range msgs {
+		var req entities.CartMergeEntity
+		if err := json.Unmarshal(msg.Body, &req); err != nil {
+			log.Printf("[RunCartMergeConsumer-7] %v", err)
+			continue
+		}
+
+		if err := d.cartService.MergeGuestCart(context.Background(), req.CartID, req.UserID); err != nil {
+			log.Printf("[RunCartMergeConsumer-8] user %d: %v", req.UserID, err)
+		}
+	}
+}
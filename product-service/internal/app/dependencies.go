@@ -0,0 +1,101 @@
+package app
+
+import (
+	"product-service/config"
+	"product-service/internal/adapter/message"
+	"product-service/internal/adapter/repository"
+	"product-service/internal/adapter/storage"
+	"product-service/internal/core/service"
+)
+
+// dependencies holds the connections and services of product-service, wired
+// once for the server and its workers so they all construct a service the
+// same way.
+type dependencies struct {
+	db *config.Postgres
+
+	storage           storage.ISupabase
+	publisherRabbitMQ message.IPublishRabbitMQ
+
+	catalogCacheService      service.ICatalogCacheService
+	categoryService          service.ICategoryService
+	purchaseLimitService     service.IPurchaseLimitService
+	cartService              service.ICartService
+	wishlistService          service.IWishlistService
+	productService           service.IProductService
+	productRatingService     service.IProductRatingService
+	reindexService           service.IReindexService
+	searchService            service.ISearchService
+	productImageService      service.IProductImageService
+	priceService             service.IPriceService
+	promotionService         service.IPromotionService
+	couponService            service.ICouponService
+	productImportService     service.IProductImportService
+	trashService             service.ITrashService
+	categoryAttributeService service.ICategoryAttributeService
+	recommendationService    service.IRecommendationService
+}
+
+// newDependencies connects to Postgres and Elasticsearch and builds every
+// repository and service on top of them. Redis and RabbitMQ are only
+// connected to when first used.
+func newDependencies(cfg *config.Config) (*dependencies, error) {
+	db, err := cfg.ConnectionPostgres()
+	if err != nil {
+		return nil, err
+	}
+
+	elasticInit, err := cfg.InitElastic()
+	if err != nil {
+		return nil, err
+	}
+
+	d := &dependencies{
+		db:                db,
+		storage:           storage.NewSupabase(cfg),
+		publisherRabbitMQ: message.NewPublishRabbitMQ(cfg),
+	}
+
+	redisClient := cfg.NewRedisClient()
+
+	categoryRepo := repository.NewCategoryRepository(db.DB)
+	productRepo := repository.NewProductRepository(db.DB, elasticInit)
+	productImageRepo := repository.NewProductImageRepository(db.DB)
+	searchRepo := repository.NewSearchRepository(elasticInit)
+	cartRepo := repository.NewCartRepository(redisClient, cartIdleTTL(cfg))
+	priceRepo := repository.NewPriceRepository(db.DB)
+	promotionRepo := repository.NewPromotionRepository(db.DB)
+	couponRepo := repository.NewCouponRepository(db.DB)
+	wishlistRepo := repository.NewWishlistRepository(db.DB)
+	suggestCacheRepo := repository.NewSuggestCacheRepository(redisClient)
+	searchSynonymRepo := repository.NewSearchSynonymRepository(db.DB)
+	productImportJobRepo := repository.NewProductImportJobRepository(db.DB)
+	trashRepo := repository.NewTrashRepository(db.DB)
+	categoryAttributeRepo := repository.NewCategoryAttributeRepository(db.DB)
+	recommendationRepo := repository.NewRecommendationRepository(db.DB)
+	recentlyViewedRepo := repository.NewRecentlyViewedRepository(redisClient)
+	catalogCacheRepo := repository.NewCatalogCacheRepository(redisClient)
+	purchaseLimitRepo := repository.NewPurchaseLimitRepository(db.DB)
+	productRatingRepo := repository.NewProductRatingRepository(db.DB)
+	reindexLockRepo := repository.NewReindexLockRepository(redisClient)
+
+	d.catalogCacheService = service.NewCatalogCacheService(catalogCacheRepo, catalogCacheTTL(cfg))
+	d.categoryService = service.NewCategoryService(categoryRepo, productRepo, d.publisherRabbitMQ, d.catalogCacheService)
+	d.purchaseLimitService = service.NewPurchaseLimitService(purchaseLimitRepo, productRepo, purchaseLimitMaxWindow(cfg))
+	d.cartService = service.NewCartService(cartRepo, productRepo, d.purchaseLimitService, cartSecret(cfg))
+	d.wishlistService = service.NewWishlistService(wishlistRepo, productRepo, d.cartService, d.publisherRabbitMQ)
+	d.productService = service.NewProductService(productRepo, categoryRepo, categoryAttributeRepo, d.publisherRabbitMQ, d.wishlistService, d.catalogCacheService)
+	d.productRatingService = service.NewProductRatingService(productRatingRepo, productRepo, d.publisherRabbitMQ, d.catalogCacheService)
+	d.reindexService = service.NewReindexService(searchRepo, productRepo, searchSynonymRepo, reindexLockRepo)
+	d.searchService = service.NewSearchService(searchRepo, suggestCacheRepo, searchSynonymRepo, d.reindexService)
+	d.productImageService = service.NewProductImageService(productImageRepo, productRepo, d.publisherRabbitMQ)
+	d.priceService = service.NewPriceService(priceRepo, productRepo, d.publisherRabbitMQ, d.wishlistService)
+	d.promotionService = service.NewPromotionService(promotionRepo, productRepo, categoryRepo)
+	d.couponService = service.NewCouponService(couponRepo, cartRepo, d.promotionService)
+	d.productImportService = service.NewProductImportService(productImportJobRepo, productRepo, categoryRepo, productImageRepo, searchRepo)
+	d.trashService = service.NewTrashService(trashRepo, productRepo, categoryRepo, d.publisherRabbitMQ, trashRetention(cfg))
+	d.categoryAttributeService = service.NewCategoryAttributeService(categoryAttributeRepo, categoryRepo, productRepo, d.publisherRabbitMQ)
+	d.recommendationService = service.NewRecommendationService(recommendationRepo, productRepo, recentlyViewedRepo, promotionRepo, recommendationWindow(cfg), recommendationTopN(cfg))
+
+	return d, nil
+}
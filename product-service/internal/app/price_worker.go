@@ -0,0 +1,52 @@
+package app
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"product-service/config"
+	"syscall"
+	"time"
+)
+
+// RunPriceScheduler applies scheduled price lists on a fixed interval until the
+// process receives SIGINT or SIGTERM.
+func RunPriceScheduler() {
+	cfg := config.NewConfig()
+	d, err := newDependencies(cfg)
+	if err != nil {
+		log.Fatalf("[RunPriceScheduler-1] %v", err)
+		return
+	}
+
+	interval := time.Duration(cfg.App.PriceSchedulerInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt)
+	signal.Notify(quit, syscall.SIGTERM)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		changed, err := d.priceService.ApplyScheduledPrices(context.Background())
+		if err != nil {
+			log.Printf("[RunPriceScheduler-2] %v", err)
+		}
+		if changed > 0 {
+			log.Printf("[RunPriceScheduler-3] %d product price(s) updated", changed)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-quit:
+			log.Print("[RunPriceScheduler-4] Shutting down price scheduler...")
+			d.db.Close()
+			return
+		}
+	}
+}
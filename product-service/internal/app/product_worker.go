@@ -0,0 +1,52 @@
+package app
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"product-service/config"
+	"syscall"
+	"time"
+)
+
+// RunProductScheduler publishes scheduled products and archives expired ones
+// on a fixed interval until the process receives SIGINT or SIGTERM.
+func RunProductScheduler() {
+	cfg := config.NewConfig()
+	d, err := newDependencies(cfg)
+	if err != nil {
+		log.Fatalf("[RunProductScheduler-1] %v", err)
+		return
+	}
+
+	interval := time.Duration(cfg.App.ProductSchedulerInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt)
+	signal.Notify(quit, syscall.SIGTERM)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		changed, err := d.productService.ApplySchedule(context.Background())
+		if err != nil {
+			log.Printf("[RunProductScheduler-2] %v", err)
+		}
+		if changed > 0 {
+			log.Printf("[RunProductScheduler-3] %d product(s) published or archived", changed)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-quit:
+			log.Print("[RunProductScheduler-4] Shutting down product scheduler...")
+			d.db.Close()
+			return
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"os/signal"
+	"product-service/config"
+	"product-service/internal/adapter/repository"
+	"product-service/internal/core/domain/entities"
+	"product-service/internal/core/service"
+	"syscall"
+	"time"
+)
+
+// RunOrderPurchaseConsumer stores the items of every order published by
+// order-service, the input of the bought together recommendations. It reads
+// from its own queue bound to the order exchange, so the order indexing in
+// order-service still gets every order.
+func RunOrderPurchaseConsumer() {
+	cfg := config.NewConfig()
+	db, err := cfg.ConnectionPostgres()
+	if err != nil {
+		log.Fatalf("[RunOrderPurchaseConsumer-1] %v", err)
+		return
+	}
+
+	productRepo := repository.NewProductRepository(db.DB, nil)
+	recommendationRepo := repository.NewRecommendationRepository(db.DB)
+	recentlyViewedRepo := repository.NewRecentlyViewedRepository(cfg.NewRedisClient())
+	promotionRepo := repository.NewPromotionRepository(db.DB)
+	recommendationService := service.NewRecommendationService(recommendationRepo, productRepo, recentlyViewedRepo, promotionRepo, recommendationWindow(cfg), recommendationTopN(cfg))
+
+	conn, err := cfg.NewRabbitMQ()
+	if err != nil {
+		log.Fatalf("[RunOrderPurchaseConsumer-2] %v", err)
+		return
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		log.Fatalf("[RunOrderPurchaseConsumer-3] %v", err)
+		return
+	}
+	defer ch.Close()
+
+	exchangeName := cfg.PublisherName.OrderPublish
+	if exchangeName == "" {
+		exchangeName = "order_publish"
+	}
+
+	if err := ch.ExchangeDeclare(exchangeName, "fanout", true, false, false, false, nil); err != nil {
+		log.Fatalf("[RunOrderPurchaseConsumer-4] %v", err)
+		return
+	}
+
+	q, err := ch.QueueDeclare(exchangeName+".product_recommendation", true, false, false, false, nil)
+	if err != nil {
+		log.Fatalf("[RunOrderPurchaseConsumer-5] %v", err)
+		return
+	}
+
+	if err := ch.QueueBind(q.Name, "", exchangeName, false, nil); err != nil {
+		log.Fatalf("[RunOrderPurchaseConsumer-6] %v", err)
+		return
+	}
+
+	msgs, err := ch.Consume(q.Name, "", true, false, false, false, nil)
+	if err != nil {
+		log.Fatalf("[RunOrderPurchaseConsumer-7] %v", err)
+		return
+	}
+
+	log.Print("[RunOrderPurchaseConsumer-8] Waiting for orders...")
+	for msg := range msgs {
+		var order entities.OrderEventEntity
+		if err := json.Unmarshal(msg.Body, &order); err != nil {
+			log.Printf("[RunOrderPurchaseConsumer-9] %v", err)
+			continue
+		}
+
+		if err := recommendationService.RecordOrder(context.Background(), order); err != nil {
+			log.Printf("[RunOrderPurchaseConsumer-10] order %d: %v", order.ID, err)
+		}
+	}
+}
+
+// RunRecommendationScheduler rebuilds the related products from the stored
+// orders on a fixed interval until the process receives SIGINT or SIGTERM.
+func RunRecommendationScheduler() {
+	cfg := config.NewConfig()
+	db, err := cfg.ConnectionPostgres()
+	if err != nil {
+		log.Fatalf("[RunRecommendationScheduler-1] %v", err)
+		return
+	}
+
+	productRepo := repository.NewProductRepository(db.DB, nil)
+	recommendationRepo := repository.NewRecommendationRepository(db.DB)
+	recentlyViewedRepo := repository.NewRecentlyViewedRepository(cfg.NewRedisClient())
+	promotionRepo := repository.NewPromotionRepository(db.DB)
+	recommendationService := service.NewRecommendationService(recommendationRepo, productRepo, recentlyViewedRepo, promotionRepo, recommendationWindow(cfg), recommendationTopN(cfg))
+
+	interval := time.Duration(cfg.App.RecommendationInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt)
+	signal.Notify(quit, syscall.SIGTERM)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		count, err := recommendationService.Compute(context.Background())
+		if err != nil {
+			log.Printf("[RunRecommendationScheduler-2] %v", err)
+		} else {
+			log.Printf("[RunRecommendationScheduler-3] %d related product(s) stored", count)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-quit:
+			log.Print("[RunRecommendationScheduler-4] Shutting down recommendation scheduler...")
+			db.Close()
+			return
+		}
+	}
+}
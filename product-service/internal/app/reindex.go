@@ -0,0 +1,70 @@
+package app
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"product-service/config"
+	"product-service/internal/core/domain/entities"
+	"syscall"
+)
+
+// verifyReportLimit caps how many product ids of each kind are printed.
+const verifyReportLimit = 20
+
+// RunReindex rebuilds the product index from Postgres, or with verify only
+// compares the current index with Postgres. It exits non-zero when the
+// reindex fails or the index is out of sync.
+func RunReindex(verify bool) {
+	cfg := config.NewConfig()
+	d, err := newDependencies(cfg)
+	if err != nil {
+		log.Fatalf("[RunReindex-1] %v", err)
+		return
+	}
+
+	// an interrupted reindex still removes the half loaded index
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if verify {
+		result, err := d.reindexService.Verify(ctx, "")
+		if err != nil {
+			log.Fatalf("[RunReindex-2] %v", err)
+			return
+		}
+
+		printVerify(result)
+		if !result.InSync() {
+			log.Fatalf("[RunReindex-3] index %s is out of sync with the database", result.Index)
+		}
+		return
+	}
+
+	result, err := d.reindexService.Reindex(ctx)
+	if err != nil {
+		log.Fatalf("[RunReindex-4] %v", err)
+		return
+	}
+
+	log.Printf("[RunReindex-5] %d products indexed into %s, %d repaired, old versions deleted: %v",
+		result.Indexed, result.Index, result.Repaired, result.Deleted)
+}
+
+func printVerify(result *entities.IndexVerifyEntity) {
+	log.Printf("index:      %s", result.Index)
+	log.Printf("documents:  database %d, index %d", result.DBCount, result.IndexCount)
+	log.Printf("checksum:   database %s", result.DBChecksum)
+	log.Printf("            index    %s", result.IndexChecksum)
+	log.Printf("missing:    %d %v", len(result.Missing), firstIDs(result.Missing))
+	log.Printf("extra:      %d %v", len(result.Extra), firstIDs(result.Extra))
+	log.Printf("mismatched: %d %v", len(result.Mismatched), firstIDs(result.Mismatched))
+}
+
+func firstIDs(ids []int64) []int64 {
+	if len(ids) > verifyReportLimit {
+		return ids[:verifyReportLimit]
+	}
+	return ids
+}
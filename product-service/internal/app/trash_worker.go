@@ -0,0 +1,53 @@
+package app
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"product-service/config"
+	"syscall"
+	"time"
+)
+
+// RunTrashPurge permanently deletes products and categories that stayed in
+// the trash longer than the retention period, on a fixed interval until the
+// process receives SIGINT or SIGTERM.
+func RunTrashPurge() {
+	cfg := config.NewConfig()
+	d, err := newDependencies(cfg)
+	if err != nil {
+		log.Fatalf("[RunTrashPurge-1] %v", err)
+		return
+	}
+
+	interval := time.Duration(cfg.App.TrashPurgeInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt)
+	signal.Notify(quit, syscall.SIGTERM)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := d.trashService.Purge(context.Background())
+		if err != nil {
+			log.Printf("[RunTrashPurge-2] %v", err)
+		}
+		if result != nil && result.Products+result.Categories > 0 {
+			log.Printf("[RunTrashPurge-3] %d product(s) and %d categorie(s) purged", result.Products, result.Categories)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-quit:
+			log.Print("[RunTrashPurge-4] Shutting down trash purge...")
+			d.db.Close()
+			return
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package entities
+
+// Product types. A bundle is sold as one product but made of components.
+const (
+	ProductTypeSimple = "simple"
+	ProductTypeBundle = "bundle"
+)
+
+// Bundle pricing. A fixed bundle keeps the price set on it; a computed one
+// costs the sum of its components minus DiscountPercent.
+const (
+	BundlePricingFixed    = "fixed"
+	BundlePricingComputed = "computed"
+)
+
+type BundleEntity struct {
+	Pricing         string             `json:"pricing"`
+	DiscountPercent float64            `json:"discount_percent"`
+	Items           []BundleItemEntity `json:"items"`
+}
+
+// BundleItemEntity is one component of a bundle. Available is how many
+// bundles the component's stock is enough for.
+type BundleItemEntity struct {
+	ComponentID  int64   `json:"component_id"`
+	Quantity     int     `json:"quantity"`
+	Name         string  `json:"name"`
+	Image        string  `json:"image"`
+	Weight       int     `json:"weight"`
+	Unit         string  `json:"unit"`
+	RegulerPrice float64 `json:"reguler_price"`
+	SalePrice    float64 `json:"sale_price"`
+	Stock        int     `json:"stock"`
+	Available    int     `json:"available"`
+}
@@ -1,6 +1,69 @@
 package entities
 
+const (
+	CartWarningPriceChanged      string = "price_changed"
+	CartWarningInsufficientStock string = "insufficient_stock"
+	CartWarningUnavailable       string = "unavailable"
+	CartWarningInvalidQuantity   string = "invalid_quantity"
+	CartWarningPurchaseLimit     string = "purchase_limit"
+)
+
+// CartOwner identifies a cart: a signed-in customer by UserID, or an
+// anonymous visitor by GuestID when UserID is zero.
+type CartOwner struct {
+	UserID  int64
+	GuestID string
+}
+
+// CartItem is stored in the quantity unit of the product. Unit is only set
+// on requests that give the quantity in another unit, like 2 kg of a product
+// counted in gram.
 type CartItem struct {
-	ProductID int64 `json:"product_id"`
-	Quantity  int64 `json:"quantity"`
+	ProductID int64   `json:"product_id"`
+	Quantity  int64   `json:"quantity"`
+	Price     float64 `json:"price,omitempty"`
+	Unit      string  `json:"-"`
+}
+
+// CartEntity is the priced cart. HasEstimated tells the subtotal is an
+// estimate because some items are weighed at packing.
+type CartEntity struct {
+	Items         []CartLineEntity `json:"items"`
+	TotalQuantity int64            `json:"total_quantity"`
+	TotalWeight   int64            `json:"total_weight"`
+	Subtotal      float64          `json:"subtotal"`
+	HasWarnings   bool             `json:"has_warnings"`
+	HasEstimated  bool             `json:"has_estimated"`
+}
+
+type CartLineEntity struct {
+	ProductID     int64               `json:"product_id"`
+	ProductName   string              `json:"product_name"`
+	ProductImage  string              `json:"product_image"`
+	ProductStatus string              `json:"product_status"`
+	Unit          string              `json:"unit"`
+	Weight        int64               `json:"weight"`
+	Stock         int64               `json:"stock"`
+	SellBy        string              `json:"sell_by"`
+	PriceUnit     string              `json:"price_unit"`
+	QuantityUnit  string              `json:"quantity_unit"`
+	Quantity      int64               `json:"quantity"`
+	UnitPrice     float64             `json:"unit_price"`
+	PreviousPrice float64             `json:"previous_price"`
+	LineTotal     float64             `json:"line_total"`
+	LineWeight    int64               `json:"line_weight"`
+	Available     bool                `json:"available"`
+	Warnings      []CartWarningEntity `json:"warnings"`
+}
+
+type CartWarningEntity struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// CartMergeEntity is published by user-service on sign-in when the visitor
+// still holds a guest cart.
+type CartMergeEntity struct {
+	UserID int64  `json:"user_id"`
+	CartID string `json:"cart_id"`
 }
@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+// Parts of the public catalog that are cached and invalidated together.
+// Product lists show category names, so a category change invalidates both.
+const (
+	CatalogScopeProducts   = "products"
+	CatalogScopeCategories = "categories"
+)
+
+// CatalogCacheEntity is a cached response of a public catalog endpoint.
+type CatalogCacheEntity struct {
+	Body         []byte    `json:"body"`
+	ContentType  string    `json:"content_type"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"last_modified"`
+}
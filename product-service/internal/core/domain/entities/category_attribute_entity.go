@@ -0,0 +1,36 @@
+package entities
+
+// Attribute types. Enum values must be one of the attribute's options.
+const (
+	AttributeTypeEnum    = "enum"
+	AttributeTypeNumber  = "number"
+	AttributeTypeBoolean = "boolean"
+)
+
+// CategoryAttributeEntity defines an attribute for the products of a
+// category and its subcategories.
+type CategoryAttributeEntity struct {
+	ID           int64    `json:"id"`
+	CategoryID   int64    `json:"category_id"`
+	Code         string   `json:"code"`
+	Name         string   `json:"name"`
+	Type         string   `json:"type"`
+	Options      []string `json:"options"`
+	Unit         string   `json:"unit"`
+	IsRequired   bool     `json:"is_required"`
+	IsFilterable bool     `json:"is_filterable"`
+	Position     int      `json:"position"`
+	// Inherited marks an attribute defined on a parent of the category it was
+	// looked up for.
+	Inherited bool `json:"inherited"`
+}
+
+// ProductAttributeEntity is the value a product has for one attribute,
+// together with the definition needed to display it.
+type ProductAttributeEntity struct {
+	Code  string      `json:"code"`
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+	Unit  string      `json:"unit"`
+}
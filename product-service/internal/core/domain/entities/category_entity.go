@@ -16,6 +16,28 @@ type CategoryEntity struct {
 	Products    []ProductEntity `json:"products"`
 }
 
+// CategoryTreeEntity is a category with its subcategories nested below it.
+// ProductCount counts the category's own products, TotalProduct adds those of
+// every descendant.
+type CategoryTreeEntity struct {
+	ID           int64                `json:"id"`
+	ParentID     *int64               `json:"parent_id"`
+	Name         string               `json:"name"`
+	Icon         string               `json:"icon"`
+	Slug         string               `json:"slug"`
+	ProductCount int64                `json:"product_count"`
+	TotalProduct int64                `json:"total_product"`
+	Children     []CategoryTreeEntity `json:"children"`
+}
+
+// BreadcrumbEntity is one step of the path from a root category down to a
+// product's category.
+type BreadcrumbEntity struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
 type QueryStringEntity struct {
 	Search    string
 	Page      int
@@ -0,0 +1,95 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	CouponDiscountPercentage string = "percentage"
+	CouponDiscountFixed      string = "fixed"
+)
+
+type CouponEntity struct {
+	ID               int64      `json:"id"`
+	Code             string     `json:"code"`
+	Name             string     `json:"name"`
+	DiscountType     string     `json:"discount_type"`
+	Value            float64    `json:"value"`
+	MaxDiscount      float64    `json:"max_discount"`
+	MinSpend         float64    `json:"min_spend"`
+	UsageLimit       int64      `json:"usage_limit"`
+	PerCustomerLimit int64      `json:"per_customer_limit"`
+	UsedCount        int64      `json:"used_count"`
+	Status           string     `json:"status"`
+	StartAt          *time.Time `json:"start_at"`
+	EndAt            *time.Time `json:"end_at"`
+}
+
+// Check reports why the coupon cannot be used for a subtotal by a customer who
+// already redeemed it userRedemptions times. A zero limit means unlimited.
+func (c CouponEntity) Check(now time.Time, subtotal float64, userRedemptions int64) error {
+	if c.Status == UnpublishedStatus {
+		return errors.New("coupon is not active")
+	}
+	if c.StartAt != nil && now.Before(*c.StartAt) {
+		return errors.New("coupon is not valid yet")
+	}
+	if c.EndAt != nil && !now.Before(*c.EndAt) {
+		return errors.New("coupon has expired")
+	}
+	if c.UsageLimit > 0 && c.UsedCount >= c.UsageLimit {
+		return errors.New("coupon usage limit reached")
+	}
+	if c.PerCustomerLimit > 0 && userRedemptions >= c.PerCustomerLimit {
+		return errors.New("coupon usage limit per customer reached")
+	}
+	if subtotal < c.MinSpend {
+		return fmt.Errorf("minimum spend for this coupon is %.0f", c.MinSpend)
+	}
+
+	return nil
+}
+
+// Discount returns the amount taken off subtotal, never more than the subtotal
+// itself.
+func (c CouponEntity) Discount(subtotal float64) float64 {
+	discount := 0.0
+	switch c.DiscountType {
+	case CouponDiscountPercentage:
+		discount = math.Round(subtotal * c.Value / 100)
+		if c.MaxDiscount > 0 {
+			discount = math.Min(discount, c.MaxDiscount)
+		}
+	case CouponDiscountFixed:
+		discount = c.Value
+	}
+
+	return math.Min(discount, subtotal)
+}
+
+type CouponBulkEntity struct {
+	Prefix   string
+	Quantity int
+	Length   int
+	Coupon   CouponEntity
+}
+
+type CouponRedemptionEntity struct {
+	ID        int64     `json:"id"`
+	CouponID  int64     `json:"coupon_id"`
+	Code      string    `json:"code"`
+	UserID    int64     `json:"user_id"`
+	OrderCode string    `json:"order_code"`
+	Discount  float64   `json:"discount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CartCouponEntity struct {
+	Code     string  `json:"code"`
+	Subtotal float64 `json:"subtotal"`
+	Discount float64 `json:"discount"`
+	Total    float64 `json:"total"`
+}
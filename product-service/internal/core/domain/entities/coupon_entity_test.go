@@ -0,0 +1,127 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCouponCheck(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	before := now.Add(-time.Hour)
+	after := now.Add(time.Hour)
+
+	tests := []struct {
+		name            string
+		coupon          CouponEntity
+		subtotal        float64
+		userRedemptions int64
+		wantErr         string
+	}{
+		{
+			name:            "usable",
+			coupon:          CouponEntity{Status: PublishedStatus, StartAt: &before, EndAt: &after, UsageLimit: 10, UsedCount: 9, PerCustomerLimit: 2},
+			subtotal:        50000,
+			userRedemptions: 1,
+		},
+		{
+			name:    "inactive",
+			coupon:  CouponEntity{Status: UnpublishedStatus},
+			wantErr: "coupon is not active",
+		},
+		{
+			name:    "not started",
+			coupon:  CouponEntity{Status: PublishedStatus, StartAt: &after},
+			wantErr: "coupon is not valid yet",
+		},
+		{
+			name:    "ends now",
+			coupon:  CouponEntity{Status: PublishedStatus, EndAt: &now},
+			wantErr: "coupon has expired",
+		},
+		{
+			name:    "used up",
+			coupon:  CouponEntity{Status: PublishedStatus, UsageLimit: 10, UsedCount: 10},
+			wantErr: "coupon usage limit reached",
+		},
+		{
+			name:            "used up by the customer",
+			coupon:          CouponEntity{Status: PublishedStatus, PerCustomerLimit: 1},
+			userRedemptions: 1,
+			wantErr:         "coupon usage limit per customer reached",
+		},
+		{
+			name:            "no limits",
+			coupon:          CouponEntity{Status: PublishedStatus, UsedCount: 1000},
+			userRedemptions: 1000,
+		},
+		{
+			name:     "below the minimum spend",
+			coupon:   CouponEntity{Status: PublishedStatus, MinSpend: 100000},
+			subtotal: 99999,
+			wantErr:  "minimum spend for this coupon is 100000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.coupon.Check(now, tt.subtotal, tt.userRedemptions)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Check() = %v, want no error", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Check() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCouponDiscount(t *testing.T) {
+	tests := []struct {
+		name     string
+		coupon   CouponEntity
+		subtotal float64
+		want     float64
+	}{
+		{
+			name:     "percentage",
+			coupon:   CouponEntity{DiscountType: CouponDiscountPercentage, Value: 15},
+			subtotal: 33333,
+			want:     5000,
+		},
+		{
+			name:     "percentage capped at the maximum discount",
+			coupon:   CouponEntity{DiscountType: CouponDiscountPercentage, Value: 50, MaxDiscount: 20000},
+			subtotal: 100000,
+			want:     20000,
+		},
+		{
+			name:     "fixed",
+			coupon:   CouponEntity{DiscountType: CouponDiscountFixed, Value: 10000},
+			subtotal: 50000,
+			want:     10000,
+		},
+		{
+			name:     "fixed never above the subtotal",
+			coupon:   CouponEntity{DiscountType: CouponDiscountFixed, Value: 10000},
+			subtotal: 7500,
+			want:     7500,
+		},
+		{
+			name:     "unknown type",
+			coupon:   CouponEntity{DiscountType: "cashback", Value: 10000},
+			subtotal: 50000,
+			want:     0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.coupon.Discount(tt.subtotal); got != tt.want {
+				t.Errorf("Discount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package entities
+
+import "time"
+
+const (
+	PriceSourceCreate   string = "create"
+	PriceSourceManual   string = "manual"
+	PriceSourceSchedule string = "schedule"
+	PriceSourceBundle   string = "bundle"
+)
+
+type PriceListEntity struct {
+	ID        int64      `json:"id"`
+	ProductID int64      `json:"product_id"`
+	Name      string     `json:"name"`
+	SalePrice float64    `json:"sale_price"`
+	StartAt   time.Time  `json:"start_at"`
+	EndAt     *time.Time `json:"end_at"`
+}
+
+type PriceHistoryEntity struct {
+	ID           int64     `json:"id"`
+	ProductID    int64     `json:"product_id"`
+	PriceListID  *int64    `json:"price_list_id"`
+	RegulerPrice float64   `json:"reguler_price"`
+	SalePrice    float64   `json:"sale_price"`
+	Source       string    `json:"source"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -1,24 +1,115 @@
 package entities
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Product lifecycle states. Only published products are sold and indexed.
+const (
+	ProductStatusDraft     = "Draft"
+	ProductStatusInReview  = "In Review"
+	ProductStatusScheduled = "Scheduled"
+	ProductStatusPublished = PublishedStatus
+	ProductStatusArchived  = "Archived"
+)
+
+// ProductStatuses lists the lifecycle states in order.
+var ProductStatuses = []string{
+	ProductStatusDraft, ProductStatusInReview, ProductStatusScheduled, ProductStatusPublished, ProductStatusArchived,
+}
 
 type ProductEntity struct {
-	ID           int64           `json:"id"`
-	CategorySlug string          `json:"category_slug"`
-	ParentID     *int64          `json:"parent_id"`
-	Name         string          `json:"name"`
-	Image        string          `json:"image"`
-	Description  string          `json:"description"`
-	RegulerPrice float64         `json:"reguler_price"`
-	SalePrice    float64         `json:"sale_price"`
-	Unit         string          `json:"unit"`
-	Weight       int             `json:"weight"`
-	Stock        int             `json:"stock"`
-	Variant      int             `json:"variant"`
-	Status       string          `json:"status"`
-	CategoryName string          `json:"category_name"`
-	Child        []ProductEntity `json:"child"`
-	CreatedAt    time.Time       `json:"created_at"`
+	ID           int64                  `json:"id"`
+	CategorySlug string                 `json:"category_slug"`
+	ParentID     *int64                 `json:"parent_id"`
+	SKU          string                 `json:"sku"`
+	Name         string                 `json:"name"`
+	Image        string                 `json:"image"`
+	Description  string                 `json:"description"`
+	RegulerPrice float64                `json:"reguler_price"`
+	SalePrice    float64                `json:"sale_price"`
+	Unit         string                 `json:"unit"`
+	Weight       int                    `json:"weight"`
+	Stock        int                    `json:"stock"`
+	Variant      int                    `json:"variant"`
+	Type         string                 `json:"type"`
+	Selling      SellingEntity          `json:"selling"`
+	Rating       float64                `json:"rating"`
+	RatingCount  int64                  `json:"rating_count"`
+	Status       string                 `json:"status"`
+	PublishAt    *time.Time             `json:"publish_at"`
+	UnpublishAt  *time.Time             `json:"unpublish_at"`
+	ApprovedBy   *int64                 `json:"approved_by"`
+	ApprovedAt   *time.Time             `json:"approved_at"`
+	ReviewNote   string                 `json:"review_note"`
+	CategoryName string                 `json:"category_name"`
+	Attributes   map[string]interface{} `json:"attributes"`
+	Child        []ProductEntity        `json:"child"`
+	Images       []ProductImageEntity   `json:"images"`
+	CreatedAt    time.Time              `json:"created_at"`
+
+	// Breadcrumbs is only filled for product details; it is left out of the
+	// JSON so it never ends up in queue messages or the search index.
+	Breadcrumbs []BreadcrumbEntity `json:"-"`
+	// Specifications pairs the attribute values with their definitions, in
+	// schema order; like Breadcrumbs it is only filled for product details.
+	Specifications []ProductAttributeEntity `json:"-"`
+	// Bundle holds the composition of a bundle product; nil for others.
+	Bundle *BundleEntity `json:"-"`
+}
+
+// IsBundle reports whether the product is made of other products.
+func (p ProductEntity) IsBundle() bool {
+	return p.Type == ProductTypeBundle
+}
+
+// UnitPrice is the price a customer pays for one unit, falling back to the
+// regular price when no sale price is set.
+func (p ProductEntity) UnitPrice() float64 {
+	if p.SalePrice > 0 {
+		return p.SalePrice
+	}
+	return p.RegulerPrice
+}
+
+// IsPublished reports whether customers may buy the product.
+func (p ProductEntity) IsPublished() bool {
+	return strings.EqualFold(p.Status, PublishedStatus)
+}
+
+// IndexChecksum hashes the fields of the product that are searched or
+// filtered on, so a product in Postgres and its document in Elasticsearch
+// can be compared.
+func (p ProductEntity) IndexChecksum() string {
+	data, _ := json.Marshal(struct {
+		ID           int64
+		ParentID     *int64
+		CategorySlug string
+		CategoryName string
+		Name         string
+		Image        string
+		Description  string
+		RegulerPrice float64
+		SalePrice    float64
+		Unit         string
+		Weight       int
+		Stock        int
+		Variant      int
+		Type         string
+		Status       string
+		Attributes   map[string]interface{}
+		Rating       float64
+		RatingCount  int64
+	}{p.ID, p.ParentID, p.CategorySlug, p.CategoryName, p.Name, p.Image, p.Description,
+		p.RegulerPrice, p.SalePrice, p.Unit, p.Weight, p.Stock, p.Variant, p.Type, p.Status, p.Attributes,
+		p.Rating, p.RatingCount})
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
 }
 
 type QueryStringProduct struct {
@@ -31,9 +122,64 @@ type QueryStringProduct struct {
 	StartPrice   int64
 	EndPrice     int64
 	Status       string
+
+	// the fields below are only used by SearchProducts
+	CategorySlugs []string
+	Units         []string
+	InStock       bool
+	OnSale        bool
+	MinRating     float64
+	// Attributes filters on attribute values, keyed by attribute code; any of
+	// the values of one code matches.
+	Attributes map[string][]string
+	// AttributeFacets are the attribute codes to count values for.
+	AttributeFacets []string
+}
+
+// ProductSearchEntity is a page of search hits with the facets of the whole
+// result set.
+type ProductSearchEntity struct {
+	Products  []ProductEntity
+	Facets    ProductFacetsEntity
+	TotalData int64
+	TotalPage int64
+}
+
+// ProductFacetsEntity counts the products per facet value. Every facet is
+// computed with all active filters except its own, so choosing one category
+// still shows the counts of the other categories.
+type ProductFacetsEntity struct {
+	Categories []FacetBucketEntity    `json:"categories"`
+	Prices     []FacetRangeEntity     `json:"prices"`
+	Units      []FacetBucketEntity    `json:"units"`
+	Weights    []FacetRangeEntity     `json:"weights"`
+	Ratings    []FacetRangeEntity     `json:"ratings"`
+	OnSale     int64                  `json:"on_sale"`
+	InStock    int64                  `json:"in_stock"`
+	Attributes []AttributeFacetEntity `json:"attributes"`
+}
+
+// AttributeFacetEntity counts the products per value of one attribute.
+type AttributeFacetEntity struct {
+	Code   string              `json:"code"`
+	Label  string              `json:"label"`
+	Values []FacetBucketEntity `json:"values"`
+}
+
+type FacetBucketEntity struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	Count int64  `json:"count"`
+}
+
+type FacetRangeEntity struct {
+	Key   string   `json:"key"`
+	From  *float64 `json:"from"`
+	To    *float64 `json:"to"`
+	Count int64    `json:"count"`
 }
 
 type PublishOrderItemEntity struct {
 	ProductID int64 `json:"product_id"`
 	Quantity  int64 `json:"quantity"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,10 @@
+package entities
+
+type ProductImageEntity struct {
+	ID        int64  `json:"id"`
+	ProductID int64  `json:"product_id"`
+	ImageURL  string `json:"image_url"`
+	AltText   string `json:"alt_text"`
+	Position  int    `json:"position"`
+	IsPrimary bool   `json:"is_primary"`
+}
@@ -0,0 +1,48 @@
+package entities
+
+import "time"
+
+const (
+	ImportStatusPending    = "pending"
+	ImportStatusProcessing = "processing"
+	ImportStatusCompleted  = "completed"
+	ImportStatusFailed     = "failed"
+)
+
+const (
+	ImportFormatCSV  = "csv"
+	ImportFormatXLSX = "xlsx"
+)
+
+// ProductImportColumns is the column layout of import and export files, so
+// an exported file can be edited and imported again.
+var ProductImportColumns = []string{
+	"sku", "parent_sku", "name", "category_slug", "description", "unit",
+	"weight", "stock", "reguler_price", "sale_price", "status", "image", "images",
+}
+
+// ProductImportJobEntity tracks an import running in the background.
+type ProductImportJobEntity struct {
+	ID            int64                  `json:"id"`
+	UserID        int64                  `json:"user_id"`
+	FileName      string                 `json:"file_name"`
+	Status        string                 `json:"status"`
+	TotalRows     int                    `json:"total_rows"`
+	ProcessedRows int                    `json:"processed_rows"`
+	CreatedCount  int                    `json:"created_count"`
+	UpdatedCount  int                    `json:"updated_count"`
+	FailedCount   int                    `json:"failed_count"`
+	RowErrors     []ImportRowErrorEntity `json:"row_errors"`
+	ErrorMessage  string                 `json:"error_message"`
+	CreatedAt     time.Time              `json:"created_at"`
+	FinishedAt    *time.Time             `json:"finished_at"`
+}
+
+// ImportRowErrorEntity explains why a row was skipped. Row is the row number
+// in the spreadsheet, the header being row 1.
+type ImportRowErrorEntity struct {
+	Row     int    `json:"row"`
+	SKU     string `json:"sku"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
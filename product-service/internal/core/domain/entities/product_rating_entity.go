@@ -0,0 +1,9 @@
+package entities
+
+// ProductRatingEntity is the rating of a product: the average of the stars
+// its customers gave, from 1 to 5, and how many customers rated it.
+type ProductRatingEntity struct {
+	ProductID   int64   `json:"product_id"`
+	Rating      float64 `json:"rating"`
+	RatingCount int64   `json:"rating_count"`
+}
@@ -0,0 +1,100 @@
+package entities
+
+import "time"
+
+const (
+	PromotionTypePercentage      string = "percentage"
+	PromotionTypeFixed           string = "fixed"
+	PromotionTypeBuyXGetY        string = "buy_x_get_y"
+	PromotionTypeTiered          string = "tiered"
+	PromotionTypeOrderPercentage string = "order_percentage"
+	PromotionTypeOrderFixed      string = "order_fixed"
+	PromotionTypeFreeShipping    string = "free_shipping"
+)
+
+// Customer segments a promotion can be limited to. Customers are not
+// segmented any further: a promotion is for everyone, for signed-in
+// customers only or for guests only.
+const (
+	CustomerSegmentGuest    string = "guest"
+	CustomerSegmentCustomer string = "customer"
+)
+
+// PromotionEntity is a promotion rule. CustomerSegment, when set, limits it
+// to signed-in customers or to guests. CategorySlug covers the products of
+// its subcategories too.
+type PromotionEntity struct {
+	ID              int64                 `json:"id"`
+	Name            string                `json:"name"`
+	Description     string                `json:"description"`
+	Type            string                `json:"type"`
+	Value           float64               `json:"value"`
+	BuyQuantity     int64                 `json:"buy_quantity"`
+	GetQuantity     int64                 `json:"get_quantity"`
+	CategorySlug    string                `json:"category_slug"`
+	ProductID       *int64                `json:"product_id"`
+	MinQuantity     int64                 `json:"min_quantity"`
+	MinSubtotal     float64               `json:"min_subtotal"`
+	CustomerSegment string                `json:"customer_segment"`
+	Priority        int                   `json:"priority"`
+	Stackable       bool                  `json:"stackable"`
+	Status          string                `json:"status"`
+	StartAt         *time.Time            `json:"start_at"`
+	EndAt           *time.Time            `json:"end_at"`
+	Tiers           []PromotionTierEntity `json:"tiers"`
+}
+
+// IsOrderLevel reports whether the promotion discounts the whole order
+// instead of individual cart lines.
+func (p PromotionEntity) IsOrderLevel() bool {
+	switch p.Type {
+	case PromotionTypeOrderPercentage, PromotionTypeOrderFixed, PromotionTypeFreeShipping:
+		return true
+	}
+	return false
+}
+
+type PromotionTierEntity struct {
+	MinQuantity int64   `json:"min_quantity"`
+	Value       float64 `json:"value"`
+}
+
+type CartPriceRequestEntity struct {
+	CustomerSegment string
+	ShippingFee     float64
+	Items           []CartItem
+}
+
+type CartPriceEntity struct {
+	Items            []CartPriceLineEntity    `json:"items"`
+	Subtotal         float64                  `json:"subtotal"`
+	LineDiscount     float64                  `json:"line_discount"`
+	OrderDiscount    float64                  `json:"order_discount"`
+	OrderPromotions  []AppliedPromotionEntity `json:"order_promotions"`
+	ShippingFee      float64                  `json:"shipping_fee"`
+	ShippingDiscount float64                  `json:"shipping_discount"`
+	Total            float64                  `json:"total"`
+}
+
+// CartPriceLineEntity is a priced cart line. CategorySlugs are the category
+// of the product and all categories above it, which category promotions are
+// matched against.
+type CartPriceLineEntity struct {
+	ProductID     int64                    `json:"product_id"`
+	ProductName   string                   `json:"product_name"`
+	CategorySlug  string                   `json:"category_slug"`
+	CategorySlugs []string                 `json:"-"`
+	Quantity      int64                    `json:"quantity"`
+	UnitPrice     float64                  `json:"unit_price"`
+	Subtotal      float64                  `json:"subtotal"`
+	Discount      float64                  `json:"discount"`
+	Total         float64                  `json:"total"`
+	Promotions    []AppliedPromotionEntity `json:"promotions"`
+}
+
+type AppliedPromotionEntity struct {
+	PromotionID int64   `json:"promotion_id"`
+	Name        string  `json:"name"`
+	Type        string  `json:"type"`
+	Discount    float64 `json:"discount"`
+}
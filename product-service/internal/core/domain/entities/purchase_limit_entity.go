@@ -0,0 +1,67 @@
+package entities
+
+import "time"
+
+// PurchaseLimitEntity caps how much one customer buys of a product, variants
+// included, or of all products of a category within WindowHours. Quantities
+// are in the quantity unit of the products.
+type PurchaseLimitEntity struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	ProductID    *int64    `json:"product_id"`
+	CategorySlug string    `json:"category_slug"`
+	MaxQuantity  int64     `json:"max_quantity"`
+	WindowHours  int       `json:"window_hours"`
+	IsActive     bool      `json:"is_active"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AppliesTo reports whether the limit counts purchases of product.
+func (l PurchaseLimitEntity) AppliesTo(product ProductEntity) bool {
+	if l.ProductID != nil {
+		return *l.ProductID == product.ID || (product.ParentID != nil && *l.ProductID == *product.ParentID)
+	}
+	return l.CategorySlug != "" && l.CategorySlug == product.CategorySlug
+}
+
+// Window is the period purchases are counted over.
+func (l PurchaseLimitEntity) Window() time.Duration {
+	return time.Duration(l.WindowHours) * time.Hour
+}
+
+// PurchaseLimitViolationEntity tells the customer how much of a limit is
+// left: Remaining more can be bought before the window moves on.
+// AffectedProductIDs are the requested products the limit covers.
+type PurchaseLimitViolationEntity struct {
+	LimitID            int64   `json:"limit_id"`
+	Name               string  `json:"name"`
+	ProductID          *int64  `json:"product_id"`
+	CategorySlug       string  `json:"category_slug"`
+	MaxQuantity        int64   `json:"max_quantity"`
+	WindowHours        int     `json:"window_hours"`
+	Purchased          int64   `json:"purchased"`
+	Requested          int64   `json:"requested"`
+	Remaining          int64   `json:"remaining"`
+	AffectedProductIDs []int64 `json:"affected_product_ids"`
+}
+
+// Limit is the limit the violation is about.
+func (v PurchaseLimitViolationEntity) Limit() PurchaseLimitEntity {
+	return PurchaseLimitEntity{
+		ID:           v.LimitID,
+		Name:         v.Name,
+		ProductID:    v.ProductID,
+		CategorySlug: v.CategorySlug,
+		MaxQuantity:  v.MaxQuantity,
+		WindowHours:  v.WindowHours,
+		IsActive:     true,
+	}
+}
+
+// Exceeds records what the customer already bought within the window and
+// reports whether the requested quantity goes over the limit.
+func (v *PurchaseLimitViolationEntity) Exceeds(purchased int64) bool {
+	v.Purchased = purchased
+	v.Remaining = max(v.MaxQuantity-purchased, 0)
+	return purchased+v.Requested > v.MaxQuantity
+}
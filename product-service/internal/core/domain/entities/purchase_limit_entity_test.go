@@ -0,0 +1,57 @@
+package entities
+
+import "testing"
+
+func TestPurchaseLimitAppliesTo(t *testing.T) {
+	productID, parentID := int64(10), int64(1)
+
+	tests := []struct {
+		name    string
+		limit   PurchaseLimitEntity
+		product ProductEntity
+		want    bool
+	}{
+		{name: "the product", limit: PurchaseLimitEntity{ProductID: &productID}, product: ProductEntity{ID: 10}, want: true},
+		{name: "a variant of the product", limit: PurchaseLimitEntity{ProductID: &parentID}, product: ProductEntity{ID: 10, ParentID: &parentID}, want: true},
+		{name: "another product", limit: PurchaseLimitEntity{ProductID: &productID}, product: ProductEntity{ID: 11, CategorySlug: "beras"}, want: false},
+		{name: "the category", limit: PurchaseLimitEntity{CategorySlug: "beras"}, product: ProductEntity{ID: 11, CategorySlug: "beras"}, want: true},
+		{name: "another category", limit: PurchaseLimitEntity{CategorySlug: "beras"}, product: ProductEntity{ID: 11, CategorySlug: "minyak"}, want: false},
+		{name: "no target", limit: PurchaseLimitEntity{}, product: ProductEntity{ID: 11}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.limit.AppliesTo(tt.product); got != tt.want {
+				t.Errorf("AppliesTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPurchaseLimitViolationExceeds(t *testing.T) {
+	tests := []struct {
+		name          string
+		requested     int64
+		purchased     int64
+		want          bool
+		wantRemaining int64
+	}{
+		{name: "nothing bought yet", requested: 2, purchased: 0, want: false, wantRemaining: 5},
+		{name: "up to the limit", requested: 2, purchased: 3, want: false, wantRemaining: 2},
+		{name: "over the limit", requested: 3, purchased: 3, want: true, wantRemaining: 2},
+		{name: "limit already used", requested: 1, purchased: 5, want: true, wantRemaining: 0},
+		{name: "bought more than allowed before the limit", requested: 1, purchased: 8, want: true, wantRemaining: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violation := PurchaseLimitViolationEntity{MaxQuantity: 5, Requested: tt.requested}
+			if got := violation.Exceeds(tt.purchased); got != tt.want {
+				t.Errorf("Exceeds() = %v, want %v", got, tt.want)
+			}
+			if violation.Purchased != tt.purchased || violation.Remaining != tt.wantRemaining {
+				t.Errorf("Purchased, Remaining = %d, %d, want %d, %d", violation.Purchased, violation.Remaining, tt.purchased, tt.wantRemaining)
+			}
+		})
+	}
+}
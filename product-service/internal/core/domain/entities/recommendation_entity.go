@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+// Where a related product or a home feed product comes from: bought
+// together with the product or with earlier purchases, a best seller, in a
+// category the customer viewed recently, or on promotion.
+const (
+	RelatedReasonBoughtTogether = "bought_together"
+	RelatedReasonBestSeller     = "best_seller"
+	RelatedReasonViewedCategory = "viewed_category"
+	RelatedReasonPromotion      = "promotion"
+)
+
+// OrderEventEntity is the part of an order published by order-service that
+// recommendations use.
+type OrderEventEntity struct {
+	ID         int64                    `json:"id"`
+	BuyerID    int64                    `json:"buyer_id"`
+	CreatedAt  time.Time                `json:"created_at"`
+	OrderItems []PublishOrderItemEntity `json:"order_items"`
+}
+
+type RelatedProductEntity struct {
+	Product ProductEntity
+	Reason  string
+}
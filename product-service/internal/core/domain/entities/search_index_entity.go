@@ -0,0 +1,28 @@
+package entities
+
+// ReindexResultEntity summarizes a full reindex.
+type ReindexResultEntity struct {
+	Index    string   `json:"index"`
+	Indexed  int      `json:"indexed"`
+	Repaired int      `json:"repaired"`
+	Deleted  []string `json:"deleted"`
+}
+
+// IndexVerifyEntity compares the products in Postgres with the documents of
+// an index. Checksums cover every product, so equal checksums mean equal
+// content.
+type IndexVerifyEntity struct {
+	Index         string  `json:"index"`
+	DBCount       int     `json:"db_count"`
+	IndexCount    int     `json:"index_count"`
+	DBChecksum    string  `json:"db_checksum"`
+	IndexChecksum string  `json:"index_checksum"`
+	Missing       []int64 `json:"missing"`
+	Extra         []int64 `json:"extra"`
+	Mismatched    []int64 `json:"mismatched"`
+}
+
+// InSync reports whether the index matches the database.
+func (v IndexVerifyEntity) InSync() bool {
+	return v.DBCount == v.IndexCount && v.DBChecksum == v.IndexChecksum
+}
@@ -0,0 +1,19 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+// SearchSynonymEntity is a set of terms that find the same products, e.g.
+// "cabai" and "cabe".
+type SearchSynonymEntity struct {
+	ID        int64     `json:"id"`
+	Terms     []string  `json:"terms"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Rule formats the set as an Elasticsearch synonym rule.
+func (s SearchSynonymEntity) Rule() string {
+	return strings.Join(s.Terms, ", ")
+}
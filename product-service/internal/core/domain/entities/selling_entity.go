@@ -0,0 +1,140 @@
+package entities
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// How a product is sold. By item the quantity counts items and the price is
+// per item. By measure the quantity is an amount in QuantityUnit, say 250
+// gram, and the price is per PriceUnit, say per kg. Estimated items are
+// counted like items but priced by weight: each weighs about Weight Unit, and
+// the final price follows the weight measured at packing.
+const (
+	SellByItem      = "item"
+	SellByMeasure   = "measure"
+	SellByEstimated = "estimated"
+)
+
+// Kinds of units; only units of the same kind convert into each other.
+const (
+	UnitKindMass   = "mass"
+	UnitKindVolume = "volume"
+)
+
+type unitDefinition struct {
+	kind string
+	// base is the size of the unit in gram or millilitre.
+	base float64
+}
+
+// sellingUnits are the units products can be priced and measured in.
+var sellingUnits = map[string]unitDefinition{
+	"g":        {UnitKindMass, 1},
+	"gram":     {UnitKindMass, 1},
+	"ons":      {UnitKindMass, 100},
+	"kg":       {UnitKindMass, 1000},
+	"kilogram": {UnitKindMass, 1000},
+	"ml":       {UnitKindVolume, 1},
+	"l":        {UnitKindVolume, 1000},
+	"liter":    {UnitKindVolume, 1000},
+}
+
+// SellingEntity holds the selling rules of a product. Quantities, steps and
+// limits are in QuantityUnit for products sold by measure and in items
+// otherwise; zero limits mean no limit.
+type SellingEntity struct {
+	SellBy       string `json:"sell_by"`
+	PriceUnit    string `json:"price_unit"`
+	QuantityUnit string `json:"quantity_unit"`
+	QuantityStep int64  `json:"quantity_step"`
+	MinQuantity  int64  `json:"min_quantity"`
+	MaxQuantity  int64  `json:"max_quantity"`
+}
+
+// Step is the quantity step, at least 1.
+func (s SellingEntity) Step() int64 {
+	if s.QuantityStep < 1 {
+		return 1
+	}
+	return s.QuantityStep
+}
+
+// IsMeasured reports whether quantities are amounts instead of items.
+func (s SellingEntity) IsMeasured() bool {
+	return s.SellBy == SellByMeasure
+}
+
+// IsEstimated reports whether the price is only known at packing.
+func (s SellingEntity) IsEstimated() bool {
+	return s.SellBy == SellByEstimated
+}
+
+// UnitKind returns the kind of unit, or "" for an unknown unit.
+func UnitKind(unit string) string {
+	return sellingUnits[strings.ToLower(unit)].kind
+}
+
+// ConvertQuantity converts an amount between two units of the same kind.
+func ConvertQuantity(amount float64, from, to string) (float64, error) {
+	fromUnit, ok := sellingUnits[strings.ToLower(from)]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", from)
+	}
+
+	toUnit, ok := sellingUnits[strings.ToLower(to)]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", to)
+	}
+
+	if fromUnit.kind != toUnit.kind {
+		return 0, fmt.Errorf("cannot convert %s to %s", from, to)
+	}
+
+	return amount * fromUnit.base / toUnit.base, nil
+}
+
+// BilledQuantity is what the unit price is multiplied with for quantity:
+// items for products sold by item, and the amount in PriceUnit otherwise.
+// Estimated items are billed by their estimated weight.
+func (p ProductEntity) BilledQuantity(quantity int64) float64 {
+	switch p.Selling.SellBy {
+	case SellByMeasure:
+		billed, err := ConvertQuantity(float64(quantity), p.Selling.QuantityUnit, p.Selling.PriceUnit)
+		if err == nil {
+			return billed
+		}
+	case SellByEstimated:
+		billed, err := ConvertQuantity(float64(quantity)*float64(p.Weight), p.Unit, p.Selling.PriceUnit)
+		if err == nil {
+			return billed
+		}
+	}
+	return float64(quantity)
+}
+
+// LinePrice is the price of quantity of the product, rounded to whole
+// rupiah. For estimated items it is the estimate.
+func (p ProductEntity) LinePrice(quantity int64) float64 {
+	return math.Round(p.UnitPrice() * p.BilledQuantity(quantity))
+}
+
+// LineWeight is the weight in gram of quantity of the product, as far as it
+// is known.
+func (p ProductEntity) LineWeight(quantity int64) int64 {
+	if p.Selling.IsMeasured() {
+		weight, err := ConvertQuantity(float64(quantity), p.Selling.QuantityUnit, "g")
+		if err != nil {
+			return 0
+		}
+		return int64(math.Round(weight))
+	}
+
+	weight, err := ConvertQuantity(float64(int64(p.Weight)*quantity), p.Unit, "g")
+	if err != nil {
+		// weights used to be stored in gram whatever the unit said
+		return int64(p.Weight) * quantity
+	}
+	return int64(math.Round(weight))
+}
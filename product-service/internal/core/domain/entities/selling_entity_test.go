@@ -0,0 +1,131 @@
+package entities
+
+import "testing"
+
+func TestConvertQuantity(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   float64
+		from, to string
+		want     float64
+		wantErr  bool
+	}{
+		{name: "gram to kg", amount: 250, from: "gram", to: "kg", want: 0.25},
+		{name: "kg to gram", amount: 1.5, from: "kg", to: "g", want: 1500},
+		{name: "ons to kg", amount: 5, from: "ons", to: "kilogram", want: 0.5},
+		{name: "units are case insensitive", amount: 500, from: "ML", to: "L", want: 0.5},
+		{name: "same unit", amount: 3, from: "liter", to: "l", want: 3},
+		{name: "mass to volume", amount: 1, from: "kg", to: "l", wantErr: true},
+		{name: "unknown source unit", amount: 1, from: "pcs", to: "kg", wantErr: true},
+		{name: "unknown target unit", amount: 1, from: "kg", to: "lb", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertQuantity(tt.amount, tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConvertQuantity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ConvertQuantity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBilledQuantityAndLinePrice(t *testing.T) {
+	tests := []struct {
+		name       string
+		product    ProductEntity
+		quantity   int64
+		wantBilled float64
+		wantPrice  float64
+	}{
+		{
+			name:       "by item",
+			product:    ProductEntity{SalePrice: 12000, Selling: SellingEntity{SellBy: SellByItem}},
+			quantity:   3,
+			wantBilled: 3,
+			wantPrice:  36000,
+		},
+		{
+			name:       "without selling rules counts items",
+			product:    ProductEntity{RegulerPrice: 5000},
+			quantity:   2,
+			wantBilled: 2,
+			wantPrice:  10000,
+		},
+		{
+			name:       "by measure, 250 gram priced per kg",
+			product:    ProductEntity{SalePrice: 40000, Selling: SellingEntity{SellBy: SellByMeasure, QuantityUnit: "gram", PriceUnit: "kg"}},
+			quantity:   250,
+			wantBilled: 0.25,
+			wantPrice:  10000,
+		},
+		{
+			name:       "by measure with units that do not convert counts the amount",
+			product:    ProductEntity{SalePrice: 100, Selling: SellingEntity{SellBy: SellByMeasure, QuantityUnit: "gram", PriceUnit: "l"}},
+			quantity:   250,
+			wantBilled: 250,
+			wantPrice:  25000,
+		},
+		{
+			name:       "estimated, two items of about 500 gram priced per kg",
+			product:    ProductEntity{SalePrice: 30000, Weight: 500, Unit: "gram", Selling: SellingEntity{SellBy: SellByEstimated, PriceUnit: "kg"}},
+			quantity:   2,
+			wantBilled: 1,
+			wantPrice:  30000,
+		},
+		{
+			name:       "the regular price without a sale price",
+			product:    ProductEntity{RegulerPrice: 33333, Selling: SellingEntity{SellBy: SellByMeasure, QuantityUnit: "ons", PriceUnit: "kg"}},
+			quantity:   5,
+			wantBilled: 0.5,
+			wantPrice:  16667,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.product.BilledQuantity(tt.quantity); got != tt.wantBilled {
+				t.Errorf("BilledQuantity() = %v, want %v", got, tt.wantBilled)
+			}
+			if got := tt.product.LinePrice(tt.quantity); got != tt.wantPrice {
+				t.Errorf("LinePrice() = %v, want %v", got, tt.wantPrice)
+			}
+		})
+	}
+}
+
+func TestLineWeight(t *testing.T) {
+	tests := []struct {
+		name     string
+		product  ProductEntity
+		quantity int64
+		want     int64
+	}{
+		{name: "items in gram", product: ProductEntity{Weight: 250, Unit: "gram"}, quantity: 4, want: 1000},
+		{name: "items in kg", product: ProductEntity{Weight: 2, Unit: "kg"}, quantity: 3, want: 6000},
+		{name: "legacy units stored in gram", product: ProductEntity{Weight: 300, Unit: "pack"}, quantity: 2, want: 600},
+		{
+			name:     "measured amount",
+			product:  ProductEntity{Selling: SellingEntity{SellBy: SellByMeasure, QuantityUnit: "ons"}},
+			quantity: 3,
+			want:     300,
+		},
+		{
+			name:     "measured volume has no weight",
+			product:  ProductEntity{Selling: SellingEntity{SellBy: SellByMeasure, QuantityUnit: "ml"}},
+			quantity: 500,
+			want:     0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.product.LineWeight(tt.quantity); got != tt.want {
+				t.Errorf("LineWeight() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
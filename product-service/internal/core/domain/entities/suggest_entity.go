@@ -0,0 +1,24 @@
+package entities
+
+type SuggestEntity struct {
+	Query      string                  `json:"query"`
+	Products   []SuggestProductEntity  `json:"products"`
+	Categories []SuggestCategoryEntity `json:"categories"`
+	// DidYouMean is only set when nothing matched the query.
+	DidYouMean string `json:"did_you_mean"`
+}
+
+type SuggestProductEntity struct {
+	ID           int64   `json:"id"`
+	Name         string  `json:"name"`
+	Image        string  `json:"image"`
+	CategoryName string  `json:"category_name"`
+	CategorySlug string  `json:"category_slug"`
+	RegulerPrice float64 `json:"reguler_price"`
+	SalePrice    float64 `json:"sale_price"`
+}
+
+type SuggestCategoryEntity struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// TrashItemEntity is a soft deleted product or category. PurgeAt is when the
+// purge worker deletes it for good.
+type TrashItemEntity struct {
+	ID           int64     `json:"id"`
+	ParentID     *int64    `json:"parent_id"`
+	Name         string    `json:"name"`
+	Slug         string    `json:"slug"`
+	SKU          string    `json:"sku"`
+	Image        string    `json:"image"`
+	CategorySlug string    `json:"category_slug"`
+	Status       string    `json:"status"`
+	DeletedAt    time.Time `json:"deleted_at"`
+	PurgeAt      time.Time `json:"purge_at"`
+}
+
+// TrashPurgeEntity counts the rows a purge deleted permanently.
+type TrashPurgeEntity struct {
+	Products   int64 `json:"products"`
+	Categories int64 `json:"categories"`
+}
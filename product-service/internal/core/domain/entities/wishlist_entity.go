@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+const (
+	WishlistListWishlist      = "wishlist"
+	WishlistListSavedForLater = "saved_for_later"
+)
+
+// WishlistItemEntity is a bookmarked product. LastPrice and LastInStock are
+// what the customer was last told about, so sale and back in stock events
+// are only sent once per change.
+type WishlistItemEntity struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"user_id"`
+	ProductID   int64     `json:"product_id"`
+	List        string    `json:"list"`
+	Quantity    int64     `json:"quantity"`
+	LastPrice   float64   `json:"last_price"`
+	LastInStock bool      `json:"last_in_stock"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	Product *ProductEntity `json:"product,omitempty"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+type BundleItem struct {
+	ID          int64     `gorm:"primaryKey"`
+	BundleID    int64     `gorm:"column:bundle_id;not null"`
+	ComponentID int64     `gorm:"column:component_id;not null"`
+	Quantity    int       `gorm:"column:quantity;not null"`
+	CreatedAt   time.Time `gorm:"column:created_at;default:CURRENT_TIMESTAMP"`
+	Component   Product   `gorm:"foreignKey:ComponentID;references:ID"`
+}
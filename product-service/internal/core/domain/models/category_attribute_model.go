@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+type CategoryAttribute struct {
+	ID           int64      `gorm:"primaryKey"`
+	CategoryID   int64      `gorm:"column:category_id;not null"`
+	Code         string     `gorm:"column:code;not null;size:50"`
+	Name         string     `gorm:"column:name;not null;size:100"`
+	Type         string     `gorm:"column:type;not null;size:10"`
+	Options      string     `gorm:"column:options"`
+	Unit         string     `gorm:"column:unit;size:20"`
+	IsRequired   bool       `gorm:"column:is_required;default:false"`
+	IsFilterable bool       `gorm:"column:is_filterable;default:true"`
+	Position     int        `gorm:"column:position;default:0"`
+	CreatedAt    time.Time  `gorm:"column:created_at;default:CURRENT_TIMESTAMP"`
+	UpdatedAt    *time.Time `gorm:"column:updated_at"`
+}
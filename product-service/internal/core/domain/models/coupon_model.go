@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Coupon struct {
+	ID               int64          `gorm:"primaryKey"`
+	Code             string         `gorm:"column:code;not null;size:40"`
+	Name             string         `gorm:"column:name;not null"`
+	DiscountType     string         `gorm:"column:discount_type;not null;size:20"`
+	Value            float64        `gorm:"column:value;default:0"`
+	MaxDiscount      float64        `gorm:"column:max_discount;default:0"`
+	MinSpend         float64        `gorm:"column:min_spend;default:0"`
+	UsageLimit       int64          `gorm:"column:usage_limit;default:0"`
+	PerCustomerLimit int64          `gorm:"column:per_customer_limit;default:0"`
+	UsedCount        int64          `gorm:"column:used_count;default:0"`
+	Status           bool           `gorm:"column:status;default:true"`
+	StartAt          *time.Time     `gorm:"column:start_at"`
+	EndAt            *time.Time     `gorm:"column:end_at"`
+	CreatedAt        time.Time      `gorm:"column:created_at;default:CURRENT_TIMESTAMP"`
+	UpdatedAt        *time.Time     `gorm:"column:updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"column:deleted_at;index"`
+}
+
+type CouponRedemption struct {
+	ID        int64     `gorm:"primaryKey"`
+	CouponID  int64     `gorm:"column:coupon_id;not null"`
+	UserID    int64     `gorm:"column:user_id;not null"`
+	OrderCode string    `gorm:"column:order_code;not null;size:64"`
+	Discount  float64   `gorm:"column:discount;default:0"`
+	CreatedAt time.Time `gorm:"column:created_at;default:CURRENT_TIMESTAMP"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PriceList struct {
+	ID        int64          `gorm:"primaryKey"`
+	ProductID int64          `gorm:"column:product_id;not null"`
+	Name      string         `gorm:"column:name;not null"`
+	SalePrice float64        `gorm:"column:sale_price;not null"`
+	StartAt   time.Time      `gorm:"column:start_at;not null"`
+	EndAt     *time.Time     `gorm:"column:end_at"`
+	CreatedAt time.Time      `gorm:"column:created_at;default:CURRENT_TIMESTAMP"`
+	UpdatedAt *time.Time     `gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index"`
+}
+
+type PriceHistory struct {
+	ID           int64     `gorm:"primaryKey"`
+	ProductID    int64     `gorm:"column:product_id;not null"`
+	PriceListID  *int64    `gorm:"column:price_list_id"`
+	RegulerPrice float64   `gorm:"column:reguler_price;default:0"`
+	SalePrice    float64   `gorm:"column:sale_price;default:0"`
+	Source       string    `gorm:"column:source;not null"`
+	CreatedAt    time.Time `gorm:"column:created_at;default:CURRENT_TIMESTAMP"`
+}
+
+func (PriceHistory) TableName() string {
+	return "price_history"
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type ProductImage struct {
+	ID        int64      `gorm:"primaryKey"`
+	ProductID int64      `gorm:"column:product_id;not null"`
+	ImageURL  string     `gorm:"column:image_url;not null"`
+	AltText   string     `gorm:"column:alt_text"`
+	Position  int        `gorm:"column:position;default:0"`
+	IsPrimary bool       `gorm:"column:is_primary;default:false"`
+	CreatedAt time.Time  `gorm:"column:created_at;default:CURRENT_TIMESTAMP"`
+	UpdatedAt *time.Time `gorm:"column:updated_at"`
+}
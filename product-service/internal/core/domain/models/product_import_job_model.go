@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type ProductImportJob struct {
+	ID            int64      `gorm:"primaryKey"`
+	UserID        int64      `gorm:"column:user_id;not null"`
+	FileName      string     `gorm:"column:file_name;not null"`
+	Status        string     `gorm:"column:status;not null;size:20"`
+	TotalRows     int        `gorm:"column:total_rows;default:0"`
+	ProcessedRows int        `gorm:"column:processed_rows;default:0"`
+	CreatedCount  int        `gorm:"column:created_count;default:0"`
+	UpdatedCount  int        `gorm:"column:updated_count;default:0"`
+	FailedCount   int        `gorm:"column:failed_count;default:0"`
+	RowErrors     string     `gorm:"column:row_errors"`
+	ErrorMessage  string     `gorm:"column:error_message"`
+	CreatedAt     time.Time  `gorm:"column:created_at;default:CURRENT_TIMESTAMP"`
+	UpdatedAt     *time.Time `gorm:"column:updated_at"`
+	FinishedAt    *time.Time `gorm:"column:finished_at"`
+}
@@ -7,22 +7,43 @@ import (
 )
 
 type Product struct {
-	ID           int64          `gorm:"primaryKey"`
-	ParentID     *int64         `gorm:"column:parent_id"`
-	CategorySlug string         `gorm:"column:category_slug;not null"`
-	Name         string         `gorm:"column:name;not null"`
-	Image        string         `gorm:"column:image;not null"`
-	Description  string         `gorm:"column:description"`
-	RegulerPrice float64        `gorm:"column:reguler_price;default:0"`
-	SalePrice    float64        `gorm:"column:sale_price;default:0"`
-	Unit         string         `gorm:"column:unit;default:'gram'"`
-	Weight       int            `gorm:"column:weight;default:0"`
-	Stock        int            `gorm:"column:stock;default:0"`
-	Variant      int            `gorm:"column:variant;default:1"`
-	Status       string         `gorm:"column:status;default:'DRAFT';size:20"`
-	CreatedAt    time.Time      `gorm:"column:created_at;default:CURRENT_TIMESTAMP"`
-	UpdatedAt    *time.Time     `gorm:"column:updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"column:deleted_at;index"`
-	Childs       []Product      `gorm:"foreignKey:ParentID;references:ID"`
-	Category     Category       `gorm:"foreignKey:CategorySlug;references:Slug"`
-}
\ No newline at end of file
+	ID             int64          `gorm:"primaryKey"`
+	ParentID       *int64         `gorm:"column:parent_id"`
+	SKU            *string        `gorm:"column:sku;size:64"`
+	CategorySlug   string         `gorm:"column:category_slug;not null"`
+	Name           string         `gorm:"column:name;not null"`
+	Image          string         `gorm:"column:image;not null"`
+	Description    string         `gorm:"column:description"`
+	RegulerPrice   float64        `gorm:"column:reguler_price;default:0"`
+	SalePrice      float64        `gorm:"column:sale_price;default:0"`
+	BaseSalePrice  float64        `gorm:"column:base_sale_price;default:0"`
+	PriceListID    *int64         `gorm:"column:price_list_id"`
+	Unit           string         `gorm:"column:unit;default:'gram'"`
+	Weight         int            `gorm:"column:weight;default:0"`
+	Stock          int            `gorm:"column:stock;default:0"`
+	Variant        int            `gorm:"column:variant;default:1"`
+	Status         string         `gorm:"column:status;default:'Draft';size:20"`
+	PublishAt      *time.Time     `gorm:"column:publish_at"`
+	UnpublishAt    *time.Time     `gorm:"column:unpublish_at"`
+	ApprovedBy     *int64         `gorm:"column:approved_by"`
+	ApprovedAt     *time.Time     `gorm:"column:approved_at"`
+	ReviewNote     string         `gorm:"column:review_note"`
+	Attributes     string         `gorm:"column:attributes;type:jsonb;default:'{}'"`
+	Type           string         `gorm:"column:type;default:'simple';size:10"`
+	BundlePricing  *string        `gorm:"column:bundle_pricing;size:10"`
+	BundleDiscount float64        `gorm:"column:bundle_discount;default:0"`
+	SellBy         string         `gorm:"column:sell_by;default:'item';size:10"`
+	PriceUnit      string         `gorm:"column:price_unit;size:20"`
+	QuantityUnit   string         `gorm:"column:quantity_unit;size:20"`
+	QuantityStep   int64          `gorm:"column:quantity_step;default:1"`
+	MinQuantity    int64          `gorm:"column:min_quantity;default:0"`
+	MaxQuantity    int64          `gorm:"column:max_quantity;default:0"`
+	Rating         float64        `gorm:"column:rating;default:0;->"`
+	RatingCount    int64          `gorm:"column:rating_count;default:0;->"`
+	CreatedAt      time.Time      `gorm:"column:created_at;default:CURRENT_TIMESTAMP"`
+	UpdatedAt      *time.Time     `gorm:"column:updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"column:deleted_at;index"`
+	Childs         []Product      `gorm:"foreignKey:ParentID;references:ID"`
+	Category       Category       `gorm:"foreignKey:CategorySlug;references:Slug"`
+	Images         []ProductImage `gorm:"foreignKey:ProductID;references:ID"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+type ProductRating struct {
+	ProductID int64      `gorm:"column:product_id;primaryKey"`
+	UserID    int64      `gorm:"column:user_id;primaryKey"`
+	Rating    int        `gorm:"column:rating;not null"`
+	CreatedAt time.Time  `gorm:"column:created_at;default:CURRENT_TIMESTAMP"`
+	UpdatedAt *time.Time `gorm:"column:updated_at"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Promotion struct {
+	ID              int64           `gorm:"primaryKey"`
+	Name            string          `gorm:"column:name;not null"`
+	Description     string          `gorm:"column:description"`
+	Type            string          `gorm:"column:type;not null;size:30"`
+	Value           float64         `gorm:"column:value;default:0"`
+	BuyQuantity     int64           `gorm:"column:buy_quantity;default:0"`
+	GetQuantity     int64           `gorm:"column:get_quantity;default:0"`
+	CategorySlug    string          `gorm:"column:category_slug"`
+	ProductID       *int64          `gorm:"column:product_id"`
+	MinQuantity     int64           `gorm:"column:min_quantity;default:0"`
+	MinSubtotal     float64         `gorm:"column:min_subtotal;default:0"`
+	CustomerSegment string          `gorm:"column:customer_segment"`
+	Priority        int             `gorm:"column:priority;default:0"`
+	Stackable       bool            `gorm:"column:stackable;default:false"`
+	Status          bool            `gorm:"column:status;default:true"`
+	StartAt         *time.Time      `gorm:"column:start_at"`
+	EndAt           *time.Time      `gorm:"column:end_at"`
+	CreatedAt       time.Time       `gorm:"column:created_at;default:CURRENT_TIMESTAMP"`
+	UpdatedAt       *time.Time      `gorm:"column:updated_at"`
+	DeletedAt       gorm.DeletedAt  `gorm:"column:deleted_at;index"`
+	Tiers           []PromotionTier `gorm:"foreignKey:PromotionID;references:ID"`
+}
+
+type PromotionTier struct {
+	ID          int64   `gorm:"primaryKey"`
+	PromotionID int64   `gorm:"column:promotion_id;not null"`
+	MinQuantity int64   `gorm:"column:min_quantity;not null"`
+	Value       float64 `gorm:"column:value;not null"`
+}
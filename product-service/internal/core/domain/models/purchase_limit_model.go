@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PurchaseLimit struct {
+	ID           int64          `gorm:"primaryKey"`
+	Name         string         `gorm:"column:name;not null"`
+	ProductID    *int64         `gorm:"column:product_id"`
+	CategorySlug *string        `gorm:"column:category_slug"`
+	MaxQuantity  int64          `gorm:"column:max_quantity;not null"`
+	WindowHours  int            `gorm:"column:window_hours;not null;default:24"`
+	IsActive     bool           `gorm:"column:is_active;not null;default:true"`
+	CreatedAt    time.Time      `gorm:"column:created_at;default:CURRENT_TIMESTAMP"`
+	UpdatedAt    *time.Time     `gorm:"column:updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"column:deleted_at;index"`
+}
+
+type PurchaseReservation struct {
+	OrderCode  string    `gorm:"column:order_code;primaryKey"`
+	ProductID  int64     `gorm:"column:product_id;primaryKey"`
+	BuyerID    int64     `gorm:"column:buyer_id;not null"`
+	Quantity   int64     `gorm:"column:quantity;not null"`
+	ReservedAt time.Time `gorm:"column:reserved_at"`
+}
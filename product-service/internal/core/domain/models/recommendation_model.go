@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type OrderPurchase struct {
+	OrderID   int64     `gorm:"column:order_id;primaryKey"`
+	ProductID int64     `gorm:"column:product_id;primaryKey"`
+	BuyerID   *int64    `gorm:"column:buyer_id"`
+	Quantity  int64     `gorm:"column:quantity;default:1"`
+	OrderedAt time.Time `gorm:"column:ordered_at"`
+}
+
+type ProductRecommendation struct {
+	ProductID  int64     `gorm:"column:product_id;primaryKey"`
+	RelatedID  int64     `gorm:"column:related_id;primaryKey"`
+	Score      int       `gorm:"column:score"`
+	Rank       int       `gorm:"column:rank"`
+	ComputedAt time.Time `gorm:"column:computed_at"`
+}
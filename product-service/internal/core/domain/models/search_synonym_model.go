@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+type SearchSynonym struct {
+	ID        int64      `gorm:"primaryKey"`
+	Terms     string     `gorm:"column:terms;not null"`
+	CreatedAt time.Time  `gorm:"column:created_at;default:CURRENT_TIMESTAMP"`
+	UpdatedAt *time.Time `gorm:"column:updated_at"`
+}
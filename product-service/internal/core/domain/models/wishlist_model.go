@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type WishlistItem struct {
+	ID          int64      `gorm:"primaryKey"`
+	UserID      int64      `gorm:"column:user_id;not null"`
+	ProductID   int64      `gorm:"column:product_id;not null"`
+	List        string     `gorm:"column:list;not null;size:20"`
+	Quantity    int64      `gorm:"column:quantity;default:1"`
+	LastPrice   float64    `gorm:"column:last_price;default:0"`
+	LastInStock bool       `gorm:"column:last_in_stock;default:true"`
+	CreatedAt   time.Time  `gorm:"column:created_at;default:CURRENT_TIMESTAMP"`
+	UpdatedAt   *time.Time `gorm:"column:updated_at"`
+}
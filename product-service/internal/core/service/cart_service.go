@@ -2,57 +2,275 @@ package service
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"product-service/internal/adapter/repository"
 	"product-service/internal/core/domain/entities"
+	"slices"
+	"strings"
 
+	"github.com/google/uuid"
 	"github.com/labstack/gommon/log"
 )
 
 type ICartService interface {
-	AddToCart(ctx context.Context, userID int64, req entities.CartItem) error
-	GetCartByUserID(ctx context.Context, userID int64) ([]entities.CartItem, error)
-	RemoveFromCart(ctx context.Context, userID int64, productID int64) error
-	RemoveAllCart(ctx context.Context, userID int64) error
+	AddToCart(ctx context.Context, owner entities.CartOwner, req entities.CartItem) error
+	SetQuantity(ctx context.Context, owner entities.CartOwner, req entities.CartItem) error
+	GetCart(ctx context.Context, owner entities.CartOwner) ([]entities.CartItem, error)
+	GetCartDetail(ctx context.Context, owner entities.CartOwner) (*entities.CartEntity, error)
+	RemoveFromCart(ctx context.Context, owner entities.CartOwner, productID int64) error
+	RemoveAllCart(ctx context.Context, owner entities.CartOwner) error
+
+	NewGuestCartID() string
+	VerifyGuestCartID(cartID string) (string, error)
+	MergeGuestCart(ctx context.Context, cartID string, userID int64) error
 }
 
 type cartService struct {
-	cartRepository repository.ICartRepository
+	cartRepository       repository.ICartRepository
+	repoProduct          repository.IProductRepository
+	purchaseLimitService IPurchaseLimitService
+	secret               []byte
 }
 
 // RemoveAllCart implements [ICartService].
-func (c *cartService) RemoveAllCart(ctx context.Context, userID int64) error {
-	return c.cartRepository.RemoveAllCart(ctx, userID)
+func (c *cartService) RemoveAllCart(ctx context.Context, owner entities.CartOwner) error {
+	return c.cartRepository.RemoveCart(ctx, owner)
 }
 
 // RemoveFromCart implements [ICartService].
-func (c *cartService) RemoveFromCart(ctx context.Context, userID int64, productID int64) error {
-	return c.cartRepository.RemoveFromCart(ctx, userID, productID)
+func (c *cartService) RemoveFromCart(ctx context.Context, owner entities.CartOwner, productID int64) error {
+	return c.cartRepository.RemoveFromCart(ctx, owner, productID)
 }
 
-// GetCartByUserID implements [ICartService].
-func (c *cartService) GetCartByUserID(ctx context.Context, userID int64) ([]entities.CartItem, error) {
-	cart, err := c.cartRepository.GetCart(ctx, fmt.Sprintf("cart:%d", userID))
+// GetCart implements [ICartService].
+func (c *cartService) GetCart(ctx context.Context, owner entities.CartOwner) ([]entities.CartItem, error) {
+	cart, err := c.cartRepository.GetCart(ctx, owner)
 	if err != nil {
-		log.Errorf("[CartService-1] GetCartByUserID: %v", err)
+		log.Errorf("[CartService-1] GetCart: %v", err)
 		return nil, err
 	}
 
 	return cart, nil
 }
 
+// GetCartDetail implements [ICartService]. Lines are priced with the current
+// product data and carry warnings instead of failing, so the customer can
+// still see and fix a cart whose products changed since they were added.
+func (c *cartService) GetCartDetail(ctx context.Context, owner entities.CartOwner) (*entities.CartEntity, error) {
+	items, err := c.GetCart(ctx, owner)
+	if err != nil {
+		log.Errorf("[CartService-1] GetCartDetail: %v", err)
+		return nil, err
+	}
+
+	productIDs := []int64{}
+	for _, item := range items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	products, err := c.repoProduct.GetByIDs(ctx, productIDs)
+	if err != nil {
+		log.Errorf("[CartService-2] GetCartDetail: %v", err)
+		return nil, err
+	}
+
+	productMap := map[int64]entities.ProductEntity{}
+	for _, product := range products {
+		productMap[product.ID] = product
+	}
+
+	// a cart filled as a guest or before an order went through can be over
+	// a limit; like the other rules it is reported, not enforced, here
+	violations, err := c.purchaseLimitService.Check(ctx, owner.UserID, items)
+	if err != nil {
+		log.Errorf("[CartService-3] GetCartDetail: %v", err)
+		return nil, err
+	}
+
+	result := &entities.CartEntity{
+		Items: []entities.CartLineEntity{},
+	}
+	for _, item := range items {
+		line := entities.CartLineEntity{
+			ProductID:     item.ProductID,
+			Quantity:      item.Quantity,
+			PreviousPrice: item.Price,
+			Warnings:      []entities.CartWarningEntity{},
+		}
+
+		product, ok := productMap[item.ProductID]
+		if !ok || !product.IsPublished() {
+			line.Warnings = append(line.Warnings, entities.CartWarningEntity{
+				Code:    entities.CartWarningUnavailable,
+				Message: "product is no longer available",
+			})
+		}
+
+		if ok {
+			line.ProductName = product.Name
+			line.ProductImage = product.Image
+			line.ProductStatus = product.Status
+			line.Unit = product.Unit
+			line.Weight = int64(product.Weight)
+			line.Stock = int64(product.Stock)
+			line.SellBy = product.Selling.SellBy
+			line.PriceUnit = product.Selling.PriceUnit
+			line.QuantityUnit = product.Selling.QuantityUnit
+			line.UnitPrice = product.UnitPrice()
+			line.LineTotal = product.LinePrice(item.Quantity)
+			line.LineWeight = product.LineWeight(item.Quantity)
+			line.Available = product.IsPublished() && line.Stock >= item.Quantity
+
+			// the rules may have changed since the line was added; checkout
+			// refuses the quantity, so the customer is told here first
+			if err := checkQuantity(product, item.Quantity); err != nil {
+				line.Warnings = append(line.Warnings, entities.CartWarningEntity{
+					Code:    entities.CartWarningInvalidQuantity,
+					Message: err.Error(),
+				})
+			}
+
+			if product.IsPublished() && line.Stock < item.Quantity {
+				line.Warnings = append(line.Warnings, entities.CartWarningEntity{
+					Code:    entities.CartWarningInsufficientStock,
+					Message: fmt.Sprintf("only %d left in stock", line.Stock),
+				})
+			}
+
+			for _, violation := range violations {
+				if slices.Contains(violation.AffectedProductIDs, item.ProductID) {
+					line.Warnings = append(line.Warnings, entities.CartWarningEntity{
+						Code:    entities.CartWarningPurchaseLimit,
+						Message: fmt.Sprintf("%s allows %d per %d hours, %d left", violation.Name, violation.MaxQuantity, violation.WindowHours, violation.Remaining),
+					})
+				}
+			}
+
+			if item.Price > 0 && item.Price != line.UnitPrice {
+				line.Warnings = append(line.Warnings, entities.CartWarningEntity{
+					Code:    entities.CartWarningPriceChanged,
+					Message: fmt.Sprintf("price changed from %.0f to %.0f", item.Price, line.UnitPrice),
+				})
+			}
+		}
+
+		if line.Available {
+			// an amount of 500 gram is one thing in the cart
+			if line.SellBy == entities.SellByMeasure {
+				result.TotalQuantity++
+			} else {
+				result.TotalQuantity += line.Quantity
+			}
+			result.TotalWeight += line.LineWeight
+			result.Subtotal += line.LineTotal
+			if line.SellBy == entities.SellByEstimated {
+				result.HasEstimated = true
+			}
+		}
+		if len(line.Warnings) > 0 {
+			result.HasWarnings = true
+		}
+
+		result.Items = append(result.Items, line)
+	}
+
+	return result, nil
+}
+
 // AddToCart implements [ICartService].
-func (c *cartService) AddToCart(ctx context.Context, userID int64, req entities.CartItem) error {
-	cart, err := c.cartRepository.GetCart(ctx, fmt.Sprintf("cart:%d", userID))
+func (c *cartService) AddToCart(ctx context.Context, owner entities.CartOwner, req entities.CartItem) error {
+	cart, err := c.cartRepository.GetCart(ctx, owner)
 	if err != nil {
 		log.Errorf("[CartService-1] AddToCart: %v", err)
 		return err
 	}
 
+	product, err := c.repoProduct.GetByID(ctx, req.ProductID)
+	if err != nil {
+		log.Errorf("[CartService-2] AddToCart: %v", err)
+		return err
+	}
+
+	quantity, err := cartQuantity(*product, req.Quantity, req.Unit)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range cart {
+		if item.ProductID == req.ProductID {
+			quantity += item.Quantity
+			break
+		}
+	}
+
+	req.Quantity = quantity
+	if err := c.upsertItem(ctx, owner, cart, *product, req); err != nil {
+		log.Errorf("[CartService-3] AddToCart: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// SetQuantity implements [ICartService]. A zero quantity removes the line.
+func (c *cartService) SetQuantity(ctx context.Context, owner entities.CartOwner, req entities.CartItem) error {
+	if req.Quantity <= 0 {
+		return c.cartRepository.RemoveFromCart(ctx, owner, req.ProductID)
+	}
+
+	cart, err := c.cartRepository.GetCart(ctx, owner)
+	if err != nil {
+		log.Errorf("[CartService-1] SetQuantity: %v", err)
+		return err
+	}
+
+	product, err := c.repoProduct.GetByID(ctx, req.ProductID)
+	if err != nil {
+		log.Errorf("[CartService-2] SetQuantity: %v", err)
+		return err
+	}
+
+	if req.Quantity, err = cartQuantity(*product, req.Quantity, req.Unit); err != nil {
+		return err
+	}
+
+	if err := c.upsertItem(ctx, owner, cart, *product, req); err != nil {
+		log.Errorf("[CartService-3] SetQuantity: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// upsertItem checks the product can be sold in req.Quantity and stores that
+// quantity together with the current unit price. Purchase limits are only
+// checked when the quantity goes up, so a customer over a limit can still
+// lower it.
+func (c *cartService) upsertItem(ctx context.Context, owner entities.CartOwner, cart []entities.CartItem, product entities.ProductEntity, req entities.CartItem) error {
+	if !product.IsPublished() {
+		return errors.New("product is not available")
+	}
+
+	if err := checkQuantity(product, req.Quantity); err != nil {
+		return err
+	}
+
+	if int64(product.Stock) < req.Quantity {
+		return fmt.Errorf("only %d left in stock", product.Stock)
+	}
+
+	req.Price = product.UnitPrice()
+	req.Unit = ""
+	var previous int64
 	found := false
 	for i, item := range cart {
 		if item.ProductID == req.ProductID {
-			cart[i].Quantity += req.Quantity
+			previous = item.Quantity
+			cart[i] = req
 			found = true
 			break
 		}
@@ -62,12 +280,143 @@ func (c *cartService) AddToCart(ctx context.Context, userID int64, req entities.
 		cart = append(cart, req)
 	}
 
-	return c.cartRepository.AddToCart(ctx, fmt.Sprintf("cart:%d", userID), cart)
+	if req.Quantity > previous {
+		violations, err := c.purchaseLimitService.Check(ctx, owner.UserID, cart)
+		if err != nil {
+			return err
+		}
+
+		exceeded := []entities.PurchaseLimitViolationEntity{}
+		for _, violation := range violations {
+			if slices.Contains(violation.AffectedProductIDs, req.ProductID) {
+				exceeded = append(exceeded, violation)
+			}
+		}
+		if len(exceeded) > 0 {
+			return &PurchaseLimitError{Violations: exceeded}
+		}
+	}
+
+	return c.cartRepository.SaveCart(ctx, owner, cart)
+}
+
+// NewGuestCartID implements [ICartService]. The ID is a random UUID followed
+// by its HMAC, so visitors cannot guess or forge another visitor's cart.
+func (c *cartService) NewGuestCartID() string {
+	id := uuid.NewString()
+	return id + "." + c.signGuestCartID(id)
+}
+
+// VerifyGuestCartID implements [ICartService].
+func (c *cartService) VerifyGuestCartID(cartID string) (string, error) {
+	id, signature, ok := strings.Cut(cartID, ".")
+	if !ok || id == "" || !hmac.Equal([]byte(signature), []byte(c.signGuestCartID(id))) {
+		return "", errors.New("invalid cart id")
+	}
+
+	return id, nil
+}
+
+// MergeGuestCart implements [ICartService]. Quantities of products in both
+// carts are added up and capped at the available stock and the maximum per
+// order. The guest cart is removed in the same Redis transaction, so a merge
+// started twice, from sign-in and from the first cart request, adds the guest
+// items once.
+func (c *cartService) MergeGuestCart(ctx context.Context, cartID string, userID int64) error {
+	guestID, err := c.VerifyGuestCartID(cartID)
+	if err != nil {
+		log.Errorf("[CartService-1] MergeGuestCart: %v", err)
+		return err
+	}
+
+	guestOwner := entities.CartOwner{GuestID: guestID}
+	userOwner := entities.CartOwner{UserID: userID}
+
+	guestItems, err := c.cartRepository.GetCart(ctx, guestOwner)
+	if err != nil {
+		log.Errorf("[CartService-2] MergeGuestCart: %v", err)
+		return err
+	}
+	if len(guestItems) == 0 {
+		return nil
+	}
+
+	productIDs := []int64{}
+	for _, item := range guestItems {
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	products, err := c.repoProduct.GetByIDs(ctx, productIDs)
+	if err != nil {
+		log.Errorf("[CartService-3] MergeGuestCart: %v", err)
+		return err
+	}
+
+	productMap := map[int64]entities.ProductEntity{}
+	for _, product := range products {
+		productMap[product.ID] = product
+	}
+
+	err = c.cartRepository.MergeCart(ctx, guestOwner, userOwner, func(guestItems, userItems []entities.CartItem) []entities.CartItem {
+		return mergeCartItems(guestItems, userItems, productMap)
+	})
+	if err != nil {
+		log.Errorf("[CartService-4] MergeGuestCart: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// mergeCartItems adds the guest items to the customer's. A quantity added up
+// is capped at the stock and the maximum per order, rounded down to the
+// quantity step, but never below what the customer already had; the cart
+// detail reports the stock shortage if even that is too much.
+func mergeCartItems(guestItems, userItems []entities.CartItem, products map[int64]entities.ProductEntity) []entities.CartItem {
+	merged := append([]entities.CartItem{}, userItems...)
+
+	for _, guestItem := range guestItems {
+		found := false
+		for i, userItem := range merged {
+			if userItem.ProductID != guestItem.ProductID {
+				continue
+			}
+
+			found = true
+			quantity := userItem.Quantity + guestItem.Quantity
+			if product, ok := products[guestItem.ProductID]; ok {
+				available := int64(product.Stock)
+				if product.Selling.MaxQuantity > 0 {
+					available = min(available, product.Selling.MaxQuantity)
+				}
+				if quantity > available {
+					available -= available % product.Selling.Step()
+					quantity = max(available, userItem.Quantity)
+				}
+			}
+			merged[i].Quantity = quantity
+			break
+		}
+
+		if !found {
+			merged = append(merged, guestItem)
+		}
+	}
+
+	return merged
+}
 
+func (c *cartService) signGuestCartID(id string) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(id))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
 }
 
-func NewCartService(cartRepository repository.ICartRepository) ICartService {
+func NewCartService(cartRepository repository.ICartRepository, repoProduct repository.IProductRepository, purchaseLimitService IPurchaseLimitService, secret string) ICartService {
 	return &cartService{
-		cartRepository: cartRepository,
+		cartRepository:       cartRepository,
+		repoProduct:          repoProduct,
+		purchaseLimitService: purchaseLimitService,
+		secret:               []byte(secret),
 	}
 }
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"product-service/internal/core/domain/entities"
+	"reflect"
+	"testing"
+)
+
+func TestMergeCartItems(t *testing.T) {
+	products := map[int64]entities.ProductEntity{
+		1: {ID: 1, Stock: 10},
+		2: {ID: 2, Stock: 100, Selling: entities.SellingEntity{MaxQuantity: 6}},
+		3: {ID: 3, Stock: 1100, Selling: entities.SellingEntity{SellBy: entities.SellByMeasure, QuantityStep: 250}},
+		4: {ID: 4, Stock: 2},
+	}
+
+	tests := []struct {
+		name  string
+		guest []entities.CartItem
+		user  []entities.CartItem
+		want  []entities.CartItem
+	}{
+		{
+			name:  "guest items are added after the customer's",
+			guest: []entities.CartItem{{ProductID: 2, Quantity: 1}},
+			user:  []entities.CartItem{{ProductID: 1, Quantity: 2}},
+			want:  []entities.CartItem{{ProductID: 1, Quantity: 2}, {ProductID: 2, Quantity: 1}},
+		},
+		{
+			name:  "quantities add up",
+			guest: []entities.CartItem{{ProductID: 1, Quantity: 3}},
+			user:  []entities.CartItem{{ProductID: 1, Quantity: 2}},
+			want:  []entities.CartItem{{ProductID: 1, Quantity: 5}},
+		},
+		{
+			name:  "capped at the stock",
+			guest: []entities.CartItem{{ProductID: 1, Quantity: 8}},
+			user:  []entities.CartItem{{ProductID: 1, Quantity: 4}},
+			want:  []entities.CartItem{{ProductID: 1, Quantity: 10}},
+		},
+		{
+			name:  "capped at the maximum per order",
+			guest: []entities.CartItem{{ProductID: 2, Quantity: 4}},
+			user:  []entities.CartItem{{ProductID: 2, Quantity: 4}},
+			want:  []entities.CartItem{{ProductID: 2, Quantity: 6}},
+		},
+		{
+			name:  "capped down to the quantity step",
+			guest: []entities.CartItem{{ProductID: 3, Quantity: 500}},
+			user:  []entities.CartItem{{ProductID: 3, Quantity: 750}},
+			want:  []entities.CartItem{{ProductID: 3, Quantity: 1000}},
+		},
+		{
+			name:  "never below what the customer had",
+			guest: []entities.CartItem{{ProductID: 4, Quantity: 1}},
+			user:  []entities.CartItem{{ProductID: 4, Quantity: 3}},
+			want:  []entities.CartItem{{ProductID: 4, Quantity: 3}},
+		},
+		{
+			name:  "unknown products add up uncapped",
+			guest: []entities.CartItem{{ProductID: 9, Quantity: 50}},
+			user:  []entities.CartItem{{ProductID: 9, Quantity: 50}},
+			want:  []entities.CartItem{{ProductID: 9, Quantity: 100}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := append([]entities.CartItem{}, tt.user...)
+			got := mergeCartItems(tt.guest, user, products)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeCartItems() = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(user, tt.user) {
+				t.Errorf("mergeCartItems() changed the customer's items to %+v", user)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"product-service/internal/adapter/repository"
+	"product-service/internal/core/domain/entities"
+	"time"
+
+	"github.com/labstack/gommon/log"
+)
+
+type ICatalogCacheService interface {
+	Lookup(ctx context.Context, scope, key string) (*entities.CatalogCacheEntity, int64, error)
+	Store(ctx context.Context, scope string, version int64, key string, entry entities.CatalogCacheEntity) error
+	Invalidate(ctx context.Context, scopes ...string) error
+}
+
+type catalogCacheService struct {
+	repo repository.ICatalogCacheRepository
+	ttl  time.Duration
+}
+
+// Lookup implements [ICatalogCacheService]. It returns the cached response,
+// nil on a miss, and the version of the scope to store a fresh response
+// under.
+func (c *catalogCacheService) Lookup(ctx context.Context, scope, key string) (*entities.CatalogCacheEntity, int64, error) {
+	version, err := c.repo.Version(ctx, scope)
+	if err != nil {
+		log.Errorf("[CatalogCacheService-1] Lookup: %v", err)
+		return nil, 0, err
+	}
+
+	entry, err := c.repo.Get(ctx, scope, version, key)
+	if err != nil {
+		log.Errorf("[CatalogCacheService-2] Lookup: %v", err)
+		return nil, version, err
+	}
+
+	return entry, version, nil
+}
+
+// Store implements [ICatalogCacheService].
+func (c *catalogCacheService) Store(ctx context.Context, scope string, version int64, key string, entry entities.CatalogCacheEntity) error {
+	return c.repo.Set(ctx, scope, version, key, entry, c.ttl)
+}
+
+// Invalidate implements [ICatalogCacheService].
+func (c *catalogCacheService) Invalidate(ctx context.Context, scopes ...string) error {
+	return c.repo.Invalidate(ctx, scopes...)
+}
+
+// NewCatalogCacheEntry wraps a response body. The ETag is derived from the
+// body, so a response rebuilt with the same content keeps its ETag.
+func NewCatalogCacheEntry(body []byte, contentType string) entities.CatalogCacheEntity {
+	sum := sha256.Sum256(body)
+	return entities.CatalogCacheEntity{
+		Body:         body,
+		ContentType:  contentType,
+		ETag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+		LastModified: time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+func NewCatalogCacheService(repo repository.ICatalogCacheRepository, ttl time.Duration) ICatalogCacheService {
+	return &catalogCacheService{
+		repo: repo,
+		ttl:  ttl,
+	}
+}
@@ -0,0 +1,395 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-service/internal/adapter/message"
+	"product-service/internal/adapter/repository"
+	"product-service/internal/core/domain/entities"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/gommon/log"
+)
+
+var (
+	ErrInvalidAttributeCode     = errors.New("code must start with a lowercase letter and contain only lowercase letters, digits and underscores")
+	ErrInvalidAttributeType     = errors.New("type must be one of enum, number or boolean")
+	ErrAttributeOptionsRequired = errors.New("an enum attribute needs at least one option")
+	ErrAttributeCodeTaken       = errors.New("the code is already used by this category, a parent or a subcategory")
+	ErrUnknownAttribute         = errors.New("attribute is not defined for the category")
+	ErrInvalidAttributeValue    = errors.New("invalid attribute value")
+	ErrAttributeRequired        = errors.New("attribute is required")
+)
+
+// attributeCodePattern keeps codes safe to use as a field name in the
+// search index.
+var attributeCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+type ICategoryAttributeService interface {
+	GetByCategoryID(ctx context.Context, categoryID int64) ([]entities.CategoryAttributeEntity, error)
+	GetByCategorySlug(ctx context.Context, slug string) ([]entities.CategoryAttributeEntity, error)
+	Create(ctx context.Context, req entities.CategoryAttributeEntity) (*entities.CategoryAttributeEntity, error)
+	Update(ctx context.Context, req entities.CategoryAttributeEntity) (*entities.CategoryAttributeEntity, error)
+	Delete(ctx context.Context, categoryID, id int64) error
+}
+
+type categoryAttributeService struct {
+	repo              repository.ICategoryAttributeRepository
+	repoCat           repository.ICategoryRepository
+	repoProduct       repository.IProductRepository
+	publisherRabbitMQ message.IPublishRabbitMQ
+}
+
+// GetByCategoryID implements [ICategoryAttributeService]. It returns the
+// attributes that apply to the category, including those inherited from its
+// parents.
+func (c *categoryAttributeService) GetByCategoryID(ctx context.Context, categoryID int64) ([]entities.CategoryAttributeEntity, error) {
+	category, err := c.repoCat.GetCategoryByID(ctx, categoryID)
+	if err != nil {
+		log.Errorf("[CategoryAttributeService-1] GetByCategoryID: %v", err)
+		return nil, err
+	}
+
+	return c.GetByCategorySlug(ctx, category.Slug)
+}
+
+// GetByCategorySlug implements [ICategoryAttributeService].
+func (c *categoryAttributeService) GetByCategorySlug(ctx context.Context, slug string) ([]entities.CategoryAttributeEntity, error) {
+	categories, err := c.repoCat.GetAllNodes(ctx)
+	if err != nil {
+		log.Errorf("[CategoryAttributeService-1] GetByCategorySlug: %v", err)
+		return nil, err
+	}
+
+	tree := newCategoryTree(categories)
+	if _, ok := tree.bySlug[slug]; !ok {
+		return nil, errors.New("404")
+	}
+
+	return categorySchema(ctx, c.repo, tree, slug)
+}
+
+// Create implements [ICategoryAttributeService].
+func (c *categoryAttributeService) Create(ctx context.Context, req entities.CategoryAttributeEntity) (*entities.CategoryAttributeEntity, error) {
+	if !attributeCodePattern.MatchString(req.Code) {
+		return nil, ErrInvalidAttributeCode
+	}
+	if err := checkAttributeDefinition(&req); err != nil {
+		return nil, err
+	}
+
+	categories, err := c.repoCat.GetAllNodes(ctx)
+	if err != nil {
+		log.Errorf("[CategoryAttributeService-1] Create: %v", err)
+		return nil, err
+	}
+
+	tree := newCategoryTree(categories)
+	category, ok := tree.byID[req.CategoryID]
+	if !ok {
+		return nil, errors.New("404")
+	}
+
+	// a code may only exist once along every path through the tree, so no
+	// product sees two definitions of it
+	categoryIDs := []int64{}
+	for _, breadcrumb := range tree.breadcrumbs(category.Slug) {
+		categoryIDs = append(categoryIDs, breadcrumb.ID)
+	}
+	for _, descendant := range tree.subtree(category.ID)[1:] {
+		categoryIDs = append(categoryIDs, descendant.ID)
+	}
+
+	related, err := c.repo.GetByCategoryIDs(ctx, categoryIDs)
+	if err != nil {
+		log.Errorf("[CategoryAttributeService-2] Create: %v", err)
+		return nil, err
+	}
+	for _, attribute := range related {
+		if attribute.Code == req.Code {
+			return nil, ErrAttributeCodeTaken
+		}
+	}
+
+	req.ID, err = c.repo.Create(ctx, req)
+	if err != nil {
+		log.Errorf("[CategoryAttributeService-3] Create: %v", err)
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+// Update implements [ICategoryAttributeService]. Removing an enum option
+// does not touch the products that use it; they fail validation on their
+// next edit.
+func (c *categoryAttributeService) Update(ctx context.Context, req entities.CategoryAttributeEntity) (*entities.CategoryAttributeEntity, error) {
+	current, err := c.repo.GetByID(ctx, req.CategoryID, req.ID)
+	if err != nil {
+		log.Errorf("[CategoryAttributeService-1] Update: %v", err)
+		return nil, err
+	}
+
+	req.Code = current.Code
+	req.Type = current.Type
+	if err := checkAttributeDefinition(&req); err != nil {
+		return nil, err
+	}
+
+	if err := c.repo.Update(ctx, req); err != nil {
+		log.Errorf("[CategoryAttributeService-2] Update: %v", err)
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+// Delete implements [ICategoryAttributeService]. Products of the category
+// and its subcategories lose their value and are sent to the indexing queue
+// again, so the attribute disappears from search as well.
+func (c *categoryAttributeService) Delete(ctx context.Context, categoryID, id int64) error {
+	attribute, err := c.repo.GetByID(ctx, categoryID, id)
+	if err != nil {
+		log.Errorf("[CategoryAttributeService-1] Delete: %v", err)
+		return err
+	}
+
+	categories, err := c.repoCat.GetAllNodes(ctx)
+	if err != nil {
+		log.Errorf("[CategoryAttributeService-2] Delete: %v", err)
+		return err
+	}
+
+	categorySlugs := []string{}
+	for _, category := range newCategoryTree(categories).subtree(categoryID) {
+		categorySlugs = append(categorySlugs, category.Slug)
+	}
+
+	productIDs, err := c.repo.Delete(ctx, *attribute, categorySlugs)
+	if err != nil {
+		log.Errorf("[CategoryAttributeService-3] Delete: %v", err)
+		return err
+	}
+
+	for _, productID := range productIDs {
+		product, err := c.repoProduct.GetByID(ctx, productID)
+		if err != nil {
+			log.Errorf("[CategoryAttributeService-4] Delete: %v", err)
+			continue
+		}
+
+		if err := c.publisherRabbitMQ.PublishProductToQueue(*product); err != nil {
+			log.Errorf("[CategoryAttributeService-5] Delete: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// checkAttributeDefinition validates the type and cleans up the options of
+// an attribute; only enums keep options.
+func checkAttributeDefinition(req *entities.CategoryAttributeEntity) error {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Unit = strings.TrimSpace(req.Unit)
+
+	switch req.Type {
+	case entities.AttributeTypeEnum:
+		options := []string{}
+		seen := map[string]bool{}
+		for _, option := range req.Options {
+			option = strings.TrimSpace(option)
+			if option == "" || seen[strings.ToLower(option)] {
+				continue
+			}
+			seen[strings.ToLower(option)] = true
+			options = append(options, option)
+		}
+		if len(options) == 0 {
+			return ErrAttributeOptionsRequired
+		}
+		req.Options = options
+	case entities.AttributeTypeNumber, entities.AttributeTypeBoolean:
+		req.Options = []string{}
+	default:
+		return ErrInvalidAttributeType
+	}
+
+	return nil
+}
+
+// categorySchema returns the attributes that apply to the products of a
+// category: those of its root first, down to its own. Attributes of its
+// parents are marked inherited.
+func categorySchema(ctx context.Context, repo repository.ICategoryAttributeRepository, tree *categoryTree, slug string) ([]entities.CategoryAttributeEntity, error) {
+	depth := map[int64]int{}
+	categoryIDs := []int64{}
+	for i, breadcrumb := range tree.breadcrumbs(slug) {
+		depth[breadcrumb.ID] = i
+		categoryIDs = append(categoryIDs, breadcrumb.ID)
+	}
+
+	schema, err := repo.GetByCategoryIDs(ctx, categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(schema, func(i, j int) bool {
+		return depth[schema[i].CategoryID] < depth[schema[j].CategoryID]
+	})
+	for i := range schema {
+		schema[i].Inherited = schema[i].CategoryID != tree.bySlug[slug].ID
+	}
+	return schema, nil
+}
+
+// validateAttributes checks values against schema and returns them in their
+// stored form: enum values spelled as the option, numbers as float64. A nil
+// value removes the attribute. With dropUnknown, values of attributes outside
+// the schema are left out instead of rejected, which is how values saved for
+// another category are handled.
+func validateAttributes(schema []entities.CategoryAttributeEntity, values map[string]interface{}, dropUnknown bool) (map[string]interface{}, error) {
+	byCode := map[string]entities.CategoryAttributeEntity{}
+	for _, attribute := range schema {
+		byCode[attribute.Code] = attribute
+	}
+
+	result := map[string]interface{}{}
+	for code, value := range values {
+		attribute, ok := byCode[code]
+		if !ok {
+			if dropUnknown {
+				continue
+			}
+			return nil, fmt.Errorf("%w: %s", ErrUnknownAttribute, code)
+		}
+		if value == nil {
+			continue
+		}
+
+		value, ok = attributeValue(attribute, value)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s must be %s", ErrInvalidAttributeValue, code, attributeExpectation(attribute))
+		}
+		result[code] = value
+	}
+
+	for _, attribute := range schema {
+		if _, ok := result[attribute.Code]; attribute.IsRequired && !ok {
+			return nil, fmt.Errorf("%w: %s", ErrAttributeRequired, attribute.Code)
+		}
+	}
+
+	return result, nil
+}
+
+func attributeValue(attribute entities.CategoryAttributeEntity, value interface{}) (interface{}, bool) {
+	switch attribute.Type {
+	case entities.AttributeTypeEnum:
+		text, ok := value.(string)
+		if !ok {
+			return nil, false
+		}
+		for _, option := range attribute.Options {
+			if strings.EqualFold(strings.TrimSpace(text), option) {
+				return option, true
+			}
+		}
+	case entities.AttributeTypeNumber:
+		switch number := value.(type) {
+		case float64:
+			return number, true
+		case int:
+			return float64(number), true
+		case int64:
+			return float64(number), true
+		}
+	case entities.AttributeTypeBoolean:
+		flag, ok := value.(bool)
+		return flag, ok
+	}
+
+	return nil, false
+}
+
+func attributeExpectation(attribute entities.CategoryAttributeEntity) string {
+	switch attribute.Type {
+	case entities.AttributeTypeEnum:
+		return "one of " + strings.Join(attribute.Options, ", ")
+	case entities.AttributeTypeNumber:
+		return "a number"
+	}
+	return "true or false"
+}
+
+// attributeFilters keeps the filters on filterable attributes of schema and
+// spells their values the way they are indexed.
+func attributeFilters(schema []entities.CategoryAttributeEntity, filters map[string][]string) map[string][]string {
+	result := map[string][]string{}
+	for _, attribute := range schema {
+		values, ok := filters[attribute.Code]
+		if !ok || !attribute.IsFilterable {
+			continue
+		}
+
+		for _, value := range values {
+			var indexed interface{}
+			switch attribute.Type {
+			case entities.AttributeTypeNumber:
+				number, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					continue
+				}
+				indexed = number
+			case entities.AttributeTypeBoolean:
+				flag, err := strconv.ParseBool(value)
+				if err != nil {
+					continue
+				}
+				indexed = flag
+			default:
+				indexed = value
+			}
+
+			if stored, ok := attributeValue(attribute, indexed); ok {
+				result[attribute.Code] = append(result[attribute.Code], fmt.Sprint(stored))
+			}
+		}
+	}
+
+	return result
+}
+
+// specifications pairs the values of a product with their definitions, in
+// schema order.
+func specifications(schema []entities.CategoryAttributeEntity, values map[string]interface{}) []entities.ProductAttributeEntity {
+	result := []entities.ProductAttributeEntity{}
+	for _, attribute := range schema {
+		value, ok := values[attribute.Code]
+		if !ok {
+			continue
+		}
+
+		result = append(result, entities.ProductAttributeEntity{
+			Code:  attribute.Code,
+			Name:  attribute.Name,
+			Type:  attribute.Type,
+			Value: value,
+			Unit:  attribute.Unit,
+		})
+	}
+
+	return result
+}
+
+func NewCategoryAttributeService(repo repository.ICategoryAttributeRepository, repoCat repository.ICategoryRepository, repoProduct repository.IProductRepository, publisherRabbitMQ message.IPublishRabbitMQ) ICategoryAttributeService {
+	return &categoryAttributeService{
+		repo:              repo,
+		repoCat:           repoCat,
+		repoProduct:       repoProduct,
+		publisherRabbitMQ: publisherRabbitMQ,
+	}
+}
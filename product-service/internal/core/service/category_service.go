@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"product-service/internal/adapter/message"
 	"product-service/internal/adapter/repository"
 	"product-service/internal/core/domain/entities"
 	"product-service/utils/conv"
@@ -18,15 +19,22 @@ type ICategoryService interface {
 	GetCategoryBySlug(ctx context.Context, slug string) (*entities.CategoryEntity, error)
 	CreateCategory(ctx context.Context, req entities.CategoryEntity) error
 	UpdateCategory(ctx context.Context, req entities.CategoryEntity) error
-	DeleteCategory(ctx context.Context, id int64) error
+	DeleteCategory(ctx context.Context, id int64, reassignTo int64) error
 
 	GetAllPublished(ctx context.Context) ([]entities.CategoryEntity, error)
+	GetPublishedBySlugs(ctx context.Context, slugs []string) ([]entities.CategoryEntity, error)
+	GetPublishedByIDs(ctx context.Context, ids []int64) ([]entities.CategoryEntity, error)
+	GetPublishedByParentIDs(ctx context.Context, parentIDs []int64) ([]entities.CategoryEntity, error)
+	GetTree(ctx context.Context) ([]entities.CategoryTreeEntity, error)
 }
 
 //struct
 
 type categoryService struct {
-	repo repository.ICategoryRepository
+	repo                repository.ICategoryRepository
+	repoProduct         repository.IProductRepository
+	publisherRabbitMQ   message.IPublishRabbitMQ
+	catalogCacheService ICatalogCacheService
 }
 
 // GetAllPublished implements [ICategoryService].
@@ -34,8 +42,64 @@ func (c *categoryService) GetAllPublished(ctx context.Context) ([]entities.Categ
 	return c.repo.GetAllPublished(ctx)
 }
 
+// GetPublishedBySlugs implements [ICategoryService].
+func (c *categoryService) GetPublishedBySlugs(ctx context.Context, slugs []string) ([]entities.CategoryEntity, error) {
+	return c.repo.GetPublishedBySlugs(ctx, slugs)
+}
+
+// GetPublishedByIDs implements [ICategoryService].
+func (c *categoryService) GetPublishedByIDs(ctx context.Context, ids []int64) ([]entities.CategoryEntity, error) {
+	return c.repo.GetPublishedByIDs(ctx, ids)
+}
+
+// GetPublishedByParentIDs implements [ICategoryService].
+func (c *categoryService) GetPublishedByParentIDs(ctx context.Context, parentIDs []int64) ([]entities.CategoryEntity, error) {
+	return c.repo.GetPublishedByParentIDs(ctx, parentIDs)
+}
+
+// GetTree implements [ICategoryService].
+func (c *categoryService) GetTree(ctx context.Context) ([]entities.CategoryTreeEntity, error) {
+	categories, err := c.repo.GetAllNodes(ctx)
+	if err != nil {
+		log.Errorf("[CategoryService-1] GetTree: %v", err)
+		return nil, err
+	}
+
+	counts, err := c.repo.CountPublishedProducts(ctx)
+	if err != nil {
+		log.Errorf("[CategoryService-2] GetTree: %v", err)
+		return nil, err
+	}
+
+	tree := newCategoryTree(categories).published(nil, counts, map[int64]bool{})
+	if len(tree) == 0 {
+		return nil, errors.New("404")
+	}
+
+	return tree, nil
+}
+
+// validateParent loads the hierarchy and checks the new parent of a category.
+func (c *categoryService) validateParent(ctx context.Context, categoryID int64, parentID *int64) error {
+	if parentID == nil {
+		return nil
+	}
+
+	categories, err := c.repo.GetAllNodes(ctx)
+	if err != nil {
+		return err
+	}
+
+	return newCategoryTree(categories).validateParent(categoryID, parentID)
+}
+
 // CreateCategory implements [ICategoryService].
 func (c *categoryService) CreateCategory(ctx context.Context, req entities.CategoryEntity) error {
+	if err := c.validateParent(ctx, 0, req.ParentID); err != nil {
+		log.Errorf("[CategoryService-1] CreateCategory: %v", err)
+		return err
+	}
+
 	slug := conv.GenerateSlug(req.Name)
 	// GetBySlug
 	result, err := c.repo.GetCategoryBySlug(ctx, slug)
@@ -43,7 +107,11 @@ func (c *categoryService) CreateCategory(ctx context.Context, req entities.Categ
 		if err.Error() == "404" {
 			log.Errorf("[CategoryService-1] CreateCategory: %v", err)
 			req.Slug = slug
-			return c.repo.CreateCategory(ctx, req)
+			if err := c.repo.CreateCategory(ctx, req); err != nil {
+				return err
+			}
+			c.invalidateCatalog(ctx)
+			return nil
 		}
 		return err
 	}
@@ -61,12 +129,79 @@ func (c *categoryService) CreateCategory(ctx context.Context, req entities.Categ
 		log.Errorf("[CategoryService-3] CreateCategory: %v", err)
 		return err
 	}
+
+	c.invalidateCatalog(ctx)
+	return nil
+}
+
+// DeleteCategory implements [ICategoryService]. A category with products can
+// only be deleted when reassignTo names the category they move to; one with
+// subcategories has to be emptied first.
+func (c *categoryService) DeleteCategory(ctx context.Context, id int64, reassignTo int64) error {
+	category, err := c.repo.GetCategoryByID(ctx, id)
+	if err != nil {
+		log.Errorf("[CategoryService-1] DeleteCategory: %v", err)
+		return err
+	}
+
+	categories, err := c.repo.GetAllNodes(ctx)
+	if err != nil {
+		log.Errorf("[CategoryService-2] DeleteCategory: %v", err)
+		return err
+	}
+
+	tree := newCategoryTree(categories)
+	if len(tree.children[id]) > 0 {
+		log.Errorf("[CategoryService-3] DeleteCategory: %v", ErrCategoryHasChildren)
+		return ErrCategoryHasChildren
+	}
+
+	reassignSlug := ""
+	if reassignTo != 0 {
+		target, ok := tree.byID[reassignTo]
+		if !ok || target.ID == id {
+			log.Errorf("[CategoryService-4] DeleteCategory: %v", ErrInvalidReassignTarget)
+			return ErrInvalidReassignTarget
+		}
+		reassignSlug = target.Slug
+	}
+
+	productIDs, err := c.repo.GetIndexedProductIDs(ctx, category.Slug)
+	if err != nil {
+		log.Errorf("[CategoryService-5] DeleteCategory: %v", err)
+		return err
+	}
+
+	if err := c.repo.DeleteCategory(ctx, id, reassignSlug); err != nil {
+		log.Errorf("[CategoryService-6] DeleteCategory: %v", err)
+		if err.Error() == "304" {
+			return ErrCategoryHasProducts
+		}
+		return err
+	}
+
+	go c.republishProducts(productIDs)
+	c.invalidateCatalog(ctx)
+
 	return nil
 }
 
-// DeleteCategory implements [ICategoryService].
-func (c *categoryService) DeleteCategory(ctx context.Context, id int64) error {
-	return c.repo.DeleteCategory(ctx, id)
+// republishProducts sends the products to the indexing queue again after
+// their category changed. It runs detached from the request, so a large
+// category does not hold up the admin.
+func (c *categoryService) republishProducts(productIDs []int64) {
+	ctx := context.Background()
+	for _, productID := range productIDs {
+		product, err := c.repoProduct.GetByID(ctx, productID)
+		if err != nil {
+			log.Errorf("[CategoryService-1] republishProducts: product %d: %v", productID, err)
+			continue
+		}
+
+		if err := c.publisherRabbitMQ.PublishProductToQueue(*product); err != nil {
+			log.Errorf("[CategoryService-2] republishProducts: product %d: %v", productID, err)
+		}
+	}
 }
 
 // GetAll implements [ICategoryService].
@@ -92,6 +227,11 @@ func (c *categoryService) UpdateCategory(ctx context.Context, req entities.Categ
 		return err
 	}
 
+	if err := c.validateParent(ctx, req.ID, req.ParentID); err != nil {
+		log.Errorf("[CategoryService-1] EditCategory : %v", err)
+		return err
+	}
+
 	if slug != result.Slug {
 		resSlug, err := c.repo.GetCategoryBySlug(ctx, slug)
 		if err != nil && err.Error() != "404" {
@@ -106,19 +246,45 @@ func (c *categoryService) UpdateCategory(ctx context.Context, req entities.Categ
 		}
 	}
 
+	// the category name and slug are part of every product document
+	var productIDs []int64
+	if slug != result.Slug || req.Name != result.Name {
+		productIDs, err = c.repo.GetIndexedProductIDs(ctx, result.Slug)
+		if err != nil {
+			log.Errorf("[CategoryService-4] EditCategory : %v", err)
+			return err
+		}
+	}
+
 	req.Slug = slug
 	err = c.repo.UpdateCategory(ctx, req)
 	if err != nil {
-		log.Errorf("[CategoryService-4] EditCategory : %v", err)
+		log.Errorf("[CategoryService-5] EditCategory : %v", err)
 		return err
 	}
 
+	if len(productIDs) > 0 {
+		go c.republishProducts(productIDs)
+	}
+	c.invalidateCatalog(ctx)
+
 	return nil
 
 }
 
-func NewCategoryService(repo repository.ICategoryRepository) ICategoryService {
+// invalidateCatalog drops the cached public category and product lists;
+// product lists show the category names.
+func (c *categoryService) invalidateCatalog(ctx context.Context) {
+	if err := c.catalogCacheService.Invalidate(ctx, entities.CatalogScopeCategories, entities.CatalogScopeProducts); err != nil {
+		log.Errorf("[CategoryService-1] invalidateCatalog: %v", err)
+	}
+}
+
+func NewCategoryService(repo repository.ICategoryRepository, repoProduct repository.IProductRepository, publisherRabbitMQ message.IPublishRabbitMQ, catalogCacheService ICatalogCacheService) ICategoryService {
 	return &categoryService{
-		repo: repo,
+		repo:                repo,
+		repoProduct:         repoProduct,
+		publisherRabbitMQ:   publisherRabbitMQ,
+		catalogCacheService: catalogCacheService,
 	}
 }
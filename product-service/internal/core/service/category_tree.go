@@ -0,0 +1,218 @@
+package service
+
+import (
+	"errors"
+	"product-service/internal/core/domain/entities"
+)
+
+var (
+	ErrCategoryCycle          = errors.New("a category cannot be moved under itself or one of its subcategories")
+	ErrParentCategoryNotFound = errors.New("parent category not found")
+	ErrCategoryHasProducts    = errors.New("category still has products, choose a category to move them to")
+	ErrCategoryHasChildren    = errors.New("category still has subcategories, move or delete them first")
+	ErrInvalidReassignTarget  = errors.New("products can only be moved to another existing category")
+)
+
+// categoryTree indexes the flat category list by id, slug and parent. Every
+// walk keeps track of visited categories, so rows that already form a cycle
+// cannot make it loop.
+type categoryTree struct {
+	byID     map[int64]entities.CategoryEntity
+	bySlug   map[string]entities.CategoryEntity
+	children map[int64][]entities.CategoryEntity
+	roots    []entities.CategoryEntity
+}
+
+func newCategoryTree(categories []entities.CategoryEntity) *categoryTree {
+	tree := &categoryTree{
+		byID:     map[int64]entities.CategoryEntity{},
+		bySlug:   map[string]entities.CategoryEntity{},
+		children: map[int64][]entities.CategoryEntity{},
+	}
+
+	for _, category := range categories {
+		tree.byID[category.ID] = category
+		tree.bySlug[category.Slug] = category
+	}
+
+	for _, category := range categories {
+		if category.ParentID == nil {
+			tree.roots = append(tree.roots, category)
+			continue
+		}
+		// a category whose parent was deleted is shown at the top level
+		if _, ok := tree.byID[*category.ParentID]; !ok {
+			tree.roots = append(tree.roots, category)
+			continue
+		}
+		tree.children[*category.ParentID] = append(tree.children[*category.ParentID], category)
+	}
+
+	return tree
+}
+
+// descendantSlugs returns the slugs of the given categories and all their
+// published subcategories. Unknown slugs are kept so they still match
+// nothing rather than everything.
+func (t *categoryTree) descendantSlugs(slugs []string) []string {
+	result := []string{}
+	visited := map[string]bool{}
+
+	queue := []entities.CategoryEntity{}
+	for _, slug := range slugs {
+		category, ok := t.bySlug[slug]
+		if !ok {
+			if !visited[slug] {
+				visited[slug] = true
+				result = append(result, slug)
+			}
+			continue
+		}
+		queue = append(queue, category)
+	}
+
+	for len(queue) > 0 {
+		category := queue[0]
+		queue = queue[1:]
+		if visited[category.Slug] {
+			continue
+		}
+		visited[category.Slug] = true
+		result = append(result, category.Slug)
+
+		for _, child := range t.children[category.ID] {
+			if child.Status == entities.PublishedStatus {
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	return result
+}
+
+// breadcrumbs returns the path from the root category down to slug.
+func (t *categoryTree) breadcrumbs(slug string) []entities.BreadcrumbEntity {
+	path := []entities.BreadcrumbEntity{}
+
+	category, ok := t.bySlug[slug]
+	visited := map[int64]bool{}
+	for ok && !visited[category.ID] {
+		visited[category.ID] = true
+		path = append([]entities.BreadcrumbEntity{{
+			ID:   category.ID,
+			Name: category.Name,
+			Slug: category.Slug,
+		}}, path...)
+
+		if category.ParentID == nil {
+			break
+		}
+		category, ok = t.byID[*category.ParentID]
+	}
+
+	return path
+}
+
+// ancestorSlugs returns slug and the slugs of all categories above it. An
+// unknown slug is returned on its own.
+func (t *categoryTree) ancestorSlugs(slug string) []string {
+	slugs := []string{}
+	for _, crumb := range t.breadcrumbs(slug) {
+		slugs = append(slugs, crumb.Slug)
+	}
+	if len(slugs) == 0 && slug != "" {
+		slugs = append(slugs, slug)
+	}
+
+	return slugs
+}
+
+// subtree returns the category with the given id and all its descendants,
+// published or not.
+func (t *categoryTree) subtree(id int64) []entities.CategoryEntity {
+	result := []entities.CategoryEntity{}
+	visited := map[int64]bool{}
+
+	category, ok := t.byID[id]
+	if !ok {
+		return result
+	}
+
+	queue := []entities.CategoryEntity{category}
+	for len(queue) > 0 {
+		category := queue[0]
+		queue = queue[1:]
+		if visited[category.ID] {
+			continue
+		}
+		visited[category.ID] = true
+		result = append(result, category)
+		queue = append(queue, t.children[category.ID]...)
+	}
+
+	return result
+}
+
+// validateParent checks that categoryID may be placed under parentID: the
+// parent exists and is neither the category itself nor one of its
+// descendants. A zero categoryID is a category that does not exist yet.
+func (t *categoryTree) validateParent(categoryID int64, parentID *int64) error {
+	if parentID == nil {
+		return nil
+	}
+
+	parent, ok := t.byID[*parentID]
+	if !ok {
+		return ErrParentCategoryNotFound
+	}
+
+	visited := map[int64]bool{}
+	for ok && !visited[parent.ID] {
+		if parent.ID == categoryID {
+			return ErrCategoryCycle
+		}
+		visited[parent.ID] = true
+
+		if parent.ParentID == nil {
+			break
+		}
+		parent, ok = t.byID[*parent.ParentID]
+	}
+
+	return nil
+}
+
+// published builds the nested tree of published categories below parentID
+// (nil for the roots). A hidden category hides its whole subtree.
+func (t *categoryTree) published(parentID *int64, counts map[string]int64, visited map[int64]bool) []entities.CategoryTreeEntity {
+	level := t.roots
+	if parentID != nil {
+		level = t.children[*parentID]
+	}
+
+	nodes := []entities.CategoryTreeEntity{}
+	for _, category := range level {
+		if category.Status != entities.PublishedStatus || visited[category.ID] {
+			continue
+		}
+		visited[category.ID] = true
+
+		node := entities.CategoryTreeEntity{
+			ID:           category.ID,
+			ParentID:     category.ParentID,
+			Name:         category.Name,
+			Icon:         category.Icon,
+			Slug:         category.Slug,
+			ProductCount: counts[category.Slug],
+			Children:     t.published(&category.ID, counts, visited),
+		}
+
+		node.TotalProduct = node.ProductCount
+		for _, child := range node.Children {
+			node.TotalProduct += child.TotalProduct
+		}
+		nodes = append(nodes, node)
+	}
+
+	return nodes
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"errors"
+	"product-service/internal/core/domain/entities"
+	"reflect"
+	"testing"
+)
+
+// testCategories is
+//
+//	1 sayur
+//	├── 2 sayur-daun
+//	│   └── 3 bayam
+//	└── 4 sayur-akar (unpublished)
+//	5 buah
+//	6 loop-a <-> 7 loop-b, a cycle already in the data
+func testCategories() []entities.CategoryEntity {
+	return []entities.CategoryEntity{
+		{ID: 1, Slug: "sayur", Status: entities.PublishedStatus},
+		{ID: 2, Slug: "sayur-daun", ParentID: ptrInt64(1), Status: entities.PublishedStatus},
+		{ID: 3, Slug: "bayam", ParentID: ptrInt64(2), Status: entities.PublishedStatus},
+		{ID: 4, Slug: "sayur-akar", ParentID: ptrInt64(1), Status: entities.UnpublishedStatus},
+		{ID: 5, Slug: "buah", Status: entities.PublishedStatus},
+		{ID: 6, Slug: "loop-a", ParentID: ptrInt64(7), Status: entities.PublishedStatus},
+		{ID: 7, Slug: "loop-b", ParentID: ptrInt64(6), Status: entities.PublishedStatus},
+	}
+}
+
+func TestCategoryTreeValidateParent(t *testing.T) {
+	tree := newCategoryTree(testCategories())
+
+	tests := []struct {
+		name       string
+		categoryID int64
+		parentID   *int64
+		wantErr    error
+	}{
+		{name: "to the top level", categoryID: 3},
+		{name: "under another root", categoryID: 2, parentID: ptrInt64(5)},
+		{name: "under a sibling", categoryID: 4, parentID: ptrInt64(2)},
+		{name: "a new category", categoryID: 0, parentID: ptrInt64(3)},
+		{name: "under itself", categoryID: 2, parentID: ptrInt64(2), wantErr: ErrCategoryCycle},
+		{name: "under its child", categoryID: 2, parentID: ptrInt64(3), wantErr: ErrCategoryCycle},
+		{name: "under its grandchild", categoryID: 1, parentID: ptrInt64(3), wantErr: ErrCategoryCycle},
+		{name: "under a missing parent", categoryID: 2, parentID: ptrInt64(99), wantErr: ErrParentCategoryNotFound},
+		{name: "under an existing cycle", categoryID: 5, parentID: ptrInt64(6)},
+		{name: "into an existing cycle", categoryID: 6, parentID: ptrInt64(7), wantErr: ErrCategoryCycle},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tree.validateParent(tt.categoryID, tt.parentID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateParent() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCategoryTreeDescendantSlugs(t *testing.T) {
+	tree := newCategoryTree(testCategories())
+
+	tests := []struct {
+		name  string
+		slugs []string
+		want  []string
+	}{
+		{name: "published subcategories only", slugs: []string{"sayur"}, want: []string{"sayur", "sayur-daun", "bayam"}},
+		{name: "a leaf", slugs: []string{"bayam"}, want: []string{"bayam"}},
+		{name: "overlapping selection", slugs: []string{"sayur-daun", "bayam"}, want: []string{"sayur-daun", "bayam"}},
+		{name: "unknown slugs are kept", slugs: []string{"daging", "daging"}, want: []string{"daging"}},
+		{name: "a cycle ends", slugs: []string{"loop-a"}, want: []string{"loop-a", "loop-b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tree.descendantSlugs(tt.slugs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("descendantSlugs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCategoryTreeAncestorSlugs(t *testing.T) {
+	tree := newCategoryTree(testCategories())
+
+	tests := []struct {
+		name string
+		slug string
+		want []string
+	}{
+		{name: "root first", slug: "bayam", want: []string{"sayur", "sayur-daun", "bayam"}},
+		{name: "a root", slug: "buah", want: []string{"buah"}},
+		{name: "unknown", slug: "daging", want: []string{"daging"}},
+		{name: "empty", slug: "", want: []string{}},
+		{name: "a cycle ends", slug: "loop-a", want: []string{"loop-b", "loop-a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tree.ancestorSlugs(tt.slug); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ancestorSlugs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
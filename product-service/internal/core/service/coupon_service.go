@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"product-service/internal/adapter/repository"
+	"product-service/internal/core/domain/entities"
+	"strings"
+	"time"
+
+	"github.com/labstack/gommon/log"
+)
+
+// couponAlphabet leaves out characters that are easy to misread (0/O, 1/I/L).
+const couponAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+type ICouponService interface {
+	GetAll(ctx context.Context, query entities.QueryStringEntity) ([]entities.CouponEntity, int64, int64, error)
+	GetByID(ctx context.Context, id int64) (*entities.CouponEntity, error)
+	Create(ctx context.Context, req entities.CouponEntity) error
+	GenerateBulk(ctx context.Context, req entities.CouponBulkEntity) ([]string, error)
+	Update(ctx context.Context, req entities.CouponEntity) error
+	Delete(ctx context.Context, id int64) error
+	GetRedemptions(ctx context.Context, couponID int64) ([]entities.CouponRedemptionEntity, error)
+
+	ApplyToCart(ctx context.Context, userID int64, segment, code string) (*entities.CartCouponEntity, error)
+	RemoveFromCart(ctx context.Context, userID int64) error
+	Redeem(ctx context.Context, userID int64, code, orderCode string, subtotal float64) (*entities.CouponRedemptionEntity, error)
+	ReleaseRedemption(ctx context.Context, userID int64, orderCode string) error
+}
+
+type couponService struct {
+	repo             repository.ICouponRepository
+	cartRepo         repository.ICartRepository
+	promotionService IPromotionService
+}
+
+// GetAll implements [ICouponService].
+func (c *couponService) GetAll(ctx context.Context, query entities.QueryStringEntity) ([]entities.CouponEntity, int64, int64, error) {
+	return c.repo.GetAll(ctx, query)
+}
+
+// GetByID implements [ICouponService].
+func (c *couponService) GetByID(ctx context.Context, id int64) (*entities.CouponEntity, error) {
+	return c.repo.GetByID(ctx, id)
+}
+
+// Create implements [ICouponService].
+func (c *couponService) Create(ctx context.Context, req entities.CouponEntity) error {
+	req.Code = normalizeCouponCode(req.Code)
+	if err := validateCoupon(req); err != nil {
+		log.Errorf("[CouponService-1] Create: %v", err)
+		return err
+	}
+
+	existing, err := c.repo.GetExistingCodes(ctx, []string{req.Code})
+	if err != nil {
+		log.Errorf("[CouponService-2] Create: %v", err)
+		return err
+	}
+	if len(existing) > 0 {
+		err = errors.New("coupon code already exists")
+		log.Errorf("[CouponService-3] Create: %v", err)
+		return err
+	}
+
+	return c.repo.Create(ctx, req)
+}
+
+// GenerateBulk implements [ICouponService]. Codes are drawn at random and
+// checked against the table, retrying the collisions a few times before
+// giving up.
+func (c *couponService) GenerateBulk(ctx context.Context, req entities.CouponBulkEntity) ([]string, error) {
+	prefix := normalizeCouponCode(req.Prefix)
+	req.Coupon.Code = prefix + strings.Repeat("X", req.Length)
+	if err := validateCoupon(req.Coupon); err != nil {
+		log.Errorf("[CouponService-1] GenerateBulk: %v", err)
+		return nil, err
+	}
+
+	codes := map[string]bool{}
+	for attempt := 0; attempt < 5 && len(codes) < req.Quantity; attempt++ {
+		candidates := []string{}
+		for len(codes)+len(candidates) < req.Quantity {
+			code, err := randomCouponCode(prefix, req.Length)
+			if err != nil {
+				log.Errorf("[CouponService-2] GenerateBulk: %v", err)
+				return nil, err
+			}
+			if !codes[code] {
+				candidates = append(candidates, code)
+			}
+		}
+
+		existing, err := c.repo.GetExistingCodes(ctx, candidates)
+		if err != nil {
+			log.Errorf("[CouponService-3] GenerateBulk: %v", err)
+			return nil, err
+		}
+		taken := map[string]bool{}
+		for _, code := range existing {
+			taken[code] = true
+		}
+
+		for _, code := range candidates {
+			if !taken[code] {
+				codes[code] = true
+			}
+		}
+	}
+
+	if len(codes) < req.Quantity {
+		err := errors.New("not enough unique codes available, use a longer code length")
+		log.Errorf("[CouponService-4] GenerateBulk: %v", err)
+		return nil, err
+	}
+
+	result := []string{}
+	coupons := []entities.CouponEntity{}
+	for code := range codes {
+		coupon := req.Coupon
+		coupon.Code = code
+		coupons = append(coupons, coupon)
+		result = append(result, code)
+	}
+
+	if err := c.repo.CreateBulk(ctx, coupons); err != nil {
+		log.Errorf("[CouponService-5] GenerateBulk: %v", err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Update implements [ICouponService].
+func (c *couponService) Update(ctx context.Context, req entities.CouponEntity) error {
+	req.Code = normalizeCouponCode(req.Code)
+	if err := validateCoupon(req); err != nil {
+		log.Errorf("[CouponService-1] Update: %v", err)
+		return err
+	}
+
+	current, err := c.repo.GetByCode(ctx, req.Code)
+	if err != nil && err.Error() != "404" {
+		log.Errorf("[CouponService-2] Update: %v", err)
+		return err
+	}
+	if current != nil && current.ID != req.ID {
+		err = errors.New("coupon code already exists")
+		log.Errorf("[CouponService-3] Update: %v", err)
+		return err
+	}
+
+	return c.repo.Update(ctx, req)
+}
+
+// Delete implements [ICouponService].
+func (c *couponService) Delete(ctx context.Context, id int64) error {
+	return c.repo.Delete(ctx, id)
+}
+
+// GetRedemptions implements [ICouponService].
+func (c *couponService) GetRedemptions(ctx context.Context, couponID int64) ([]entities.CouponRedemptionEntity, error) {
+	if _, err := c.repo.GetByID(ctx, couponID); err != nil {
+		log.Errorf("[CouponService-1] GetRedemptions: %v", err)
+		return nil, err
+	}
+
+	return c.repo.GetRedemptions(ctx, couponID)
+}
+
+// ApplyToCart implements [ICouponService]. The cart is priced with the
+// promotions of the customer's segment, as at checkout.
+func (c *couponService) ApplyToCart(ctx context.Context, userID int64, segment, code string) (*entities.CartCouponEntity, error) {
+	code = normalizeCouponCode(code)
+	coupon, err := c.repo.GetByCode(ctx, code)
+	if err != nil {
+		log.Errorf("[CouponService-1] ApplyToCart: %v", err)
+		return nil, err
+	}
+
+	items, err := c.cartRepo.GetCart(ctx, entities.CartOwner{UserID: userID})
+	if err != nil {
+		log.Errorf("[CouponService-2] ApplyToCart: %v", err)
+		return nil, err
+	}
+	if len(items) == 0 {
+		err = errors.New("cart is empty")
+		log.Errorf("[CouponService-3] ApplyToCart: %v", err)
+		return nil, err
+	}
+
+	subtotal, err := c.cartSubtotal(ctx, segment, items)
+	if err != nil {
+		log.Errorf("[CouponService-4] ApplyToCart: %v", err)
+		return nil, err
+	}
+
+	userRedemptions, err := c.repo.CountRedemptions(ctx, coupon.ID, userID)
+	if err != nil {
+		log.Errorf("[CouponService-5] ApplyToCart: %v", err)
+		return nil, err
+	}
+
+	if err := coupon.Check(time.Now(), subtotal, userRedemptions); err != nil {
+		log.Errorf("[CouponService-6] ApplyToCart: %v", err)
+		return nil, err
+	}
+
+	if err := c.cartRepo.SetCoupon(ctx, userID, coupon.Code); err != nil {
+		log.Errorf("[CouponService-7] ApplyToCart: %v", err)
+		return nil, err
+	}
+
+	discount := coupon.Discount(subtotal)
+	return &entities.CartCouponEntity{
+		Code:     coupon.Code,
+		Subtotal: subtotal,
+		Discount: discount,
+		Total:    subtotal - discount,
+	}, nil
+}
+
+// RemoveFromCart implements [ICouponService].
+func (c *couponService) RemoveFromCart(ctx context.Context, userID int64) error {
+	return c.cartRepo.RemoveCoupon(ctx, userID)
+}
+
+// Redeem implements [ICouponService]. The subtotal is the one order-service
+// priced the order with, so the coupon and the order agree on the amount.
+func (c *couponService) Redeem(ctx context.Context, userID int64, code string, orderCode string, subtotal float64) (*entities.CouponRedemptionEntity, error) {
+	result, err := c.repo.Redeem(ctx, normalizeCouponCode(code), userID, orderCode, subtotal)
+	if err != nil {
+		log.Errorf("[CouponService-1] Redeem: %v", err)
+		return nil, err
+	}
+
+	if err := c.cartRepo.RemoveCoupon(ctx, userID); err != nil {
+		log.Errorf("[CouponService-2] Redeem: %v", err)
+	}
+
+	return result, nil
+}
+
+// ReleaseRedemption implements [ICouponService].
+func (c *couponService) ReleaseRedemption(ctx context.Context, userID int64, orderCode string) error {
+	return c.repo.ReleaseRedemption(ctx, userID, orderCode)
+}
+
+// cartSubtotal is what the customer pays for the items after promotions,
+// before shipping; minimum spend and percentage coupons work on this amount.
+func (c *couponService) cartSubtotal(ctx context.Context, segment string, items []entities.CartItem) (float64, error) {
+	priced, err := c.promotionService.PriceCart(ctx, entities.CartPriceRequestEntity{CustomerSegment: segment, Items: items})
+	if err != nil {
+		return 0, err
+	}
+
+	return priced.Subtotal - priced.LineDiscount - priced.OrderDiscount, nil
+}
+
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func randomCouponCode(prefix string, length int) (string, error) {
+	var sb strings.Builder
+	sb.WriteString(prefix)
+	max := big.NewInt(int64(len(couponAlphabet)))
+	for i := 0; i < length; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(couponAlphabet[n.Int64()])
+	}
+
+	return sb.String(), nil
+}
+
+func validateCoupon(req entities.CouponEntity) error {
+	if req.Code == "" || len(req.Code) > 40 {
+		return errors.New("code must be between 1 and 40 characters")
+	}
+
+	switch req.DiscountType {
+	case entities.CouponDiscountPercentage:
+		if req.Value <= 0 || req.Value > 100 {
+			return errors.New("percentage value must be between 1 and 100")
+		}
+	case entities.CouponDiscountFixed:
+		if req.Value <= 0 {
+			return errors.New("fixed value must be greater than 0")
+		}
+	default:
+		return errors.New("unknown discount type")
+	}
+
+	if req.StartAt != nil && req.EndAt != nil && !req.EndAt.After(*req.StartAt) {
+		return errors.New("end_at must be after start_at")
+	}
+
+	return nil
+}
+
+func NewCouponService(repo repository.ICouponRepository, cartRepo repository.ICartRepository, promotionService IPromotionService) ICouponService {
+	return &couponService{
+		repo:             repo,
+		cartRepo:         cartRepo,
+		promotionService: promotionService,
+	}
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"product-service/internal/core/domain/entities"
+	"time"
+
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	// homeFeedSize is the length of the home feed when no limit is asked.
+	homeFeedSize = 5
+	// homeFeedMax caps the limit a client can ask for.
+	homeFeedMax = 20
+	// homeFeedViews is how many recent views decide the favourite categories.
+	homeFeedViews = 20
+	// homeFeedCategories is how many of those categories the feed uses.
+	homeFeedCategories = 3
+	// homeFeedPurchases is how many earlier purchases the feed builds on.
+	homeFeedPurchases = 20
+)
+
+// feedSource is one list of candidates for the home feed, best first.
+type feedSource struct {
+	reason     string
+	productIDs []int64
+}
+
+// RecordView implements [IRecommendationService].
+func (r *recommendationService) RecordView(ctx context.Context, userID, productID int64) error {
+	return r.repoViewed.Add(ctx, userID, productID, time.Now())
+}
+
+// GetRecentlyViewed implements [IRecommendationService]. Products that are
+// no longer published are left out.
+func (r *recommendationService) GetRecentlyViewed(ctx context.Context, userID int64, limit int) ([]entities.ProductEntity, error) {
+	productIDs, err := r.repoViewed.Get(ctx, userID, limit)
+	if err != nil {
+		log.Errorf("[RecommendationService-1] GetRecentlyViewed: %v", err)
+		return nil, err
+	}
+
+	return r.publishedInOrder(ctx, productIDs)
+}
+
+// ClearRecentlyViewed implements [IRecommendationService].
+func (r *recommendationService) ClearRecentlyViewed(ctx context.Context, userID int64) error {
+	return r.repoViewed.Clear(ctx, userID)
+}
+
+// GetHomeFeed implements [IRecommendationService]. For a customer it blends,
+// in turns, best sellers of the categories viewed recently, products bought
+// together with earlier purchases and products on promotion, leaving out
+// what the customer just viewed or bought. Anonymous visitors (userID 0)
+// get the promotions only. Best sellers of the whole shop fill up the rest,
+// so every feed is complete and the same inputs give the same feed.
+func (r *recommendationService) GetHomeFeed(ctx context.Context, userID int64, limit int) ([]entities.RelatedProductEntity, error) {
+	if limit <= 0 {
+		limit = homeFeedSize
+	}
+	if limit > homeFeedMax {
+		limit = homeFeedMax
+	}
+
+	since := time.Now().Add(-r.window)
+	excludeIDs := []int64{}
+	sources := []feedSource{}
+
+	if userID != 0 {
+		viewedIDs, err := r.repoViewed.Get(ctx, userID, homeFeedViews)
+		if err != nil {
+			log.Errorf("[RecommendationService-1] GetHomeFeed: %v", err)
+			return nil, err
+		}
+
+		purchasedIDs, err := r.repo.GetPurchasedIDs(ctx, userID, homeFeedPurchases)
+		if err != nil {
+			log.Errorf("[RecommendationService-2] GetHomeFeed: %v", err)
+			return nil, err
+		}
+		excludeIDs = append(append(excludeIDs, viewedIDs...), purchasedIDs...)
+
+		categorySlugs, err := r.viewedCategories(ctx, viewedIDs)
+		if err != nil {
+			log.Errorf("[RecommendationService-3] GetHomeFeed: %v", err)
+			return nil, err
+		}
+
+		if len(categorySlugs) > 0 {
+			bestSellerIDs, err := r.repo.GetBestSellerIDs(ctx, categorySlugs, since, excludeIDs, limit)
+			if err != nil {
+				log.Errorf("[RecommendationService-4] GetHomeFeed: %v", err)
+				return nil, err
+			}
+			sources = append(sources, feedSource{reason: entities.RelatedReasonViewedCategory, productIDs: bestSellerIDs})
+		}
+
+		relatedIDs, err := r.repo.GetRelatedToIDs(ctx, purchasedIDs, excludeIDs, limit)
+		if err != nil {
+			log.Errorf("[RecommendationService-5] GetHomeFeed: %v", err)
+			return nil, err
+		}
+		sources = append(sources, feedSource{reason: entities.RelatedReasonBoughtTogether, productIDs: relatedIDs})
+	}
+
+	promotedIDs, err := r.promotedIDs(ctx, since, excludeIDs, limit)
+	if err != nil {
+		log.Errorf("[RecommendationService-6] GetHomeFeed: %v", err)
+		return nil, err
+	}
+	sources = append(sources, feedSource{reason: entities.RelatedReasonPromotion, productIDs: promotedIDs})
+
+	feedIDs, reasons := blendFeed(sources, excludeIDs, limit)
+
+	if len(feedIDs) < limit {
+		bestSellerIDs, err := r.repo.GetBestSellerIDs(ctx, nil, since, append(excludeIDs, feedIDs...), limit-len(feedIDs))
+		if err != nil {
+			log.Errorf("[RecommendationService-7] GetHomeFeed: %v", err)
+			return nil, err
+		}
+
+		for _, productID := range bestSellerIDs {
+			feedIDs = append(feedIDs, productID)
+			reasons[productID] = entities.RelatedReasonBestSeller
+		}
+	}
+
+	products, err := r.publishedInOrder(ctx, feedIDs)
+	if err != nil {
+		log.Errorf("[RecommendationService-8] GetHomeFeed: %v", err)
+		return nil, err
+	}
+
+	results := []entities.RelatedProductEntity{}
+	for _, product := range products {
+		results = append(results, entities.RelatedProductEntity{
+			Product: product,
+			Reason:  reasons[product.ID],
+		})
+	}
+
+	return results, nil
+}
+
+// viewedCategories returns the categories of the viewed products, the one
+// viewed last first.
+func (r *recommendationService) viewedCategories(ctx context.Context, viewedIDs []int64) ([]string, error) {
+	products, err := r.publishedInOrder(ctx, viewedIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	categorySlugs := []string{}
+	seen := map[string]bool{}
+	for _, product := range products {
+		if seen[product.CategorySlug] {
+			continue
+		}
+		seen[product.CategorySlug] = true
+		categorySlugs = append(categorySlugs, product.CategorySlug)
+		if len(categorySlugs) == homeFeedCategories {
+			break
+		}
+	}
+
+	return categorySlugs, nil
+}
+
+// promotedIDs returns the products of the active promotions in promotion
+// priority: the products a promotion targets, then best sellers of the
+// categories a promotion targets. Promotions on the whole cart are left out.
+func (r *recommendationService) promotedIDs(ctx context.Context, since time.Time, excludeIDs []int64, limit int) ([]int64, error) {
+	promotions, err := r.repoPromotion.GetActive(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	targetIDs := []int64{}
+	categorySlugs := []string{}
+	for _, promotion := range promotions {
+		switch {
+		case promotion.ProductID != nil:
+			targetIDs = append(targetIDs, *promotion.ProductID)
+		case promotion.CategorySlug != "":
+			categorySlugs = append(categorySlugs, promotion.CategorySlug)
+		}
+	}
+
+	products, err := r.publishedInOrder(ctx, targetIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := map[int64]bool{}
+	for _, productID := range excludeIDs {
+		excluded[productID] = true
+	}
+
+	productIDs := []int64{}
+	for _, product := range products {
+		if !excluded[product.ID] {
+			productIDs = append(productIDs, product.ID)
+		}
+	}
+
+	if len(categorySlugs) > 0 && len(productIDs) < limit {
+		bestSellerIDs, err := r.repo.GetBestSellerIDs(ctx, categorySlugs, since, append(excludeIDs, productIDs...), limit-len(productIDs))
+		if err != nil {
+			return nil, err
+		}
+		productIDs = append(productIDs, bestSellerIDs...)
+	}
+
+	return productIDs, nil
+}
+
+// publishedInOrder loads the products in the order of productIDs, leaving
+// out variants and products that are not published.
+func (r *recommendationService) publishedInOrder(ctx context.Context, productIDs []int64) ([]entities.ProductEntity, error) {
+	products, err := r.repoProduct.GetByIDs(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := map[int64]entities.ProductEntity{}
+	for _, product := range products {
+		byID[product.ID] = product
+	}
+
+	results := []entities.ProductEntity{}
+	for _, productID := range productIDs {
+		product, ok := byID[productID]
+		if !ok || product.ParentID != nil || !product.IsPublished() {
+			continue
+		}
+		results = append(results, product)
+		delete(byID, productID)
+	}
+
+	return results, nil
+}
+
+// blendFeed takes one product from each source in turn until limit
+// products are chosen or the sources run out. A product already chosen or
+// excluded is skipped; the first source it came from gives its reason.
+func blendFeed(sources []feedSource, excludeIDs []int64, limit int) ([]int64, map[int64]string) {
+	taken := map[int64]bool{}
+	for _, productID := range excludeIDs {
+		taken[productID] = true
+	}
+
+	feedIDs := []int64{}
+	reasons := map[int64]string{}
+	next := make([]int, len(sources))
+	for len(feedIDs) < limit {
+		added := false
+		for i, source := range sources {
+			for next[i] < len(source.productIDs) {
+				productID := source.productIDs[next[i]]
+				next[i]++
+				if taken[productID] {
+					continue
+				}
+
+				taken[productID] = true
+				feedIDs = append(feedIDs, productID)
+				reasons[productID] = source.reason
+				added = true
+				break
+			}
+
+			if len(feedIDs) == limit {
+				break
+			}
+		}
+
+		if !added {
+			break
+		}
+	}
+
+	return feedIDs, reasons
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"product-service/internal/adapter/message"
+	"product-service/internal/adapter/repository"
+	"product-service/internal/core/domain/entities"
+	"time"
+
+	"github.com/labstack/gommon/log"
+)
+
+type IPriceService interface {
+	GetPriceLists(ctx context.Context, productID int64) ([]entities.PriceListEntity, error)
+	CreatePriceList(ctx context.Context, req entities.PriceListEntity) error
+	DeletePriceList(ctx context.Context, productID, priceListID int64) error
+	GetPriceHistory(ctx context.Context, productID int64, at *time.Time) ([]entities.PriceHistoryEntity, error)
+
+	ApplyScheduledPrices(ctx context.Context) (int, error)
+}
+
+type priceService struct {
+	repo              repository.IPriceRepository
+	repoProduct       repository.IProductRepository
+	publisherRabbitMQ message.IPublishRabbitMQ
+	wishlistService   IWishlistService
+}
+
+// GetPriceLists implements [IPriceService].
+func (p *priceService) GetPriceLists(ctx context.Context, productID int64) ([]entities.PriceListEntity, error) {
+	return p.repo.GetPriceLists(ctx, productID)
+}
+
+// CreatePriceList implements [IPriceService].
+func (p *priceService) CreatePriceList(ctx context.Context, req entities.PriceListEntity) error {
+	if req.EndAt != nil && !req.EndAt.After(req.StartAt) {
+		err := errors.New("end_at must be after start_at")
+		log.Errorf("[PriceService-1] CreatePriceList: %v", err)
+		return err
+	}
+
+	if _, err := p.repo.CreatePriceList(ctx, req); err != nil {
+		log.Errorf("[PriceService-2] CreatePriceList: %v", err)
+		return err
+	}
+
+	// a list that is already running should not wait for the next worker tick
+	if _, err := p.ApplyScheduledPrices(ctx); err != nil {
+		log.Errorf("[PriceService-3] CreatePriceList: %v", err)
+	}
+
+	return nil
+}
+
+// DeletePriceList implements [IPriceService].
+func (p *priceService) DeletePriceList(ctx context.Context, productID, priceListID int64) error {
+	if err := p.repo.DeletePriceList(ctx, productID, priceListID); err != nil {
+		log.Errorf("[PriceService-1] DeletePriceList: %v", err)
+		return err
+	}
+
+	if _, err := p.ApplyScheduledPrices(ctx); err != nil {
+		log.Errorf("[PriceService-2] DeletePriceList: %v", err)
+	}
+
+	return nil
+}
+
+// GetPriceHistory implements [IPriceService].
+func (p *priceService) GetPriceHistory(ctx context.Context, productID int64, at *time.Time) ([]entities.PriceHistoryEntity, error) {
+	return p.repo.GetPriceHistory(ctx, productID, at)
+}
+
+// ApplyScheduledPrices implements [IPriceService].
+func (p *priceService) ApplyScheduledPrices(ctx context.Context) (int, error) {
+	changedIDs, err := p.repo.ApplyScheduledPrices(ctx, time.Now())
+	if err != nil {
+		log.Errorf("[PriceService-1] ApplyScheduledPrices: %v", err)
+	}
+
+	for _, productID := range changedIDs {
+		product, err := p.repoProduct.GetByID(ctx, productID)
+		if err != nil {
+			log.Errorf("[PriceService-2] ApplyScheduledPrices: %v", err)
+			continue
+		}
+
+		if err := p.wishlistService.NotifyProductChanged(ctx, *product); err != nil {
+			log.Errorf("[PriceService-3] ApplyScheduledPrices: %v", err)
+		}
+
+		// variants live inside their parent document in the products index
+		if product.ParentID != nil {
+			product, err = p.repoProduct.GetByID(ctx, *product.ParentID)
+			if err != nil {
+				log.Errorf("[PriceService-4] ApplyScheduledPrices: %v", err)
+				continue
+			}
+		}
+
+		if err := p.publisherRabbitMQ.PublishProductToQueue(*product); err != nil {
+			log.Errorf("[PriceService-5] ApplyScheduledPrices: %v", err)
+		}
+	}
+
+	// bundles with a computed price follow the prices of their components
+	refreshBundles(ctx, p.repoProduct, p.publisherRabbitMQ, changedIDs...)
+
+	return len(changedIDs), err
+}
+
+func NewPriceService(repo repository.IPriceRepository, repoProduct repository.IProductRepository, publisherRabbitMQ message.IPublishRabbitMQ, wishlistService IWishlistService) IPriceService {
+	return &priceService{
+		repo:              repo,
+		repoProduct:       repoProduct,
+		publisherRabbitMQ: publisherRabbitMQ,
+		wishlistService:   wishlistService,
+	}
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-service/internal/adapter/message"
+	"product-service/internal/adapter/repository"
+	"product-service/internal/core/domain/entities"
+
+	"github.com/labstack/gommon/log"
+)
+
+var (
+	ErrInvalidBundlePricing  = errors.New("pricing must be fixed or computed")
+	ErrInvalidBundleDiscount = errors.New("discount_percent must be between 0 and 100")
+	ErrBundleEmpty           = errors.New("a bundle needs at least one component")
+	ErrInvalidBundleQuantity = errors.New("component quantity must be at least 1")
+	ErrBundleComponent       = errors.New("component not allowed")
+	ErrBundleVariant         = errors.New("a variant or a product with variants cannot be a bundle")
+	ErrBundleInUse           = errors.New("product is a component of a bundle")
+)
+
+// GetBundle implements [IProductService].
+func (p *productService) GetBundle(ctx context.Context, productID int64) (*entities.ProductEntity, error) {
+	result, err := p.repo.GetByID(ctx, productID)
+	if err != nil {
+		log.Errorf("[ProductService-1] GetBundle: %v", err)
+		return nil, err
+	}
+
+	if !result.IsBundle() {
+		return nil, errors.New("404")
+	}
+
+	return result, nil
+}
+
+// SetBundle implements [IProductService]. A bundle is a top level product
+// without variants, and its components are single products that are not
+// bundles themselves, so bundles never nest.
+func (p *productService) SetBundle(ctx context.Context, productID int64, bundle entities.BundleEntity) (*entities.ProductEntity, error) {
+	if bundle.Pricing == "" {
+		bundle.Pricing = entities.BundlePricingFixed
+	}
+	if bundle.Pricing != entities.BundlePricingFixed && bundle.Pricing != entities.BundlePricingComputed {
+		return nil, ErrInvalidBundlePricing
+	}
+	if bundle.DiscountPercent < 0 || bundle.DiscountPercent >= 100 {
+		return nil, ErrInvalidBundleDiscount
+	}
+	if bundle.Pricing == entities.BundlePricingFixed {
+		bundle.DiscountPercent = 0
+	}
+	if len(bundle.Items) == 0 {
+		return nil, ErrBundleEmpty
+	}
+
+	product, err := p.repo.GetByID(ctx, productID)
+	if err != nil {
+		log.Errorf("[ProductService-1] SetBundle: %v", err)
+		return nil, err
+	}
+	if product.ParentID != nil || len(product.Child) > 0 {
+		return nil, ErrBundleVariant
+	}
+	if product.Selling.SellBy != entities.SellByItem {
+		return nil, fmt.Errorf("%w: a bundle is sold by item", ErrInvalidSelling)
+	}
+
+	bundleIDs, err := p.repo.GetBundleIDs(ctx, productID)
+	if err != nil {
+		log.Errorf("[ProductService-2] SetBundle: %v", err)
+		return nil, err
+	}
+	if len(bundleIDs) > 0 {
+		return nil, ErrBundleInUse
+	}
+
+	componentIDs := []int64{}
+	seen := map[int64]bool{}
+	for _, item := range bundle.Items {
+		if item.Quantity < 1 {
+			return nil, ErrInvalidBundleQuantity
+		}
+		if item.ComponentID == productID {
+			return nil, fmt.Errorf("%w: a bundle cannot contain itself", ErrBundleComponent)
+		}
+		if seen[item.ComponentID] {
+			return nil, fmt.Errorf("%w: product %d is listed twice", ErrBundleComponent, item.ComponentID)
+		}
+		seen[item.ComponentID] = true
+		componentIDs = append(componentIDs, item.ComponentID)
+	}
+
+	components, err := p.repo.GetByIDs(ctx, componentIDs)
+	if err != nil {
+		log.Errorf("[ProductService-3] SetBundle: %v", err)
+		return nil, err
+	}
+
+	found := map[int64]entities.ProductEntity{}
+	for _, component := range components {
+		found[component.ID] = component
+	}
+	for _, componentID := range componentIDs {
+		component, ok := found[componentID]
+		if !ok {
+			return nil, fmt.Errorf("%w: product %d not found", ErrBundleComponent, componentID)
+		}
+		if component.IsBundle() {
+			return nil, fmt.Errorf("%w: product %d is a bundle", ErrBundleComponent, componentID)
+		}
+		if component.Selling.SellBy != entities.SellByItem {
+			return nil, fmt.Errorf("%w: product %d is not sold by item", ErrBundleComponent, componentID)
+		}
+	}
+
+	if err := p.repo.SetBundle(ctx, productID, bundle); err != nil {
+		log.Errorf("[ProductService-4] SetBundle: %v", err)
+		return nil, err
+	}
+
+	p.syncIndex(ctx, productID)
+	p.invalidateCatalog(ctx)
+
+	return p.GetBundle(ctx, productID)
+}
+
+// RemoveBundle implements [IProductService].
+func (p *productService) RemoveBundle(ctx context.Context, productID int64) error {
+	if err := p.repo.RemoveBundle(ctx, productID); err != nil {
+		log.Errorf("[ProductService-1] RemoveBundle: %v", err)
+		return err
+	}
+
+	p.syncIndex(ctx, productID)
+	p.invalidateCatalog(ctx)
+
+	return nil
+}
+
+// refreshBundles derives the stock and computed prices of the bundles that
+// contain the given products again, after a change to those products, and
+// sends the bundles to the indexing queue.
+func refreshBundles(ctx context.Context, repo repository.IProductRepository, publisherRabbitMQ message.IPublishRabbitMQ, componentIDs ...int64) {
+	bundleIDs, err := repo.RefreshBundles(ctx, componentIDs)
+	if err != nil {
+		log.Errorf("[ProductService-1] refreshBundles: %v", err)
+		return
+	}
+
+	for _, bundleID := range bundleIDs {
+		bundle, err := repo.GetByID(ctx, bundleID)
+		if err != nil {
+			log.Errorf("[ProductService-2] refreshBundles: %v", err)
+			continue
+		}
+
+		if err := publisherRabbitMQ.PublishProductToQueue(*bundle); err != nil {
+			log.Errorf("[ProductService-3] refreshBundles: %v", err)
+		}
+	}
+}
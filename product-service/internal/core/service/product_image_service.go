@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"product-service/internal/adapter/message"
+	"product-service/internal/adapter/repository"
+	"product-service/internal/core/domain/entities"
+
+	"github.com/labstack/gommon/log"
+)
+
+// ErrIncompleteImageOrder is returned by Reorder when the image IDs are not
+// exactly the images of the product.
+var ErrIncompleteImageOrder = repository.ErrIncompleteImageOrder
+
+type IProductImageService interface {
+	GetByProductID(ctx context.Context, productID int64) ([]entities.ProductImageEntity, error)
+	GetByProductIDs(ctx context.Context, productIDs []int64) ([]entities.ProductImageEntity, error)
+	AddImage(ctx context.Context, req entities.ProductImageEntity) error
+	Reorder(ctx context.Context, productID int64, imageIDs []int64) error
+	SetPrimary(ctx context.Context, productID, imageID int64) error
+	Delete(ctx context.Context, productID, imageID int64) error
+}
+
+type productImageService struct {
+	repo              repository.IProductImageRepository
+	repoProduct       repository.IProductRepository
+	publisherRabbitMQ message.IPublishRabbitMQ
+}
+
+// GetByProductID implements [IProductImageService].
+func (p *productImageService) GetByProductID(ctx context.Context, productID int64) ([]entities.ProductImageEntity, error) {
+	if _, err := p.repoProduct.GetByID(ctx, productID); err != nil {
+		log.Errorf("[ProductImageService-1] GetByProductID: %v", err)
+		return nil, err
+	}
+
+	return p.repo.GetByProductID(ctx, productID)
+}
+
+// GetByProductIDs implements [IProductImageService].
+func (p *productImageService) GetByProductIDs(ctx context.Context, productIDs []int64) ([]entities.ProductImageEntity, error) {
+	return p.repo.GetByProductIDs(ctx, productIDs)
+}
+
+// AddImage implements [IProductImageService].
+func (p *productImageService) AddImage(ctx context.Context, req entities.ProductImageEntity) error {
+	if _, err := p.repo.Create(ctx, req); err != nil {
+		log.Errorf("[ProductImageService-1] AddImage: %v", err)
+		return err
+	}
+
+	p.republishProduct(ctx, req.ProductID)
+	return nil
+}
+
+// Reorder implements [IProductImageService].
+func (p *productImageService) Reorder(ctx context.Context, productID int64, imageIDs []int64) error {
+	if err := p.repo.Reorder(ctx, productID, imageIDs); err != nil {
+		log.Errorf("[ProductImageService-1] Reorder: %v", err)
+		return err
+	}
+
+	p.republishProduct(ctx, productID)
+	return nil
+}
+
+// SetPrimary implements [IProductImageService].
+func (p *productImageService) SetPrimary(ctx context.Context, productID, imageID int64) error {
+	if err := p.repo.SetPrimary(ctx, productID, imageID); err != nil {
+		log.Errorf("[ProductImageService-1] SetPrimary: %v", err)
+		return err
+	}
+
+	p.republishProduct(ctx, productID)
+	return nil
+}
+
+// Delete implements [IProductImageService].
+func (p *productImageService) Delete(ctx context.Context, productID, imageID int64) error {
+	if err := p.repo.Delete(ctx, productID, imageID); err != nil {
+		log.Errorf("[ProductImageService-1] Delete: %v", err)
+		return err
+	}
+
+	p.republishProduct(ctx, productID)
+	return nil
+}
+
+// republishProduct pushes the product with its current gallery to the
+// products index. Failures are logged only, like productService.Update.
+func (p *productImageService) republishProduct(ctx context.Context, productID int64) {
+	product, err := p.repoProduct.GetByID(ctx, productID)
+	if err != nil {
+		log.Errorf("[ProductImageService-1] republishProduct: %v", err)
+		return
+	}
+
+	if err := p.publisherRabbitMQ.PublishProductToQueue(*product); err != nil {
+		log.Errorf("[ProductImageService-2] republishProduct: %v", err)
+	}
+}
+
+func NewProductImageService(repo repository.IProductImageRepository, repoProduct repository.IProductRepository, publisherRabbitMQ message.IPublishRabbitMQ) IProductImageService {
+	return &productImageService{
+		repo:              repo,
+		repoProduct:       repoProduct,
+		publisherRabbitMQ: publisherRabbitMQ,
+	}
+}
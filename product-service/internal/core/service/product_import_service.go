@@ -0,0 +1,675 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"path/filepath"
+	"product-service/internal/adapter/repository"
+	"product-service/internal/core/domain/entities"
+	"product-service/utils/conv"
+	"product-service/utils/xlsx"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	// importMaxRows keeps a single job short enough to finish between deploys.
+	importMaxRows = 5000
+	// importProgressEvery is how many rows are processed between progress saves.
+	importProgressEvery = 100
+	// importPublishBatch is how many products go into one bulk index request.
+	importPublishBatch = 200
+	// exportBatchSize is how many top level products are read at once.
+	exportBatchSize = 500
+)
+
+var (
+	ErrInvalidImportFile = errors.New("invalid import file")
+	ErrInvalidFormat     = errors.New("format must be csv or xlsx")
+
+	skuPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+)
+
+type IProductImportService interface {
+	StartImport(ctx context.Context, userID int64, fileName string, data []byte) (*entities.ProductImportJobEntity, error)
+	GetJob(ctx context.Context, id int64) (*entities.ProductImportJobEntity, error)
+	WriteErrorReport(ctx context.Context, id int64, format string, w io.Writer) error
+	Export(ctx context.Context, format string, w io.Writer) error
+	FailUnfinished(ctx context.Context) error
+}
+
+type productImportService struct {
+	repo        repository.IProductImportJobRepository
+	repoProduct repository.IProductRepository
+	repoCat     repository.ICategoryRepository
+	repoImage   repository.IProductImageRepository
+	repoSearch  repository.ISearchRepository
+}
+
+// importRow is one data row of the file with its row number in the
+// spreadsheet, the header being row 1.
+type importRow struct {
+	number int
+	values map[string]string
+}
+
+func (r importRow) get(column string) string {
+	return strings.TrimSpace(r.values[column])
+}
+
+// StartImport implements [IProductImportService]. The file is parsed and its
+// header checked right away, so an unusable file is rejected in the request;
+// the rows are then processed in the background.
+func (p *productImportService) StartImport(ctx context.Context, userID int64, fileName string, data []byte) (*entities.ProductImportJobEntity, error) {
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(fileName)), ".")
+	records, err := readSheet(format, data)
+	if err != nil {
+		log.Errorf("[ProductImportService-1] StartImport: %v", err)
+		return nil, err
+	}
+
+	rows, err := importRows(records)
+	if err != nil {
+		log.Errorf("[ProductImportService-2] StartImport: %v", err)
+		return nil, err
+	}
+
+	job := entities.ProductImportJobEntity{
+		UserID:    userID,
+		FileName:  fileName,
+		Status:    entities.ImportStatusPending,
+		TotalRows: len(rows),
+		RowErrors: []entities.ImportRowErrorEntity{},
+	}
+
+	job.ID, err = p.repo.Create(ctx, job)
+	if err != nil {
+		log.Errorf("[ProductImportService-3] StartImport: %v", err)
+		return nil, err
+	}
+	job.CreatedAt = time.Now()
+
+	go p.run(job, rows)
+
+	return &job, nil
+}
+
+// readSheet decodes a CSV or XLSX file into its raw rows.
+func readSheet(format string, data []byte) ([][]string, error) {
+	switch format {
+	case entities.ImportFormatCSV:
+		reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+		}
+		return records, nil
+	case entities.ImportFormatXLSX:
+		records, err := xlsx.Read(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+		}
+		return records, nil
+	}
+
+	return nil, fmt.Errorf("%w: only .csv and .xlsx files are supported", ErrInvalidImportFile)
+}
+
+// importRows maps the data rows by the column names of the header, skipping
+// blank rows.
+func importRows(records [][]string) ([]importRow, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: the file is empty", ErrInvalidImportFile)
+	}
+
+	known := map[string]bool{}
+	for _, column := range entities.ProductImportColumns {
+		known[column] = true
+	}
+
+	header := []string{}
+	hasSKU := false
+	for _, column := range records[0] {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if column != "" && !known[column] {
+			return nil, fmt.Errorf("%w: unknown column %q, expected %s", ErrInvalidImportFile, column, strings.Join(entities.ProductImportColumns, ", "))
+		}
+		hasSKU = hasSKU || column == "sku"
+		header = append(header, column)
+	}
+	if !hasSKU {
+		return nil, fmt.Errorf("%w: the sku column is required", ErrInvalidImportFile)
+	}
+
+	rows := []importRow{}
+	for i, record := range records[1:] {
+		row := importRow{number: i + 2, values: map[string]string{}}
+		blank := true
+		for column, value := range record {
+			if column >= len(header) || header[column] == "" {
+				continue
+			}
+			row.values[header[column]] = value
+			blank = blank && strings.TrimSpace(value) == ""
+		}
+		if blank {
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: the file has no product rows", ErrInvalidImportFile)
+	}
+	if len(rows) > importMaxRows {
+		return nil, fmt.Errorf("%w: at most %d rows can be imported at once", ErrInvalidImportFile, importMaxRows)
+	}
+
+	return rows, nil
+}
+
+// run processes every row and indexes the changed products. It owns the job
+// until it is completed or failed.
+func (p *productImportService) run(job entities.ProductImportJobEntity, rows []importRow) {
+	ctx := context.Background()
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("[ProductImportService-1] run: job %d: %v", job.ID, r)
+			now := time.Now()
+			job.Status = entities.ImportStatusFailed
+			job.ErrorMessage = fmt.Sprintf("import stopped unexpectedly: %v", r)
+			job.FinishedAt = &now
+			if err := p.repo.Update(ctx, job); err != nil {
+				log.Errorf("[ProductImportService-2] run: %v", err)
+			}
+		}
+	}()
+
+	job.Status = entities.ImportStatusProcessing
+	if err := p.repo.Update(ctx, job); err != nil {
+		log.Errorf("[ProductImportService-3] run: %v", err)
+	}
+
+	categories, err := p.repoCat.GetAllNodes(ctx)
+	if err != nil {
+		log.Errorf("[ProductImportService-4] run: %v", err)
+		p.fail(ctx, job, err)
+		return
+	}
+	tree := newCategoryTree(categories)
+
+	seenSKUs := map[string]int{}
+	changedIDs := []int64{}
+	changedSeen := map[int64]bool{}
+	for i, row := range rows {
+		created, productID, rowErrors := p.importRow(ctx, row, tree, seenSKUs)
+		job.RowErrors = append(job.RowErrors, rowErrors...)
+		switch {
+		case productID == 0:
+			job.FailedCount++
+		case created:
+			job.CreatedCount++
+		default:
+			job.UpdatedCount++
+		}
+
+		if productID != 0 && !changedSeen[productID] {
+			changedSeen[productID] = true
+			changedIDs = append(changedIDs, productID)
+		}
+
+		job.ProcessedRows = i + 1
+		if job.ProcessedRows%importProgressEvery == 0 {
+			if err := p.repo.Update(ctx, job); err != nil {
+				log.Errorf("[ProductImportService-5] run: %v", err)
+			}
+		}
+	}
+
+	// bundles made of imported products take over their stock and prices
+	bundleIDs, err := p.repoProduct.RefreshBundles(ctx, changedIDs)
+	if err != nil {
+		log.Errorf("[ProductImportService-8] run: %v", err)
+	}
+	for _, bundleID := range bundleIDs {
+		if !changedSeen[bundleID] {
+			changedSeen[bundleID] = true
+			changedIDs = append(changedIDs, bundleID)
+		}
+	}
+
+	if err := p.publish(ctx, changedIDs); err != nil {
+		log.Errorf("[ProductImportService-6] run: %v", err)
+		job.ErrorMessage = fmt.Sprintf("products saved but search index not updated, run a reindex: %v", err)
+	}
+
+	now := time.Now()
+	job.Status = entities.ImportStatusCompleted
+	job.FinishedAt = &now
+	if err := p.repo.Update(ctx, job); err != nil {
+		log.Errorf("[ProductImportService-7] run: %v", err)
+	}
+}
+
+func (p *productImportService) fail(ctx context.Context, job entities.ProductImportJobEntity, cause error) {
+	now := time.Now()
+	job.Status = entities.ImportStatusFailed
+	job.ErrorMessage = cause.Error()
+	job.FinishedAt = &now
+	if err := p.repo.Update(ctx, job); err != nil {
+		log.Errorf("[ProductImportService-1] fail: %v", err)
+	}
+}
+
+// importRow creates or updates the product of one row. Empty cells keep the
+// current value of an existing product. It returns the id of the search
+// document that changed, which for a variant is its parent, or zero when the
+// row was not saved.
+func (p *productImportService) importRow(ctx context.Context, row importRow, tree *categoryTree, seenSKUs map[string]int) (bool, int64, []entities.ImportRowErrorEntity) {
+	rowErrors := []entities.ImportRowErrorEntity{}
+	sku := row.get("sku")
+	addError := func(field, message string) {
+		rowErrors = append(rowErrors, entities.ImportRowErrorEntity{Row: row.number, SKU: sku, Field: field, Message: message})
+	}
+
+	if !skuPattern.MatchString(sku) {
+		addError("sku", "sku is required and may only contain letters, numbers, dots, dashes and underscores (max 64)")
+		return false, 0, rowErrors
+	}
+	if firstRow, ok := seenSKUs[sku]; ok {
+		addError("sku", fmt.Sprintf("sku already used in row %d", firstRow))
+		return false, 0, rowErrors
+	}
+	seenSKUs[sku] = row.number
+
+	product := entities.ProductEntity{SKU: sku, Unit: "gram", Status: entities.ProductStatusDraft, Variant: 1}
+	existing, err := p.repoProduct.GetBySKU(ctx, sku)
+	if err != nil && err.Error() != "404" {
+		addError("sku", "could not look up the product, try again")
+		return false, 0, rowErrors
+	}
+	if existing != nil {
+		product = *existing
+		// variants of an existing product are left as they are
+		product.Child = nil
+	}
+
+	if parentSKU := row.get("parent_sku"); parentSKU != "" {
+		parent, err := p.repoProduct.GetBySKU(ctx, parentSKU)
+		switch {
+		case err != nil:
+			addError("parent_sku", "parent product not found, import it in an earlier row or file")
+		case parent.ParentID != nil:
+			addError("parent_sku", "parent product is a variant itself")
+		case parent.ID == product.ID:
+			addError("parent_sku", "a product cannot be its own parent")
+		default:
+			product.ParentID = &parent.ID
+			product.Status = parent.Status
+			if row.get("category_slug") == "" {
+				product.CategorySlug = parent.CategorySlug
+			}
+		}
+	}
+
+	if name := row.get("name"); name != "" {
+		if len([]rune(name)) > 100 {
+			addError("name", "name may be at most 100 characters")
+		}
+		product.Name = name
+	}
+
+	if value := row.get("category_slug"); value != "" {
+		category, ok := tree.bySlug[value]
+		if !ok {
+			category, ok = tree.bySlug[conv.GenerateSlug(value)]
+		}
+		if ok {
+			product.CategorySlug = category.Slug
+		} else {
+			addError("category_slug", fmt.Sprintf("category %q not found", value))
+		}
+	}
+
+	if description := row.get("description"); description != "" {
+		product.Description = description
+	}
+
+	if unit := row.get("unit"); unit != "" {
+		product.Unit = unit
+	}
+
+	if value := row.get("weight"); value != "" {
+		weight, err := parseImportInt(value)
+		if err != nil {
+			addError("weight", err.Error())
+		}
+		product.Weight = weight
+	}
+
+	if value := row.get("stock"); value != "" {
+		stock, err := parseImportInt(value)
+		if err != nil {
+			addError("stock", err.Error())
+		}
+		product.Stock = stock
+	}
+
+	if value := row.get("reguler_price"); value != "" {
+		price, err := parseImportPrice(value)
+		if err != nil || price <= 0 {
+			addError("reguler_price", "reguler_price must be a number greater than 0")
+		}
+		product.RegulerPrice = price
+	}
+
+	if value := row.get("sale_price"); value != "" {
+		price, err := parseImportPrice(value)
+		if err != nil {
+			addError("sale_price", err.Error())
+		}
+		product.SalePrice = price
+	}
+
+	if product.SalePrice > 0 && product.RegulerPrice > 0 && product.SalePrice > product.RegulerPrice {
+		addError("sale_price", "sale_price may not be higher than reguler_price")
+	}
+
+	// the status column follows the same lifecycle rules as the admin API,
+	// so an import can submit products for review but never publish them
+	status := product.Status
+	if value := row.get("status"); value != "" {
+		var err error
+		switch status, err = normalizeProductStatus(value); {
+		case err != nil:
+			addError("status", err.Error())
+		case product.ParentID != nil:
+			if status != product.Status {
+				addError("status", ErrVariantStatus.Error())
+			}
+		case existing == nil:
+			if status, err = initialProductStatus(value); err != nil {
+				addError("status", err.Error())
+			}
+			product.Status = status
+		default:
+			if err := checkTransition(product.Status, status); err != nil {
+				addError("status", err.Error())
+			}
+		}
+	}
+
+	if image := row.get("image"); image != "" {
+		if !isImportURL(image) {
+			addError("image", "image must be an http or https URL")
+		}
+		product.Image = image
+	}
+
+	gallery := []string{}
+	for _, image := range strings.Split(row.get("images"), "|") {
+		image = strings.TrimSpace(image)
+		if image == "" {
+			continue
+		}
+		if !isImportURL(image) {
+			addError("images", fmt.Sprintf("%q is not an http or https URL", image))
+			continue
+		}
+		gallery = append(gallery, image)
+	}
+
+	if existing == nil {
+		if product.Name == "" {
+			addError("name", "name is required for a new product")
+		}
+		if product.CategorySlug == "" {
+			addError("category_slug", "category_slug is required for a new product")
+		}
+		if product.RegulerPrice <= 0 && row.get("reguler_price") == "" {
+			addError("reguler_price", "reguler_price is required for a new product")
+		}
+		if product.Image == "" {
+			addError("image", "image is required for a new product")
+		}
+	}
+
+	if len(rowErrors) > 0 {
+		return false, 0, rowErrors
+	}
+
+	productID := product.ID
+	if existing == nil {
+		productID, err = p.repoProduct.Create(ctx, product)
+	} else {
+		err = p.repoProduct.Update(ctx, product)
+	}
+	if err != nil {
+		addError("", fmt.Sprintf("could not save the product: %v", err))
+		return false, 0, rowErrors
+	}
+
+	if existing != nil && product.ParentID == nil && status != product.Status {
+		applyTransition(&product, status, "")
+		if err := p.repoProduct.UpdateStatus(ctx, product); err != nil {
+			addError("status", fmt.Sprintf("product saved but status not changed: %v", err))
+		}
+	}
+
+	if err := p.addGallery(ctx, productID, product.Image, gallery); err != nil {
+		addError("images", fmt.Sprintf("product saved but images could not be added: %v", err))
+	}
+
+	documentID := productID
+	if product.ParentID != nil {
+		documentID = *product.ParentID
+	}
+
+	// a saved product counts as imported even if its gallery failed, the
+	// error is still reported
+	return existing == nil, documentID, rowErrors
+}
+
+// addGallery appends the images the product does not have yet.
+func (p *productImportService) addGallery(ctx context.Context, productID int64, primary string, gallery []string) error {
+	if len(gallery) == 0 {
+		return nil
+	}
+
+	images, err := p.repoImage.GetByProductID(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	known := map[string]bool{primary: true}
+	for _, image := range images {
+		known[image.ImageURL] = true
+	}
+
+	for _, image := range gallery {
+		if known[image] {
+			continue
+		}
+		known[image] = true
+
+		if _, err := p.repoImage.Create(ctx, entities.ProductImageEntity{ProductID: productID, ImageURL: image}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// publish writes the changed products to the search index in bulk batches.
+func (p *productImportService) publish(ctx context.Context, productIDs []int64) error {
+	batch := []entities.ProductEntity{}
+	for i, productID := range productIDs {
+		product, err := p.repoProduct.GetByID(ctx, productID)
+		if err != nil {
+			log.Errorf("[ProductImportService-1] publish: product %d: %v", productID, err)
+		} else {
+			batch = append(batch, *product)
+		}
+
+		if len(batch) == importPublishBatch || (i == len(productIDs)-1 && len(batch) > 0) {
+			if err := p.repoSearch.BulkIndex(ctx, repository.ProductIndex, batch, nil); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+
+	return nil
+}
+
+func parseImportInt(value string) (int, error) {
+	number, err := strconv.ParseFloat(strings.ReplaceAll(value, " ", ""), 64)
+	if err != nil || number < 0 || number != math.Trunc(number) || number > math.MaxInt32 {
+		return 0, errors.New("must be a whole number of 0 or more")
+	}
+	return int(number), nil
+}
+
+func parseImportPrice(value string) (float64, error) {
+	number, err := strconv.ParseFloat(strings.ReplaceAll(value, " ", ""), 64)
+	if err != nil || number < 0 {
+		return 0, errors.New("must be a number of 0 or more")
+	}
+	return number, nil
+}
+
+func isImportURL(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// GetJob implements [IProductImportService].
+func (p *productImportService) GetJob(ctx context.Context, id int64) (*entities.ProductImportJobEntity, error) {
+	return p.repo.GetByID(ctx, id)
+}
+
+// WriteErrorReport implements [IProductImportService].
+func (p *productImportService) WriteErrorReport(ctx context.Context, id int64, format string, w io.Writer) error {
+	job, err := p.repo.GetByID(ctx, id)
+	if err != nil {
+		log.Errorf("[ProductImportService-1] WriteErrorReport: %v", err)
+		return err
+	}
+
+	records := [][]string{{"row", "sku", "field", "message"}}
+	for _, rowError := range job.RowErrors {
+		records = append(records, []string{strconv.Itoa(rowError.Row), rowError.SKU, rowError.Field, rowError.Message})
+	}
+
+	return writeSheet(format, "Errors", records, w)
+}
+
+// Export implements [IProductImportService]. Every product is written in the
+// import layout, each variant on its own row below its parent.
+func (p *productImportService) Export(ctx context.Context, format string, w io.Writer) error {
+	if format != entities.ImportFormatCSV && format != entities.ImportFormatXLSX {
+		return ErrInvalidFormat
+	}
+
+	records := [][]string{entities.ProductImportColumns}
+
+	var afterID int64
+	for {
+		products, err := p.repoProduct.GetForIndex(ctx, afterID, exportBatchSize, false)
+		if err != nil {
+			log.Errorf("[ProductImportService-1] Export: %v", err)
+			return err
+		}
+		if len(products) == 0 {
+			break
+		}
+
+		for _, product := range products {
+			gallery := []string{}
+			for _, image := range product.Images {
+				if !image.IsPrimary && image.ImageURL != product.Image {
+					gallery = append(gallery, image.ImageURL)
+				}
+			}
+			records = append(records, exportRecord(product, "", gallery))
+
+			for _, child := range product.Child {
+				records = append(records, exportRecord(child, product.SKU, nil))
+			}
+		}
+		afterID = products[len(products)-1].ID
+	}
+
+	return writeSheet(format, "Products", records, w)
+}
+
+// exportRecord lays out a product in the order of ProductImportColumns.
+func exportRecord(product entities.ProductEntity, parentSKU string, gallery []string) []string {
+	return []string{
+		product.SKU,
+		parentSKU,
+		product.Name,
+		product.CategorySlug,
+		product.Description,
+		product.Unit,
+		strconv.Itoa(product.Weight),
+		strconv.Itoa(product.Stock),
+		strconv.FormatFloat(product.RegulerPrice, 'f', -1, 64),
+		strconv.FormatFloat(product.SalePrice, 'f', -1, 64),
+		product.Status,
+		product.Image,
+		strings.Join(gallery, "|"),
+	}
+}
+
+func writeSheet(format, sheetName string, records [][]string, w io.Writer) error {
+	switch format {
+	case entities.ImportFormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.WriteAll(records); err != nil {
+			return err
+		}
+		return writer.Error()
+	case entities.ImportFormatXLSX:
+		return xlsx.Write(w, sheetName, records)
+	}
+
+	return ErrInvalidFormat
+}
+
+// FailUnfinished implements [IProductImportService].
+func (p *productImportService) FailUnfinished(ctx context.Context) error {
+	count, err := p.repo.FailUnfinished(ctx, "the import was interrupted by a restart, upload the file again")
+	if err != nil {
+		log.Errorf("[ProductImportService-1] FailUnfinished: %v", err)
+		return err
+	}
+
+	if count > 0 {
+		log.Infof("[ProductImportService-2] FailUnfinished: %d interrupted import jobs marked as failed", count)
+	}
+	return nil
+}
+
+func NewProductImportService(repo repository.IProductImportJobRepository, repoProduct repository.IProductRepository, repoCat repository.ICategoryRepository, repoImage repository.IProductImageRepository, repoSearch repository.ISearchRepository) IProductImportService {
+	return &productImportService{
+		repo:        repo,
+		repoProduct: repoProduct,
+		repoCat:     repoCat,
+		repoImage:   repoImage,
+		repoSearch:  repoSearch,
+	}
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-service/internal/core/domain/entities"
+	"strings"
+	"time"
+
+	"github.com/labstack/gommon/log"
+)
+
+var (
+	ErrInvalidProductStatus    = errors.New("status must be one of Draft, In Review, Scheduled, Published or Archived")
+	ErrInvalidInitialStatus    = errors.New("a new product starts as Draft or In Review")
+	ErrInvalidStatusTransition = errors.New("status change not allowed")
+	ErrVariantStatus           = errors.New("variants follow the status of their product")
+	ErrInvalidSchedule         = errors.New("unpublish_at must be later than publish_at and now")
+)
+
+// manualTransitions are the status changes an editor makes directly. In
+// Review only moves on to Scheduled or Published through an approval, and
+// the scheduler makes the timed moves from Scheduled to Published and from
+// Published to Archived.
+var manualTransitions = map[string][]string{
+	entities.ProductStatusDraft:     {entities.ProductStatusInReview, entities.ProductStatusArchived},
+	entities.ProductStatusInReview:  {entities.ProductStatusDraft},
+	entities.ProductStatusScheduled: {entities.ProductStatusDraft},
+	entities.ProductStatusPublished: {entities.ProductStatusArchived},
+	entities.ProductStatusArchived:  {entities.ProductStatusDraft},
+}
+
+// normalizeProductStatus matches value case-insensitively against the
+// lifecycle states. The Unpublished status clients sent before the
+// lifecycle existed means Draft.
+func normalizeProductStatus(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, entities.UnpublishedStatus) {
+		return entities.ProductStatusDraft, nil
+	}
+
+	for _, status := range entities.ProductStatuses {
+		if strings.EqualFold(value, status) {
+			return status, nil
+		}
+	}
+
+	return "", ErrInvalidProductStatus
+}
+
+// initialProductStatus returns the status a new product is created in.
+func initialProductStatus(value string) (string, error) {
+	if strings.TrimSpace(value) == "" {
+		return entities.ProductStatusDraft, nil
+	}
+
+	status, err := normalizeProductStatus(value)
+	if err != nil {
+		return "", err
+	}
+	if status != entities.ProductStatusDraft && status != entities.ProductStatusInReview {
+		return "", ErrInvalidInitialStatus
+	}
+
+	return status, nil
+}
+
+// checkTransition reports whether an editor may move a product from one
+// status to another. Staying in the same status is always allowed.
+func checkTransition(from, to string) error {
+	if strings.EqualFold(from, to) {
+		return nil
+	}
+
+	for _, allowed := range manualTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s to %s", ErrInvalidStatusTransition, from, to)
+}
+
+// applyTransition moves the product to status and resets what no longer
+// holds: going back to Draft drops the approval and schedule, and a new
+// review starts without the note of the last rejection.
+func applyTransition(product *entities.ProductEntity, status, note string) {
+	product.Status = status
+
+	switch status {
+	case entities.ProductStatusDraft:
+		product.PublishAt = nil
+		product.ApprovedBy = nil
+		product.ApprovedAt = nil
+		product.ReviewNote = strings.TrimSpace(note)
+	case entities.ProductStatusInReview:
+		product.ReviewNote = ""
+	}
+}
+
+// ChangeStatus implements [IProductService].
+func (p *productService) ChangeStatus(ctx context.Context, productID int64, status, note string) (*entities.ProductEntity, error) {
+	product, err := p.repo.GetByID(ctx, productID)
+	if err != nil {
+		log.Errorf("[ProductService-1] ChangeStatus: %v", err)
+		return nil, err
+	}
+
+	if product.ParentID != nil {
+		return nil, ErrVariantStatus
+	}
+
+	status, err = normalizeProductStatus(status)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTransition(product.Status, status); err != nil {
+		return nil, err
+	}
+
+	applyTransition(product, status, note)
+	if err := p.repo.UpdateStatus(ctx, *product); err != nil {
+		log.Errorf("[ProductService-2] ChangeStatus: %v", err)
+		return nil, err
+	}
+
+	p.syncIndex(ctx, productID)
+	refreshBundles(ctx, p.repo, p.publisherRabbitMQ, productID)
+	p.invalidateCatalog(ctx)
+	return product, nil
+}
+
+// Approve implements [IProductService]. An approval without publishAt, or
+// with one that has passed, publishes the product right away.
+func (p *productService) Approve(ctx context.Context, productID, approverID int64, publishAt, unpublishAt *time.Time) (*entities.ProductEntity, error) {
+	product, err := p.repo.GetByID(ctx, productID)
+	if err != nil {
+		log.Errorf("[ProductService-1] Approve: %v", err)
+		return nil, err
+	}
+
+	if product.ParentID != nil {
+		return nil, ErrVariantStatus
+	}
+
+	if product.Status != entities.ProductStatusInReview {
+		return nil, fmt.Errorf("%w: only products in review can be approved, this one is %s", ErrInvalidStatusTransition, product.Status)
+	}
+
+	now := time.Now()
+	product.Status = entities.ProductStatusPublished
+	if publishAt != nil && publishAt.After(now) {
+		product.Status = entities.ProductStatusScheduled
+	} else {
+		publishAt = &now
+	}
+
+	if unpublishAt != nil && !unpublishAt.After(*publishAt) {
+		return nil, ErrInvalidSchedule
+	}
+
+	product.PublishAt = publishAt
+	product.UnpublishAt = unpublishAt
+	product.ApprovedBy = &approverID
+	product.ApprovedAt = &now
+	product.ReviewNote = ""
+
+	if err := p.repo.UpdateStatus(ctx, *product); err != nil {
+		log.Errorf("[ProductService-2] Approve: %v", err)
+		return nil, err
+	}
+
+	p.syncIndex(ctx, productID)
+	refreshBundles(ctx, p.repo, p.publisherRabbitMQ, productID)
+	p.invalidateCatalog(ctx)
+	return product, nil
+}
+
+// ApplySchedule implements [IProductService].
+func (p *productService) ApplySchedule(ctx context.Context) (int, error) {
+	publishedIDs, archivedIDs, err := p.repo.ApplySchedule(ctx, time.Now())
+	if err != nil {
+		log.Errorf("[ProductService-1] ApplySchedule: %v", err)
+	}
+
+	changedIDs := append(publishedIDs, archivedIDs...)
+	for _, productID := range changedIDs {
+		p.syncIndex(ctx, productID)
+	}
+	refreshBundles(ctx, p.repo, p.publisherRabbitMQ, changedIDs...)
+	if len(changedIDs) > 0 {
+		p.invalidateCatalog(ctx)
+	}
+
+	return len(publishedIDs) + len(archivedIDs), err
+}
+
+// syncIndex sends the current state of a product to the indexing queue,
+// whose consumer indexes published products and removes all others.
+func (p *productService) syncIndex(ctx context.Context, productID int64) {
+	product, err := p.GetByID(ctx, productID)
+	if err != nil {
+		log.Errorf("[ProductService-1] syncIndex: %v", err)
+		return
+	}
+
+	if err := p.publisherRabbitMQ.PublishProductToQueue(*product); err != nil {
+		log.Errorf("[ProductService-2] syncIndex: %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"errors"
+	"product-service/internal/core/domain/entities"
+	"testing"
+	"time"
+)
+
+func TestCheckTransition(t *testing.T) {
+	const (
+		draft     = entities.ProductStatusDraft
+		inReview  = entities.ProductStatusInReview
+		scheduled = entities.ProductStatusScheduled
+		published = entities.ProductStatusPublished
+		archived  = entities.ProductStatusArchived
+	)
+
+	tests := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{draft, draft, true},
+		{draft, inReview, true},
+		{draft, scheduled, false},
+		{draft, published, false},
+		{draft, archived, true},
+
+		{inReview, draft, true},
+		{inReview, inReview, true},
+		{inReview, scheduled, false},
+		{inReview, published, false},
+		{inReview, archived, false},
+
+		{scheduled, draft, true},
+		{scheduled, inReview, false},
+		{scheduled, scheduled, true},
+		{scheduled, published, false},
+		{scheduled, archived, false},
+
+		{published, draft, false},
+		{published, inReview, false},
+		{published, scheduled, false},
+		{published, published, true},
+		{published, archived, true},
+
+		{archived, draft, true},
+		{archived, inReview, false},
+		{archived, scheduled, false},
+		{archived, published, false},
+		{archived, archived, true},
+
+		{"", published, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			err := checkTransition(tt.from, tt.to)
+			if tt.allowed && err != nil {
+				t.Errorf("checkTransition() = %v, want allowed", err)
+			}
+			if !tt.allowed && !errors.Is(err, ErrInvalidStatusTransition) {
+				t.Errorf("checkTransition() = %v, want %v", err, ErrInvalidStatusTransition)
+			}
+		})
+	}
+}
+
+func TestNormalizeProductStatus(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr error
+	}{
+		{value: "Published", want: entities.ProductStatusPublished},
+		{value: " in review ", want: entities.ProductStatusInReview},
+		{value: "ARCHIVED", want: entities.ProductStatusArchived},
+		{value: "Unpublished", want: entities.ProductStatusDraft},
+		{value: "Deleted", wantErr: ErrInvalidProductStatus},
+		{value: "", wantErr: ErrInvalidProductStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := normalizeProductStatus(tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("normalizeProductStatus() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("normalizeProductStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInitialProductStatus(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr error
+	}{
+		{value: "", want: entities.ProductStatusDraft},
+		{value: "draft", want: entities.ProductStatusDraft},
+		{value: "In Review", want: entities.ProductStatusInReview},
+		{value: "Published", wantErr: ErrInvalidInitialStatus},
+		{value: "Scheduled", wantErr: ErrInvalidInitialStatus},
+		{value: "Sold", wantErr: ErrInvalidProductStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := initialProductStatus(tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("initialProductStatus() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("initialProductStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyTransition(t *testing.T) {
+	now := time.Now()
+	approver := int64(3)
+	reviewed := func() entities.ProductEntity {
+		return entities.ProductEntity{
+			Status:     entities.ProductStatusScheduled,
+			PublishAt:  &now,
+			ApprovedBy: &approver,
+			ApprovedAt: &now,
+			ReviewNote: "old note",
+		}
+	}
+
+	t.Run("back to draft drops the approval and schedule", func(t *testing.T) {
+		product := reviewed()
+		applyTransition(&product, entities.ProductStatusDraft, "  foto buram  ")
+		if product.PublishAt != nil || product.ApprovedBy != nil || product.ApprovedAt != nil {
+			t.Errorf("approval kept: %+v", product)
+		}
+		if product.ReviewNote != "foto buram" {
+			t.Errorf("ReviewNote = %q, want %q", product.ReviewNote, "foto buram")
+		}
+	})
+
+	t.Run("a new review starts without a note", func(t *testing.T) {
+		product := reviewed()
+		applyTransition(&product, entities.ProductStatusInReview, "ignored")
+		if product.ReviewNote != "" {
+			t.Errorf("ReviewNote = %q, want empty", product.ReviewNote)
+		}
+	})
+
+	t.Run("archiving keeps the rest", func(t *testing.T) {
+		product := reviewed()
+		applyTransition(&product, entities.ProductStatusArchived, "")
+		if product.Status != entities.ProductStatusArchived || product.ApprovedBy == nil || product.ReviewNote != "old note" {
+			t.Errorf("product = %+v", product)
+		}
+	})
+}
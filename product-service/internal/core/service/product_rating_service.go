@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"product-service/internal/adapter/message"
+	"product-service/internal/adapter/repository"
+	"product-service/internal/core/domain/entities"
+
+	"github.com/labstack/gommon/log"
+)
+
+type IProductRatingService interface {
+	Rate(ctx context.Context, productID, userID int64, rating int) (*entities.ProductRatingEntity, error)
+}
+
+type productRatingService struct {
+	repo                repository.IProductRatingRepository
+	repoProduct         repository.IProductRepository
+	publisherRabbitMQ   message.IPublishRabbitMQ
+	catalogCacheService ICatalogCacheService
+}
+
+// Rate implements [IProductRatingService]. Only published products can be
+// rated; a variant is rated through its parent, the product the search index
+// holds. The product is indexed again, so the rating filter and facet follow.
+func (p *productRatingService) Rate(ctx context.Context, productID, userID int64, rating int) (*entities.ProductRatingEntity, error) {
+	if rating < 1 || rating > 5 {
+		return nil, errors.New("rating must be between 1 and 5")
+	}
+
+	product, err := p.repoProduct.GetByID(ctx, productID)
+	if err != nil {
+		log.Errorf("[ProductRatingService-1] Rate: %v", err)
+		return nil, err
+	}
+	if product.ParentID != nil {
+		product, err = p.repoProduct.GetByID(ctx, *product.ParentID)
+		if err != nil {
+			log.Errorf("[ProductRatingService-2] Rate: %v", err)
+			return nil, err
+		}
+	}
+	if !product.IsPublished() {
+		return nil, errors.New("404")
+	}
+
+	result, err := p.repo.Rate(ctx, product.ID, userID, rating)
+	if err != nil {
+		log.Errorf("[ProductRatingService-3] Rate: %v", err)
+		return nil, err
+	}
+
+	product, err = p.repoProduct.GetByID(ctx, product.ID)
+	if err != nil {
+		log.Errorf("[ProductRatingService-4] Rate: %v", err)
+		return result, nil
+	}
+	if err := p.publisherRabbitMQ.PublishProductToQueue(*product); err != nil {
+		log.Errorf("[ProductRatingService-5] Rate: %v", err)
+	}
+	if err := p.catalogCacheService.Invalidate(ctx, entities.CatalogScopeProducts); err != nil {
+		log.Errorf("[ProductRatingService-6] Rate: %v", err)
+	}
+
+	return result, nil
+}
+
+func NewProductRatingService(repo repository.IProductRatingRepository, repoProduct repository.IProductRepository, publisherRabbitMQ message.IPublishRabbitMQ, catalogCacheService ICatalogCacheService) IProductRatingService {
+	return &productRatingService{
+		repo:                repo,
+		repoProduct:         repoProduct,
+		publisherRabbitMQ:   publisherRabbitMQ,
+		catalogCacheService: catalogCacheService,
+	}
+}
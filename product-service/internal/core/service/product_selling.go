@@ -0,0 +1,106 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"product-service/internal/core/domain/entities"
+)
+
+var (
+	ErrInvalidSelling       = errors.New("invalid selling rules")
+	ErrInvalidQuantityUnit  = errors.New("quantity cannot be given in this unit")
+	ErrQuantityStep         = errors.New("quantity does not match the quantity step")
+	ErrQuantityBelowMinimum = errors.New("quantity is below the minimum")
+	ErrQuantityAboveMaximum = errors.New("quantity is above the maximum")
+)
+
+// normalizeSelling checks the selling rules of product and clears what does
+// not apply to its way of selling. Estimated items are priced per PriceUnit
+// of their Weight, so the product unit has to be a unit of the same kind.
+func normalizeSelling(product entities.ProductEntity) (entities.SellingEntity, error) {
+	selling := product.Selling
+	if selling.SellBy == "" {
+		selling.SellBy = entities.SellByItem
+	}
+	if selling.QuantityStep == 0 {
+		selling.QuantityStep = 1
+	}
+	if selling.QuantityStep < 0 || selling.MinQuantity < 0 || selling.MaxQuantity < 0 {
+		return selling, fmt.Errorf("%w: quantity step and limits cannot be negative", ErrInvalidSelling)
+	}
+	if selling.MaxQuantity > 0 && selling.MaxQuantity < selling.MinQuantity {
+		return selling, fmt.Errorf("%w: max_quantity is below min_quantity", ErrInvalidSelling)
+	}
+
+	switch selling.SellBy {
+	case entities.SellByItem:
+		selling.PriceUnit, selling.QuantityUnit = "", ""
+	case entities.SellByMeasure:
+		kind := entities.UnitKind(selling.PriceUnit)
+		if kind == "" || entities.UnitKind(selling.QuantityUnit) != kind {
+			return selling, fmt.Errorf("%w: price_unit and quantity_unit must be known units of the same kind", ErrInvalidSelling)
+		}
+	case entities.SellByEstimated:
+		kind := entities.UnitKind(selling.PriceUnit)
+		if kind == "" || entities.UnitKind(product.Unit) != kind {
+			return selling, fmt.Errorf("%w: price_unit must be a known unit of the same kind as the product unit", ErrInvalidSelling)
+		}
+		if product.Weight <= 0 {
+			return selling, fmt.Errorf("%w: an estimated item needs its estimated weight", ErrInvalidSelling)
+		}
+		selling.QuantityUnit = ""
+	default:
+		return selling, fmt.Errorf("%w: sell_by must be item, measure or estimated", ErrInvalidSelling)
+	}
+
+	if product.IsBundle() && selling.SellBy != entities.SellByItem {
+		return selling, fmt.Errorf("%w: a bundle is sold by item", ErrInvalidSelling)
+	}
+
+	return selling, nil
+}
+
+// checkQuantity checks quantity against the step and limits of product.
+func checkQuantity(product entities.ProductEntity, quantity int64) error {
+	selling := product.Selling
+	unit := selling.QuantityUnit
+	if unit == "" {
+		unit = "item(s)"
+	}
+
+	if quantity%selling.Step() != 0 {
+		return fmt.Errorf("%w: order in steps of %d %s", ErrQuantityStep, selling.Step(), unit)
+	}
+	if quantity < selling.MinQuantity {
+		return fmt.Errorf("%w: order at least %d %s", ErrQuantityBelowMinimum, selling.MinQuantity, unit)
+	}
+	if selling.MaxQuantity > 0 && quantity > selling.MaxQuantity {
+		return fmt.Errorf("%w: order at most %d %s", ErrQuantityAboveMaximum, selling.MaxQuantity, unit)
+	}
+
+	return nil
+}
+
+// cartQuantity converts a quantity given in unit into the quantity unit of
+// product. An empty unit means the quantity unit itself.
+func cartQuantity(product entities.ProductEntity, quantity int64, unit string) (int64, error) {
+	if unit == "" || unit == product.Selling.QuantityUnit {
+		return quantity, nil
+	}
+	if !product.Selling.IsMeasured() {
+		return 0, fmt.Errorf("%w: the product is sold by item", ErrInvalidQuantityUnit)
+	}
+
+	converted, err := entities.ConvertQuantity(float64(quantity), unit, product.Selling.QuantityUnit)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidQuantityUnit, err)
+	}
+
+	rounded := math.Round(converted)
+	if math.Abs(converted-rounded) > 1e-9 {
+		return 0, fmt.Errorf("%w: %d %s is not a whole number of %s", ErrInvalidQuantityUnit, quantity, unit, product.Selling.QuantityUnit)
+	}
+
+	return int64(rounded), nil
+}
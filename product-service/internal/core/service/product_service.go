@@ -6,6 +6,7 @@ import (
 	"product-service/internal/adapter/message"
 	"product-service/internal/adapter/repository"
 	"product-service/internal/core/domain/entities"
+	"time"
 
 	"github.com/labstack/gommon/log"
 )
@@ -14,20 +15,34 @@ import (
 type IProductService interface {
 	GetAll(ctx context.Context, query entities.QueryStringProduct) ([]entities.ProductEntity, int64, int64, error)
 	GetByID(ctx context.Context, productID int64) (*entities.ProductEntity, error)
+	GetDetail(ctx context.Context, productID int64) (*entities.ProductEntity, error)
 	GetByIDs(ctx context.Context, productIDs []int64) ([]entities.ProductEntity, error)
 
 	Create(ctx context.Context, req entities.ProductEntity) error
 	Update(ctx context.Context, req entities.ProductEntity) error
 	Delete(ctx context.Context, productID int64) error
 
-	SearchProducts(ctx context.Context, query entities.QueryStringProduct) ([]entities.ProductEntity, int64, int64, error)
+	SearchProducts(ctx context.Context, query entities.QueryStringProduct) (*entities.ProductSearchEntity, error)
+	SearchProductsMulti(ctx context.Context, queries []entities.QueryStringProduct) ([]*entities.ProductSearchEntity, error)
+	GetVariants(ctx context.Context, parentIDs []int64) ([]entities.ProductEntity, error)
+
+	ChangeStatus(ctx context.Context, productID int64, status, note string) (*entities.ProductEntity, error)
+	Approve(ctx context.Context, productID, approverID int64, publishAt, unpublishAt *time.Time) (*entities.ProductEntity, error)
+	ApplySchedule(ctx context.Context) (int, error)
+
+	GetBundle(ctx context.Context, productID int64) (*entities.ProductEntity, error)
+	SetBundle(ctx context.Context, productID int64, bundle entities.BundleEntity) (*entities.ProductEntity, error)
+	RemoveBundle(ctx context.Context, productID int64) error
 }
 
 // struct
 type productService struct {
-	repo              repository.IProductRepository
-	repoCat           repository.ICategoryRepository
-	publisherRabbitMQ message.IPublishRabbitMQ
+	repo                repository.IProductRepository
+	repoCat             repository.ICategoryRepository
+	repoAttr            repository.ICategoryAttributeRepository
+	publisherRabbitMQ   message.IPublishRabbitMQ
+	wishlistService     IWishlistService
+	catalogCacheService ICatalogCacheService
 }
 
 // GetByIDs implements [IProductService].
@@ -35,9 +50,119 @@ func (p *productService) GetByIDs(ctx context.Context, productIDs []int64) ([]en
 	return p.repo.GetByIDs(ctx, productIDs)
 }
 
-// SearchProducts implements [IProductService].
-func (p *productService) SearchProducts(ctx context.Context, query entities.QueryStringProduct) ([]entities.ProductEntity, int64, int64, error) {
-	return p.repo.SearchProducts(ctx, query)
+// SearchProducts implements [IProductService]. A category filter includes
+// the products of all its published subcategories. Attributes can only be
+// filtered on, and get facets, when exactly one category is chosen: the
+// attributes are those that apply to that category.
+func (p *productService) SearchProducts(ctx context.Context, query entities.QueryStringProduct) (*entities.ProductSearchEntity, error) {
+	slugs := query.CategorySlugs
+	if query.CategorySlug != "" {
+		slugs = append(slugs, query.CategorySlug)
+	}
+
+	filters := query.Attributes
+	query.Attributes = nil
+	schema := []entities.CategoryAttributeEntity{}
+
+	if len(slugs) > 0 {
+		categories, err := p.repoCat.GetAllNodes(ctx)
+		if err != nil {
+			log.Errorf("[ProductService-1] SearchProducts: %v", err)
+			return nil, err
+		}
+
+		tree := newCategoryTree(categories)
+		if len(slugs) == 1 {
+			schema, err = categorySchema(ctx, p.repoAttr, tree, slugs[0])
+			if err != nil {
+				log.Errorf("[ProductService-2] SearchProducts: %v", err)
+				return nil, err
+			}
+		}
+
+		query.CategorySlug = ""
+		query.CategorySlugs = tree.descendantSlugs(slugs)
+	}
+
+	query.Attributes = attributeFilters(schema, filters)
+	query.AttributeFacets = []string{}
+	for _, attribute := range schema {
+		if attribute.IsFilterable {
+			query.AttributeFacets = append(query.AttributeFacets, attribute.Code)
+		}
+	}
+
+	result, err := p.repo.SearchProducts(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]string{}
+	for _, attribute := range schema {
+		names[attribute.Code] = attribute.Name
+	}
+	for i := range result.Facets.Attributes {
+		result.Facets.Attributes[i].Label = names[result.Facets.Attributes[i].Code]
+	}
+
+	return result, nil
+}
+
+// SearchProductsMulti implements [IProductService]. It runs several plain
+// searches at once, like the product lists of many categories; category
+// filters include subcategories as in SearchProducts, attribute filters and
+// facets are not supported.
+func (p *productService) SearchProductsMulti(ctx context.Context, queries []entities.QueryStringProduct) ([]*entities.ProductSearchEntity, error) {
+	var tree *categoryTree
+	for i := range queries {
+		slugs := queries[i].CategorySlugs
+		if queries[i].CategorySlug != "" {
+			slugs = append(slugs, queries[i].CategorySlug)
+		}
+		if len(slugs) == 0 {
+			continue
+		}
+
+		if tree == nil {
+			categories, err := p.repoCat.GetAllNodes(ctx)
+			if err != nil {
+				log.Errorf("[ProductService-1] SearchProductsMulti: %v", err)
+				return nil, err
+			}
+			tree = newCategoryTree(categories)
+		}
+
+		queries[i].CategorySlug = ""
+		queries[i].CategorySlugs = tree.descendantSlugs(slugs)
+		queries[i].Attributes = nil
+		queries[i].AttributeFacets = []string{}
+	}
+
+	return p.repo.SearchProductsMulti(ctx, queries)
+}
+
+// GetVariants implements [IProductService].
+func (p *productService) GetVariants(ctx context.Context, parentIDs []int64) ([]entities.ProductEntity, error) {
+	return p.repo.GetVariants(ctx, parentIDs)
+}
+
+// productAttributes validates the attribute values of a product against the
+// schema of its category. stored marks values read back from the database,
+// whose attributes may belong to the category the product was moved from.
+func (p *productService) productAttributes(ctx context.Context, categorySlug string, values map[string]interface{}, stored bool) (map[string]interface{}, error) {
+	categories, err := p.repoCat.GetAllNodes(ctx)
+	if err != nil {
+		log.Errorf("[ProductService-1] productAttributes: %v", err)
+		return nil, err
+	}
+
+	schema, err := categorySchema(ctx, p.repoAttr, newCategoryTree(categories), categorySlug)
+	if err != nil {
+		log.Errorf("[ProductService-2] productAttributes: %v", err)
+		return nil, err
+	}
+
+	return validateAttributes(schema, values, stored)
 }
 
 // Delete implements [IProductService].
@@ -52,18 +177,76 @@ func (p *productService) Delete(ctx context.Context, productID int64) error {
 		log.Errorf("[ProductService-2] Delete: %v", err)
 	}
 
+	refreshBundles(ctx, p.repo, p.publisherRabbitMQ, productID)
+	p.invalidateCatalog(ctx)
+
 	return nil
 
 }
 
-// Update implements [IProductService].
+// Update implements [IProductService]. A status that differs from the
+// current one has to be an allowed transition and is applied after the
+// content is saved; an empty status keeps the current one.
 func (p *productService) Update(ctx context.Context, req entities.ProductEntity) error {
-	err := p.repo.Update(ctx, req)
+	current, err := p.repo.GetByID(ctx, req.ID)
+	if err != nil {
+		log.Errorf("[ProductService-5] Update: %v", err)
+		return err
+	}
+
+	if current.IsBundle() && len(req.Child) > 0 {
+		return ErrBundleVariant
+	}
+
+	status := current.Status
+	if req.Status != "" {
+		if status, err = normalizeProductStatus(req.Status); err != nil {
+			return err
+		}
+		if err := checkTransition(current.Status, status); err != nil {
+			return err
+		}
+	}
+
+	// without attributes in the request the stored ones are checked against
+	// the category again
+	values, stored := req.Attributes, false
+	if values == nil {
+		values, stored = current.Attributes, true
+	}
+	if req.Attributes, err = p.productAttributes(ctx, req.CategorySlug, values, stored); err != nil {
+		return err
+	}
+
+	// without selling rules in the request the stored ones stay and have to
+	// fit the new unit and weight
+	selling := req
+	selling.Type = current.Type
+	if selling.Selling.SellBy == "" {
+		selling.Selling = current.Selling
+	}
+	if selling.Selling, err = normalizeSelling(selling); err != nil {
+		return err
+	}
+	if req.Selling.SellBy != "" {
+		req.Selling = selling.Selling
+	}
+
+	req.Status = current.Status
+	err = p.repo.Update(ctx, req)
 	if err != nil {
 		log.Errorf("[ProductService-1] Update: %v", err)
 		return err
 	}
 
+	if status != current.Status {
+		applyTransition(current, status, "")
+		if err := p.repo.UpdateStatus(ctx, *current); err != nil {
+			log.Errorf("[ProductService-6] Update: %v", err)
+			return err
+		}
+	}
+
 	getProductByID, err := p.GetByID(ctx, req.ID)
 	if err != nil {
 		log.Errorf("[ProductService-2] Update: %v", err)
@@ -73,6 +256,13 @@ func (p *productService) Update(ctx context.Context, req entities.ProductEntity)
 		log.Errorf("[ProductService-3] Update: %v", err)
 	}
 
+	if err := p.wishlistService.NotifyProductChanged(ctx, *getProductByID); err != nil {
+		log.Errorf("[ProductService-4] Update: %v", err)
+	}
+
+	refreshBundles(ctx, p.repo, p.publisherRabbitMQ, req.ID)
+	p.invalidateCatalog(ctx)
+
 	return nil
 }
 
@@ -94,11 +284,56 @@ func (p *productService) GetByID(ctx context.Context, productID int64) (*entitie
 		return nil, errors.New("category not found")
 	}
 	result.CategoryName = resultCat.Name
+
 	return result, nil
 }
 
-// Create implements [IProductService].
+// GetDetail implements [IProductService]. It is GetByID plus the breadcrumbs
+// and specifications of the product page, which need the whole category
+// tree; anything loading products in a loop uses GetByID.
+func (p *productService) GetDetail(ctx context.Context, productID int64) (*entities.ProductEntity, error) {
+	result, err := p.GetByID(ctx, productID)
+	if err != nil {
+		log.Errorf("[ProductService-1] GetDetail: %v", err)
+		return nil, err
+	}
+
+	categories, err := p.repoCat.GetAllNodes(ctx)
+	if err != nil {
+		log.Errorf("[ProductService-2] GetDetail: %v", err)
+		return nil, err
+	}
+	tree := newCategoryTree(categories)
+	result.Breadcrumbs = tree.breadcrumbs(result.CategorySlug)
+
+	schema, err := categorySchema(ctx, p.repoAttr, tree, result.CategorySlug)
+	if err != nil {
+		log.Errorf("[ProductService-3] GetDetail: %v", err)
+		return nil, err
+	}
+	result.Specifications = specifications(schema, result.Attributes)
+
+	return result, nil
+}
+
+// Create implements [IProductService]. New products start in Draft or In
+// Review; publishing takes an approval.
 func (p *productService) Create(ctx context.Context, req entities.ProductEntity) error {
+	status, err := initialProductStatus(req.Status)
+	if err != nil {
+		return err
+	}
+	req.Status = status
+
+	req.Attributes, err = p.productAttributes(ctx, req.CategorySlug, req.Attributes, false)
+	if err != nil {
+		return err
+	}
+
+	if req.Selling, err = normalizeSelling(req); err != nil {
+		return err
+	}
+
 	productID, err := p.repo.Create(ctx, req)
 	if err != nil {
 		log.Errorf("[ProductService-1] Create: %v", err)
@@ -114,6 +349,8 @@ func (p *productService) Create(ctx context.Context, req entities.ProductEntity)
 		log.Errorf("[ProductService-3] Create: %v", err)
 	}
 
+	p.invalidateCatalog(ctx)
+
 	return nil
 }
 
@@ -122,10 +359,20 @@ func (p *productService) GetAll(ctx context.Context, query entities.QueryStringP
 	return p.repo.GetAll(ctx, query)
 }
 
-func NewProductService(repo repository.IProductRepository, repoCat repository.ICategoryRepository, publisherRabbitMQ message.IPublishRabbitMQ) IProductService {
+// invalidateCatalog drops the cached public product lists after a change.
+func (p *productService) invalidateCatalog(ctx context.Context) {
+	if err := p.catalogCacheService.Invalidate(ctx, entities.CatalogScopeProducts); err != nil {
+		log.Errorf("[ProductService-1] invalidateCatalog: %v", err)
+	}
+}
+
+func NewProductService(repo repository.IProductRepository, repoCat repository.ICategoryRepository, repoAttr repository.ICategoryAttributeRepository, publisherRabbitMQ message.IPublishRabbitMQ, wishlistService IWishlistService, catalogCacheService ICatalogCacheService) IProductService {
 	return &productService{
-		repo:              repo,
-		repoCat:           repoCat,
-		publisherRabbitMQ: publisherRabbitMQ,
+		repo:                repo,
+		repoCat:             repoCat,
+		repoAttr:            repoAttr,
+		publisherRabbitMQ:   publisherRabbitMQ,
+		wishlistService:     wishlistService,
+		catalogCacheService: catalogCacheService,
 	}
 }
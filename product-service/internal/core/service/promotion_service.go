@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"product-service/internal/adapter/repository"
+	"product-service/internal/core/domain/entities"
+	"time"
+
+	"github.com/labstack/gommon/log"
+)
+
+type IPromotionService interface {
+	GetAll(ctx context.Context, query entities.QueryStringEntity) ([]entities.PromotionEntity, int64, int64, error)
+	GetByID(ctx context.Context, id int64) (*entities.PromotionEntity, error)
+	Create(ctx context.Context, req entities.PromotionEntity) error
+	Update(ctx context.Context, req entities.PromotionEntity) error
+	Delete(ctx context.Context, id int64) error
+
+	PriceCart(ctx context.Context, req entities.CartPriceRequestEntity) (*entities.CartPriceEntity, error)
+}
+
+type promotionService struct {
+	repo         repository.IPromotionRepository
+	repoProduct  repository.IProductRepository
+	repoCategory repository.ICategoryRepository
+}
+
+// GetAll implements [IPromotionService].
+func (p *promotionService) GetAll(ctx context.Context, query entities.QueryStringEntity) ([]entities.PromotionEntity, int64, int64, error) {
+	return p.repo.GetAll(ctx, query)
+}
+
+// GetByID implements [IPromotionService].
+func (p *promotionService) GetByID(ctx context.Context, id int64) (*entities.PromotionEntity, error) {
+	return p.repo.GetByID(ctx, id)
+}
+
+// Create implements [IPromotionService].
+func (p *promotionService) Create(ctx context.Context, req entities.PromotionEntity) error {
+	if err := validatePromotion(req); err != nil {
+		log.Errorf("[PromotionService-1] Create: %v", err)
+		return err
+	}
+
+	return p.repo.Create(ctx, req)
+}
+
+// Update implements [IPromotionService].
+func (p *promotionService) Update(ctx context.Context, req entities.PromotionEntity) error {
+	if err := validatePromotion(req); err != nil {
+		log.Errorf("[PromotionService-1] Update: %v", err)
+		return err
+	}
+
+	return p.repo.Update(ctx, req)
+}
+
+// Delete implements [IPromotionService].
+func (p *promotionService) Delete(ctx context.Context, id int64) error {
+	return p.repo.Delete(ctx, id)
+}
+
+// PriceCart implements [IPromotionService].
+func (p *promotionService) PriceCart(ctx context.Context, req entities.CartPriceRequestEntity) (*entities.CartPriceEntity, error) {
+	productIDs := []int64{}
+	for _, item := range req.Items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	products, err := p.repoProduct.GetByIDs(ctx, productIDs)
+	if err != nil {
+		log.Errorf("[PromotionService-1] PriceCart: %v", err)
+		return nil, err
+	}
+
+	productMap := map[int64]entities.ProductEntity{}
+	for _, product := range products {
+		productMap[product.ID] = product
+	}
+
+	// a promotion on a category covers its subcategories too
+	categories, err := p.repoCategory.GetAllNodes(ctx)
+	if err != nil {
+		log.Errorf("[PromotionService-2] PriceCart: %v", err)
+		return nil, err
+	}
+	tree := newCategoryTree(categories)
+
+	lines := []entities.CartPriceLineEntity{}
+	for _, item := range req.Items {
+		product, ok := productMap[item.ProductID]
+		if !ok {
+			err := errors.New("404")
+			log.Errorf("[PromotionService-3] PriceCart: product %d not found", item.ProductID)
+			return nil, err
+		}
+
+		lines = append(lines, entities.CartPriceLineEntity{
+			ProductID:     product.ID,
+			ProductName:   product.Name,
+			CategorySlug:  product.CategorySlug,
+			CategorySlugs: tree.ancestorSlugs(product.CategorySlug),
+			Quantity:      item.Quantity,
+			UnitPrice:     product.UnitPrice(),
+			Subtotal:      product.LinePrice(item.Quantity),
+			Promotions:    []entities.AppliedPromotionEntity{},
+		})
+	}
+
+	promotions, err := p.repo.GetActive(ctx, time.Now())
+	if err != nil {
+		log.Errorf("[PromotionService-4] PriceCart: %v", err)
+		return nil, err
+	}
+
+	return evaluatePromotions(lines, promotions, req.CustomerSegment, req.ShippingFee), nil
+}
+
+// evaluatePromotions applies line promotions first and order promotions on the
+// discounted subtotal afterwards. Promotions must be sorted by priority; a
+// non-stackable promotion only applies when nothing else has, and blocks the
+// promotions after it.
+func evaluatePromotions(lines []entities.CartPriceLineEntity, promotions []entities.PromotionEntity, segment string, shippingFee float64) *entities.CartPriceEntity {
+	result := &entities.CartPriceEntity{
+		OrderPromotions: []entities.AppliedPromotionEntity{},
+		ShippingFee:     shippingFee,
+	}
+
+	for i := range lines {
+		line := &lines[i]
+		exclusive := false
+		for _, promo := range promotions {
+			if promo.IsOrderLevel() || exclusive || !matchesSegment(promo, segment) || !matchesLine(promo, *line) {
+				continue
+			}
+			if len(line.Promotions) > 0 && !promo.Stackable {
+				continue
+			}
+
+			discount := math.Min(lineDiscount(promo, *line), line.Subtotal-line.Discount)
+			if discount <= 0 {
+				continue
+			}
+
+			line.Discount += discount
+			line.Promotions = append(line.Promotions, entities.AppliedPromotionEntity{
+				PromotionID: promo.ID,
+				Name:        promo.Name,
+				Type:        promo.Type,
+				Discount:    discount,
+			})
+			exclusive = !promo.Stackable
+		}
+
+		line.Total = line.Subtotal - line.Discount
+		result.Subtotal += line.Subtotal
+		result.LineDiscount += line.Discount
+	}
+	result.Items = lines
+
+	exclusive := false
+	for _, promo := range promotions {
+		if !promo.IsOrderLevel() || exclusive || !matchesSegment(promo, segment) || !matchesOrder(promo, lines, result.Subtotal-result.LineDiscount) {
+			continue
+		}
+		if len(result.OrderPromotions) > 0 && !promo.Stackable {
+			continue
+		}
+
+		remaining := result.Subtotal - result.LineDiscount - result.OrderDiscount
+		discount := 0.0
+		switch promo.Type {
+		case entities.PromotionTypeOrderPercentage:
+			discount = math.Round(remaining * promo.Value / 100)
+		case entities.PromotionTypeOrderFixed:
+			discount = math.Min(promo.Value, remaining)
+		case entities.PromotionTypeFreeShipping:
+			discount = shippingFee - result.ShippingDiscount
+		}
+		if discount <= 0 {
+			continue
+		}
+
+		if promo.Type == entities.PromotionTypeFreeShipping {
+			result.ShippingDiscount += discount
+		} else {
+			result.OrderDiscount += discount
+		}
+		result.OrderPromotions = append(result.OrderPromotions, entities.AppliedPromotionEntity{
+			PromotionID: promo.ID,
+			Name:        promo.Name,
+			Type:        promo.Type,
+			Discount:    discount,
+		})
+		exclusive = !promo.Stackable
+	}
+
+	result.Total = result.Subtotal - result.LineDiscount - result.OrderDiscount + result.ShippingFee - result.ShippingDiscount
+	return result
+}
+
+// lineDiscount works on the line subtotal rather than on the unit price, as
+// the unit price of a product sold by weight is per kg while the quantity
+// may be in gram.
+func lineDiscount(promo entities.PromotionEntity, line entities.CartPriceLineEntity) float64 {
+	if line.Quantity <= 0 || line.UnitPrice <= 0 {
+		return 0
+	}
+
+	switch promo.Type {
+	case entities.PromotionTypePercentage:
+		return math.Round(line.Subtotal * promo.Value / 100)
+	case entities.PromotionTypeFixed:
+		return math.Round(math.Min(promo.Value, line.UnitPrice) * line.Subtotal / line.UnitPrice)
+	case entities.PromotionTypeBuyXGetY:
+		bundle := promo.BuyQuantity + promo.GetQuantity
+		if promo.GetQuantity <= 0 || bundle <= 0 {
+			return 0
+		}
+		return math.Round(float64(line.Quantity/bundle*promo.GetQuantity) * line.Subtotal / float64(line.Quantity))
+	case entities.PromotionTypeTiered:
+		percent := 0.0
+		var reached int64
+		for _, tier := range promo.Tiers {
+			if line.Quantity >= tier.MinQuantity && tier.MinQuantity >= reached {
+				percent = tier.Value
+				reached = tier.MinQuantity
+			}
+		}
+		return math.Round(line.Subtotal * percent / 100)
+	}
+	return 0
+}
+
+func matchesSegment(promo entities.PromotionEntity, segment string) bool {
+	return promo.CustomerSegment == "" || promo.CustomerSegment == segment
+}
+
+func matchesLine(promo entities.PromotionEntity, line entities.CartPriceLineEntity) bool {
+	if promo.ProductID != nil && *promo.ProductID != line.ProductID {
+		return false
+	}
+	if !inCategory(promo, line) {
+		return false
+	}
+	return line.Quantity >= promo.MinQuantity
+}
+
+// matchesOrder checks order promotions; product and category conditions
+// require at least MinQuantity matching units somewhere in the cart.
+func matchesOrder(promo entities.PromotionEntity, lines []entities.CartPriceLineEntity, subtotal float64) bool {
+	if subtotal < promo.MinSubtotal {
+		return false
+	}
+
+	var quantity int64
+	for _, line := range lines {
+		if promo.ProductID != nil && *promo.ProductID != line.ProductID {
+			continue
+		}
+		if !inCategory(promo, line) {
+			continue
+		}
+		quantity += line.Quantity
+	}
+
+	return quantity > 0 && quantity >= promo.MinQuantity
+}
+
+// inCategory reports whether the line is in the category of the promotion
+// or in one of its subcategories; a promotion without a category covers
+// every line.
+func inCategory(promo entities.PromotionEntity, line entities.CartPriceLineEntity) bool {
+	if promo.CategorySlug == "" || promo.CategorySlug == line.CategorySlug {
+		return true
+	}
+
+	for _, slug := range line.CategorySlugs {
+		if slug == promo.CategorySlug {
+			return true
+		}
+	}
+	return false
+}
+
+func validatePromotion(req entities.PromotionEntity) error {
+	switch req.Type {
+	case entities.PromotionTypePercentage, entities.PromotionTypeOrderPercentage:
+		if req.Value <= 0 || req.Value > 100 {
+			return errors.New("percentage value must be between 1 and 100")
+		}
+	case entities.PromotionTypeFixed, entities.PromotionTypeOrderFixed:
+		if req.Value <= 0 {
+			return errors.New("fixed value must be greater than 0")
+		}
+	case entities.PromotionTypeBuyXGetY:
+		if req.BuyQuantity <= 0 || req.GetQuantity <= 0 {
+			return errors.New("buy_quantity and get_quantity are required")
+		}
+	case entities.PromotionTypeTiered:
+		if len(req.Tiers) == 0 {
+			return errors.New("tiers are required")
+		}
+		for _, tier := range req.Tiers {
+			if tier.MinQuantity <= 0 || tier.Value <= 0 || tier.Value > 100 {
+				return errors.New("tier needs min_quantity and a percentage between 1 and 100")
+			}
+		}
+	case entities.PromotionTypeFreeShipping:
+	default:
+		return errors.New("unknown promotion type")
+	}
+
+	switch req.CustomerSegment {
+	case "", entities.CustomerSegmentGuest, entities.CustomerSegmentCustomer:
+	default:
+		return errors.New("customer_segment must be guest or customer")
+	}
+
+	if req.StartAt != nil && req.EndAt != nil && !req.EndAt.After(*req.StartAt) {
+		return errors.New("end_at must be after start_at")
+	}
+
+	return nil
+}
+
+func NewPromotionService(repo repository.IPromotionRepository, repoProduct repository.IProductRepository, repoCategory repository.ICategoryRepository) IPromotionService {
+	return &promotionService{
+		repo:         repo,
+		repoProduct:  repoProduct,
+		repoCategory: repoCategory,
+	}
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"product-service/internal/core/domain/entities"
+	"testing"
+)
+
+func TestLineDiscount(t *testing.T) {
+	line := func(quantity int64, unitPrice float64) entities.CartPriceLineEntity {
+		return entities.CartPriceLineEntity{
+			Quantity:  quantity,
+			UnitPrice: unitPrice,
+			Subtotal:  float64(quantity) * unitPrice,
+		}
+	}
+	tiered := entities.PromotionEntity{
+		Type: entities.PromotionTypeTiered,
+		Tiers: []entities.PromotionTierEntity{
+			{MinQuantity: 10, Value: 10},
+			{MinQuantity: 3, Value: 5},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		promo entities.PromotionEntity
+		line  entities.CartPriceLineEntity
+		want  float64
+	}{
+		{
+			name:  "percentage of the subtotal",
+			promo: entities.PromotionEntity{Type: entities.PromotionTypePercentage, Value: 10},
+			line:  line(2, 10000),
+			want:  2000,
+		},
+		{
+			name:  "fixed per unit",
+			promo: entities.PromotionEntity{Type: entities.PromotionTypeFixed, Value: 3000},
+			line:  line(2, 10000),
+			want:  6000,
+		},
+		{
+			name:  "fixed never above the unit price",
+			promo: entities.PromotionEntity{Type: entities.PromotionTypeFixed, Value: 15000},
+			line:  line(2, 10000),
+			want:  20000,
+		},
+		{
+			name:  "buy two get one free for every full bundle",
+			promo: entities.PromotionEntity{Type: entities.PromotionTypeBuyXGetY, BuyQuantity: 2, GetQuantity: 1},
+			line:  line(7, 1000),
+			want:  2000,
+		},
+		{
+			name:  "buy x get nothing",
+			promo: entities.PromotionEntity{Type: entities.PromotionTypeBuyXGetY, BuyQuantity: 2},
+			line:  line(7, 1000),
+			want:  0,
+		},
+		{
+			name:  "below the first tier",
+			promo: tiered,
+			line:  line(2, 1000),
+			want:  0,
+		},
+		{
+			name:  "first tier",
+			promo: tiered,
+			line:  line(5, 1000),
+			want:  250,
+		},
+		{
+			name:  "highest tier reached, whatever the order of the tiers",
+			promo: tiered,
+			line:  line(12, 1000),
+			want:  1200,
+		},
+		{
+			name:  "empty line",
+			promo: entities.PromotionEntity{Type: entities.PromotionTypePercentage, Value: 10},
+			line:  line(0, 1000),
+			want:  0,
+		},
+		{
+			name:  "order promotions do not discount lines",
+			promo: entities.PromotionEntity{Type: entities.PromotionTypeOrderPercentage, Value: 10},
+			line:  line(2, 1000),
+			want:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lineDiscount(tt.promo, tt.line); got != tt.want {
+				t.Errorf("lineDiscount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluatePromotions(t *testing.T) {
+	lines := func() []entities.CartPriceLineEntity {
+		return []entities.CartPriceLineEntity{
+			{ProductID: 1, CategorySlug: "bayam", CategorySlugs: []string{"bayam", "sayur"}, Quantity: 1, UnitPrice: 10000, Subtotal: 10000},
+			{ProductID: 2, CategorySlug: "apel", CategorySlugs: []string{"apel", "buah"}, Quantity: 2, UnitPrice: 5000, Subtotal: 10000},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		promotions       []entities.PromotionEntity
+		segment          string
+		shippingFee      float64
+		lineDiscount     float64
+		orderDiscount    float64
+		shippingDiscount float64
+		total            float64
+	}{
+		{
+			name:        "no promotions",
+			shippingFee: 5000,
+			total:       25000,
+		},
+		{
+			name: "a non stackable promotion excludes the next ones on the line",
+			promotions: []entities.PromotionEntity{
+				{ID: 1, Type: entities.PromotionTypePercentage, Value: 10},
+				{ID: 2, Type: entities.PromotionTypeFixed, Value: 500, Stackable: true},
+			},
+			lineDiscount: 2000,
+			total:        18000,
+		},
+		{
+			name: "stackable promotions add up",
+			promotions: []entities.PromotionEntity{
+				{ID: 1, Type: entities.PromotionTypePercentage, Value: 10, Stackable: true},
+				{ID: 2, Type: entities.PromotionTypeFixed, Value: 500, Stackable: true},
+			},
+			lineDiscount: 3500,
+			total:        16500,
+		},
+		{
+			name: "a line discount never exceeds the line",
+			promotions: []entities.PromotionEntity{
+				{ID: 1, Type: entities.PromotionTypeFixed, Value: 20000, ProductID: ptrInt64(1)},
+			},
+			lineDiscount: 10000,
+			total:        10000,
+		},
+		{
+			name: "a category promotion covers its subcategories",
+			promotions: []entities.PromotionEntity{
+				{ID: 1, Type: entities.PromotionTypePercentage, Value: 50, CategorySlug: "sayur"},
+			},
+			lineDiscount: 5000,
+			total:        15000,
+		},
+		{
+			name: "a promotion for guests skips customers",
+			promotions: []entities.PromotionEntity{
+				{ID: 1, Type: entities.PromotionTypePercentage, Value: 10, CustomerSegment: entities.CustomerSegmentGuest},
+			},
+			segment: entities.CustomerSegmentCustomer,
+			total:   20000,
+		},
+		{
+			name: "an order percentage once the minimum subtotal is reached",
+			promotions: []entities.PromotionEntity{
+				{ID: 1, Type: entities.PromotionTypeOrderPercentage, Value: 10, MinSubtotal: 15000},
+			},
+			orderDiscount: 2000,
+			total:         18000,
+		},
+		{
+			name: "no order discount below the minimum subtotal",
+			promotions: []entities.PromotionEntity{
+				{ID: 1, Type: entities.PromotionTypeOrderFixed, Value: 3000, MinSubtotal: 25000},
+			},
+			total: 20000,
+		},
+		{
+			name: "the order minimum counts the subtotal after line discounts",
+			promotions: []entities.PromotionEntity{
+				{ID: 1, Type: entities.PromotionTypePercentage, Value: 50},
+				{ID: 2, Type: entities.PromotionTypeOrderFixed, Value: 3000, MinSubtotal: 15000},
+			},
+			lineDiscount: 10000,
+			total:        10000,
+		},
+		{
+			name: "free shipping",
+			promotions: []entities.PromotionEntity{
+				{ID: 1, Type: entities.PromotionTypeFreeShipping},
+			},
+			shippingFee:      7000,
+			shippingDiscount: 7000,
+			total:            20000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluatePromotions(lines(), tt.promotions, tt.segment, tt.shippingFee)
+			if got.LineDiscount != tt.lineDiscount {
+				t.Errorf("LineDiscount = %v, want %v", got.LineDiscount, tt.lineDiscount)
+			}
+			if got.OrderDiscount != tt.orderDiscount {
+				t.Errorf("OrderDiscount = %v, want %v", got.OrderDiscount, tt.orderDiscount)
+			}
+			if got.ShippingDiscount != tt.shippingDiscount {
+				t.Errorf("ShippingDiscount = %v, want %v", got.ShippingDiscount, tt.shippingDiscount)
+			}
+			if got.Total != tt.total {
+				t.Errorf("Total = %v, want %v", got.Total, tt.total)
+			}
+		})
+	}
+}
+
+func ptrInt64(v int64) *int64 {
+	return &v
+}
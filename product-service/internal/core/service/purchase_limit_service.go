@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-service/internal/adapter/repository"
+	"product-service/internal/core/domain/entities"
+	"strings"
+	"time"
+
+	"github.com/labstack/gommon/log"
+)
+
+var ErrPurchaseLimitExceeded = errors.New("purchase limit exceeded")
+
+// PurchaseLimitError lists the limits a request goes over and what is left
+// of each. It unwraps to ErrPurchaseLimitExceeded.
+type PurchaseLimitError struct {
+	Violations []entities.PurchaseLimitViolationEntity
+}
+
+func (e *PurchaseLimitError) Error() string {
+	messages := []string{}
+	for _, val := range e.Violations {
+		messages = append(messages, fmt.Sprintf("%s allows %d per %d hours, %d left", val.Name, val.MaxQuantity, val.WindowHours, val.Remaining))
+	}
+
+	return fmt.Sprintf("%v: %s", ErrPurchaseLimitExceeded, strings.Join(messages, "; "))
+}
+
+func (e *PurchaseLimitError) Unwrap() error {
+	return ErrPurchaseLimitExceeded
+}
+
+type IPurchaseLimitService interface {
+	GetAll(ctx context.Context, query entities.QueryStringEntity) ([]entities.PurchaseLimitEntity, int64, int64, error)
+	GetByID(ctx context.Context, id int64) (*entities.PurchaseLimitEntity, error)
+	Create(ctx context.Context, req entities.PurchaseLimitEntity) error
+	Update(ctx context.Context, req entities.PurchaseLimitEntity) error
+	Delete(ctx context.Context, id int64) error
+
+	Check(ctx context.Context, userID int64, items []entities.CartItem) ([]entities.PurchaseLimitViolationEntity, error)
+	Reserve(ctx context.Context, userID int64, orderCode string, items []entities.CartItem) ([]entities.PurchaseLimitViolationEntity, error)
+	Release(ctx context.Context, orderCode string) error
+}
+
+type purchaseLimitService struct {
+	repo        repository.IPurchaseLimitRepository
+	repoProduct repository.IProductRepository
+	maxWindow   time.Duration
+}
+
+// GetAll implements [IPurchaseLimitService].
+func (p *purchaseLimitService) GetAll(ctx context.Context, query entities.QueryStringEntity) ([]entities.PurchaseLimitEntity, int64, int64, error) {
+	return p.repo.GetAll(ctx, query)
+}
+
+// GetByID implements [IPurchaseLimitService].
+func (p *purchaseLimitService) GetByID(ctx context.Context, id int64) (*entities.PurchaseLimitEntity, error) {
+	return p.repo.GetByID(ctx, id)
+}
+
+// Create implements [IPurchaseLimitService].
+func (p *purchaseLimitService) Create(ctx context.Context, req entities.PurchaseLimitEntity) error {
+	if err := p.validate(ctx, req); err != nil {
+		log.Errorf("[PurchaseLimitService-1] Create: %v", err)
+		return err
+	}
+
+	return p.repo.Create(ctx, req)
+}
+
+// Update implements [IPurchaseLimitService].
+func (p *purchaseLimitService) Update(ctx context.Context, req entities.PurchaseLimitEntity) error {
+	if err := p.validate(ctx, req); err != nil {
+		log.Errorf("[PurchaseLimitService-1] Update: %v", err)
+		return err
+	}
+
+	return p.repo.Update(ctx, req)
+}
+
+// Delete implements [IPurchaseLimitService].
+func (p *purchaseLimitService) Delete(ctx context.Context, id int64) error {
+	return p.repo.Delete(ctx, id)
+}
+
+// Check implements [IPurchaseLimitService]. It returns the limits that the
+// items, added to what userID bought within each window, go over. Guests
+// have no purchases to count, so only the items count for them.
+func (p *purchaseLimitService) Check(ctx context.Context, userID int64, items []entities.CartItem) ([]entities.PurchaseLimitViolationEntity, error) {
+	limits, err := p.requestedLimits(ctx, items)
+	if err != nil {
+		log.Errorf("[PurchaseLimitService-1] Check: %v", err)
+		return nil, err
+	}
+
+	violations := []entities.PurchaseLimitViolationEntity{}
+	now := time.Now()
+	for _, limit := range limits {
+		purchased := int64(0)
+		if userID > 0 {
+			purchased, err = p.repo.GetPurchased(ctx, userID, limit.Limit(), now.Add(-limit.Limit().Window()))
+			if err != nil {
+				log.Errorf("[PurchaseLimitService-2] Check: %v", err)
+				return nil, err
+			}
+		}
+
+		if limit.Exceeds(purchased) {
+			violations = append(violations, limit)
+		}
+	}
+
+	return violations, nil
+}
+
+// Reserve implements [IPurchaseLimitService]. It counts the items of the
+// order against the limits of userID when the order is created, so orders
+// placed at the same time cannot both take what is left of a limit. Nothing
+// is recorded when a limit is gone over; the limits are returned instead.
+func (p *purchaseLimitService) Reserve(ctx context.Context, userID int64, orderCode string, items []entities.CartItem) ([]entities.PurchaseLimitViolationEntity, error) {
+	limits, err := p.requestedLimits(ctx, items)
+	if err != nil {
+		log.Errorf("[PurchaseLimitService-1] Reserve: %v", err)
+		return nil, err
+	}
+
+	violations, err := p.repo.Reserve(ctx, userID, orderCode, items, limits, time.Now().Add(-p.maxWindow))
+	if err != nil {
+		log.Errorf("[PurchaseLimitService-2] Reserve: %v", err)
+		return nil, err
+	}
+
+	return violations, nil
+}
+
+// Release implements [IPurchaseLimitService]. The items of a cancelled
+// order no longer count against the limits of its buyer.
+func (p *purchaseLimitService) Release(ctx context.Context, orderCode string) error {
+	return p.repo.Release(ctx, orderCode)
+}
+
+// requestedLimits returns the active limits covering any of the items, with
+// the quantity of the items each covers as Requested.
+func (p *purchaseLimitService) requestedLimits(ctx context.Context, items []entities.CartItem) ([]entities.PurchaseLimitViolationEntity, error) {
+	requested := []entities.PurchaseLimitViolationEntity{}
+	if len(items) == 0 {
+		return requested, nil
+	}
+
+	productIDs := []int64{}
+	for _, item := range items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	products, err := p.repoProduct.GetByIDs(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	productMap := map[int64]entities.ProductEntity{}
+	limitProductIDs := []int64{}
+	categorySlugs := []string{}
+	for _, product := range products {
+		productMap[product.ID] = product
+		limitProductIDs = append(limitProductIDs, product.ID)
+		if product.ParentID != nil {
+			limitProductIDs = append(limitProductIDs, *product.ParentID)
+		}
+		if product.CategorySlug != "" {
+			categorySlugs = append(categorySlugs, product.CategorySlug)
+		}
+	}
+
+	limits, err := p.repo.GetActive(ctx, limitProductIDs, categorySlugs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, limit := range limits {
+		violation := entities.PurchaseLimitViolationEntity{
+			LimitID:            limit.ID,
+			Name:               limit.Name,
+			ProductID:          limit.ProductID,
+			CategorySlug:       limit.CategorySlug,
+			MaxQuantity:        limit.MaxQuantity,
+			WindowHours:        limit.WindowHours,
+			AffectedProductIDs: []int64{},
+		}
+		for _, item := range items {
+			if product, ok := productMap[item.ProductID]; ok && limit.AppliesTo(product) {
+				violation.Requested += item.Quantity
+				violation.AffectedProductIDs = append(violation.AffectedProductIDs, item.ProductID)
+			}
+		}
+
+		if violation.Requested > 0 {
+			requested = append(requested, violation)
+		}
+	}
+
+	return requested, nil
+}
+
+// validate checks the limit targets exactly one product or category and
+// that its window fits in the purchase reservations kept for it.
+func (p *purchaseLimitService) validate(ctx context.Context, req entities.PurchaseLimitEntity) error {
+	if (req.ProductID == nil) == (req.CategorySlug == "") {
+		return errors.New("either product_id or category_slug is required, not both")
+	}
+
+	if req.MaxQuantity <= 0 {
+		return errors.New("max_quantity must be greater than 0")
+	}
+
+	if req.WindowHours <= 0 {
+		return errors.New("window_hours must be greater than 0")
+	}
+
+	if maxHours := int(p.maxWindow.Hours()); req.WindowHours > maxHours {
+		return fmt.Errorf("window_hours cannot be longer than the %d hours purchases are kept for", maxHours)
+	}
+
+	if req.ProductID != nil {
+		if _, err := p.repoProduct.GetByID(ctx, *req.ProductID); err != nil {
+			if err.Error() == "404" {
+				return errors.New("product not found")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func NewPurchaseLimitService(repo repository.IPurchaseLimitRepository, repoProduct repository.IProductRepository, maxWindow time.Duration) IPurchaseLimitService {
+	return &purchaseLimitService{
+		repo:        repo,
+		repoProduct: repoProduct,
+		maxWindow:   maxWindow,
+	}
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"product-service/internal/adapter/repository"
+	"product-service/internal/core/domain/entities"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeLimitProducts serves the products the limits are checked against.
+type fakeLimitProducts struct {
+	repository.IProductRepository
+	products map[int64]entities.ProductEntity
+}
+
+func (f fakeLimitProducts) GetByID(_ context.Context, productID int64) (*entities.ProductEntity, error) {
+	product, ok := f.products[productID]
+	if !ok {
+		return nil, errors.New("404")
+	}
+	return &product, nil
+}
+
+func (f fakeLimitProducts) GetByIDs(_ context.Context, productIDs []int64) ([]entities.ProductEntity, error) {
+	products := []entities.ProductEntity{}
+	for _, id := range productIDs {
+		if product, ok := f.products[id]; ok {
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
+
+// fakeLimits returns the active limits matching the products or categories
+// asked for, and the same purchases for every limit.
+type fakeLimits struct {
+	repository.IPurchaseLimitRepository
+	limits    []entities.PurchaseLimitEntity
+	purchased int64
+}
+
+func (f fakeLimits) GetActive(_ context.Context, productIDs []int64, categorySlugs []string) ([]entities.PurchaseLimitEntity, error) {
+	limits := []entities.PurchaseLimitEntity{}
+	for _, limit := range f.limits {
+		for _, id := range productIDs {
+			if limit.ProductID != nil && *limit.ProductID == id {
+				limits = append(limits, limit)
+				break
+			}
+		}
+		for _, slug := range categorySlugs {
+			if limit.CategorySlug != "" && limit.CategorySlug == slug {
+				limits = append(limits, limit)
+				break
+			}
+		}
+	}
+	return limits, nil
+}
+
+func (f fakeLimits) GetPurchased(context.Context, int64, entities.PurchaseLimitEntity, time.Time) (int64, error) {
+	return f.purchased, nil
+}
+
+// testLimitProducts are a product with variants 2 and 3, and 4 in the
+// category beras.
+func testLimitProducts() fakeLimitProducts {
+	return fakeLimitProducts{products: map[int64]entities.ProductEntity{
+		1: {ID: 1, CategorySlug: "minyak"},
+		2: {ID: 2, ParentID: ptrInt64(1), CategorySlug: "minyak"},
+		3: {ID: 3, ParentID: ptrInt64(1), CategorySlug: "minyak"},
+		4: {ID: 4, CategorySlug: "beras"},
+	}}
+}
+
+func TestPurchaseLimitRequestedLimits(t *testing.T) {
+	limits := []entities.PurchaseLimitEntity{
+		{ID: 1, Name: "minyak goreng", ProductID: ptrInt64(1), MaxQuantity: 2, WindowHours: 24},
+		{ID: 2, Name: "beras", CategorySlug: "beras", MaxQuantity: 10, WindowHours: 168},
+	}
+
+	tests := []struct {
+		name      string
+		items     []entities.CartItem
+		requested map[int64]int64
+		affected  map[int64][]int64
+	}{
+		{name: "no items"},
+		{
+			name:      "variants count against the parent",
+			items:     []entities.CartItem{{ProductID: 2, Quantity: 1}, {ProductID: 3, Quantity: 2}},
+			requested: map[int64]int64{1: 3},
+			affected:  map[int64][]int64{1: {2, 3}},
+		},
+		{
+			name:      "a product and a category",
+			items:     []entities.CartItem{{ProductID: 1, Quantity: 1}, {ProductID: 4, Quantity: 5}},
+			requested: map[int64]int64{1: 1, 2: 5},
+			affected:  map[int64][]int64{1: {1}, 2: {4}},
+		},
+		{
+			name:  "unknown products are not limited",
+			items: []entities.CartItem{{ProductID: 9, Quantity: 5}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &purchaseLimitService{repo: fakeLimits{limits: limits}, repoProduct: testLimitProducts()}
+			got, err := service.requestedLimits(context.Background(), tt.items)
+			if err != nil {
+				t.Fatalf("requestedLimits() error = %v", err)
+			}
+			if len(got) != len(tt.requested) {
+				t.Fatalf("requestedLimits() = %+v, want %d limits", got, len(tt.requested))
+			}
+			for _, limit := range got {
+				if limit.Requested != tt.requested[limit.LimitID] {
+					t.Errorf("limit %d Requested = %d, want %d", limit.LimitID, limit.Requested, tt.requested[limit.LimitID])
+				}
+				if !reflect.DeepEqual(limit.AffectedProductIDs, tt.affected[limit.LimitID]) {
+					t.Errorf("limit %d AffectedProductIDs = %v, want %v", limit.LimitID, limit.AffectedProductIDs, tt.affected[limit.LimitID])
+				}
+			}
+		})
+	}
+}
+
+func TestPurchaseLimitCheck(t *testing.T) {
+	limits := []entities.PurchaseLimitEntity{{ID: 1, Name: "minyak goreng", ProductID: ptrInt64(1), MaxQuantity: 3, WindowHours: 24}}
+	items := []entities.CartItem{{ProductID: 2, Quantity: 2}}
+
+	tests := []struct {
+		name          string
+		userID        int64
+		purchased     int64
+		wantRemaining []int64
+	}{
+		{name: "a guest only counts the items", userID: 0, purchased: 2, wantRemaining: []int64{}},
+		{name: "within the limit", userID: 7, purchased: 1, wantRemaining: []int64{}},
+		{name: "over the limit", userID: 7, purchased: 2, wantRemaining: []int64{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &purchaseLimitService{repo: fakeLimits{limits: limits, purchased: tt.purchased}, repoProduct: testLimitProducts()}
+			violations, err := service.Check(context.Background(), tt.userID, items)
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			remaining := []int64{}
+			for _, violation := range violations {
+				remaining = append(remaining, violation.Remaining)
+			}
+			if !reflect.DeepEqual(remaining, tt.wantRemaining) {
+				t.Errorf("Check() remaining = %v, want %v", remaining, tt.wantRemaining)
+			}
+		})
+	}
+}
+
+func TestPurchaseLimitValidate(t *testing.T) {
+	service := &purchaseLimitService{repoProduct: testLimitProducts(), maxWindow: 90 * 24 * time.Hour}
+
+	tests := []struct {
+		name    string
+		limit   entities.PurchaseLimitEntity
+		wantErr bool
+	}{
+		{name: "a product", limit: entities.PurchaseLimitEntity{ProductID: ptrInt64(1), MaxQuantity: 2, WindowHours: 24}},
+		{name: "a category", limit: entities.PurchaseLimitEntity{CategorySlug: "beras", MaxQuantity: 10, WindowHours: 90 * 24}},
+		{name: "no target", limit: entities.PurchaseLimitEntity{MaxQuantity: 2, WindowHours: 24}, wantErr: true},
+		{name: "both targets", limit: entities.PurchaseLimitEntity{ProductID: ptrInt64(1), CategorySlug: "beras", MaxQuantity: 2, WindowHours: 24}, wantErr: true},
+		{name: "no quantity", limit: entities.PurchaseLimitEntity{CategorySlug: "beras", WindowHours: 24}, wantErr: true},
+		{name: "no window", limit: entities.PurchaseLimitEntity{CategorySlug: "beras", MaxQuantity: 2}, wantErr: true},
+		{name: "window longer than purchases are kept", limit: entities.PurchaseLimitEntity{CategorySlug: "beras", MaxQuantity: 2, WindowHours: 90*24 + 1}, wantErr: true},
+		{name: "missing product", limit: entities.PurchaseLimitEntity{ProductID: ptrInt64(99), MaxQuantity: 2, WindowHours: 24}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := service.validate(context.Background(), tt.limit); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"product-service/internal/adapter/repository"
+	"product-service/internal/core/domain/entities"
+	"time"
+
+	"github.com/labstack/gommon/log"
+)
+
+type IRecommendationService interface {
+	RecordOrder(ctx context.Context, order entities.OrderEventEntity) error
+	Compute(ctx context.Context) (int64, error)
+	GetRelated(ctx context.Context, productID int64, limit int) ([]entities.RelatedProductEntity, error)
+
+	RecordView(ctx context.Context, userID, productID int64) error
+	GetRecentlyViewed(ctx context.Context, userID int64, limit int) ([]entities.ProductEntity, error)
+	ClearRecentlyViewed(ctx context.Context, userID int64) error
+	GetHomeFeed(ctx context.Context, userID int64, limit int) ([]entities.RelatedProductEntity, error)
+}
+
+type recommendationService struct {
+	repo          repository.IRecommendationRepository
+	repoProduct   repository.IProductRepository
+	repoViewed    repository.IRecentlyViewedRepository
+	repoPromotion repository.IPromotionRepository
+	window        time.Duration
+	topN          int
+}
+
+// RecordOrder implements [IRecommendationService].
+func (r *recommendationService) RecordOrder(ctx context.Context, order entities.OrderEventEntity) error {
+	if order.ID == 0 {
+		return errors.New("order without id")
+	}
+
+	items := []entities.PublishOrderItemEntity{}
+	for _, item := range order.OrderItems {
+		if item.ProductID != 0 {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	order.OrderItems = items
+
+	return r.repo.RecordOrder(ctx, order)
+}
+
+// Compute implements [IRecommendationService]. It returns the number of
+// related products stored.
+func (r *recommendationService) Compute(ctx context.Context) (int64, error) {
+	return r.repo.Compute(ctx, time.Now().Add(-r.window), r.topN)
+}
+
+// GetRelated implements [IRecommendationService]. Products bought together
+// with the product come first; when there are fewer than limit, best sellers
+// of the same category fill the list.
+func (r *recommendationService) GetRelated(ctx context.Context, productID int64, limit int) ([]entities.RelatedProductEntity, error) {
+	if limit <= 0 || limit > r.topN {
+		limit = r.topN
+	}
+
+	product, err := r.repoProduct.GetByID(ctx, productID)
+	if err != nil {
+		log.Errorf("[RecommendationService-1] GetRelated: %v", err)
+		return nil, err
+	}
+	if !product.IsPublished() {
+		return nil, errors.New("404")
+	}
+
+	relatedIDs, err := r.repo.GetRelatedIDs(ctx, productID, limit)
+	if err != nil {
+		log.Errorf("[RecommendationService-2] GetRelated: %v", err)
+		return nil, err
+	}
+
+	reasons := map[int64]string{}
+	for _, relatedID := range relatedIDs {
+		reasons[relatedID] = entities.RelatedReasonBoughtTogether
+	}
+
+	if len(relatedIDs) < limit {
+		excludeIDs := append([]int64{productID}, relatedIDs...)
+		bestSellerIDs, err := r.repo.GetBestSellerIDs(ctx, []string{product.CategorySlug}, time.Now().Add(-r.window), excludeIDs, limit-len(relatedIDs))
+		if err != nil {
+			log.Errorf("[RecommendationService-3] GetRelated: %v", err)
+			return nil, err
+		}
+
+		for _, bestSellerID := range bestSellerIDs {
+			reasons[bestSellerID] = entities.RelatedReasonBestSeller
+		}
+		relatedIDs = append(relatedIDs, bestSellerIDs...)
+	}
+
+	products, err := r.repoProduct.GetByIDs(ctx, relatedIDs)
+	if err != nil {
+		log.Errorf("[RecommendationService-4] GetRelated: %v", err)
+		return nil, err
+	}
+
+	byID := map[int64]entities.ProductEntity{}
+	for _, val := range products {
+		byID[val.ID] = val
+	}
+
+	results := []entities.RelatedProductEntity{}
+	for _, relatedID := range relatedIDs {
+		if val, ok := byID[relatedID]; ok {
+			results = append(results, entities.RelatedProductEntity{
+				Product: val,
+				Reason:  reasons[relatedID],
+			})
+		}
+	}
+
+	return results, nil
+}
+
+func NewRecommendationService(repo repository.IRecommendationRepository, repoProduct repository.IProductRepository, repoViewed repository.IRecentlyViewedRepository, repoPromotion repository.IPromotionRepository, window time.Duration, topN int) IRecommendationService {
+	return &recommendationService{
+		repo:          repo,
+		repoProduct:   repoProduct,
+		repoViewed:    repoViewed,
+		repoPromotion: repoPromotion,
+		window:        window,
+		topN:          topN,
+	}
+}
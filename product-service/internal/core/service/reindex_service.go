@@ -0,0 +1,393 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"product-service/internal/adapter/repository"
+	"product-service/internal/core/domain/entities"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/labstack/gommon/log"
+)
+
+// reindexBatchSize is how many products are read and bulk indexed at once.
+const reindexBatchSize = 500
+
+const (
+	// reindexLockTTL is how long the reindex lock outlives a process that
+	// died while holding it; a running reindex refreshes it well before.
+	reindexLockTTL = time.Minute
+	// reindexLockRetry is how often a reindex waiting for another one to
+	// finish tries to take the lock again.
+	reindexLockRetry = 2 * time.Second
+)
+
+var errReindexLockLost = errors.New("reindex lock lost")
+
+type IReindexService interface {
+	Reindex(ctx context.Context) (*entities.ReindexResultEntity, error)
+	Schedule()
+	Verify(ctx context.Context, index string) (*entities.IndexVerifyEntity, error)
+}
+
+type reindexService struct {
+	repo        repository.ISearchRepository
+	repoProduct repository.IProductRepository
+	repoSynonym repository.ISearchSynonymRepository
+	repoLock    repository.IReindexLockRepository
+
+	// running serializes reindexes within the process and repoLock across
+	// the server and the reindex command, since each one deletes the
+	// versions of the others; pending coalesces the ones scheduled while one
+	// runs
+	running   sync.Mutex
+	pendingMu sync.Mutex
+	pending   bool
+}
+
+// Reindex implements [IReindexService]. All published products are loaded
+// into a new versioned index while searches keep using the current one, then
+// the alias is swapped over and the old versions are deleted. Products
+// changed while loading are repaired before and after the swap, since until
+// the swap the consumers keep writing to the old index.
+func (r *reindexService) Reindex(ctx context.Context) (*entities.ReindexResultEntity, error) {
+	r.running.Lock()
+	defer r.running.Unlock()
+
+	ctx, unlock, err := r.lock(ctx)
+	if err != nil {
+		log.Errorf("[ReindexService-1] Reindex: %v", err)
+		return nil, err
+	}
+	defer unlock()
+
+	return r.reindex(ctx)
+}
+
+// Schedule implements [IReindexService]. It reindexes in the background,
+// after the reindex already running if any. Schedules made before the next
+// reindex starts are served by that one, as it reads the synonyms when it
+// starts.
+func (r *reindexService) Schedule() {
+	r.pendingMu.Lock()
+	defer r.pendingMu.Unlock()
+	if r.pending {
+		return
+	}
+	r.pending = true
+
+	go func() {
+		r.running.Lock()
+		defer r.running.Unlock()
+
+		r.pendingMu.Lock()
+		r.pending = false
+		r.pendingMu.Unlock()
+
+		ctx, unlock, err := r.lock(context.Background())
+		if err != nil {
+			log.Errorf("[ReindexService-1] Schedule: %v", err)
+			return
+		}
+		defer unlock()
+
+		result, err := r.reindex(ctx)
+		if err != nil {
+			log.Errorf("[ReindexService-2] Schedule: %v", err)
+			return
+		}
+		log.Infof("[ReindexService-3] Schedule: %d products indexed into %s", result.Indexed, result.Index)
+	}()
+}
+
+// lock waits until no other process reindexes and takes the reindex lock.
+// The lock is refreshed while the returned context is in use; if it is lost
+// anyway, the context is cancelled so the reindex stops before it swaps the
+// alias or deletes versions another process may be loading. unlock stops the
+// refresh and releases the lock.
+func (r *reindexService) lock(ctx context.Context) (context.Context, func(), error) {
+	var token string
+	for {
+		var (
+			ok  bool
+			err error
+		)
+		token, ok, err = r.repoLock.Acquire(ctx, reindexLockTTL)
+		if err != nil {
+			log.Errorf("[ReindexService-1] lock: %v", err)
+			return nil, nil, err
+		}
+		if ok {
+			break
+		}
+
+		log.Infof("[ReindexService-2] lock: another reindex is running, waiting")
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(reindexLockRetry):
+		}
+	}
+
+	lockCtx, cancel := context.WithCancelCause(ctx)
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(reindexLockTTL / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				ok, err := r.repoLock.Refresh(context.Background(), token, reindexLockTTL)
+				if err != nil {
+					log.Errorf("[ReindexService-3] lock: %v", err)
+					continue
+				}
+				if !ok {
+					log.Errorf("[ReindexService-4] lock: %v", errReindexLockLost)
+					cancel(errReindexLockLost)
+					return
+				}
+			}
+		}
+	}()
+
+	unlock := func() {
+		close(done)
+		cancel(nil)
+		if err := r.repoLock.Release(context.Background(), token); err != nil {
+			log.Errorf("[ReindexService-5] lock: %v", err)
+		}
+	}
+
+	return lockCtx, unlock, nil
+}
+
+func (r *reindexService) reindex(ctx context.Context) (*entities.ReindexResultEntity, error) {
+	// the template carries the current mapping and synonyms into the new index
+	rules, err := synonymRules(ctx, r.repoSynonym)
+	if err != nil {
+		log.Errorf("[ReindexService-1] Reindex: %v", err)
+		return nil, err
+	}
+	if err := r.repo.PutIndexTemplate(ctx, rules); err != nil {
+		log.Errorf("[ReindexService-2] Reindex: %v", err)
+		return nil, err
+	}
+
+	index, err := r.repo.CreateVersion(ctx)
+	if err != nil {
+		log.Errorf("[ReindexService-3] Reindex: %v", err)
+		return nil, err
+	}
+
+	result := &entities.ReindexResultEntity{Index: index, Deleted: []string{}}
+	swapped := false
+	defer func() {
+		if swapped {
+			return
+		}
+		if err := r.repo.DeleteIndices(context.Background(), []string{index}); err != nil {
+			log.Errorf("[ReindexService-4] Reindex: %v", err)
+		}
+	}()
+
+	var afterID int64
+	for {
+		products, err := r.repoProduct.GetForIndex(ctx, afterID, reindexBatchSize, true)
+		if err != nil {
+			log.Errorf("[ReindexService-5] Reindex: %v", err)
+			return nil, err
+		}
+		if len(products) == 0 {
+			break
+		}
+
+		if err := r.repo.BulkIndex(ctx, index, products, nil); err != nil {
+			log.Errorf("[ReindexService-6] Reindex: %v", err)
+			return nil, err
+		}
+
+		result.Indexed += len(products)
+		afterID = products[len(products)-1].ID
+		log.Infof("[ReindexService-7] Reindex: %d products indexed into %s", result.Indexed, index)
+	}
+
+	if err := r.repo.FinishVersion(ctx, index); err != nil {
+		log.Errorf("[ReindexService-8] Reindex: %v", err)
+		return nil, err
+	}
+
+	repaired, err := r.repair(ctx, index)
+	if err != nil {
+		log.Errorf("[ReindexService-9] Reindex: %v", err)
+		return nil, err
+	}
+	result.Repaired += repaired
+
+	if err := r.repo.SwapAlias(ctx, index); err != nil {
+		log.Errorf("[ReindexService-10] Reindex: %v", err)
+		return nil, err
+	}
+	swapped = true
+
+	repaired, err = r.repair(ctx, index)
+	if err != nil {
+		log.Errorf("[ReindexService-11] Reindex: %v", err)
+		return result, err
+	}
+	result.Repaired += repaired
+
+	versions, err := r.repo.Versions(ctx)
+	if err != nil {
+		log.Errorf("[ReindexService-12] Reindex: %v", err)
+		return result, err
+	}
+
+	for _, version := range versions {
+		if version != index {
+			result.Deleted = append(result.Deleted, version)
+		}
+	}
+
+	if err := r.repo.DeleteIndices(ctx, result.Deleted); err != nil {
+		log.Errorf("[ReindexService-13] Reindex: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// repair re-indexes the products whose documents are missing or outdated and
+// deletes documents of products that are gone, returning how many documents
+// it touched.
+func (r *reindexService) repair(ctx context.Context, index string) (int, error) {
+	verify, err := r.Verify(ctx, index)
+	if err != nil {
+		log.Errorf("[ReindexService-1] repair: %v", err)
+		return 0, err
+	}
+
+	if verify.InSync() {
+		return 0, nil
+	}
+
+	products := []entities.ProductEntity{}
+	for _, productID := range append(verify.Missing, verify.Mismatched...) {
+		product, err := r.repoProduct.GetByID(ctx, productID)
+		if err != nil {
+			if err.Error() == "404" {
+				verify.Extra = append(verify.Extra, productID)
+				continue
+			}
+			log.Errorf("[ReindexService-2] repair: %v", err)
+			return 0, err
+		}
+		products = append(products, *product)
+	}
+
+	if err := r.repo.BulkIndex(ctx, index, products, verify.Extra); err != nil {
+		log.Errorf("[ReindexService-3] repair: %v", err)
+		return 0, err
+	}
+
+	return len(products) + len(verify.Extra), nil
+}
+
+// Verify implements [IReindexService]. An empty index verifies the one behind
+// the alias.
+func (r *reindexService) Verify(ctx context.Context, index string) (*entities.IndexVerifyEntity, error) {
+	if index == "" {
+		index = repository.ProductIndex
+	}
+
+	dbChecksums := map[int64]string{}
+	var afterID int64
+	for {
+		products, err := r.repoProduct.GetForIndex(ctx, afterID, reindexBatchSize, true)
+		if err != nil {
+			log.Errorf("[ReindexService-1] Verify: %v", err)
+			return nil, err
+		}
+		if len(products) == 0 {
+			break
+		}
+
+		for _, product := range products {
+			dbChecksums[product.ID] = product.IndexChecksum()
+		}
+		afterID = products[len(products)-1].ID
+	}
+
+	indexChecksums, err := r.repo.Checksums(ctx, index)
+	if err != nil {
+		log.Errorf("[ReindexService-2] Verify: %v", err)
+		return nil, err
+	}
+
+	result := &entities.IndexVerifyEntity{
+		Index:         index,
+		DBCount:       len(dbChecksums),
+		IndexCount:    len(indexChecksums),
+		DBChecksum:    combineChecksums(dbChecksums),
+		IndexChecksum: combineChecksums(indexChecksums),
+		Missing:       []int64{},
+		Extra:         []int64{},
+		Mismatched:    []int64{},
+	}
+
+	for productID, checksum := range dbChecksums {
+		indexChecksum, ok := indexChecksums[productID]
+		switch {
+		case !ok:
+			result.Missing = append(result.Missing, productID)
+		case indexChecksum != checksum:
+			result.Mismatched = append(result.Mismatched, productID)
+		}
+	}
+	for productID := range indexChecksums {
+		if _, ok := dbChecksums[productID]; !ok {
+			result.Extra = append(result.Extra, productID)
+		}
+	}
+
+	sortIDs(result.Missing)
+	sortIDs(result.Extra)
+	sortIDs(result.Mismatched)
+
+	return result, nil
+}
+
+// combineChecksums hashes the per product checksums in id order into one.
+func combineChecksums(checksums map[int64]string) string {
+	ids := []int64{}
+	for productID := range checksums {
+		ids = append(ids, productID)
+	}
+	sortIDs(ids)
+
+	hash := sha256.New()
+	for _, productID := range ids {
+		fmt.Fprintf(hash, "%d:%s\n", productID, checksums[productID])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func sortIDs(ids []int64) {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+}
+
+func NewReindexService(repo repository.ISearchRepository, repoProduct repository.IProductRepository, repoSynonym repository.ISearchSynonymRepository, repoLock repository.IReindexLockRepository) IReindexService {
+	return &reindexService{
+		repo:        repo,
+		repoProduct: repoProduct,
+		repoSynonym: repoSynonym,
+		repoLock:    repoLock,
+	}
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-service/internal/adapter/repository"
+	"product-service/internal/core/domain/entities"
+	"product-service/utils/esquery"
+	"regexp"
+	"strings"
+
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	suggestMinLength = 2
+	// suggestPopularHits is how many lookups make a prefix worth caching.
+	suggestPopularHits = 3
+)
+
+// synonymTermPattern keeps commas and "=>" out of terms, which would change
+// the meaning of the synonym rule.
+var synonymTermPattern = regexp.MustCompile(`^[\p{L}\p{N} \-]+$`)
+
+var ErrInvalidSynonym = errors.New("invalid synonym")
+
+type ISearchService interface {
+	Suggest(ctx context.Context, text string, limit int) (*entities.SuggestEntity, error)
+
+	EnsureIndex(ctx context.Context) error
+	GetSynonyms(ctx context.Context) ([]entities.SearchSynonymEntity, error)
+	GetSynonymByID(ctx context.Context, id int64) (*entities.SearchSynonymEntity, error)
+	CreateSynonym(ctx context.Context, req entities.SearchSynonymEntity) error
+	UpdateSynonym(ctx context.Context, req entities.SearchSynonymEntity) error
+	DeleteSynonym(ctx context.Context, id int64) error
+}
+
+type searchService struct {
+	repo           repository.ISearchRepository
+	cache          repository.ISuggestCacheRepository
+	repoSynonym    repository.ISearchSynonymRepository
+	reindexService IReindexService
+}
+
+// Suggest implements [ISearchService]. Only prefixes typed often enough are
+// cached, so one-off typos do not fill up Redis.
+func (s *searchService) Suggest(ctx context.Context, text string, limit int) (*entities.SuggestEntity, error) {
+	text = strings.ToLower(esquery.CleanText(text))
+	if len([]rune(text)) < suggestMinLength {
+		return &entities.SuggestEntity{
+			Query:      text,
+			Products:   []entities.SuggestProductEntity{},
+			Categories: []entities.SuggestCategoryEntity{},
+		}, nil
+	}
+
+	cached, err := s.cache.Get(ctx, text, limit)
+	if err != nil {
+		log.Errorf("[SearchService-1] Suggest: %v", err)
+	}
+	if cached != nil {
+		return cached, nil
+	}
+
+	result, err := s.repo.Suggest(ctx, text, limit)
+	if err != nil {
+		log.Errorf("[SearchService-2] Suggest: %v", err)
+		return nil, err
+	}
+
+	hits, err := s.cache.Hit(ctx, text, limit)
+	if err != nil {
+		log.Errorf("[SearchService-3] Suggest: %v", err)
+		return result, nil
+	}
+
+	if hits >= suggestPopularHits {
+		if err := s.cache.Set(ctx, text, limit, *result); err != nil {
+			log.Errorf("[SearchService-4] Suggest: %v", err)
+		}
+	}
+
+	return result, nil
+}
+
+// EnsureIndex implements [ISearchService]. An index without the Indonesian
+// analyzers is rebuilt in the background; searches keep using it meanwhile.
+func (s *searchService) EnsureIndex(ctx context.Context) error {
+	rules, err := synonymRules(ctx, s.repoSynonym)
+	if err != nil {
+		log.Errorf("[SearchService-1] EnsureIndex: %v", err)
+		return err
+	}
+
+	needsReindex, err := s.repo.EnsureIndex(ctx, rules)
+	if err != nil {
+		log.Errorf("[SearchService-2] EnsureIndex: %v", err)
+		return err
+	}
+
+	if needsReindex {
+		log.Infof("[SearchService-3] EnsureIndex: %s lacks the analyzers of the template, reindexing", repository.ProductIndex)
+		s.reindexService.Schedule()
+	}
+	return nil
+}
+
+// GetSynonyms implements [ISearchService].
+func (s *searchService) GetSynonyms(ctx context.Context) ([]entities.SearchSynonymEntity, error) {
+	return s.repoSynonym.GetAll(ctx)
+}
+
+// GetSynonymByID implements [ISearchService].
+func (s *searchService) GetSynonymByID(ctx context.Context, id int64) (*entities.SearchSynonymEntity, error) {
+	return s.repoSynonym.GetByID(ctx, id)
+}
+
+// CreateSynonym implements [ISearchService].
+func (s *searchService) CreateSynonym(ctx context.Context, req entities.SearchSynonymEntity) error {
+	terms, err := normalizeSynonymTerms(req.Terms)
+	if err != nil {
+		log.Errorf("[SearchService-1] CreateSynonym: %v", err)
+		return err
+	}
+	req.Terms = terms
+
+	if err := s.repoSynonym.Create(ctx, req); err != nil {
+		log.Errorf("[SearchService-2] CreateSynonym: %v", err)
+		return err
+	}
+
+	return s.reloadSynonyms(ctx)
+}
+
+// UpdateSynonym implements [ISearchService].
+func (s *searchService) UpdateSynonym(ctx context.Context, req entities.SearchSynonymEntity) error {
+	terms, err := normalizeSynonymTerms(req.Terms)
+	if err != nil {
+		log.Errorf("[SearchService-1] UpdateSynonym: %v", err)
+		return err
+	}
+	req.Terms = terms
+
+	if err := s.repoSynonym.Update(ctx, req); err != nil {
+		log.Errorf("[SearchService-2] UpdateSynonym: %v", err)
+		return err
+	}
+
+	return s.reloadSynonyms(ctx)
+}
+
+// DeleteSynonym implements [ISearchService].
+func (s *searchService) DeleteSynonym(ctx context.Context, id int64) error {
+	if err := s.repoSynonym.Delete(ctx, id); err != nil {
+		log.Errorf("[SearchService-1] DeleteSynonym: %v", err)
+		return err
+	}
+
+	return s.reloadSynonyms(ctx)
+}
+
+// reloadSynonyms stores every synonym in the index template and rebuilds the
+// index from it in the background, swapping the alias once it is loaded, so
+// searches never hit a closed index. The database stays the source of truth,
+// so a failed reload is retried by the next change or restart.
+func (s *searchService) reloadSynonyms(ctx context.Context) error {
+	rules, err := synonymRules(ctx, s.repoSynonym)
+	if err != nil {
+		log.Errorf("[SearchService-1] reloadSynonyms: %v", err)
+		return err
+	}
+
+	if err := s.repo.PutIndexTemplate(ctx, rules); err != nil {
+		log.Errorf("[SearchService-2] reloadSynonyms: %v", err)
+		return fmt.Errorf("synonyms saved but search index not updated: %v", err)
+	}
+	s.reindexService.Schedule()
+
+	// cached suggestions were computed with the old synonyms
+	if err := s.cache.Flush(ctx); err != nil {
+		log.Errorf("[SearchService-3] reloadSynonyms: %v", err)
+	}
+
+	return nil
+}
+
+func synonymRules(ctx context.Context, repoSynonym repository.ISearchSynonymRepository) ([]string, error) {
+	synonyms, err := repoSynonym.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := []string{}
+	for _, synonym := range synonyms {
+		rules = append(rules, synonym.Rule())
+	}
+	return rules, nil
+}
+
+// normalizeSynonymTerms lowercases and trims the terms and drops duplicates.
+func normalizeSynonymTerms(terms []string) ([]string, error) {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, term := range terms {
+		term = strings.ToLower(strings.Join(strings.Fields(term), " "))
+		if term == "" {
+			continue
+		}
+		if !synonymTermPattern.MatchString(term) {
+			return nil, fmt.Errorf("%w: term %q may only contain letters, numbers, spaces and dashes", ErrInvalidSynonym, term)
+		}
+		if seen[term] {
+			continue
+		}
+		seen[term] = true
+		result = append(result, term)
+	}
+
+	if len(result) < 2 {
+		return nil, fmt.Errorf("%w: at least two different terms are needed", ErrInvalidSynonym)
+	}
+	return result, nil
+}
+
+func NewSearchService(repo repository.ISearchRepository, cache repository.ISuggestCacheRepository, repoSynonym repository.ISearchSynonymRepository, reindexService IReindexService) ISearchService {
+	return &searchService{
+		repo:           repo,
+		cache:          cache,
+		repoSynonym:    repoSynonym,
+		reindexService: reindexService,
+	}
+}
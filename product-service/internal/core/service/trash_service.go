@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"product-service/internal/adapter/message"
+	"product-service/internal/adapter/repository"
+	"product-service/internal/core/domain/entities"
+	"time"
+
+	"github.com/labstack/gommon/log"
+)
+
+var (
+	ErrCategoryInTrash       = errors.New("the category of this product is deleted, restore the category first")
+	ErrSKUTaken              = errors.New("another product uses this SKU now, change its SKU first")
+	ErrParentCategoryInTrash = errors.New("the parent category is deleted, restore the parent first")
+)
+
+type ITrashService interface {
+	GetProducts(ctx context.Context, query entities.QueryStringEntity) ([]entities.TrashItemEntity, int64, int64, error)
+	RestoreProduct(ctx context.Context, id int64) error
+	GetCategories(ctx context.Context, query entities.QueryStringEntity) ([]entities.TrashItemEntity, int64, int64, error)
+	RestoreCategory(ctx context.Context, id int64) error
+	Purge(ctx context.Context) (*entities.TrashPurgeEntity, error)
+}
+
+type trashService struct {
+	repo              repository.ITrashRepository
+	repoProduct       repository.IProductRepository
+	repoCat           repository.ICategoryRepository
+	publisherRabbitMQ message.IPublishRabbitMQ
+	retention         time.Duration
+}
+
+// GetProducts implements [ITrashService].
+func (t *trashService) GetProducts(ctx context.Context, query entities.QueryStringEntity) ([]entities.TrashItemEntity, int64, int64, error) {
+	results, countData, totalPage, err := t.repo.GetProducts(ctx, query)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	t.setPurgeAt(results)
+	return results, countData, totalPage, nil
+}
+
+// RestoreProduct implements [ITrashService]. The restored product goes back
+// to the indexing queue, which puts it in search again if it is published.
+func (t *trashService) RestoreProduct(ctx context.Context, id int64) error {
+	item, err := t.repo.GetProductByID(ctx, id)
+	if err != nil {
+		log.Errorf("[TrashService-1] RestoreProduct: %v", err)
+		return err
+	}
+
+	if _, err := t.repoCat.GetCategoryBySlug(ctx, item.CategorySlug); err != nil {
+		log.Errorf("[TrashService-2] RestoreProduct: %v", err)
+		if err.Error() == "404" {
+			return ErrCategoryInTrash
+		}
+		return err
+	}
+
+	// the unique index on sku ignores deleted products, so the SKU may have
+	// been given to another product in the meantime
+	if item.SKU != "" {
+		_, err := t.repoProduct.GetBySKU(ctx, item.SKU)
+		if err == nil {
+			return ErrSKUTaken
+		}
+		if err.Error() != "404" {
+			log.Errorf("[TrashService-3] RestoreProduct: %v", err)
+			return err
+		}
+	}
+
+	if err := t.repo.RestoreProduct(ctx, id, item.DeletedAt); err != nil {
+		log.Errorf("[TrashService-4] RestoreProduct: %v", err)
+		return err
+	}
+
+	product, err := t.repoProduct.GetByID(ctx, id)
+	if err != nil {
+		log.Errorf("[TrashService-5] RestoreProduct: %v", err)
+		return nil
+	}
+
+	if err := t.publisherRabbitMQ.PublishProductToQueue(*product); err != nil {
+		log.Errorf("[TrashService-6] RestoreProduct: %v", err)
+	}
+
+	refreshBundles(ctx, t.repoProduct, t.publisherRabbitMQ, id)
+
+	return nil
+}
+
+// GetCategories implements [ITrashService].
+func (t *trashService) GetCategories(ctx context.Context, query entities.QueryStringEntity) ([]entities.TrashItemEntity, int64, int64, error) {
+	results, countData, totalPage, err := t.repo.GetCategories(ctx, query)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	t.setPurgeAt(results)
+	return results, countData, totalPage, nil
+}
+
+// RestoreCategory implements [ITrashService]. A category whose parent is in
+// the trash waits for the parent; one whose parent was purged comes back at
+// the top level.
+func (t *trashService) RestoreCategory(ctx context.Context, id int64) error {
+	item, err := t.repo.GetCategoryByID(ctx, id)
+	if err != nil {
+		log.Errorf("[TrashService-1] RestoreCategory: %v", err)
+		return err
+	}
+
+	parentID := item.ParentID
+	if parentID != nil {
+		_, err := t.repoCat.GetCategoryByID(ctx, *parentID)
+		switch {
+		case err == nil:
+		case err.Error() != "404":
+			log.Errorf("[TrashService-2] RestoreCategory: %v", err)
+			return err
+		default:
+			_, err := t.repo.GetCategoryByID(ctx, *parentID)
+			if err == nil {
+				return ErrParentCategoryInTrash
+			}
+			if err.Error() != "404" {
+				log.Errorf("[TrashService-3] RestoreCategory: %v", err)
+				return err
+			}
+			parentID = nil
+		}
+	}
+
+	if err := t.repo.RestoreCategory(ctx, id, parentID); err != nil {
+		log.Errorf("[TrashService-4] RestoreCategory: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Purge implements [ITrashService].
+func (t *trashService) Purge(ctx context.Context) (*entities.TrashPurgeEntity, error) {
+	return t.repo.Purge(ctx, time.Now().Add(-t.retention))
+}
+
+func (t *trashService) setPurgeAt(items []entities.TrashItemEntity) {
+	for i := range items {
+		items[i].PurgeAt = items[i].DeletedAt.Add(t.retention)
+	}
+}
+
+func NewTrashService(repo repository.ITrashRepository, repoProduct repository.IProductRepository, repoCat repository.ICategoryRepository, publisherRabbitMQ message.IPublishRabbitMQ, retention time.Duration) ITrashService {
+	return &trashService{
+		repo:              repo,
+		repoProduct:       repoProduct,
+		repoCat:           repoCat,
+		publisherRabbitMQ: publisherRabbitMQ,
+		retention:         retention,
+	}
+}
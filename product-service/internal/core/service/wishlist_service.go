@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-service/internal/adapter/message"
+	"product-service/internal/adapter/repository"
+	"product-service/internal/core/domain/entities"
+
+	"github.com/labstack/gommon/log"
+)
+
+type IWishlistService interface {
+	GetAll(ctx context.Context, userID int64, list string) ([]entities.WishlistItemEntity, error)
+	Add(ctx context.Context, req entities.WishlistItemEntity) error
+	Remove(ctx context.Context, userID, productID int64, list string) error
+	MoveToCart(ctx context.Context, userID, productID int64, list string) error
+	SaveForLater(ctx context.Context, userID, productID int64) error
+
+	NotifyProductChanged(ctx context.Context, product entities.ProductEntity) error
+}
+
+type wishlistService struct {
+	repo              repository.IWishlistRepository
+	repoProduct       repository.IProductRepository
+	cartService       ICartService
+	publisherRabbitMQ message.IPublishRabbitMQ
+}
+
+// GetAll implements [IWishlistService]. Entries carry the current product so
+// the customer sees today's price and stock rather than those when saved.
+func (w *wishlistService) GetAll(ctx context.Context, userID int64, list string) ([]entities.WishlistItemEntity, error) {
+	if err := validateWishlistList(list); err != nil {
+		log.Errorf("[WishlistService-1] GetAll: %v", err)
+		return nil, err
+	}
+
+	items, err := w.repo.GetByUser(ctx, userID, list)
+	if err != nil {
+		log.Errorf("[WishlistService-2] GetAll: %v", err)
+		return nil, err
+	}
+
+	productIDs := []int64{}
+	for _, item := range items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	products, err := w.repoProduct.GetByIDs(ctx, productIDs)
+	if err != nil {
+		log.Errorf("[WishlistService-3] GetAll: %v", err)
+		return nil, err
+	}
+
+	productMap := map[int64]entities.ProductEntity{}
+	for _, product := range products {
+		productMap[product.ID] = product
+	}
+
+	for i, item := range items {
+		if product, ok := productMap[item.ProductID]; ok {
+			items[i].Product = &product
+		}
+	}
+
+	return items, nil
+}
+
+// Add implements [IWishlistService].
+func (w *wishlistService) Add(ctx context.Context, req entities.WishlistItemEntity) error {
+	if err := validateWishlistList(req.List); err != nil {
+		log.Errorf("[WishlistService-1] Add: %v", err)
+		return err
+	}
+
+	product, err := w.repoProduct.GetByID(ctx, req.ProductID)
+	if err != nil {
+		log.Errorf("[WishlistService-2] Add: %v", err)
+		return err
+	}
+
+	if req.Quantity <= 0 {
+		req.Quantity = 1
+	}
+	req.LastPrice = product.UnitPrice()
+	req.LastInStock = product.Stock > 0
+
+	return w.repo.Upsert(ctx, req)
+}
+
+// Remove implements [IWishlistService].
+func (w *wishlistService) Remove(ctx context.Context, userID, productID int64, list string) error {
+	if err := validateWishlistList(list); err != nil {
+		log.Errorf("[WishlistService-1] Remove: %v", err)
+		return err
+	}
+
+	return w.repo.Delete(ctx, userID, productID, list)
+}
+
+// MoveToCart implements [IWishlistService]. The entry is only removed once the
+// cart accepted it, so an out of stock product stays on the list.
+func (w *wishlistService) MoveToCart(ctx context.Context, userID, productID int64, list string) error {
+	if err := validateWishlistList(list); err != nil {
+		log.Errorf("[WishlistService-1] MoveToCart: %v", err)
+		return err
+	}
+
+	item, err := w.repo.GetItem(ctx, userID, productID, list)
+	if err != nil {
+		log.Errorf("[WishlistService-2] MoveToCart: %v", err)
+		return err
+	}
+
+	owner := entities.CartOwner{UserID: userID}
+	if err := w.cartService.AddToCart(ctx, owner, entities.CartItem{
+		ProductID: item.ProductID,
+		Quantity:  item.Quantity,
+	}); err != nil {
+		log.Errorf("[WishlistService-3] MoveToCart: %v", err)
+		return err
+	}
+
+	return w.repo.Delete(ctx, userID, productID, list)
+}
+
+// SaveForLater implements [IWishlistService]. The cart line keeps its
+// quantity so moving it back restores the cart as it was.
+func (w *wishlistService) SaveForLater(ctx context.Context, userID, productID int64) error {
+	owner := entities.CartOwner{UserID: userID}
+	cart, err := w.cartService.GetCart(ctx, owner)
+	if err != nil {
+		log.Errorf("[WishlistService-1] SaveForLater: %v", err)
+		return err
+	}
+
+	var line *entities.CartItem
+	for i := range cart {
+		if cart[i].ProductID == productID {
+			line = &cart[i]
+			break
+		}
+	}
+	if line == nil {
+		err = errors.New("404")
+		log.Errorf("[WishlistService-2] SaveForLater: %v", err)
+		return err
+	}
+
+	if err := w.Add(ctx, entities.WishlistItemEntity{
+		UserID:    userID,
+		ProductID: productID,
+		List:      entities.WishlistListSavedForLater,
+		Quantity:  line.Quantity,
+	}); err != nil {
+		log.Errorf("[WishlistService-3] SaveForLater: %v", err)
+		return err
+	}
+
+	return w.cartService.RemoveFromCart(ctx, owner, productID)
+}
+
+// NotifyProductChanged implements [IWishlistService]. It compares the product
+// with what each customer last saw and pushes a notification when it went on
+// sale or came back in stock.
+func (w *wishlistService) NotifyProductChanged(ctx context.Context, product entities.ProductEntity) error {
+	items, err := w.repo.GetByProduct(ctx, product.ID)
+	if err != nil {
+		log.Errorf("[WishlistService-1] NotifyProductChanged: %v", err)
+		return err
+	}
+
+	price := product.UnitPrice()
+	inStock := product.Stock > 0
+	changedIDs := []int64{}
+	for _, item := range items {
+		if item.LastPrice == price && item.LastInStock == inStock {
+			continue
+		}
+		changedIDs = append(changedIDs, item.ID)
+
+		if !product.IsPublished() {
+			continue
+		}
+
+		switch {
+		case inStock && !item.LastInStock:
+			w.notify(item.UserID, "Produk kembali tersedia",
+				fmt.Sprintf("%s yang ada di wishlist kamu sudah tersedia kembali.", product.Name))
+		case inStock && product.SalePrice > 0 && price < item.LastPrice:
+			w.notify(item.UserID, "Produk sedang diskon",
+				fmt.Sprintf("%s yang ada di wishlist kamu turun harga menjadi %.0f.", product.Name, price))
+		}
+	}
+
+	return w.repo.UpdateLastSeen(ctx, changedIDs, price, inStock)
+}
+
+func (w *wishlistService) notify(userID int64, subject, message string) {
+	if err := w.publisherRabbitMQ.PublishPushNotif(userID, subject, message); err != nil {
+		log.Errorf("[WishlistService-1] notify: %v", err)
+	}
+}
+
+func validateWishlistList(list string) error {
+	switch list {
+	case entities.WishlistListWishlist, entities.WishlistListSavedForLater:
+		return nil
+	}
+
+	return errors.New("list must be wishlist or saved_for_later")
+}
+
+func NewWishlistService(repo repository.IWishlistRepository, repoProduct repository.IProductRepository, cartService ICartService, publisherRabbitMQ message.IPublishRabbitMQ) IWishlistService {
+	return &wishlistService{
+		repo:              repo,
+		repoProduct:       repoProduct,
+		cartService:       cartService,
+		publisherRabbitMQ: publisherRabbitMQ,
+	}
+}
@@ -1 +1,5 @@
 package utils
+
+const (
+	PUSH_NOTIF = "push-notif"
+)
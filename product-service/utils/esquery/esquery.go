@@ -0,0 +1,225 @@
+// Package esquery builds Elasticsearch search bodies from typed values instead
+// of formatted strings. Bodies are encoded with encoding/json, so user input
+// always ends up as a properly escaped JSON string and cannot change the shape
+// of the query, and sorting is limited to a whitelist of fields.
+package esquery
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// maxTextLength caps free text sent to Elasticsearch; longer input is cut.
+const maxTextLength = 200
+
+var ErrInvalidSort = errors.New("invalid sort field")
+
+// Query is a single query clause such as {"term": {...}}.
+type Query map[string]interface{}
+
+func MatchAll() Query {
+	return Query{"match_all": map[string]interface{}{}}
+}
+
+func Match(field, text string) Query {
+	return Query{"match": map[string]interface{}{field: text}}
+}
+
+func MultiMatch(text string, fields ...string) Query {
+	return Query{"multi_match": map[string]interface{}{
+		"query":  text,
+		"fields": fields,
+	}}
+}
+
+// BoolPrefix matches text as you type: every term but the last must match
+// exactly, the last one as a prefix. Meant for search_as_you_type fields.
+func BoolPrefix(text string, fields ...string) Query {
+	return Query{"multi_match": map[string]interface{}{
+		"query":  text,
+		"type":   "bool_prefix",
+		"fields": fields,
+	}}
+}
+
+// FuzzyMatch tolerates typos in text, keeping the first character fixed.
+func FuzzyMatch(field, text string) Query {
+	return Query{"match": map[string]interface{}{field: map[string]interface{}{
+		"query":         text,
+		"fuzziness":     "AUTO",
+		"prefix_length": 1,
+	}}}
+}
+
+func Term(field string, value interface{}) Query {
+	return Query{"term": map[string]interface{}{field: value}}
+}
+
+func Terms(field string, values []string) Query {
+	return Query{"terms": map[string]interface{}{field: values}}
+}
+
+// Range matches values between gte and lte; a nil bound is left open.
+func Range(field string, gte, lte interface{}) Query {
+	bounds := map[string]interface{}{}
+	if gte != nil {
+		bounds["gte"] = gte
+	}
+	if lte != nil {
+		bounds["lte"] = lte
+	}
+	return Query{"range": map[string]interface{}{field: bounds}}
+}
+
+// Bool combines clauses; Must and Should are scored, Filter and MustNot are
+// not.
+type Bool struct {
+	Must    []Query
+	Filter  []Query
+	Should  []Query
+	MustNot []Query
+
+	// MinimumShouldMatch is needed when Should is combined with Filter,
+	// otherwise none of the Should clauses has to match.
+	MinimumShouldMatch int
+}
+
+// Query returns the bool clause, or match_all when no clause was added.
+func (b Bool) Query() Query {
+	clauses := map[string]interface{}{}
+	if len(b.Must) > 0 {
+		clauses["must"] = b.Must
+	}
+	if len(b.Filter) > 0 {
+		clauses["filter"] = b.Filter
+	}
+	if len(b.Should) > 0 {
+		clauses["should"] = b.Should
+	}
+	if len(b.MustNot) > 0 {
+		clauses["must_not"] = b.MustNot
+	}
+	if b.MinimumShouldMatch > 0 {
+		clauses["minimum_should_match"] = b.MinimumShouldMatch
+	}
+
+	if len(clauses) == 0 {
+		return MatchAll()
+	}
+	return Query{"bool": clauses}
+}
+
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// SortFields whitelists sortable fields, mapping the name accepted from
+// clients to the field in the index (e.g. "name" to "name.keyword").
+type SortFields map[string]string
+
+// Sort resolves name and order ("asc" or "desc") to a Sort, returning
+// ErrInvalidSort for names outside the whitelist.
+func (s SortFields) Sort(name, order string) (Sort, error) {
+	field, ok := s[name]
+	if !ok {
+		return Sort{}, ErrInvalidSort
+	}
+	return Sort{Field: field, Desc: strings.EqualFold(order, "desc")}, nil
+}
+
+// Search is a complete search request body.
+type Search struct {
+	From    int
+	Size    int
+	Query   Query
+	Sort    []Sort
+	Aggs    map[string]interface{}
+	Suggest map[string]interface{}
+
+	// PostFilter narrows the hits after aggregations were computed, so facets
+	// can ignore their own selection.
+	PostFilter Query
+
+	// Source limits the returned document fields; empty returns all.
+	Source []string
+
+	// SearchAfter continues after the sort values of the last hit of the
+	// previous page, for paging deeper than from/size allows.
+	SearchAfter []interface{}
+}
+
+// Reader encodes the search as a JSON body for the Search API.
+func (s Search) Reader() (io.Reader, error) {
+	body := map[string]interface{}{
+		"from": max(s.From, 0),
+		"size": max(s.Size, 0),
+	}
+
+	if s.Query != nil {
+		body["query"] = s.Query
+	} else {
+		body["query"] = MatchAll()
+	}
+
+	if s.PostFilter != nil {
+		body["post_filter"] = s.PostFilter
+	}
+
+	if len(s.Sort) > 0 {
+		sorts := []map[string]interface{}{}
+		for _, sort := range s.Sort {
+			order := "asc"
+			if sort.Desc {
+				order = "desc"
+			}
+			sorts = append(sorts, map[string]interface{}{
+				sort.Field: map[string]string{"order": order},
+			})
+		}
+		body["sort"] = sorts
+	}
+
+	if len(s.Aggs) > 0 {
+		body["aggs"] = s.Aggs
+	}
+
+	if len(s.Suggest) > 0 {
+		body["suggest"] = s.Suggest
+	}
+
+	if len(s.Source) > 0 {
+		body["_source"] = s.Source
+	}
+
+	if len(s.SearchAfter) > 0 {
+		body["search_after"] = s.SearchAfter
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+// CleanText drops control characters, collapses whitespace and caps the
+// length of free text typed by users.
+func CleanText(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, text)
+	text = strings.Join(strings.Fields(text), " ")
+
+	if runes := []rune(text); len(runes) > maxTextLength {
+		text = string(runes[:maxTextLength])
+	}
+	return text
+}
@@ -0,0 +1,202 @@
+package esquery
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestQueries(t *testing.T) {
+	tests := []struct {
+		name  string
+		query Query
+		want  string
+	}{
+		{
+			name:  "match all",
+			query: MatchAll(),
+			want:  `{"match_all":{}}`,
+		},
+		{
+			name:  "match",
+			query: Match("name", "apel"),
+			want:  `{"match":{"name":"apel"}}`,
+		},
+		{
+			name:  "multi match",
+			query: MultiMatch("apel", "name", "description"),
+			want:  `{"multi_match":{"fields":["name","description"],"query":"apel"}}`,
+		},
+		{
+			name:  "bool prefix",
+			query: BoolPrefix("apel mer", "name", "name._2gram"),
+			want:  `{"multi_match":{"fields":["name","name._2gram"],"query":"apel mer","type":"bool_prefix"}}`,
+		},
+		{
+			name:  "fuzzy match",
+			query: FuzzyMatch("name", "apl"),
+			want:  `{"match":{"name":{"fuzziness":"AUTO","prefix_length":1,"query":"apl"}}}`,
+		},
+		{
+			name:  "term",
+			query: Term("status", "Published"),
+			want:  `{"term":{"status":"Published"}}`,
+		},
+		{
+			name:  "terms",
+			query: Terms("unit", []string{"kg", "gram"}),
+			want:  `{"terms":{"unit":["kg","gram"]}}`,
+		},
+		{
+			name:  "closed range",
+			query: Range("sale_price", 1000, 5000),
+			want:  `{"range":{"sale_price":{"gte":1000,"lte":5000}}}`,
+		},
+		{
+			name:  "range open above",
+			query: Range("rating", 4, nil),
+			want:  `{"range":{"rating":{"gte":4}}}`,
+		},
+		{
+			name:  "range open below",
+			query: Range("weight", nil, 250),
+			want:  `{"range":{"weight":{"lte":250}}}`,
+		},
+		{
+			name:  "empty bool",
+			query: Bool{}.Query(),
+			want:  `{"match_all":{}}`,
+		},
+		{
+			name: "bool with every clause",
+			query: Bool{
+				Must:               []Query{Match("name", "apel")},
+				Filter:             []Query{Term("status", "Published")},
+				Should:             []Query{Term("unit", "kg")},
+				MustNot:            []Query{Term("stock", 0)},
+				MinimumShouldMatch: 1,
+			}.Query(),
+			want: `{"bool":{"filter":[{"term":{"status":"Published"}}],"minimum_should_match":1,"must":[{"match":{"name":"apel"}}],"must_not":[{"term":{"stock":0}}],"should":[{"term":{"unit":"kg"}}]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.query)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("query = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortFieldsSort(t *testing.T) {
+	fields := SortFields{"name": "name.keyword", "id": "id"}
+
+	tests := []struct {
+		name    string
+		field   string
+		order   string
+		want    Sort
+		wantErr error
+	}{
+		{name: "ascending", field: "id", order: "asc", want: Sort{Field: "id"}},
+		{name: "descending in any case", field: "name", order: "DESC", want: Sort{Field: "name.keyword", Desc: true}},
+		{name: "unknown order is ascending", field: "id", order: "sideways", want: Sort{Field: "id"}},
+		{name: "outside the whitelist", field: "price; drop", order: "asc", wantErr: ErrInvalidSort},
+		{name: "index field names are not accepted", field: "name.keyword", order: "asc", wantErr: ErrInvalidSort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fields.Sort(tt.field, tt.order)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Sort() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Sort() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchReader(t *testing.T) {
+	tests := []struct {
+		name   string
+		search Search
+		want   string
+	}{
+		{
+			name:   "defaults to match all",
+			search: Search{Size: 10},
+			want:   `{"from":0,"query":{"match_all":{}},"size":10}`,
+		},
+		{
+			name:   "negative paging is clamped",
+			search: Search{From: -5, Size: -1},
+			want:   `{"from":0,"query":{"match_all":{}},"size":0}`,
+		},
+		{
+			name: "every part",
+			search: Search{
+				From:        20,
+				Size:        10,
+				Query:       Match("name", "apel"),
+				PostFilter:  Term("unit", "kg"),
+				Sort:        []Sort{{Field: "sale_price", Desc: true}, {Field: "id"}},
+				Aggs:        map[string]interface{}{"units": map[string]interface{}{"terms": map[string]interface{}{"field": "unit"}}},
+				Source:      []string{"id", "name"},
+				SearchAfter: []interface{}{5000, 42},
+			},
+			want: `{"_source":["id","name"],"aggs":{"units":{"terms":{"field":"unit"}}},"from":20,"post_filter":{"term":{"unit":"kg"}},"query":{"match":{"name":"apel"}},"search_after":[5000,42],"size":10,"sort":[{"sale_price":{"order":"desc"}},{"id":{"order":"asc"}}]}`,
+		},
+		{
+			name:   "user input stays a string",
+			search: Search{Query: Match("name", `"}},"size":10000,"x":{"`)},
+			want:   `{"from":0,"query":{"match":{"name":"\"}},\"size\":10000,\"x\":{\""}},"size":0}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := tt.search.Reader()
+			if err != nil {
+				t.Fatalf("Reader() error = %v", err)
+			}
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("body = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestCleanText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "untouched", text: "apel merah", want: "apel merah"},
+		{name: "whitespace collapsed", text: "  apel \t\n merah  ", want: "apel merah"},
+		{name: "control characters dropped", text: "apel\x00merah\x1b", want: "apel merah"},
+		{name: "empty", text: " \n ", want: ""},
+		{name: "capped in runes", text: strings.Repeat("é", maxTextLength+10), want: strings.Repeat("é", maxTextLength)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CleanText(tt.text); got != tt.want {
+				t.Errorf("CleanText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+// Package xlsx reads the first worksheet of an Office Open XML workbook as
+// rows of strings and writes rows back as a single sheet workbook. It covers
+// what spreadsheets exchanged with the catalog team need, plain text and
+// numbers, and ignores styles, formulas and dates.
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// maxPartSize caps how much of a single part of the workbook is read, so a
+// small upload cannot unpack into gigabytes.
+const maxPartSize = 64 << 20
+
+// maxRows and maxColumns are the sheet limits of Excel. Row and cell
+// references beyond them are rejected rather than allocated.
+const (
+	maxRows    = 1048576
+	maxColumns = 16384
+)
+
+var ErrInvalidWorkbook = errors.New("invalid xlsx workbook")
+
+type workbookXML struct {
+	Sheets []struct {
+		RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type relationshipsXML struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type richTextXML struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (r richTextXML) String() string {
+	if len(r.Runs) == 0 {
+		return r.Text
+	}
+
+	var text strings.Builder
+	for _, run := range r.Runs {
+		text.WriteString(run.Text)
+	}
+	return text.String()
+}
+
+type sharedStringsXML struct {
+	Items []richTextXML `xml:"si"`
+}
+
+type worksheetXML struct {
+	Rows []struct {
+		Index int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string      `xml:"r,attr"`
+			Type   string      `xml:"t,attr"`
+			Value  string      `xml:"v"`
+			Inline richTextXML `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// Read returns the rows of the first worksheet. Rows and cells missing from
+// the sheet come back as empty strings, so every row index matches the row
+// number in the spreadsheet minus one.
+func Read(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWorkbook, err)
+	}
+
+	files := map[string]*zip.File{}
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	sharedStrings := []string{}
+	if file, ok := files["xl/sharedStrings.xml"]; ok {
+		var sst sharedStringsXML
+		if err := decodePart(file, &sst); err != nil {
+			return nil, err
+		}
+		for _, item := range sst.Items {
+			sharedStrings = append(sharedStrings, item.String())
+		}
+	}
+
+	file, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("%w: missing %s", ErrInvalidWorkbook, sheetPath)
+	}
+
+	var sheet worksheetXML
+	if err := decodePart(file, &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := [][]string{}
+	for _, row := range sheet.Rows {
+		rowIndex := len(rows)
+		if row.Index > 0 {
+			rowIndex = row.Index - 1
+		}
+		if rowIndex >= maxRows {
+			return nil, fmt.Errorf("%w: row %d is beyond the last row of a sheet", ErrInvalidWorkbook, rowIndex+1)
+		}
+		for len(rows) <= rowIndex {
+			rows = append(rows, []string{})
+		}
+
+		cells := []string{}
+		for _, cell := range row.Cells {
+			column := len(cells)
+			if cell.Ref != "" {
+				column = columnIndex(cell.Ref)
+			}
+			if column < 0 || column >= maxColumns {
+				return nil, fmt.Errorf("%w: bad cell reference %q", ErrInvalidWorkbook, cell.Ref)
+			}
+			for len(cells) <= column {
+				cells = append(cells, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(cell.Value)
+				if err != nil || index < 0 || index >= len(sharedStrings) {
+					return nil, fmt.Errorf("%w: bad shared string in %s", ErrInvalidWorkbook, cell.Ref)
+				}
+				cells[column] = sharedStrings[index]
+			case "inlineStr":
+				cells[column] = cell.Inline.String()
+			case "n", "":
+				cells[column] = formatNumber(cell.Value)
+			default:
+				cells[column] = cell.Value
+			}
+		}
+		rows[rowIndex] = cells
+	}
+
+	return rows, nil
+}
+
+// firstSheetPath resolves the part name of the first sheet listed in the
+// workbook.
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	const fallback = "xl/worksheets/sheet1.xml"
+
+	workbookFile, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", fmt.Errorf("%w: missing xl/workbook.xml", ErrInvalidWorkbook)
+	}
+
+	var workbook workbookXML
+	if err := decodePart(workbookFile, &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", fmt.Errorf("%w: workbook has no sheets", ErrInvalidWorkbook)
+	}
+
+	relsFile, ok := files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return fallback, nil
+	}
+
+	var rels relationshipsXML
+	if err := decodePart(relsFile, &rels); err != nil {
+		return "", err
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].RelID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+
+	return fallback, nil
+}
+
+func decodePart(file *zip.File, v interface{}) error {
+	reader, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWorkbook, err)
+	}
+	defer reader.Close()
+
+	if err := xml.NewDecoder(io.LimitReader(reader, maxPartSize)).Decode(v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidWorkbook, file.Name, err)
+	}
+	return nil
+}
+
+// columnIndex turns the letters of a cell reference such as "AB12" into a
+// zero based column index. It returns -1 for a reference without column
+// letters and stops counting past maxColumns, so the caller can reject both.
+func columnIndex(ref string) int {
+	index := 0
+	for _, r := range strings.ToUpper(ref) {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		index = index*26 + int(r-'A'+1)
+		if index > maxColumns {
+			break
+		}
+	}
+	return index - 1
+}
+
+func columnName(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
+
+// formatNumber drops the binary floating point noise Excel leaves in stored
+// numbers, e.g. 0.30000000000000004 becomes 0.3.
+func formatNumber(value string) string {
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+
+	number, _ = strconv.ParseFloat(strconv.FormatFloat(number, 'g', 15, 64), 64)
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
+
+const (
+	contentTypesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`
+
+	rootRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	workbookRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`
+)
+
+// Write encodes rows as a workbook with one sheet. Every cell is written as
+// text, so values such as SKUs with leading zeros survive a round trip.
+func Write(w io.Writer, sheetName string, rows [][]string) error {
+	archive := zip.NewWriter(w)
+
+	var sheetNameEscaped bytes.Buffer
+	if err := xml.EscapeText(&sheetNameEscaped, []byte(sheetName)); err != nil {
+		return err
+	}
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypesXML},
+		{"_rels/.rels", rootRelsXML},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML},
+		{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="` + sheetNameEscaped.String() + `" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	}
+
+	for _, part := range parts {
+		writer, err := archive.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(writer, part.content); err != nil {
+			return err
+		}
+	}
+
+	writer, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+
+	io.WriteString(writer, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for rowIndex, row := range rows {
+		fmt.Fprintf(writer, `<row r="%d">`, rowIndex+1)
+		for column, value := range row {
+			fmt.Fprintf(writer, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">`, columnName(column), rowIndex+1)
+			if err := xml.EscapeText(writer, []byte(value)); err != nil {
+				return err
+			}
+			io.WriteString(writer, `</t></is></c>`)
+		}
+		io.WriteString(writer, `</row>`)
+	}
+	if _, err := io.WriteString(writer, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
@@ -0,0 +1,232 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const testWorkbookXML = `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="Produk" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+// testWorkbook zips the given parts, adding a workbook listing one sheet
+// unless one is given.
+func testWorkbook(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+
+	if _, ok := parts["xl/workbook.xml"]; !ok {
+		parts["xl/workbook.xml"] = testWorkbookXML
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range parts {
+		writer, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := writer.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func sheetXML(rows string) string {
+	return `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + rows + `</sheetData></worksheet>`
+}
+
+func TestRead(t *testing.T) {
+	sharedStrings := `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<si><t>sku</t></si>` +
+		`<si><r><t>Apel </t></r><r><t>Merah</t></r></si>` +
+		`</sst>`
+
+	tests := []struct {
+		name    string
+		parts   map[string]string
+		want    [][]string
+		wantErr bool
+	}{
+		{
+			name: "shared, inline, number and boolean cells",
+			parts: map[string]string{
+				"xl/sharedStrings.xml": sharedStrings,
+				"xl/worksheets/sheet1.xml": sheetXML(
+					`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>` +
+						`<row r="2"><c r="A2" t="inlineStr"><is><t>00123</t></is></c><c r="B2"><v>0.30000000000000004</v></c><c r="C2" t="b"><v>1</v></c></row>`),
+			},
+			want: [][]string{{"sku", "Apel Merah"}, {"00123", "0.3", "1"}},
+		},
+		{
+			name: "skipped rows and cells come back empty",
+			parts: map[string]string{
+				"xl/worksheets/sheet1.xml": sheetXML(`<row r="3"><c r="C3" t="n"><v>42</v></c></row>`),
+			},
+			want: [][]string{{}, {}, {"", "", "42"}},
+		},
+		{
+			name: "cells without references follow each other",
+			parts: map[string]string{
+				"xl/worksheets/sheet1.xml": sheetXML(`<row><c><v>1</v></c><c><v>2</v></c></row>`),
+			},
+			want: [][]string{{"1", "2"}},
+		},
+		{
+			name: "the first sheet is found through the relationships",
+			parts: map[string]string{
+				"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+					`<Relationship Id="rId1" Target="worksheets/produk.xml"/></Relationships>`,
+				"xl/worksheets/produk.xml": sheetXML(`<row r="1"><c r="A1"><v>7</v></c></row>`),
+			},
+			want: [][]string{{"7"}},
+		},
+		{
+			name: "shared string out of range",
+			parts: map[string]string{
+				"xl/sharedStrings.xml":     sharedStrings,
+				"xl/worksheets/sheet1.xml": sheetXML(`<row r="1"><c r="A1" t="s"><v>5</v></c></row>`),
+			},
+			wantErr: true,
+		},
+		{
+			name: "row beyond the sheet limit",
+			parts: map[string]string{
+				"xl/worksheets/sheet1.xml": sheetXML(`<row r="1048577"><c r="A1048577"><v>1</v></c></row>`),
+			},
+			wantErr: true,
+		},
+		{
+			name: "column beyond the sheet limit",
+			parts: map[string]string{
+				"xl/worksheets/sheet1.xml": sheetXML(`<row r="1"><c r="ZZZZ1"><v>1</v></c></row>`),
+			},
+			wantErr: true,
+		},
+		{
+			name: "reference without a column",
+			parts: map[string]string{
+				"xl/worksheets/sheet1.xml": sheetXML(`<row r="1"><c r="12"><v>1</v></c></row>`),
+			},
+			wantErr: true,
+		},
+		{
+			name:    "missing sheet",
+			parts:   map[string]string{},
+			wantErr: true,
+		},
+		{
+			name: "workbook without sheets",
+			parts: map[string]string{
+				"xl/workbook.xml":          `<workbook><sheets></sheets></workbook>`,
+				"xl/worksheets/sheet1.xml": sheetXML(""),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Read(testWorkbook(t, tt.parts))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidWorkbook) {
+					t.Errorf("Read() error = %v, want %v", err, ErrInvalidWorkbook)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Read() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadNotAZip(t *testing.T) {
+	if _, err := Read([]byte("sku,name\n1,apel")); !errors.Is(err, ErrInvalidWorkbook) {
+		t.Errorf("Read() error = %v, want %v", err, ErrInvalidWorkbook)
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	rows := [][]string{
+		{"sku", "name", "price"},
+		{"00123", "Apel <Fuji> & Pir", "15000"},
+		{},
+		{"", "  spasi  "},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, "Produk & Harga", rows); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	got, err := Read(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, rows) {
+		t.Errorf("round trip = %q, want %q", got, rows)
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want int
+	}{
+		{ref: "A1", want: 0},
+		{ref: "z9", want: 25},
+		{ref: "AA10", want: 26},
+		{ref: "AB12", want: 27},
+		{ref: "XFD1", want: maxColumns - 1},
+		{ref: "12", want: -1},
+		{ref: "", want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			if got := columnIndex(tt.ref); got != tt.want {
+				t.Errorf("columnIndex(%q) = %d, want %d", tt.ref, got, tt.want)
+			}
+			if tt.want >= 0 {
+				if name := columnName(tt.want); columnIndex(name) != tt.want {
+					t.Errorf("columnName(%d) = %q does not read back", tt.want, name)
+				}
+			}
+		})
+	}
+
+	if got := columnIndex("XFE1"); got < maxColumns {
+		t.Errorf("columnIndex(XFE1) = %d, want at least %d", got, maxColumns)
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "15000", want: "15000"},
+		{value: "0.30000000000000004", want: "0.3"},
+		{value: "1.5E+3", want: "1500"},
+		{value: "-2.5", want: "-2.5"},
+		{value: "apel", want: "apel"},
+		{value: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := formatNumber(tt.value); got != tt.want {
+				t.Errorf("formatNumber(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
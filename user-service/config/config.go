@@ -41,12 +41,17 @@ type Supabase struct {
 	Bucket string `json:"bucket"`
 }
 
+type PublisherName struct {
+	CartMerge string `json:"cart_merge"`
+}
+
 type Config struct {
-	App      App      `json:"app"`
-	Database Database `json:"database"`
-	Redis    Redis    `json:"redis"`
-	RabbitMQ RabbitMQ `json:"rabbitmq"`
-	Storage  Supabase `json:"storage"`
+	App           App           `json:"app"`
+	Database      Database      `json:"database"`
+	Redis         Redis         `json:"redis"`
+	RabbitMQ      RabbitMQ      `json:"rabbitmq"`
+	Storage       Supabase      `json:"storage"`
+	PublisherName PublisherName `json:"publisher_name"`
 }
 
 func NewConfig() *Config {
@@ -83,5 +88,8 @@ func NewConfig() *Config {
 			Key:    viper.GetString("SUPABASE_STORAGE_KEY"),
 			Bucket: viper.GetString("SUPABASE_STORAGE_BUCKET"),
 		},
+		PublisherName: PublisherName{
+			CartMerge: viper.GetString("CART_MERGE_NAME"),
+		},
 	}
 }
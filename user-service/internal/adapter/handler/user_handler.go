@@ -12,6 +12,7 @@ import (
 	"user-service/internal/adapter"
 	"user-service/internal/adapter/handler/request"
 	"user-service/internal/adapter/handler/response"
+	"user-service/internal/adapter/message"
 	"user-service/internal/core/domain/entity"
 	"user-service/internal/core/service"
 	"user-service/utils/conv"
@@ -206,6 +207,21 @@ func (u *userHandler) SignIn(c echo.Context) error {
 	respSign.AccessToken = token
 	respSign.Role = user.RoleName // Assign RoleName here
 
+	// a guest cart started before signing in is merged into the customer
+	// cart; product-service also merges it on the first cart request, so a
+	// lost message only delays the merge
+	cartID := c.Request().Header.Get("X-Cart-ID")
+	if cookie, err := c.Cookie("cart_id"); err == nil && cookie.Value != "" {
+		cartID = cookie.Value
+	}
+	if cartID != "" {
+		go func(userID int, cartID string) {
+			if err := message.PublishCartMerge(userID, cartID); err != nil {
+				log.Errorf("[UserHandler-2] SignIn: %v", err)
+			}
+		}(user.ID, cartID)
+	}
+
 	resp.Message = "success"
 	resp.Data = respSign
 
@@ -70,3 +70,57 @@ func PublishMessage(userId int, email, message, queueName, subject string) error
 		},
 	)
 }
+
+// PublishCartMerge asks product-service to move the guest cart identified by
+// cartID into the cart of the user who just signed in. The queue is
+// CART_MERGE_NAME, the one product-service consumes.
+func PublishCartMerge(userId int, cartID string) error {
+	cfg := config.NewConfig()
+	conn, err := cfg.NewRabbitMQ()
+	if err != nil {
+		log.Errorf("[PublishCartMerge-1] PublishCartMerge: %v", err)
+		return err
+	}
+
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		log.Errorf("[PublishCartMerge-2] PublishCartMerge: %v", err)
+		return err
+	}
+
+	defer ch.Close()
+
+	// the same default as the consumer in product-service
+	queueName := cfg.PublisherName.CartMerge
+	if queueName == "" {
+		queueName = "cart_merge"
+	}
+
+	queue, err := ch.QueueDeclare(queueName, true, false, false, false, nil)
+	if err != nil {
+		log.Errorf("[PublishCartMerge-3] PublishCartMerge: %v", err)
+		return err
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"user_id": userId,
+		"cart_id": cartID,
+	})
+	if err != nil {
+		log.Errorf("[PublishCartMerge-4] PublishCartMerge: %v", err)
+		return err
+	}
+
+	return ch.Publish(
+		"",
+		queue.Name,
+		false,
+		false,
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+}